@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+)
+
+// dataSources holds the connections the repositories are initialized with
+type dataSources struct {
+	DB          *sqlx.DB
+	RedisClient *redis.Client // nil when REDIS_URL is not set, rate limits then being counted in memory
+}
+
+// initDS establishes connections to the data sources configured in the environment
+func initDS() (*dataSources, error) {
+	log.Printf("Initializing data sources\n")
+
+	pgSSL := os.Getenv("PG_SSL")
+	if pgSSL == "" {
+		pgSSL = "disable"
+	}
+
+	pgConnString := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		os.Getenv("PG_HOST"), os.Getenv("PG_PORT"), os.Getenv("PG_USER"), os.Getenv("PG_PASSWORD"), os.Getenv("PG_DB"), pgSSL)
+
+	log.Printf("Connecting to Postgresql\n")
+	db, err := sqlx.Open("postgres", pgConnString)
+	if err != nil {
+		return nil, fmt.Errorf("error opening db: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("error connecting to db: %w", err)
+	}
+
+	ds := &dataSources{DB: db}
+
+	if redisURL := os.Getenv("REDIS_URL"); redisURL != "" {
+		log.Printf("Connecting to Redis\n")
+		opts, err := redis.ParseURL(redisURL)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing REDIS_URL: %w", err)
+		}
+
+		ds.RedisClient = redis.NewClient(opts)
+
+		if err := ds.RedisClient.Ping(context.Background()).Err(); err != nil {
+			return nil, fmt.Errorf("error connecting to redis: %w", err)
+		}
+	}
+
+	return ds, nil
+}
+
+// close closes the connections to the data sources
+func (d *dataSources) close() error {
+	if err := d.DB.Close(); err != nil {
+		return fmt.Errorf("error closing Postgresql: %w", err)
+	}
+
+	if d.RedisClient != nil {
+		if err := d.RedisClient.Close(); err != nil {
+			return fmt.Errorf("error closing Redis: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// deleteAccountReq is not exported
+type deleteAccountReq struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// restoreAccountReq is not exported
+type restoreAccountReq struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// DeleteAccount handler deletes the account of the signed in user confirming their password and signs out
+// all of their sessions. The account can be restored with the link emailed to the user until it is purged
+func (h *Handler) DeleteAccount(c *gin.Context) {
+	u, ok := contextUser(c)
+	if !ok || !h.requireSession(c, u) {
+		return
+	}
+
+	var req deleteAccountReq
+
+	if ok := BindData(c, &req); !ok {
+		return
+	}
+
+	if err := h.UserService.DeleteAccount(c, u.UID, req.Password); err != nil {
+		respondError(c, "Failed to delete account", err)
+		return
+	}
+
+	if err := h.TokenService.Signout(c, u.UID); err != nil {
+		respondError(c, "Failed to sign out sessions of deleted account", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "account deleted successfully",
+	})
+}
+
+// RestoreAccount handler restores an account deleted by its user with the token emailed to them
+func (h *Handler) RestoreAccount(c *gin.Context) {
+	var req restoreAccountReq
+
+	if ok := BindData(c, &req); !ok {
+		return
+	}
+
+	u, err := h.UserService.RestoreAccount(c, req.Token)
+	if err != nil {
+		respondError(c, "Failed to restore account", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user": u,
+	})
+}
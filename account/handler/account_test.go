@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
+)
+
+func TestDeleteAccount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	uid, _ := uuid.NewRandom()
+
+	// setupRouter returns a router whose context already holds u
+	setupRouter := func(u *model.User, us model.UserService, ts model.TokenService) *gin.Engine {
+		router := gin.Default()
+		router.Use(func(c *gin.Context) {
+			c.Set("user", u)
+		})
+
+		NewHandler(&Config{
+			Router:       router,
+			UserService:  us,
+			TokenService: ts,
+		})
+
+		return router
+	}
+
+	request := func(router *gin.Engine, body gin.H) *httptest.ResponseRecorder {
+		reqBody, _ := json.Marshal(body)
+
+		request, _ := http.NewRequest(http.MethodDelete, "/me", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, request)
+
+		return rr
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockUserService := new(mocks.MockUserService)
+		mockTokenService := new(mocks.MockTokenService)
+		mockUserService.On("DeleteAccount", mock.AnythingOfType("*gin.Context"), uid, "howdyhoneighbor!").Return(nil)
+		mockTokenService.On("Signout", mock.AnythingOfType("*gin.Context"), uid).Return(nil)
+
+		rr := request(setupRouter(&model.User{UID: uid}, mockUserService, mockTokenService), gin.H{
+			"password": "howdyhoneighbor!",
+		})
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockUserService.AssertExpectations(t)
+		mockTokenService.AssertExpectations(t)
+	})
+
+	t.Run("Invalid password", func(t *testing.T) {
+		mockUserService := new(mocks.MockUserService)
+		mockTokenService := new(mocks.MockTokenService)
+		mockUserService.On("DeleteAccount", mock.AnythingOfType("*gin.Context"), uid, "wrongpassword").
+			Return(apperrors.NewAuthorization("Invalid password"))
+
+		rr := request(setupRouter(&model.User{UID: uid}, mockUserService, mockTokenService), gin.H{
+			"password": "wrongpassword",
+		})
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		mockTokenService.AssertNotCalled(t, "Signout", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Impersonating admin", func(t *testing.T) {
+		actor := &model.Actor{Subject: uuid.New()}
+		mockUserService := new(mocks.MockUserService)
+		mockTokenService := new(mocks.MockTokenService)
+
+		rr := request(setupRouter(&model.User{UID: uid, Actor: actor}, mockUserService, mockTokenService), gin.H{
+			"password": "howdyhoneighbor!",
+		})
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		mockUserService.AssertNotCalled(t, "DeleteAccount", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestRestoreAccount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	uid, _ := uuid.NewRandom()
+
+	mockUserService := new(mocks.MockUserService)
+	u := &model.User{UID: uid, Status: model.UserActive}
+	mockUserService.On("RestoreAccount", mock.AnythingOfType("*gin.Context"), "aRestoreToken").Return(u, nil)
+
+	router := gin.Default()
+
+	NewHandler(&Config{
+		Router:       router,
+		UserService:  mockUserService,
+		TokenService: new(mocks.MockTokenService),
+	})
+
+	reqBody, _ := json.Marshal(gin.H{"token": "aRestoreToken"})
+
+	request, _ := http.NewRequest(http.MethodPost, "/account/restore", bytes.NewBuffer(reqBody))
+	request.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, request)
+
+	expectedRespBody, _ := json.Marshal(gin.H{
+		"user": u,
+	})
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, expectedRespBody, rr.Body.Bytes())
+	mockUserService.AssertExpectations(t)
+}
@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// searchUsersReq holds the filters of a user search, given as query parameters
+type searchUsersReq struct {
+	Query         string     `form:"q" binding:"max=256"`
+	Status        string     `form:"status" binding:"omitempty,oneof=active disabled pending deleted"`
+	EmailVerified *bool      `form:"emailVerified"`
+	CreatedAfter  *time.Time `form:"createdAfter" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedBefore *time.Time `form:"createdBefore" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit         int        `form:"limit" binding:"min=0"`
+	Offset        int        `form:"offset" binding:"min=0"`
+}
+
+// adminDetailsReq holds the profile fields of a user to update, omitted fields are left unchanged.
+// Version is the version of the user the admin last read
+type adminDetailsReq struct {
+	Name    *string `json:"name" binding:"omitempty,max=50"`
+	Website *string `json:"website" binding:"omitempty,url"`
+	Version int     `json:"version" binding:"required,gte=1"`
+}
+
+type disableUserReq struct {
+	Reason string `json:"reason" binding:"max=512"`
+}
+
+// SearchUsers handler lists a page of the users of the application matching the filters of the query
+func (h *Handler) SearchUsers(c *gin.Context) {
+	var req searchUsersReq
+
+	if ok := bindQuery(c, &req); !ok {
+		return
+	}
+
+	page, err := h.AdminUserService.Search(c, &model.UserFilter{
+		Query:         req.Query,
+		Status:        model.UserStatus(req.Status),
+		EmailVerified: req.EmailVerified,
+		CreatedAfter:  req.CreatedAfter,
+		CreatedBefore: req.CreatedBefore,
+		Limit:         req.Limit,
+		Offset:        req.Offset,
+	})
+	if err != nil {
+		respondError(c, "Failed to search users", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// AdminUser handler gets a user of the application
+func (h *Handler) AdminUser(c *gin.Context) {
+	uid, ok := bindUUIDParam(c, "uid")
+	if !ok {
+		return
+	}
+
+	u, err := h.UserService.Get(c, uid)
+	if err != nil {
+		respondError(c, "Failed to get user", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user": u,
+	})
+}
+
+// UpdateUser handler updates the profile of a user of the application
+func (h *Handler) UpdateUser(c *gin.Context) {
+	actor, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	uid, ok := bindUUIDParam(c, "uid")
+	if !ok {
+		return
+	}
+
+	var req adminDetailsReq
+
+	if ok := BindData(c, &req); !ok {
+		return
+	}
+
+	u, err := h.AdminUserService.Update(c, actor.UID, uid, req.Version, &model.UserDetails{
+		Name:    req.Name,
+		Website: req.Website,
+	})
+	if err != nil {
+		respondError(c, "Failed to update user", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user": u,
+	})
+}
+
+// DisableUser handler prevents a user of the application from signing in, revoking their sessions
+func (h *Handler) DisableUser(c *gin.Context) {
+	actor, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	uid, ok := bindUUIDParam(c, "uid")
+	if !ok {
+		return
+	}
+
+	var req disableUserReq
+
+	if ok := BindData(c, &req); !ok {
+		return
+	}
+
+	u, err := h.AdminUserService.Disable(c, actor.UID, uid, req.Reason)
+	if err != nil {
+		respondError(c, "Failed to disable user", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user": u,
+	})
+}
+
+// EnableUser handler allows a disabled user of the application to sign in again
+func (h *Handler) EnableUser(c *gin.Context) {
+	actor, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	uid, ok := bindUUIDParam(c, "uid")
+	if !ok {
+		return
+	}
+
+	u, err := h.AdminUserService.Enable(c, actor.UID, uid)
+	if err != nil {
+		respondError(c, "Failed to enable user", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user": u,
+	})
+}
+
+// RequirePasswordReset handler requires a user of the application to change their password when next signing in
+func (h *Handler) RequirePasswordReset(c *gin.Context) {
+	actor, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	uid, ok := bindUUIDParam(c, "uid")
+	if !ok {
+		return
+	}
+
+	if err := h.AdminUserService.RequirePasswordReset(c, actor.UID, uid); err != nil {
+		respondError(c, "Failed to require password reset", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "password reset required successfully",
+	})
+}
+
+// RevokeUserSessions handler revokes every session of a user of the application
+func (h *Handler) RevokeUserSessions(c *gin.Context) {
+	actor, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	uid, ok := bindUUIDParam(c, "uid")
+	if !ok {
+		return
+	}
+
+	if err := h.AdminUserService.RevokeSessions(c, actor.UID, uid); err != nil {
+		respondError(c, "Failed to revoke user sessions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "sessions revoked successfully",
+	})
+}
+
+// UnlockUser handler lifts the lockout of a user after too many failed signins
+func (h *Handler) UnlockUser(c *gin.Context) {
+	actor, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	uid, ok := bindUUIDParam(c, "uid")
+	if !ok {
+		return
+	}
+
+	if err := h.AdminUserService.Unlock(c, actor.UID, uid); err != nil {
+		respondError(c, "Failed to unlock user", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "user unlocked successfully",
+	})
+}
+
+// DeleteUser handler soft-deletes a user of the application, who is purged once their retention period is over
+func (h *Handler) DeleteUser(c *gin.Context) {
+	actor, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	uid, ok := bindUUIDParam(c, "uid")
+	if !ok {
+		return
+	}
+
+	if err := h.AdminUserService.Delete(c, actor.UID, uid); err != nil {
+		respondError(c, "Failed to delete user", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "user deleted successfully",
+	})
+}
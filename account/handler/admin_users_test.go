@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
+)
+
+func TestAdminUsers(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+
+	admin := &model.User{
+		UID:         uuid.New(),
+		Permissions: model.Names{model.PermissionReadUsers, model.PermissionWriteUsers},
+	}
+	reader := &model.User{
+		UID:         uuid.New(),
+		Permissions: model.Names{model.PermissionReadUsers},
+	}
+
+	// setup returns a router serving requests as the user
+	setup := func(u *model.User) (*gin.Engine, *mocks.MockAdminUserService) {
+		router := gin.Default()
+		router.Use(func(c *gin.Context) {
+			c.Set("user", u)
+		})
+
+		mockAdminUserService := new(mocks.MockAdminUserService)
+
+		NewHandler(&Config{
+			Router:           router,
+			AdminUserService: mockAdminUserService,
+		})
+
+		return router, mockAdminUserService
+	}
+
+	t.Run("Search users", func(t *testing.T) {
+		router, mockAdminUserService := setup(reader)
+
+		verified := true
+		createdAfter := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		page := &model.UserPage{
+			Users: []*model.User{{UID: uuid.New(), Email: "bob@bob.com", Status: model.UserActive}},
+			Total: 1,
+			Limit: 10,
+		}
+
+		mockAdminUserService.
+			On("Search", mock.Anything, mock.MatchedBy(func(f *model.UserFilter) bool {
+				return f.Query == "bob" && f.Status == model.UserActive && *f.EmailVerified == verified &&
+					f.CreatedAfter.Equal(createdAfter) && f.CreatedBefore == nil && f.Limit == 10 && f.Offset == 0
+			})).
+			Return(page, nil)
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodGet, "/admin/users?q=bob&status=active&emailVerified=true&createdAfter=2026-01-01T00:00:00Z&limit=10", nil)
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(page)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+	})
+
+	t.Run("Search with an invalid filter", func(t *testing.T) {
+		router, mockAdminUserService := setup(reader)
+
+		for _, query := range []string{"status=banned", "limit=many", "createdAfter=yesterday"} {
+			rr := httptest.NewRecorder()
+			request, _ := http.NewRequest(http.MethodGet, "/admin/users?"+query, nil)
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+		}
+
+		mockAdminUserService.AssertNotCalled(t, "Search")
+	})
+
+	t.Run("Disable user", func(t *testing.T) {
+		router, mockAdminUserService := setup(admin)
+
+		uid := uuid.New()
+		disabled := &model.User{UID: uid, Status: model.UserDisabled}
+		mockAdminUserService.On("Disable", mock.Anything, admin.UID, uid, "left the company").Return(disabled, nil)
+
+		reqBody, _ := json.Marshal(gin.H{
+			"reason": "left the company",
+		})
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/admin/users/%s/disable", uid), bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(gin.H{
+			"user": disabled,
+		})
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+	})
+
+	t.Run("Write routes require the write permission", func(t *testing.T) {
+		router, mockAdminUserService := setup(reader)
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("/admin/users/%s", uuid.New()), nil)
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		mockAdminUserService.AssertNotCalled(t, "Delete")
+	})
+
+	t.Run("Not while impersonating", func(t *testing.T) {
+		impersonating := &model.User{
+			UID:         uuid.New(),
+			Permissions: admin.Permissions,
+			Actor:       &model.Actor{Subject: uuid.New()},
+		}
+		router, mockAdminUserService := setup(impersonating)
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("/admin/users/%s/sessions", uuid.New()), nil)
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		mockAdminUserService.AssertNotCalled(t, "RevokeSessions")
+	})
+
+	t.Run("Require password reset", func(t *testing.T) {
+		router, mockAdminUserService := setup(admin)
+
+		uid := uuid.New()
+		mockAdminUserService.On("RequirePasswordReset", mock.Anything, admin.UID, uid).Return(nil)
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/admin/users/%s/password-reset", uid), nil)
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockAdminUserService.AssertExpectations(t)
+	})
+
+	t.Run("Unlock user", func(t *testing.T) {
+		router, mockAdminUserService := setup(admin)
+
+		uid := uuid.New()
+		mockAdminUserService.On("Unlock", mock.Anything, admin.UID, uid).Return(nil)
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/admin/users/%s/unlock", uid), nil)
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockAdminUserService.AssertExpectations(t)
+	})
+
+	t.Run("Unlock requires the write permission", func(t *testing.T) {
+		router, mockAdminUserService := setup(reader)
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/admin/users/%s/unlock", uuid.New()), nil)
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		mockAdminUserService.AssertNotCalled(t, "Unlock")
+	})
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// searchAuditEventsReq holds the filters of an audit log search, given as query parameters
+type searchAuditEventsReq struct {
+	Type          string     `form:"type" binding:"max=64"`
+	Actor         string     `form:"actor" binding:"omitempty,uuid"`
+	Target        string     `form:"target" binding:"omitempty,uuid"`
+	CreatedAfter  *time.Time `form:"createdAfter" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedBefore *time.Time `form:"createdBefore" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit         int        `form:"limit" binding:"min=0"`
+	Offset        int        `form:"offset" binding:"min=0"`
+}
+
+// SearchAuditEvents handler lists a page of the events of the audit log of the application
+// matching the filters of the query
+func (h *Handler) SearchAuditEvents(c *gin.Context) {
+	var req searchAuditEventsReq
+
+	if ok := bindQuery(c, &req); !ok {
+		return
+	}
+
+	page, err := h.AuditService.Search(c, &model.AuditFilter{
+		Type:          model.AuditEventType(req.Type),
+		Actor:         optionalUUID(req.Actor),
+		Target:        optionalUUID(req.Target),
+		CreatedAfter:  req.CreatedAfter,
+		CreatedBefore: req.CreatedBefore,
+		Limit:         req.Limit,
+		Offset:        req.Offset,
+	})
+	if err != nil {
+		respondError(c, "Failed to search audit events", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// VerifyAuditLog handler checks the hash chain of the audit log of the application,
+// responding with the first event breaking it if any
+func (h *Handler) VerifyAuditLog(c *gin.Context) {
+	v, err := h.AuditService.Verify(c)
+	if err != nil {
+		respondError(c, "Failed to verify audit log", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, v)
+}
+
+// optionalUUID parses a uuid already validated by binding, returning nil when empty
+func optionalUUID(s string) *uuid.UUID {
+	if s == "" {
+		return nil
+	}
+
+	id := uuid.MustParse(s)
+
+	return &id
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
+)
+
+func TestAuditEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	auditor := &model.User{
+		UID:         uuid.New(),
+		Permissions: model.Names{model.PermissionReadAudit},
+	}
+
+	// setup returns a router serving requests as the user
+	setup := func(u *model.User) (*gin.Engine, *mocks.MockAuditService) {
+		router := gin.Default()
+		router.Use(func(c *gin.Context) {
+			c.Set("user", u)
+		})
+
+		mockAuditService := new(mocks.MockAuditService)
+
+		NewHandler(&Config{
+			Router:       router,
+			AuditService: mockAuditService,
+		})
+
+		return router, mockAuditService
+	}
+
+	get := func(router *gin.Engine, url string) *httptest.ResponseRecorder {
+		request, _ := http.NewRequest(http.MethodGet, url, nil)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, request)
+
+		return rr
+	}
+
+	t.Run("Search", func(t *testing.T) {
+		router, mockAuditService := setup(auditor)
+
+		target := uuid.New()
+		page := &model.AuditPage{
+			Events: []*model.AuditEvent{{ID: uuid.New(), Seq: 7, Type: model.AuditUserSigninFailed, Target: &target}},
+			Total:  1,
+			Limit:  10,
+		}
+		mockAuditService.On("Search", mock.AnythingOfType("*gin.Context"), &model.AuditFilter{
+			Type:   model.AuditUserSigninFailed,
+			Target: &target,
+			Limit:  10,
+		}).Return(page, nil)
+
+		rr := get(router, "/admin/audit-events?type=user.signin_failed&target="+target.String()+"&limit=10")
+
+		expectedRespBody, _ := json.Marshal(page)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, expectedRespBody, rr.Body.Bytes())
+		mockAuditService.AssertExpectations(t)
+	})
+
+	t.Run("Invalid actor", func(t *testing.T) {
+		router, mockAuditService := setup(auditor)
+
+		rr := get(router, "/admin/audit-events?actor=notauuid")
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockAuditService.AssertNotCalled(t, "Search", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Verify", func(t *testing.T) {
+		router, mockAuditService := setup(auditor)
+
+		v := &model.AuditVerification{Valid: true, Checked: 42}
+		mockAuditService.On("Verify", mock.AnythingOfType("*gin.Context")).Return(v, nil)
+
+		rr := get(router, "/admin/audit-events/verify")
+
+		expectedRespBody, _ := json.Marshal(v)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, expectedRespBody, rr.Body.Bytes())
+	})
+
+	t.Run("Permission required", func(t *testing.T) {
+		router, mockAuditService := setup(&model.User{UID: uuid.New()})
+
+		rr := get(router, "/admin/audit-events")
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		mockAuditService.AssertNotCalled(t, "Search", mock.Anything, mock.Anything)
+	})
+}
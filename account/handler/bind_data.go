@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
 )
 
@@ -32,39 +33,82 @@ func BindData(c *gin.Context, req interface{}) bool {
 
 	// Bind incoming json to struct and check for validation errors
 	if err := c.ShouldBind(req); err != nil {
-		log.Printf("Error binding data: %+v\n", err)
-
-		if errs, ok := err.(validator.ValidationErrors); ok {
-			// could probably extract this, it is also in middleware_auth_user
-			var invalidArgs []invalidArgument
-
-			for _, err := range errs {
-				invalidArgs = append(invalidArgs, invalidArgument{
-					err.Field(),
-					err.Value().(string),
-					err.Tag(),
-					err.Param(),
-				})
-			}
-
-			err := apperrors.NewBadRequest("Invalid request parameters. See invalidArgs")
-
-			c.JSON(err.Status(), gin.H{
-				"error":       err,
-				"invalidArgs": invalidArgs,
+		respondBindError(c, err)
+		return false
+	}
+
+	return true
+}
+
+// bindQuery binds the query string of the request to req, returns false if it is not bound
+func bindQuery(c *gin.Context, req interface{}) bool {
+	err := c.ShouldBindQuery(req)
+	if err == nil {
+		return true
+	}
+
+	if _, ok := err.(validator.ValidationErrors); ok {
+		respondBindError(c, err)
+		return false
+	}
+
+	// malformed values, such as a limit which is not a number, are the mistake of the client as well
+	log.Printf("Error binding query: %+v\n", err)
+	badRequest := apperrors.NewBadRequest(fmt.Sprintf("Invalid query parameters: %v", err))
+	c.JSON(badRequest.Status(), gin.H{
+		"error": badRequest,
+	})
+
+	return false
+}
+
+// respondBindError responds with the validation errors of err, or with an internal error
+// when they cannot be extracted
+func respondBindError(c *gin.Context, err error) {
+	log.Printf("Error binding data: %+v\n", err)
+
+	if errs, ok := err.(validator.ValidationErrors); ok {
+		// could probably extract this, it is also in middleware_auth_user
+		var invalidArgs []invalidArgument
+
+		for _, err := range errs {
+			invalidArgs = append(invalidArgs, invalidArgument{
+				err.Field(),
+				fmt.Sprintf("%v", err.Value()),
+				err.Tag(),
+				err.Param(),
 			})
-			return false
 		}
 
-		// later we'll add code for validating max body size here!
+		err := apperrors.NewBadRequest("Invalid request parameters. See invalidArgs")
 
-		// if we aren't able to properly extract validation errors,
-		// we'll fallback and return an internal server error
-		fallBack := apperrors.NewInternal()
+		c.JSON(err.Status(), gin.H{
+			"error":       err,
+			"invalidArgs": invalidArgs,
+		})
+		return
+	}
 
-		c.JSON(fallBack.Status(), gin.H{"error": fallBack})
-		return false
+	// later we'll add code for validating max body size here!
+
+	// if we aren't able to properly extract validation errors,
+	// we'll fallback and return an internal server error
+	fallBack := apperrors.NewInternal()
+
+	c.JSON(fallBack.Status(), gin.H{"error": fallBack})
+}
+
+// bindUUIDParam parses the path parameter name as a uuid, returns false and
+// responds with a bad request if it is not one
+func bindUUIDParam(c *gin.Context, name string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(name))
+	if err != nil {
+		err := apperrors.NewBadRequest(fmt.Sprintf("Invalid %s", name))
+		c.JSON(err.Status(), gin.H{
+			"error": err,
+		})
+		return uuid.Nil, false
 	}
 
-	return true
+	return id, true
 }
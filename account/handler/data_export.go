@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// dataExportReq is not exported
+type dataExportReq struct {
+	Format string `form:"format" binding:"omitempty,oneof=json zip"`
+}
+
+// ExportData handler exports the data held about the signed in user as a json archive, or as a zip archive
+// of a json file per kind of data. The data of large accounts is exported in the background, the pending
+// export being responded with a 202 along with the location to fetch it from once ready
+func (h *Handler) ExportData(c *gin.Context) {
+	u, ok := contextUser(c)
+	if !ok || !h.requireSession(c, u) {
+		return
+	}
+
+	var req dataExportReq
+
+	if ok := bindQuery(c, &req); !ok {
+		return
+	}
+
+	e, err := h.DataExportService.Export(c, u.UID)
+	if err != nil {
+		respondError(c, "Failed to export data", err)
+		return
+	}
+
+	if e.Status == model.DataExportPending {
+		c.Header("Location", c.Request.URL.Path+"/"+e.ID.String())
+	}
+
+	respondDataExport(c, e, req.Format)
+}
+
+// DataExport handler fetches a data export of the signed in user generated in the background,
+// responding with its archive once ready
+func (h *Handler) DataExport(c *gin.Context) {
+	u, ok := contextUser(c)
+	if !ok || !h.requireSession(c, u) {
+		return
+	}
+
+	id, ok := bindUUIDParam(c, "exportId")
+	if !ok {
+		return
+	}
+
+	var req dataExportReq
+
+	if ok := bindQuery(c, &req); !ok {
+		return
+	}
+
+	e, err := h.DataExportService.Get(c, u.UID, id)
+	if err != nil {
+		respondError(c, "Failed to get data export", err)
+		return
+	}
+
+	respondDataExport(c, e, req.Format)
+}
+
+// respondDataExport responds with the archive of a ready export in the requested format,
+// or with the export itself while it is pending or once it failed
+func respondDataExport(c *gin.Context, e *model.DataExport, format string) {
+	// archives hold personal data which is not to be kept by caches along the way
+	c.Header("Cache-Control", "no-store")
+
+	switch e.Status {
+	case model.DataExportPending:
+		c.JSON(http.StatusAccepted, gin.H{
+			"export": e,
+		})
+		return
+	case model.DataExportFailed:
+		c.JSON(http.StatusOK, gin.H{
+			"export": e,
+		})
+		return
+	}
+
+	if format != "zip" {
+		c.Header("Content-Disposition", `attachment; filename="account-data.json"`)
+		c.Data(http.StatusOK, "application/json", e.Archive)
+		return
+	}
+
+	archive, err := zipArchive(e.Archive)
+	if err != nil {
+		log.Printf("Failed to zip data export: %v\n", err)
+		err := apperrors.NewInternal()
+		c.JSON(err.Status(), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="account-data.zip"`)
+	c.Data(http.StatusOK, "application/zip", archive)
+}
+
+// zipArchive splits a json archive of model.UserData into a zip archive of a json file per field
+func zipArchive(archive []byte) ([]byte, error) {
+	fields := map[string]json.RawMessage{}
+
+	if err := json.Unmarshal(archive, &fields); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	for _, name := range names {
+		w, err := zw.Create(name + ".json")
+		if err != nil {
+			return nil, err
+		}
+
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, fields[name], "", "  "); err != nil {
+			return nil, err
+		}
+
+		if _, err := indented.WriteTo(w); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
+)
+
+func TestExportData(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	uid, _ := uuid.NewRandom()
+
+	// setupRouter returns a router whose context already holds the authenticated user
+	setupRouter := func(des model.DataExportService) *gin.Engine {
+		router := gin.Default()
+		router.Use(func(c *gin.Context) {
+			c.Set("user", &model.User{
+				UID: uid,
+			})
+		})
+
+		NewHandler(&Config{
+			Router:            router,
+			UserService:       new(mocks.MockUserService),
+			TokenService:      new(mocks.MockTokenService),
+			DataExportService: des,
+		})
+
+		return router
+	}
+
+	get := func(router *gin.Engine, url string) *httptest.ResponseRecorder {
+		request, _ := http.NewRequest(http.MethodGet, url, nil)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, request)
+
+		return rr
+	}
+
+	archive, _ := json.Marshal(&model.UserData{
+		Profile:     &model.User{UID: uid, Email: "bob@bob.com"},
+		AuditEvents: []*model.AuditEvent{},
+	})
+	ready := &model.DataExport{UID: uid, Status: model.DataExportReady, Archive: archive}
+
+	t.Run("Json archive", func(t *testing.T) {
+		mockDataExportService := new(mocks.MockDataExportService)
+		mockDataExportService.On("Export", mock.AnythingOfType("*gin.Context"), uid).Return(ready, nil)
+
+		rr := get(setupRouter(mockDataExportService), "/me/export")
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+		assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+		assert.Equal(t, archive, rr.Body.Bytes())
+	})
+
+	t.Run("Zip archive", func(t *testing.T) {
+		mockDataExportService := new(mocks.MockDataExportService)
+		mockDataExportService.On("Export", mock.AnythingOfType("*gin.Context"), uid).Return(ready, nil)
+
+		rr := get(setupRouter(mockDataExportService), "/me/export?format=zip")
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/zip", rr.Header().Get("Content-Type"))
+
+		zr, err := zip.NewReader(bytes.NewReader(rr.Body.Bytes()), int64(rr.Body.Len()))
+		assert.NoError(t, err)
+
+		files := map[string]*zip.File{}
+		for _, f := range zr.File {
+			files[f.Name] = f
+		}
+
+		assert.Contains(t, files, "auditEvents.json")
+		assert.Contains(t, files, "profile.json")
+
+		f, _ := files["profile.json"].Open()
+		profile, _ := io.ReadAll(f)
+		assert.Contains(t, string(profile), "bob@bob.com")
+	})
+
+	t.Run("Pending export", func(t *testing.T) {
+		pending := &model.DataExport{ID: uuid.New(), UID: uid, Status: model.DataExportPending}
+
+		mockDataExportService := new(mocks.MockDataExportService)
+		mockDataExportService.On("Export", mock.AnythingOfType("*gin.Context"), uid).Return(pending, nil)
+
+		rr := get(setupRouter(mockDataExportService), "/me/export")
+
+		expectedRespBody, _ := json.Marshal(gin.H{
+			"export": pending,
+		})
+
+		assert.Equal(t, http.StatusAccepted, rr.Code)
+		assert.Equal(t, "/me/export/"+pending.ID.String(), rr.Header().Get("Location"))
+		assert.Equal(t, expectedRespBody, rr.Body.Bytes())
+	})
+
+	t.Run("Invalid format", func(t *testing.T) {
+		mockDataExportService := new(mocks.MockDataExportService)
+
+		rr := get(setupRouter(mockDataExportService), "/me/export?format=xml")
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockDataExportService.AssertNotCalled(t, "Export", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Export fetched once ready", func(t *testing.T) {
+		id := uuid.New()
+
+		mockDataExportService := new(mocks.MockDataExportService)
+		mockDataExportService.On("Get", mock.AnythingOfType("*gin.Context"), uid, id).Return(ready, nil)
+
+		rr := get(setupRouter(mockDataExportService), "/me/export/"+id.String())
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, archive, rr.Body.Bytes())
+	})
+
+	t.Run("Expired export", func(t *testing.T) {
+		id := uuid.New()
+
+		mockDataExportService := new(mocks.MockDataExportService)
+		mockDataExportService.On("Get", mock.AnythingOfType("*gin.Context"), uid, id).
+			Return(nil, apperrors.NewNotFound("export", id.String()))
+
+		rr := get(setupRouter(mockDataExportService), "/me/export/"+id.String())
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
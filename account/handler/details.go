@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// detailsReq holds the profile fields to update, omitted fields are left unchanged and an empty website clears it.
+// Version is the version of the user the client last read.
+// Email is only there to reject email changes, which go through PUT /email, so the current email may be sent along
+type detailsReq struct {
+	Name    *string `json:"name" binding:"omitempty,max=50"`
+	Website *string `json:"website" binding:"omitempty,url|len=0"`
+	Email   *string `json:"email"`
+	Version int     `json:"version" binding:"required,gte=1"`
+}
+
+// Details handler updates the profile of the authenticated user
+func (h *Handler) Details(c *gin.Context) {
+	user, exists := c.Get("user")
+
+	if !exists {
+		log.Printf("Unable to extract user from request context for unknown reason: %v\n", c)
+		err := apperrors.NewInternal()
+		c.JSON(err.Status(), gin.H{
+			"error": err,
+		})
+
+		return
+	}
+
+	var req detailsReq
+
+	if ok := BindData(c, &req); !ok {
+		return
+	}
+
+	uid := user.(*model.User).UID
+
+	if req.Email != nil && !strings.EqualFold(*req.Email, user.(*model.User).Email) {
+		err := apperrors.NewBadRequest("Email can only be changed through PUT /email")
+		c.JSON(err.Status(), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	u, err := h.UserService.UpdateDetails(c, uid, req.Version, &model.UserDetails{
+		Name:    req.Name,
+		Website: req.Website,
+	})
+
+	if err != nil {
+		log.Printf("Failed to update details for uid: %v\n%v", uid, err)
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user": u,
+	})
+}
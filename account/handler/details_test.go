@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
+)
+
+func TestDetails(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+
+	uid, _ := uuid.NewRandom()
+
+	ctxUser := &model.User{
+		UID:   uid,
+		Email: "bob@bob.com",
+	}
+
+	router := gin.Default()
+	router.Use(func(c *gin.Context) {
+		c.Set("user", ctxUser)
+	})
+
+	mockUserService := new(mocks.MockUserService)
+
+	NewHandler(&Config{
+		Router:      router,
+		UserService: mockUserService,
+	})
+
+	t.Run("Data binding error", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+
+		reqBody, _ := json.Marshal(gin.H{
+			"website": "notawebsite",
+			"version": 1,
+		})
+
+		request, _ := http.NewRequest(http.MethodPut, "/details", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockUserService.AssertNotCalled(t, "UpdateDetails")
+	})
+
+	t.Run("Email change rejected", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+
+		reqBody, _ := json.Marshal(gin.H{
+			"name":    "Bobby Bobson",
+			"email":   "new@bob.com",
+			"version": 1,
+		})
+
+		request, _ := http.NewRequest(http.MethodPut, "/details", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockUserService.AssertNotCalled(t, "UpdateDetails")
+	})
+
+	t.Run("Unchanged email accepted", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+
+		name := "Bobby Bobson"
+
+		reqBody, _ := json.Marshal(gin.H{
+			"name":    name,
+			"email":   "Bob@bob.com",
+			"version": 3,
+		})
+
+		request, _ := http.NewRequest(http.MethodPut, "/details", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		updatedUser := &model.User{UID: uid, Email: "bob@bob.com", Name: name, Version: 4}
+
+		mockUserService.
+			On("UpdateDetails", mock.AnythingOfType("*gin.Context"), uid, 3, &model.UserDetails{Name: &name}).
+			Return(updatedUser, nil)
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("Website cleared", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+
+		website := ""
+
+		reqBody, _ := json.Marshal(gin.H{
+			"website": website,
+			"version": 5,
+		})
+
+		request, _ := http.NewRequest(http.MethodPut, "/details", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		updatedUser := &model.User{UID: uid, Email: "bob@bob.com", Version: 6}
+
+		mockUserService.
+			On("UpdateDetails", mock.AnythingOfType("*gin.Context"), uid, 5, &model.UserDetails{Website: &website}).
+			Return(updatedUser, nil)
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("Version required", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+
+		reqBody, _ := json.Marshal(gin.H{
+			"name": "Bobby Bobson",
+		})
+
+		request, _ := http.NewRequest(http.MethodPut, "/details", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockUserService.AssertNotCalled(t, "UpdateDetails")
+	})
+
+	t.Run("Update success", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+
+		name := "Bobby Bobson"
+
+		reqBody, _ := json.Marshal(gin.H{
+			"name":    name,
+			"version": 1,
+		})
+
+		request, _ := http.NewRequest(http.MethodPut, "/details", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		// website was omitted so it must be left unchanged
+		details := &model.UserDetails{
+			Name: &name,
+		}
+
+		updatedUser := &model.User{
+			UID:     uid,
+			Email:   "bob@bob.com",
+			Name:    name,
+			Website: "https://bob.com",
+			Version: 2,
+		}
+
+		mockUserService.
+			On("UpdateDetails", mock.AnythingOfType("*gin.Context"), uid, 1, details).
+			Return(updatedUser, nil)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(gin.H{
+			"user": updatedUser,
+		})
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockUserService.AssertExpectations(t)
+	})
+
+	t.Run("Outdated version", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+
+		website := "https://bob.com"
+
+		reqBody, _ := json.Marshal(gin.H{
+			"website": website,
+			"version": 3,
+		})
+
+		request, _ := http.NewRequest(http.MethodPut, "/details", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		details := &model.UserDetails{
+			Website: &website,
+		}
+
+		mockError := apperrors.NewOutdated("user", uid.String())
+
+		mockUserService.
+			On("UpdateDetails", mock.AnythingOfType("*gin.Context"), uid, 3, details).
+			Return(nil, mockError)
+
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(gin.H{
+			"error": mockError,
+		})
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+	})
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+type changeEmailReq struct {
+	Password string `json:"password" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
+}
+
+type emailTokenReq struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ChangeEmail handler sends a confirmation link to the new email address of the authenticated user
+func (h *Handler) ChangeEmail(c *gin.Context) {
+	user, exists := c.Get("user")
+
+	if !exists {
+		log.Printf("Unable to extract user from request context for unknown reason: %v\n", c)
+		err := apperrors.NewInternal()
+		c.JSON(err.Status(), gin.H{
+			"error": err,
+		})
+
+		return
+	}
+
+	var req changeEmailReq
+
+	if ok := BindData(c, &req); !ok {
+		return
+	}
+
+	uid := user.(*model.User).UID
+
+	if err := h.UserService.RequestEmailChange(c, uid, req.Password, req.Email); err != nil {
+		log.Printf("Failed to request email change for uid: %v\n%v", uid, err)
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "a confirmation link has been sent to the new email address",
+	})
+}
+
+// ConfirmEmailChange handler completes an email change. Every existing session
+// is signed out and a new token pair is issued for the updated user
+func (h *Handler) ConfirmEmailChange(c *gin.Context) {
+	var req emailTokenReq
+
+	if ok := BindData(c, &req); !ok {
+		return
+	}
+
+	u, err := h.UserService.ConfirmEmailChange(c, req.Token)
+	if err != nil {
+		log.Printf("Failed to confirm email change: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	if err := h.TokenService.Signout(c, u.UID); err != nil {
+		log.Printf("Failed to sign out sessions of uid: %v\n%v", u.UID, err)
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	tokens, err := h.TokenService.NewPairFromUser(c, u, "")
+	if err != nil {
+		log.Printf("Failed to create tokens for uid: %v\n%v", u.UID, err)
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user":   u,
+		"tokens": tokens,
+	})
+}
+
+// RevertEmailChange handler restores a previous email address and signs out every session,
+// as the change may have been made by someone who took over the account
+func (h *Handler) RevertEmailChange(c *gin.Context) {
+	var req emailTokenReq
+
+	if ok := BindData(c, &req); !ok {
+		return
+	}
+
+	u, err := h.UserService.RevertEmailChange(c, req.Token)
+	if err != nil {
+		log.Printf("Failed to revert email change: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	if err := h.TokenService.Signout(c, u.UID); err != nil {
+		log.Printf("Failed to sign out sessions of uid: %v\n%v", u.UID, err)
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "email address restored, all sessions have been signed out",
+	})
+}
@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
+)
+
+func TestChangeEmail(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+
+	uid, _ := uuid.NewRandom()
+
+	t.Run("Success", func(t *testing.T) {
+		mockUserService := new(mocks.MockUserService)
+		mockUserService.On("RequestEmailChange", mock.AnythingOfType("*gin.Context"), uid, "password123", "new@bob.com").Return(nil)
+
+		rr := httptest.NewRecorder()
+		router := gin.Default()
+		router.Use(func(c *gin.Context) {
+			c.Set("user", &model.User{
+				UID: uid,
+			})
+		})
+
+		NewHandler(&Config{
+			Router:      router,
+			UserService: mockUserService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"password": "password123",
+			"email":    "new@bob.com",
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPut, "/email", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusAccepted, rr.Code)
+		mockUserService.AssertExpectations(t)
+	})
+
+	t.Run("Email already in use", func(t *testing.T) {
+		mockUserService := new(mocks.MockUserService)
+		mockErr := apperrors.NewConflict("email", "new@bob.com")
+		mockUserService.On("RequestEmailChange", mock.AnythingOfType("*gin.Context"), uid, "password123", "new@bob.com").Return(mockErr)
+
+		rr := httptest.NewRecorder()
+		router := gin.Default()
+		router.Use(func(c *gin.Context) {
+			c.Set("user", &model.User{
+				UID: uid,
+			})
+		})
+
+		NewHandler(&Config{
+			Router:      router,
+			UserService: mockUserService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"password": "password123",
+			"email":    "new@bob.com",
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPut, "/email", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+		mockUserService.AssertExpectations(t)
+	})
+}
+
+func TestConfirmEmailChange(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+
+	uid, _ := uuid.NewRandom()
+
+	t.Run("Success rotates tokens", func(t *testing.T) {
+		u := &model.User{
+			UID:           uid,
+			Email:         "new@bob.com",
+			EmailVerified: true,
+		}
+		mockTokenPair := &model.TokenPair{
+			AccessToken:  "idToken",
+			RefreshToken: "refreshToken",
+		}
+
+		mockUserService := new(mocks.MockUserService)
+		mockTokenService := new(mocks.MockTokenService)
+		mockUserService.On("ConfirmEmailChange", mock.AnythingOfType("*gin.Context"), "a-token").Return(u, nil)
+		mockTokenService.On("Signout", mock.AnythingOfType("*gin.Context"), uid).Return(nil)
+		mockTokenService.On("NewPairFromUser", mock.AnythingOfType("*gin.Context"), u, "").Return(mockTokenPair, nil)
+
+		rr := httptest.NewRecorder()
+		router := gin.Default()
+		NewHandler(&Config{
+			Router:       router,
+			UserService:  mockUserService,
+			TokenService: mockTokenService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"token": "a-token",
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/email/confirm", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(gin.H{
+			"user":   u,
+			"tokens": mockTokenPair,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockUserService.AssertExpectations(t)
+		mockTokenService.AssertExpectations(t)
+	})
+
+	t.Run("Invalid token", func(t *testing.T) {
+		mockUserService := new(mocks.MockUserService)
+		mockTokenService := new(mocks.MockTokenService)
+		mockErr := apperrors.NewBadRequest("Invalid or expired email change token")
+		mockUserService.On("ConfirmEmailChange", mock.AnythingOfType("*gin.Context"), "a-token").Return(nil, mockErr)
+
+		rr := httptest.NewRecorder()
+		router := gin.Default()
+		NewHandler(&Config{
+			Router:       router,
+			UserService:  mockUserService,
+			TokenService: mockTokenService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"token": "a-token",
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/email/confirm", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockTokenService.AssertNotCalled(t, "Signout", mock.Anything, mock.Anything)
+		mockTokenService.AssertNotCalled(t, "NewPairFromUser", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestRevertEmailChange(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+
+	uid, _ := uuid.NewRandom()
+
+	t.Run("Success signs out every session", func(t *testing.T) {
+		mockUserService := new(mocks.MockUserService)
+		mockTokenService := new(mocks.MockTokenService)
+		mockUserService.On("RevertEmailChange", mock.AnythingOfType("*gin.Context"), "a-token").Return(&model.User{
+			UID:   uid,
+			Email: "old@bob.com",
+		}, nil)
+		mockTokenService.On("Signout", mock.AnythingOfType("*gin.Context"), uid).Return(nil)
+
+		rr := httptest.NewRecorder()
+		router := gin.Default()
+		NewHandler(&Config{
+			Router:       router,
+			UserService:  mockUserService,
+			TokenService: mockTokenService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"token": "a-token",
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/email/revert", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockUserService.AssertExpectations(t)
+		mockTokenService.AssertExpectations(t)
+	})
+}
@@ -2,8 +2,10 @@ package handler
 
 import (
 	"fmt"
+	"github.com/weslleyrsr/auth-engine/account/handler/middleware"
 	"github.com/weslleyrsr/auth-engine/account/model"
-	"net/http"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"log"
 	"os"
 
 	"github.com/gin-gonic/gin"
@@ -14,6 +16,19 @@ type Handler struct {
 	UserService    model.UserService
 	TokenService   model.TokenService
 	UserRepository model.UserRepository
+	AppSettings    model.AppSettings
+	MaxBodyBytes   int64
+
+	BreachedPasswordRepository model.BreachedPasswordRepository
+	LockoutService             model.LockoutService
+	RoleService                model.RoleService
+	OrganizationService        model.OrganizationService
+	PolicyService              model.PolicyService
+	PersonalAccessTokenService model.PersonalAccessTokenService
+	ServiceAccountService      model.ServiceAccountService
+	AdminUserService           model.AdminUserService
+	DataExportService          model.DataExportService
+	AuditService               model.AuditService
 }
 
 // Config will hold services that will eventually be injected into this
@@ -23,8 +38,42 @@ type Config struct {
 	UserService    model.UserService
 	TokenService   model.TokenService
 	UserRepository model.UserRepository
+	AppSettings    model.AppSettings
+	MaxBodyBytes   int64 // max size of uploaded images, defaults to defaultMaxBodyBytes
+
+	// BreachedPasswordRepository rejects passwords exposed in data breaches, skipped when nil
+	BreachedPasswordRepository model.BreachedPasswordRepository
+	// LockoutService throttles failed signins, skipped when nil
+	LockoutService model.LockoutService
+	// RateLimiter stores the counters of RateLimits, public routes are not rate limited when nil
+	RateLimiter model.RateLimiter
+	RateLimits  *RateLimits // defaults to DefaultRateLimits
+	// RoleService manages roles and permissions through the admin routes, which are not registered when nil
+	RoleService model.RoleService
+	// OrganizationService manages organizations, whose routes are not registered when nil
+	OrganizationService model.OrganizationService
+	// PolicyService evaluates access policies, whose routes are not registered when nil
+	PolicyService model.PolicyService
+	// PersonalAccessTokenService manages personal access tokens, whose routes are not registered when nil.
+	// Tokens are accepted alongside id tokens as long as the TokenService validates them
+	PersonalAccessTokenService model.PersonalAccessTokenService
+	// ServiceAccountService manages service accounts and authenticates them at the token endpoint,
+	// whose routes are not registered when nil
+	ServiceAccountService model.ServiceAccountService
+	// AdminUserService lets admins manage users through the admin routes, which are not registered when nil
+	AdminUserService model.AdminUserService
+	// DataExportService exports the data held about users to themselves, whose routes are not registered when nil
+	DataExportService model.DataExportService
+	// AuditService lets admins query and verify the audit log through the admin routes, which are not registered when nil
+	AuditService model.AuditService
+	// ApplicationRepository resolves the application each request is made to. Every request
+	// is served as the default application when nil
+	ApplicationRepository model.ApplicationRepository
 }
 
+// defaultMaxBodyBytes is used when Config.MaxBodyBytes is not set
+const defaultMaxBodyBytes = 4 * 1024 * 1024
+
 // NewHandler initializes the handler with required injected services along with http routes
 // Does not return as it deals directly with a reference to the gin Engine
 func NewHandler(c *Config) {
@@ -36,59 +85,257 @@ func NewHandler(c *Config) {
 	h := &Handler{
 		UserService:  c.UserService,
 		TokenService: c.TokenService,
+		AppSettings:  c.AppSettings,
+		MaxBodyBytes: c.MaxBodyBytes,
+
+		BreachedPasswordRepository: c.BreachedPasswordRepository,
+		LockoutService:             c.LockoutService,
+		RoleService:                c.RoleService,
+		OrganizationService:        c.OrganizationService,
+		PolicyService:              c.PolicyService,
+		PersonalAccessTokenService: c.PersonalAccessTokenService,
+		ServiceAccountService:      c.ServiceAccountService,
+		AdminUserService:           c.AdminUserService,
+		DataExportService:          c.DataExportService,
+		AuditService:               c.AuditService,
+	}
+
+	if h.MaxBodyBytes == 0 {
+		h.MaxBodyBytes = defaultMaxBodyBytes
+	}
+
+	rateLimits := DefaultRateLimits
+	if c.RateLimits != nil {
+		rateLimits = *c.RateLimits
 	}
 
 	// Create an account group
 	g := c.Router.Group(os.Getenv("ACCOUNT_API_URL"))
+	g.Use(middleware.IdentifyClient())
 
-	g.GET("/me", h.Me)
-	g.POST("/signup", h.Signup)
-	g.POST("/signin", h.Signin)
-	g.POST("/signout", h.Signout)
-	g.POST("/tokens", h.Tokens)
-	g.POST("/image", h.Image)
-	g.DELETE("/image", h.DeleteImage)
-	g.PUT("/details", h.Details)
+	// with applications configured, requests are made to the application of their host or
+	// X-Application header, or to the one of the same routes scoped under /apps/:application
+	if c.ApplicationRepository != nil {
+		g.Use(middleware.ResolveApplication(c.ApplicationRepository))
+		h.routes(g.Group("/apps/:"+middleware.ApplicationParam), c.RateLimiter, rateLimits)
+	}
+
+	h.routes(g, c.RateLimiter, rateLimits)
 }
 
-// Signin handler
-func (h *Handler) Signin(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"hello": "it's signin",
-	})
+// routes registers the account routes on a group
+func (h *Handler) routes(g *gin.RouterGroup, rateLimiter model.RateLimiter, rateLimits RateLimits) {
+	// public routes are rate limited per group when a RateLimiter is configured
+	signup := g.Group("", h.rateLimiters(rateLimiter, rateLimits.Signup)...)
+	signup.POST("/signup", h.Signup)
+
+	signin := g.Group("", h.rateLimiters(rateLimiter, rateLimits.Signin)...)
+	signin.POST("/signin", h.Signin)
+	signin.POST("/password/expired", h.ExpiredPassword)
+
+	recovery := g.Group("", h.rateLimiters(rateLimiter, rateLimits.Recovery)...)
+	recovery.POST("/verify-email", h.VerifyEmail)
+	recovery.POST("/verify-email/resend", h.ResendVerificationEmail)
+	recovery.POST("/email/confirm", h.ConfirmEmailChange)
+	recovery.POST("/email/revert", h.RevertEmailChange)
+	recovery.POST("/account/restore", h.RestoreAccount)
+
+	if h.LockoutService != nil {
+		recovery.POST("/unlock", h.Unlock)
+	}
+
+	tokens := g.Group("", h.rateLimiters(rateLimiter, rateLimits.Tokens)...)
+	tokens.POST("/tokens", h.Tokens)
+	tokens.POST("/oauth/token", h.OAuthToken)
+
+	// routes requiring an authenticated user. In test mode the user is set
+	// to the context by the tests themselves
+	if gin.Mode() != gin.TestMode {
+		g.GET("/me", middleware.AuthUser(h.TokenService), h.Me)
+		g.DELETE("/me", middleware.AuthUser(h.TokenService), h.DeleteAccount)
+		g.POST("/signout", middleware.AuthUser(h.TokenService), h.Signout)
+		g.PUT("/password", middleware.AuthUser(h.TokenService), middleware.RejectImpersonation(), h.Password)
+		g.PUT("/email", middleware.AuthUser(h.TokenService), middleware.RejectImpersonation(), h.ChangeEmail)
+		g.PUT("/details", middleware.AuthUser(h.TokenService), h.Details)
+		g.POST("/image", middleware.AuthUser(h.TokenService), h.Image)
+		g.DELETE("/image", middleware.AuthUser(h.TokenService), h.DeleteImage)
+	} else {
+		g.GET("/me", h.Me)
+		g.DELETE("/me", h.DeleteAccount)
+		g.POST("/signout", h.Signout)
+		g.PUT("/password", middleware.RejectImpersonation(), h.Password)
+		g.PUT("/email", middleware.RejectImpersonation(), h.ChangeEmail)
+		g.PUT("/details", h.Details)
+		g.POST("/image", h.Image)
+		g.DELETE("/image", h.DeleteImage)
+	}
+
+	if h.OrganizationService != nil {
+		recovery.POST("/invitations/decline", h.DeclineInvitation)
+		h.organizationRoutes(g.Group(""))
+	}
+
+	if h.DataExportService != nil {
+		h.dataExportRoutes(g.Group("/me/export"))
+	}
+
+	if h.PersonalAccessTokenService != nil {
+		h.personalAccessTokenRoutes(g.Group("/personal-access-tokens"))
+	}
+
+	// policies are checked by service accounts on behalf of their users
+	if h.PolicyService != nil {
+		if gin.Mode() != gin.TestMode {
+			g.POST("/authorize-check", middleware.AuthUser(h.TokenService), h.AuthorizeCheck)
+		} else {
+			g.POST("/authorize-check", h.AuthorizeCheck)
+		}
+	}
+
+	h.adminRoutes(g.Group("/admin"))
 }
 
-// Signout handler
-func (h *Handler) Signout(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"hello": "it's signout",
-	})
+// organizationRoutes registers the routes of organizations of the authenticated user
+func (h *Handler) organizationRoutes(g *gin.RouterGroup) {
+	if gin.Mode() != gin.TestMode {
+		g.Use(middleware.AuthUser(h.TokenService))
+	}
+
+	isMember := middleware.RequireMembership(h.OrganizationService, model.OrgMember)
+	isAdmin := middleware.RequireMembership(h.OrganizationService, model.OrgAdmin)
+
+	g.POST("/orgs", h.CreateOrganization)
+	g.GET("/orgs", h.Organizations)
+	g.GET("/orgs/:"+middleware.OrgParam+"/members", isMember, h.Members)
+	g.POST("/orgs/:"+middleware.OrgParam+"/invitations", isAdmin, h.Invite)
+	g.POST("/orgs/:"+middleware.OrgParam+"/switch", isMember, h.SwitchOrganization)
+	g.POST("/invitations/accept", h.AcceptInvitation)
+
+	if h.ServiceAccountService != nil {
+		h.serviceAccountRoutes(g.Group("/orgs/:"+middleware.OrgParam+"/service-accounts"), isAdmin, isAdmin, middleware.RejectImpersonation())
+	}
 }
 
-// Tokens handler
-func (h *Handler) Tokens(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"hello": "it's tokens",
-	})
+// personalAccessTokenRoutes registers the routes managing the personal access tokens of the authenticated user
+func (h *Handler) personalAccessTokenRoutes(g *gin.RouterGroup) {
+	if gin.Mode() != gin.TestMode {
+		g.Use(middleware.AuthUser(h.TokenService))
+	}
+
+	g.GET("", h.PersonalAccessTokens)
+	g.POST("", h.CreatePersonalAccessToken)
+	g.DELETE("/:tokenId", h.RevokePersonalAccessToken)
 }
 
-// Image handler
-func (h *Handler) Image(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"hello": "it's image",
-	})
+// dataExportRoutes registers the routes exporting the data held about the authenticated user
+func (h *Handler) dataExportRoutes(g *gin.RouterGroup) {
+	if gin.Mode() != gin.TestMode {
+		g.Use(middleware.AuthUser(h.TokenService))
+	}
+
+	g.GET("", h.ExportData)
+	g.GET("/:exportId", h.DataExport)
 }
 
-// DeleteImage handler
-func (h *Handler) DeleteImage(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"hello": "it's deleteImage",
-	})
+// adminRoutes registers the routes managing the application, each requiring a permission
+func (h *Handler) adminRoutes(g *gin.RouterGroup) {
+	if gin.Mode() != gin.TestMode {
+		g.Use(middleware.AuthUser(h.TokenService))
+	}
+
+	// admins impersonating a user are acting on behalf of that user, not as admins
+	notImpersonated := middleware.RejectImpersonation()
+
+	if h.RoleService != nil {
+		canRead := middleware.RequirePermission(model.PermissionReadRoles)
+		canWrite := middleware.RequirePermission(model.PermissionWriteRoles)
+
+		g.GET("/roles", canRead, h.ListRoles)
+		g.POST("/roles", canWrite, notImpersonated, h.CreateRole)
+		g.DELETE("/roles/:roleId", canWrite, notImpersonated, h.DeleteRole)
+		g.PUT("/roles/:roleId/permissions/:permissionId", canWrite, notImpersonated, h.GrantPermission)
+		g.DELETE("/roles/:roleId/permissions/:permissionId", canWrite, notImpersonated, h.RevokePermission)
+		g.GET("/permissions", canRead, h.ListPermissions)
+		g.POST("/permissions", canWrite, notImpersonated, h.CreatePermission)
+		g.DELETE("/permissions/:permissionId", canWrite, notImpersonated, h.DeletePermission)
+		g.GET("/users/:uid/roles", canRead, h.UserRoles)
+		g.PUT("/users/:uid/roles/:roleId", canWrite, notImpersonated, h.AssignRole)
+		g.DELETE("/users/:uid/roles/:roleId", canWrite, notImpersonated, h.UnassignRole)
+	}
+
+	if h.PolicyService != nil {
+		canRead := middleware.RequirePermission(model.PermissionReadPolicies)
+		canWrite := middleware.RequirePermission(model.PermissionWritePolicies)
+
+		g.GET("/policies", canRead, h.ListPolicies)
+		g.POST("/policies", canWrite, notImpersonated, h.PublishPolicy)
+		g.POST("/policies/dry-run", canRead, h.DryRunPolicy)
+		g.DELETE("/policies/:name", canWrite, notImpersonated, h.DeletePolicy)
+		g.GET("/policies/:name/versions", canRead, h.PolicyVersions)
+		g.POST("/policies/:name/versions/:version/restore", canWrite, notImpersonated, h.RestorePolicy)
+	}
+
+	g.POST("/users/:uid/impersonate", middleware.RequirePermission(model.PermissionImpersonateUsers), h.Impersonate)
+
+	if h.AdminUserService != nil {
+		canRead := middleware.RequirePermission(model.PermissionReadUsers)
+		canWrite := middleware.RequirePermission(model.PermissionWriteUsers)
+
+		g.GET("/users", canRead, h.SearchUsers)
+		g.GET("/users/:uid", canRead, h.AdminUser)
+		g.PUT("/users/:uid", canWrite, notImpersonated, h.UpdateUser)
+		g.DELETE("/users/:uid", canWrite, notImpersonated, h.DeleteUser)
+		g.POST("/users/:uid/disable", canWrite, notImpersonated, h.DisableUser)
+		g.POST("/users/:uid/enable", canWrite, notImpersonated, h.EnableUser)
+		g.POST("/users/:uid/password-reset", canWrite, notImpersonated, h.RequirePasswordReset)
+		g.DELETE("/users/:uid/sessions", canWrite, notImpersonated, h.RevokeUserSessions)
+		g.POST("/users/:uid/unlock", canWrite, notImpersonated, h.UnlockUser)
+	}
+
+	if h.AuditService != nil {
+		canRead := middleware.RequirePermission(model.PermissionReadAudit)
+
+		g.GET("/audit-events", canRead, h.SearchAuditEvents)
+		g.GET("/audit-events/verify", canRead, h.VerifyAuditLog)
+	}
+
+	if h.ServiceAccountService != nil {
+		h.serviceAccountRoutes(g.Group("/service-accounts"),
+			middleware.RequirePermission(model.PermissionReadServiceAccounts),
+			middleware.RequirePermission(model.PermissionWriteServiceAccounts),
+			notImpersonated,
+		)
+	}
 }
 
-// Details handler
-func (h *Handler) Details(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"hello": "it's details",
+// respondError responds with err, logging msg along with it
+func respondError(c *gin.Context, msg string, err error) {
+	log.Printf("%v: %v\n", msg, err)
+	c.JSON(apperrors.Status(err), gin.H{
+		"error": err,
 	})
 }
+
+// contextUser returns the authenticated user set to the context by AuthUser,
+// responding with an internal error if there is none
+func contextUser(c *gin.Context) (*model.User, bool) {
+	user, exists := c.Get("user")
+
+	u, ok := user.(*model.User)
+	if !exists || !ok {
+		log.Printf("Unable to extract user from request context for unknown reason: %v\n", c)
+		err := apperrors.NewInternal()
+		c.JSON(err.Status(), gin.H{
+			"error": err,
+		})
+
+		return nil, false
+	}
+
+	return u, true
+}
+
+// settings returns the settings of the application the request is made to
+func (h *Handler) settings(c *gin.Context) model.AppSettings {
+	return model.ApplicationSettings(c, h.AppSettings)
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// validImageTypes are the sniffed content types accepted for profile images
+var validImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+// Image handler uploads a profile image for the authenticated user from
+// the "imageFile" field of a multipart/form-data request
+func (h *Handler) Image(c *gin.Context) {
+	user, exists := c.Get("user")
+
+	if !exists {
+		log.Printf("Unable to extract user from request context for unknown reason: %v\n", c)
+		err := apperrors.NewInternal()
+		c.JSON(err.Status(), gin.H{
+			"error": err,
+		})
+
+		return
+	}
+
+	// reject early when the client announces an oversized body
+	if c.Request.ContentLength > h.MaxBodyBytes {
+		err := apperrors.NewPayloadTooLarge(h.MaxBodyBytes, c.Request.ContentLength)
+		c.JSON(err.Status(), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	// limit the body for clients which do not announce its size
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.MaxBodyBytes)
+
+	imageFileHeader, err := c.FormFile("imageFile")
+
+	if err != nil {
+		log.Printf("Unable to parse multipart/form-data: %+v\n", err)
+
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			err := apperrors.NewPayloadTooLarge(h.MaxBodyBytes, c.Request.ContentLength)
+			c.JSON(err.Status(), gin.H{
+				"error": err,
+			})
+			return
+		}
+
+		e := apperrors.NewBadRequest("Must include an imageFile in a multipart/form-data request")
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	imageFile, err := imageFileHeader.Open()
+	if err != nil {
+		log.Printf("Unable to open image file: %v\n", err)
+		e := apperrors.NewBadRequest("Unable to read imageFile")
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+	defer imageFile.Close()
+
+	// do not trust the content type sent by the client, sniff it from the file content
+	sniff := make([]byte, 512)
+	n, err := io.ReadFull(imageFile, sniff)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		log.Printf("Unable to read image file: %v\n", err)
+		e := apperrors.NewBadRequest("Unable to read imageFile")
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	contentType := http.DetectContentType(sniff[:n])
+
+	if !validImageTypes[contentType] {
+		log.Printf("Image is not an allowable type: %v\n", contentType)
+		e := apperrors.NewUnsupportedMediaType("imageFile must be a JPEG, PNG or WebP image")
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	if _, err := imageFile.Seek(0, io.SeekStart); err != nil {
+		log.Printf("Unable to rewind image file: %v\n", err)
+		e := apperrors.NewInternal()
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	uid := user.(*model.User).UID
+
+	u, err := h.UserService.SetProfileImage(c, uid, imageFile)
+	if err != nil {
+		log.Printf("Failed to set profile image for uid: %v\n%v", uid, err)
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user": u,
+	})
+}
+
+// DeleteImage handler removes the profile image of the authenticated user
+func (h *Handler) DeleteImage(c *gin.Context) {
+	user, exists := c.Get("user")
+
+	if !exists {
+		log.Printf("Unable to extract user from request context for unknown reason: %v\n", c)
+		err := apperrors.NewInternal()
+		c.JSON(err.Status(), gin.H{
+			"error": err,
+		})
+
+		return
+	}
+
+	uid := user.(*model.User).UID
+
+	u, err := h.UserService.ClearProfileImage(c, uid)
+	if err != nil {
+		log.Printf("Failed to delete profile image for uid: %v\n%v", uid, err)
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user": u,
+	})
+}
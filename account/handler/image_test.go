@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
+)
+
+// multipartImageRequest creates a POST /image request with content as the imageFile field
+func multipartImageRequest(t *testing.T, fieldName string, content []byte) *http.Request {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	part, err := writer.CreateFormFile(fieldName, "image")
+	assert.NoError(t, err)
+
+	_, err = part.Write(content)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	request, err := http.NewRequest(http.MethodPost, "/image", body)
+	assert.NoError(t, err)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return request
+}
+
+func pngBytes(t *testing.T) []byte {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, png.Encode(buf, image.NewRGBA(image.Rect(0, 0, 4, 4))))
+
+	return buf.Bytes()
+}
+
+func TestImage(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+
+	uid, _ := uuid.NewRandom()
+
+	setupRouter := func(us model.UserService, maxBodyBytes int64) *gin.Engine {
+		router := gin.Default()
+		router.Use(func(c *gin.Context) {
+			c.Set("user", &model.User{
+				UID: uid,
+			})
+		})
+
+		NewHandler(&Config{
+			Router:       router,
+			UserService:  us,
+			MaxBodyBytes: maxBodyBytes,
+		})
+
+		return router
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockUserService := new(mocks.MockUserService)
+		updatedUser := &model.User{
+			UID:      uid,
+			ImageURL: "https://images.test/image.png",
+		}
+
+		mockUserService.
+			On("SetProfileImage", mock.AnythingOfType("*gin.Context"), uid, mock.Anything).
+			Return(updatedUser, nil)
+
+		rr := httptest.NewRecorder()
+		router := setupRouter(mockUserService, 0)
+
+		router.ServeHTTP(rr, multipartImageRequest(t, "imageFile", pngBytes(t)))
+
+		respBody, _ := json.Marshal(gin.H{
+			"user": updatedUser,
+		})
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockUserService.AssertExpectations(t)
+	})
+
+	t.Run("Unsupported image type", func(t *testing.T) {
+		mockUserService := new(mocks.MockUserService)
+
+		rr := httptest.NewRecorder()
+		router := setupRouter(mockUserService, 0)
+
+		router.ServeHTTP(rr, multipartImageRequest(t, "imageFile", []byte("GIF89a definitely not allowed")))
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+		mockUserService.AssertNotCalled(t, "SetProfileImage", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Missing imageFile", func(t *testing.T) {
+		mockUserService := new(mocks.MockUserService)
+
+		rr := httptest.NewRecorder()
+		router := setupRouter(mockUserService, 0)
+
+		router.ServeHTTP(rr, multipartImageRequest(t, "notImageFile", pngBytes(t)))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockUserService.AssertNotCalled(t, "SetProfileImage", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Payload too large", func(t *testing.T) {
+		mockUserService := new(mocks.MockUserService)
+
+		rr := httptest.NewRecorder()
+		router := setupRouter(mockUserService, 64)
+
+		router.ServeHTTP(rr, multipartImageRequest(t, "imageFile", pngBytes(t)))
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+		assert.Equal(t, apperrors.PayloadTooLarge, decodeErrorType(t, rr.Body.Bytes()))
+		mockUserService.AssertNotCalled(t, "SetProfileImage", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestDeleteImage(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+
+	uid, _ := uuid.NewRandom()
+
+	mockUserService := new(mocks.MockUserService)
+	updatedUser := &model.User{
+		UID: uid,
+	}
+	mockUserService.On("ClearProfileImage", mock.AnythingOfType("*gin.Context"), uid).Return(updatedUser, nil)
+
+	rr := httptest.NewRecorder()
+	router := gin.Default()
+	router.Use(func(c *gin.Context) {
+		c.Set("user", &model.User{
+			UID: uid,
+		})
+	})
+
+	NewHandler(&Config{
+		Router:      router,
+		UserService: mockUserService,
+	})
+
+	request, _ := http.NewRequest(http.MethodDelete, "/image", http.NoBody)
+	router.ServeHTTP(rr, request)
+
+	respBody, _ := json.Marshal(gin.H{
+		"user": updatedUser,
+	})
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, respBody, rr.Body.Bytes())
+	mockUserService.AssertExpectations(t)
+}
+
+// decodeErrorType extracts the type of the error in a json response body
+func decodeErrorType(t *testing.T, body []byte) apperrors.ErrorType {
+	t.Helper()
+
+	var resp struct {
+		Error apperrors.Error `json:"error"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &resp))
+
+	return resp.Error.Type
+}
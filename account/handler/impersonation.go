@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type impersonationReq struct {
+	Reason string `json:"reason" binding:"required,max=512"`
+}
+
+// Impersonate handler issues the admin a short-lived access token acting as a user of the application,
+// without a refresh token. The reason given is recorded in the audit log along with the impersonation
+func (h *Handler) Impersonate(c *gin.Context) {
+	actor, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	uid, ok := bindUUIDParam(c, "uid")
+	if !ok {
+		return
+	}
+
+	var req impersonationReq
+
+	if ok := BindData(c, &req); !ok {
+		return
+	}
+
+	target, err := h.UserService.Get(c, uid)
+	if err != nil {
+		respondError(c, "Failed to find user to impersonate", err)
+		return
+	}
+
+	token, err := h.TokenService.NewImpersonationToken(c, actor, target, req.Reason, c.ClientIP())
+	if err != nil {
+		respondError(c, "Failed to impersonate user", err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, token)
+}
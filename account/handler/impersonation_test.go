@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
+)
+
+func TestImpersonate(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+
+	admin := &model.User{
+		UID:         uuid.New(),
+		Email:       "admin@bob.com",
+		Permissions: model.Names{model.PermissionImpersonateUsers},
+	}
+	target := &model.User{
+		UID:   uuid.New(),
+		Email: "bob@bob.com",
+	}
+
+	// setup returns a router serving requests as the user
+	setup := func(u *model.User) (*gin.Engine, *mocks.MockUserService, *mocks.MockTokenService) {
+		router := gin.Default()
+		router.Use(func(c *gin.Context) {
+			c.Set("user", u)
+		})
+
+		mockUserService := new(mocks.MockUserService)
+		mockTokenService := new(mocks.MockTokenService)
+
+		NewHandler(&Config{
+			Router:       router,
+			UserService:  mockUserService,
+			TokenService: mockTokenService,
+		})
+
+		return router, mockUserService, mockTokenService
+	}
+
+	// post makes an impersonation request of the target user
+	post := func(router *gin.Engine, body gin.H) *httptest.ResponseRecorder {
+		reqBody, _ := json.Marshal(body)
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/admin/users/%s/impersonate", target.UID), bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		return rr
+	}
+
+	t.Run("Issues a token acting as the user", func(t *testing.T) {
+		router, mockUserService, mockTokenService := setup(admin)
+
+		token := &model.OAuthToken{AccessToken: "accessToken", TokenType: "Bearer", ExpiresIn: 900}
+
+		mockUserService.On("Get", mock.Anything, target.UID).Return(target, nil)
+		mockTokenService.On("NewImpersonationToken", mock.Anything, admin, target, "ticket 4242", mock.AnythingOfType("string")).Return(token, nil)
+
+		rr := post(router, gin.H{
+			"reason": "ticket 4242",
+		})
+
+		respBody, _ := json.Marshal(token)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+	})
+
+	t.Run("Requires a reason", func(t *testing.T) {
+		router, mockUserService, mockTokenService := setup(admin)
+
+		rr := post(router, gin.H{})
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockUserService.AssertNotCalled(t, "Get")
+		mockTokenService.AssertNotCalled(t, "NewImpersonationToken")
+	})
+
+	t.Run("Requires the impersonate permission", func(t *testing.T) {
+		router, mockUserService, mockTokenService := setup(&model.User{UID: uuid.New()})
+
+		rr := post(router, gin.H{
+			"reason": "ticket 4242",
+		})
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		mockUserService.AssertNotCalled(t, "Get")
+		mockTokenService.AssertNotCalled(t, "NewImpersonationToken")
+	})
+}
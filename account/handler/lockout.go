@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+type unlockReq struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// checkLockout responds with a locked error when signing in as email from the client ip
+// is not allowed yet. Returns false if the attempt is refused
+func (h *Handler) checkLockout(c *gin.Context, email string) bool {
+	if h.LockoutService == nil {
+		return true
+	}
+
+	err := h.LockoutService.Check(c, email, c.ClientIP())
+	if err == nil {
+		return true
+	}
+
+	log.Printf("Signin refused for email: %v, ip: %v. Reason: %v\n", email, c.ClientIP(), err)
+
+	var e *apperrors.Error
+	if errors.As(err, &e) && e.RetryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(e.RetryAfter))
+	}
+
+	c.JSON(apperrors.Status(err), gin.H{
+		"error": err,
+	})
+
+	return false
+}
+
+// recordSignin counts a failed signin when err is an authorization error,
+// or forgets previous failures when the signin succeeded
+func (h *Handler) recordSignin(c *gin.Context, email string, err error) {
+	if h.LockoutService == nil {
+		return
+	}
+
+	var e *apperrors.Error
+
+	switch {
+	case err == nil:
+		err = h.LockoutService.RecordSuccess(c, email)
+	case errors.As(err, &e) && e.Type == apperrors.Authorization:
+		err = h.LockoutService.RecordFailure(c, email, c.ClientIP())
+	default:
+		return
+	}
+
+	if err != nil {
+		log.Printf("Unable to record signin attempt for email: %v. Reason: %v\n", email, err)
+	}
+}
+
+// Unlock handler unlocks an account locked after failed signins with the token emailed to its owner
+func (h *Handler) Unlock(c *gin.Context) {
+	var req unlockReq
+
+	if ok := BindData(c, &req); !ok {
+		return
+	}
+
+	if err := h.LockoutService.UnlockWithToken(c, req.Token); err != nil {
+		log.Printf("Failed to unlock account: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "account unlocked successfully",
+	})
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"net"
+
+	"github.com/gin-gonic/gin"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// ApplicationParam is the path parameter routes scoped to an application under /apps/:application hold its slug in
+const ApplicationParam = "application"
+
+// ApplicationHeader is the request header holding the slug of the application a request is made to
+const ApplicationHeader = "X-Application"
+
+// ResolveApplication sets the application a request is made to on the context. The application is
+// identified by its slug in the path or the X-Application header, or else by the host of the request.
+// Requests not identifying any application are served as the default application, with the engine's
+// own settings and keys, as are requests to a host no application is served on. An unknown slug is
+// responded to with a 404, and a failure to look the application up with a 500 rather than serving
+// the request as the default application
+func ResolveApplication(r model.ApplicationRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		slug := c.Param(ApplicationParam)
+		if slug == "" {
+			slug = c.GetHeader(ApplicationHeader)
+		}
+
+		var app *model.Application
+
+		if slug != "" {
+			found, err := r.FindBySlug(c, slug)
+			if err != nil {
+				log.Printf("Unable to resolve application: %v. Reason: %v\n", slug, err)
+				c.JSON(apperrors.Status(err), gin.H{
+					"error": err,
+				})
+				c.Abort()
+				return
+			}
+
+			app = found
+		} else {
+			found, err := r.FindByHost(c, requestHost(c))
+			if err != nil && !isNotFound(err) {
+				log.Printf("Unable to resolve application of host: %v. Reason: %v\n", requestHost(c), err)
+				c.JSON(apperrors.Status(err), gin.H{
+					"error": err,
+				})
+				c.Abort()
+				return
+			}
+
+			app = found
+		}
+
+		// the default application is configured by the engine rather than its stored row
+		if app != nil && app.ID != model.DefaultApplicationID {
+			c.Set(model.ApplicationContextKey, app)
+			c.Request = c.Request.WithContext(model.NewApplicationContext(c.Request.Context(), app))
+		}
+
+		c.Next()
+	}
+}
+
+// isNotFound reports whether err is a NotFound error
+func isNotFound(err error) bool {
+	var e *apperrors.Error
+	return errors.As(err, &e) && e.Type == apperrors.NotFound
+}
+
+// requestHost returns the host of the request without its port
+func requestHost(c *gin.Context) string {
+	host, _, err := net.SplitHostPort(c.Request.Host)
+	if err != nil {
+		return c.Request.Host
+	}
+
+	return host
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
+)
+
+func TestResolveApplication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	acme := &model.Application{ID: uuid.New(), Slug: "acme", Host: "auth.acme.com"}
+	globex := &model.Application{ID: uuid.New(), Slug: "globex"}
+	defaultApp := &model.Application{ID: model.DefaultApplicationID, Slug: "default"}
+
+	mockApplicationRepository := new(mocks.MockApplicationRepository)
+	mockApplicationRepository.On("FindBySlug", mock.Anything, "acme").Return(acme, nil)
+	mockApplicationRepository.On("FindBySlug", mock.Anything, "globex").Return(globex, nil)
+	mockApplicationRepository.On("FindBySlug", mock.Anything, "default").Return(defaultApp, nil)
+	mockApplicationRepository.On("FindBySlug", mock.Anything, "unknown").Return(nil, apperrors.NewNotFound("application", "unknown"))
+	mockApplicationRepository.On("FindByHost", mock.Anything, "auth.acme.com").Return(acme, nil)
+	mockApplicationRepository.On("FindByHost", mock.Anything, "db.down").Return(nil, apperrors.NewInternal())
+	mockApplicationRepository.On("FindByHost", mock.Anything, mock.Anything).Return(nil, apperrors.NewNotFound("application", "host"))
+
+	// responds with the id of the application the request was resolved to, in both the
+	// gin context and the request context
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	respond := func(c *gin.Context) {
+		assert.Equal(t, model.ApplicationID(c), model.ApplicationID(c.Request.Context()))
+		c.String(http.StatusOK, model.ApplicationID(c).String())
+	}
+
+	r.Use(ResolveApplication(mockApplicationRepository))
+	r.GET("/me", respond)
+	r.GET(fmt.Sprintf("/apps/:%s/me", ApplicationParam), respond)
+
+	tests := []struct {
+		name   string
+		path   string
+		host   string
+		header string
+		status int
+		appID  uuid.UUID
+	}{
+		{name: "Path", path: "/apps/globex/me", host: "auth.acme.com", status: http.StatusOK, appID: globex.ID},
+		{name: "Header", path: "/me", header: "globex", host: "auth.acme.com", status: http.StatusOK, appID: globex.ID},
+		{name: "Host", path: "/me", host: "auth.acme.com:8080", status: http.StatusOK, appID: acme.ID},
+		{name: "Unknown host", path: "/me", host: "localhost", status: http.StatusOK, appID: model.DefaultApplicationID},
+		{name: "Default application", path: "/me", header: "default", status: http.StatusOK, appID: model.DefaultApplicationID},
+		{name: "Unknown slug", path: "/apps/unknown/me", status: http.StatusNotFound},
+		{name: "Host lookup failure", path: "/me", host: "db.down", status: http.StatusInternalServerError},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+
+			request, _ := http.NewRequest(http.MethodGet, tc.path, nil)
+			request.Host = tc.host
+			if tc.header != "" {
+				request.Header.Set(ApplicationHeader, tc.header)
+			}
+
+			r.ServeHTTP(rr, request)
+
+			assert.Equal(t, tc.status, rr.Code)
+			if tc.status == http.StatusOK {
+				assert.Equal(t, tc.appID.String(), rr.Body.String())
+			}
+		})
+	}
+}
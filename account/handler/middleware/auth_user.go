@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+type authHeader struct {
+	IDToken string `header:"Authorization"`
+}
+
+// AuthUser extracts a user from the Authorization header
+// which is of the form "Bearer token", the token being an id token or a personal access token.
+// It sets the user to the context if the user exists, is still allowed to sign in and belongs to the application
+// the request is made to, along with the admin acting as them as "actor" when the token was issued by impersonation
+func AuthUser(s model.TokenService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		h := authHeader{}
+
+		// bind Authorization Header to h
+		if err := c.ShouldBindHeader(&h); err != nil {
+			err := apperrors.NewBadRequest("Unable to read Authorization header")
+			c.JSON(err.Status(), gin.H{
+				"error": err,
+			})
+			c.Abort()
+			return
+		}
+
+		idTokenHeader := strings.Split(h.IDToken, "Bearer ")
+
+		if len(idTokenHeader) < 2 {
+			err := apperrors.NewAuthorization("Must provide Authorization header with format `Bearer {token}`")
+			c.JSON(err.Status(), gin.H{
+				"error": err,
+			})
+			c.Abort()
+			return
+		}
+
+		token := idTokenHeader[1]
+
+		var user *model.User
+		var err error
+
+		if strings.HasPrefix(token, model.PersonalAccessTokenPrefix) {
+			user, err = s.ValidatePersonalAccessToken(c, token, c.ClientIP())
+		} else {
+			user, err = s.ValidateIDToken(token)
+		}
+
+		if err != nil {
+			err := apperrors.NewAuthorization("Provided token is invalid")
+			c.JSON(err.Status(), gin.H{
+				"error": err,
+			})
+			c.Abort()
+			return
+		}
+
+		// tokens are only valid for the application they were issued by
+		if user.TenantID != model.ApplicationID(c) {
+			err := apperrors.NewAuthorization("Provided token is invalid")
+			c.JSON(err.Status(), gin.H{
+				"error": err,
+			})
+			c.Abort()
+			return
+		}
+
+		// id tokens outlive changes to the status of their user, which is checked again
+		if user.PersonalAccessTokenID == uuid.Nil {
+			if err := s.ValidateUserStatus(c, user); err != nil {
+				c.JSON(apperrors.Status(err), gin.H{
+					"error": err,
+				})
+				c.Abort()
+				return
+			}
+		}
+
+		c.Set("user", user)
+
+		if user.Actor != nil {
+			c.Set("actor", user.Actor)
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// IdentifyClient sets the ip address and user agent a request is made from on the context,
+// for services to record along with the audit events of the request
+func IdentifyClient() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(model.ClientContextKey, &model.Client{
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		})
+
+		c.Next()
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+func TestIdentifyClient(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	rr := httptest.NewRecorder()
+
+	_, r := gin.CreateTestContext(rr)
+
+	var client *model.Client
+
+	r.GET("/me", IdentifyClient(), func(c *gin.Context) {
+		client = model.ClientFromContext(c)
+	})
+
+	request, _ := http.NewRequest(http.MethodGet, "/me", http.NoBody)
+	request.RemoteAddr = "10.0.0.1:4321"
+	request.Header.Set("User-Agent", "curl/8.5.0")
+	r.ServeHTTP(rr, request)
+
+	assert.Equal(t, &model.Client{IP: "10.0.0.1", UserAgent: "curl/8.5.0"}, client)
+}
@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// maxKeyBodyBytes bounds how much of a request body is read to extract a rate limit key
+const maxKeyBodyBytes = 64 * 1024
+
+// KeyFunc extracts the key requests are counted by. An empty key skips the limit
+type KeyFunc func(c *gin.Context) string
+
+// RateLimit allows Limit requests per Window for each key returned by Key.
+// Name keeps the counters of different limits apart
+type RateLimit struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+	Key    KeyFunc
+}
+
+// ByIP counts requests per client ip. The ip is only taken from the X-Forwarded-For and X-Real-IP
+// headers of requests coming from the trusted proxies of the router, see gin.Engine.SetTrustedProxies,
+// as any client could otherwise spread its requests over made up ips
+func ByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// ByEmail counts requests per "email" field of a JSON body, leaving the body intact for the handler
+func ByEmail(c *gin.Context) string {
+	body, ok := peekBody(c)
+	if !ok {
+		return ""
+	}
+
+	var req struct {
+		Email string `json:"email"`
+	}
+
+	if err := json.Unmarshal(body, &req); err != nil || req.Email == "" {
+		return ""
+	}
+
+	return "email:" + strings.ToLower(strings.TrimSpace(req.Email))
+}
+
+// ByClient counts requests per client. Service accounts are identified by the issuer of the assertion
+// or client_assertion of a form body, which must be the id of the account, and other clients by the
+// application the request is made to, which RateLimiter keeps counters per. Unlike the X-Client-ID header,
+// neither can be made up to spread requests over as many counters as wanted
+func ByClient(c *gin.Context) string {
+	if c.ContentType() == "application/x-www-form-urlencoded" {
+		if iss := assertionIssuer(c); iss != "" {
+			return "service_account:" + iss
+		}
+	}
+
+	return "application"
+}
+
+// assertionIssuer returns the iss claim of the assertion of a form body, as long as it is a uuid.
+// The assertion is not verified, which is left to the handler along with the body
+func assertionIssuer(c *gin.Context) string {
+	body, ok := peekBody(c)
+	if !ok {
+		return ""
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return ""
+	}
+
+	assertion := form.Get("assertion")
+	if assertion == "" {
+		assertion = form.Get("client_assertion")
+	}
+
+	claims := &jwt.RegisteredClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(assertion, claims); err != nil {
+		return ""
+	}
+
+	id, err := uuid.Parse(claims.Issuer)
+	if err != nil {
+		return ""
+	}
+
+	return id.String()
+}
+
+// peekBody reads up to maxKeyBodyBytes of the body of a request, leaving the body intact for the handler
+func peekBody(c *gin.Context) ([]byte, bool) {
+	if c.Request.Body == nil {
+		return nil, false
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxKeyBodyBytes))
+	c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+
+	return body, err == nil
+}
+
+// RateLimiter rejects requests exceeding the rate limit with a 429. Counters are kept per application,
+// so that the traffic of an application does not throttle the users of another. Every response holds the
+// RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers, and rejected ones Retry-After.
+// Requests are let through when the limiter is unavailable
+func RateLimiter(l model.RateLimiter, rl RateLimit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := rl.Key(c)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		res, err := l.Allow(c, rl.Name+":"+model.ApplicationID(c).String()+":"+key, rl.Limit, rl.Window)
+		if err != nil {
+			log.Printf("Unable to apply rate limit %v: %v\n", rl.Name, err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(res.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+
+		if !res.Allowed {
+			err := apperrors.NewTooManyRequests(res.RetryAfter)
+
+			c.Header("Retry-After", strconv.Itoa(err.RetryAfter))
+			c.JSON(err.Status(), gin.H{
+				"error": err,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
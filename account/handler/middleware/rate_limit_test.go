@@ -0,0 +1,204 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"github.com/weslleyrsr/auth-engine/account/model/repository"
+)
+
+// failingRateLimiter stands in for an unavailable rate limit store
+type failingRateLimiter struct{}
+
+func (failingRateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (*model.RateLimitResult, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestRateLimiter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Rejects requests over the limit", func(t *testing.T) {
+		_, r := gin.CreateTestContext(httptest.NewRecorder())
+
+		r.POST("/signin", RateLimiter(repository.NewMemoryRateLimiter(), RateLimit{
+			Name:   "signin",
+			Limit:  2,
+			Window: time.Minute,
+			Key:    ByIP,
+		}), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		for i := 0; i < 2; i++ {
+			rr := httptest.NewRecorder()
+			request, _ := http.NewRequest(http.MethodPost, "/signin", nil)
+			r.ServeHTTP(rr, request)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, "2", rr.Header().Get("RateLimit-Limit"))
+			assert.NotEmpty(t, rr.Header().Get("RateLimit-Reset"))
+		}
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, "/signin", nil)
+		r.ServeHTTP(rr, request)
+
+		var body struct {
+			Error *apperrors.Error `json:"error"`
+		}
+		_ = json.Unmarshal(rr.Body.Bytes(), &body)
+
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+		assert.Equal(t, "0", rr.Header().Get("RateLimit-Remaining"))
+		assert.NotEmpty(t, rr.Header().Get("Retry-After"))
+		assert.Equal(t, apperrors.TooManyRequests, body.Error.Type)
+	})
+
+	t.Run("Counts by email and keeps the body intact", func(t *testing.T) {
+		_, r := gin.CreateTestContext(httptest.NewRecorder())
+
+		var handlerBody []byte
+
+		r.POST("/signin", RateLimiter(repository.NewMemoryRateLimiter(), RateLimit{
+			Name:   "signin",
+			Limit:  1,
+			Window: time.Minute,
+			Key:    ByEmail,
+		}), func(c *gin.Context) {
+			handlerBody, _ = io.ReadAll(c.Request.Body)
+			c.Status(http.StatusOK)
+		})
+
+		reqBody := []byte(`{"email":"Bob@bob.com","password":"tangerine-kayak-79"}`)
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, "/signin", bytes.NewBuffer(reqBody))
+		r.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, reqBody, handlerBody)
+
+		// same email with different casing shares the counter
+		rr = httptest.NewRecorder()
+		request, _ = http.NewRequest(http.MethodPost, "/signin", bytes.NewBufferString(`{"email":"bob@bob.com"}`))
+		r.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+
+		rr = httptest.NewRecorder()
+		request, _ = http.NewRequest(http.MethodPost, "/signin", bytes.NewBufferString(`{"email":"alice@bob.com"}`))
+		r.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("Counts by client and keeps the form intact", func(t *testing.T) {
+		_, r := gin.CreateTestContext(httptest.NewRecorder())
+
+		var grantType string
+
+		r.POST("/oauth/token", RateLimiter(repository.NewMemoryRateLimiter(), RateLimit{
+			Name:   "tokens",
+			Limit:  1,
+			Window: time.Minute,
+			Key:    ByClient,
+		}), func(c *gin.Context) {
+			grantType = c.PostForm("grant_type")
+			c.Status(http.StatusOK)
+		})
+
+		// assertion returns an assertion issued by the service account, the signature being left to the handler
+		assertion := func(iss string) string {
+			ss, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{Issuer: iss}).SignedString([]byte("key"))
+			return ss
+		}
+
+		post := func(form url.Values) int {
+			rr := httptest.NewRecorder()
+			request, _ := http.NewRequest(http.MethodPost, "/oauth/token", bytes.NewBufferString(form.Encode()))
+			request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			r.ServeHTTP(rr, request)
+			return rr.Code
+		}
+
+		first, second := uuid.NewString(), uuid.NewString()
+
+		assert.Equal(t, http.StatusOK, post(url.Values{"grant_type": {"jwt-bearer"}, "assertion": {assertion(first)}}))
+		assert.Equal(t, "jwt-bearer", grantType)
+		assert.Equal(t, http.StatusTooManyRequests, post(url.Values{"assertion": {assertion(first)}}))
+		assert.Equal(t, http.StatusOK, post(url.Values{"client_assertion": {assertion(second)}}))
+
+		// requests without an assertion are counted per application
+		assert.Equal(t, http.StatusOK, post(url.Values{"grant_type": {"refresh_token"}}))
+		assert.Equal(t, http.StatusTooManyRequests, post(url.Values{"assertion": {assertion("made up")}}))
+	})
+
+	t.Run("Counts per application", func(t *testing.T) {
+		_, r := gin.CreateTestContext(httptest.NewRecorder())
+
+		acme := &model.Application{ID: uuid.New(), Slug: "acme"}
+
+		r.POST("/signin", func(c *gin.Context) {
+			if c.GetHeader("X-Application") == acme.Slug {
+				c.Set(model.ApplicationContextKey, acme)
+			}
+		}, RateLimiter(repository.NewMemoryRateLimiter(), RateLimit{
+			Name:   "signin",
+			Limit:  1,
+			Window: time.Minute,
+			Key:    ByEmail,
+		}), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		post := func(app string) int {
+			rr := httptest.NewRecorder()
+			request, _ := http.NewRequest(http.MethodPost, "/signin", bytes.NewBufferString(`{"email":"bob@bob.com"}`))
+			request.Header.Set("X-Application", app)
+			r.ServeHTTP(rr, request)
+			return rr.Code
+		}
+
+		assert.Equal(t, http.StatusOK, post("default"))
+		assert.Equal(t, http.StatusTooManyRequests, post("default"))
+		// the same email is counted apart in another application
+		assert.Equal(t, http.StatusOK, post(acme.Slug))
+		assert.Equal(t, http.StatusTooManyRequests, post(acme.Slug))
+	})
+
+	t.Run("Lets requests through when the limiter fails", func(t *testing.T) {
+		_, r := gin.CreateTestContext(httptest.NewRecorder())
+
+		r.POST("/signin", RateLimiter(failingRateLimiter{}, RateLimit{
+			Name:   "signin",
+			Limit:  1,
+			Window: time.Minute,
+			Key:    ByIP,
+		}), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		for i := 0; i < 2; i++ {
+			rr := httptest.NewRecorder()
+			request, _ := http.NewRequest(http.MethodPost, "/signin", nil)
+			r.ServeHTTP(rr, request)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Empty(t, rr.Header().Get("RateLimit-Limit"))
+		}
+	})
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// RejectImpersonation rejects requests authenticated with a token issued by impersonation with a 403,
+// guarding operations only users themselves may perform. It expects the user to have been set to the context by AuthUser
+func RejectImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, _ := c.Get("user")
+
+		if u, ok := user.(*model.User); ok && u.Actor != nil {
+			err := apperrors.NewForbidden("Not allowed while impersonating a user")
+			c.JSON(err.Status(), gin.H{
+				"error": err,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
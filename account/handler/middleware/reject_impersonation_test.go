@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+func TestRejectImpersonation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		user   *model.User
+		status int
+	}{
+		{
+			name:   "Signed in as themselves",
+			user:   &model.User{UID: uuid.New()},
+			status: http.StatusOK,
+		},
+		{
+			name:   "Impersonated",
+			user:   &model.User{UID: uuid.New(), Actor: &model.Actor{Subject: uuid.New()}},
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+
+			_, r := gin.CreateTestContext(rr)
+
+			r.PUT("/password", func(c *gin.Context) {
+				c.Set("user", tc.user)
+			}, RejectImpersonation(), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			request, _ := http.NewRequest(http.MethodPut, "/password", http.NoBody)
+			r.ServeHTTP(rr, request)
+
+			assert.Equal(t, tc.status, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// OrgParam is the path parameter routes of an organization hold its id in
+const OrgParam = "orgId"
+
+// RequireMembership rejects requests of users who are not members of the organization, with a
+// role at least as privileged as min, with a 403. The organization is the one of the path, or else
+// the one the id token was issued for. Membership is checked on every request, so removed members
+// lose access before their org-scoped tokens expire. It expects the user to have been set to the
+// context by AuthUser, and sets the membership to the context
+func RequireMembership(s model.OrganizationService, min model.MembershipRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, exists := c.Get("user")
+
+		u, ok := user.(*model.User)
+		if !exists || !ok {
+			err := apperrors.NewAuthorization("Must be signed in")
+			c.JSON(err.Status(), gin.H{
+				"error": err,
+			})
+			c.Abort()
+			return
+		}
+
+		orgID := u.OrgID
+
+		if param := c.Param(OrgParam); param != "" {
+			id, err := uuid.Parse(param)
+			if err != nil {
+				err := apperrors.NewBadRequest("Invalid orgId")
+				c.JSON(err.Status(), gin.H{
+					"error": err,
+				})
+				c.Abort()
+				return
+			}
+
+			orgID = id
+		}
+
+		if orgID == uuid.Nil {
+			err := apperrors.NewForbidden("Token was not issued for an organization")
+			c.JSON(err.Status(), gin.H{
+				"error": err,
+			})
+			c.Abort()
+			return
+		}
+
+		m, err := s.GetMembership(c, orgID, u.UID)
+		if err != nil {
+			log.Printf("Membership of uid: %v to organization: %v refused. Reason: %v\n", u.UID, orgID, err)
+			err := apperrors.NewForbidden("Not a member of the organization")
+			c.JSON(err.Status(), gin.H{
+				"error": err,
+			})
+			c.Abort()
+			return
+		}
+
+		if !m.Role.AtLeast(min) {
+			err := apperrors.NewForbidden("Membership role is not allowed to perform this action")
+			c.JSON(err.Status(), gin.H{
+				"error": err,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set("membership", m)
+
+		c.Next()
+	}
+}
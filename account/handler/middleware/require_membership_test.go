@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
+)
+
+func TestRequireMembership(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	uid := uuid.New()
+	adminOf := uuid.New()
+	memberOf := uuid.New()
+	strangerTo := uuid.New()
+
+	mockOrganizationService := new(mocks.MockOrganizationService)
+	mockOrganizationService.On("GetMembership", mock.Anything, adminOf, uid).
+		Return(&model.Membership{OrgID: adminOf, UID: uid, Role: model.OrgAdmin}, nil)
+	mockOrganizationService.On("GetMembership", mock.Anything, memberOf, uid).
+		Return(&model.Membership{OrgID: memberOf, UID: uid, Role: model.OrgMember}, nil)
+	mockOrganizationService.On("GetMembership", mock.Anything, strangerTo, uid).
+		Return(nil, apperrors.NewNotFound("membership", strangerTo.String()))
+
+	tests := []struct {
+		name      string
+		tokenOrg  uuid.UUID
+		path      string
+		status    int
+		memberOrg uuid.UUID
+	}{
+		{name: "Admin of the path organization", path: fmt.Sprintf("/orgs/%s/invitations", adminOf), status: http.StatusOK, memberOrg: adminOf},
+		{name: "Role below the required one", path: fmt.Sprintf("/orgs/%s/invitations", memberOf), status: http.StatusForbidden},
+		{name: "Not a member", path: fmt.Sprintf("/orgs/%s/invitations", strangerTo), status: http.StatusForbidden},
+		{name: "Invalid organization id", path: "/orgs/notauuid/invitations", status: http.StatusBadRequest},
+		{name: "Organization of the token", tokenOrg: adminOf, path: "/invitations", status: http.StatusOK, memberOrg: adminOf},
+		{name: "Token of no organization", path: "/invitations", status: http.StatusForbidden},
+		{name: "Membership revoked since the token was issued", tokenOrg: strangerTo, path: "/invitations", status: http.StatusForbidden},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+
+			_, r := gin.CreateTestContext(rr)
+
+			setUser := func(c *gin.Context) {
+				c.Set("user", &model.User{UID: uid, OrgID: tc.tokenOrg})
+			}
+			respond := func(c *gin.Context) {
+				m := c.MustGet("membership").(*model.Membership)
+				assert.Equal(t, tc.memberOrg, m.OrgID)
+				c.Status(http.StatusOK)
+			}
+
+			r.POST(fmt.Sprintf("/orgs/:%s/invitations", OrgParam), setUser, RequireMembership(mockOrganizationService, model.OrgAdmin), respond)
+			r.POST("/invitations", setUser, RequireMembership(mockOrganizationService, model.OrgAdmin), respond)
+
+			request, _ := http.NewRequest(http.MethodPost, tc.path, http.NoBody)
+			r.ServeHTTP(rr, request)
+
+			assert.Equal(t, tc.status, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// RequirePermission rejects requests of users not granted the permission within the application
+// with a 403. It expects the user to have been set to the context by AuthUser
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, exists := c.Get("user")
+
+		u, ok := user.(*model.User)
+		if !exists || !ok {
+			err := apperrors.NewAuthorization("Must be signed in")
+			c.JSON(err.Status(), gin.H{
+				"error": err,
+			})
+			c.Abort()
+			return
+		}
+
+		if !u.Permissions.Contains(permission) {
+			err := apperrors.NewForbidden(fmt.Sprintf("Missing permission: %v", permission))
+			c.JSON(err.Status(), gin.H{
+				"error": err,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+func TestRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		user   *model.User
+		status int
+	}{
+		{
+			name:   "Granted",
+			user:   &model.User{UID: uuid.New(), Permissions: model.Names{"account.roles.read", "reports.read"}},
+			status: http.StatusOK,
+		},
+		{
+			name:   "Missing permission",
+			user:   &model.User{UID: uuid.New(), Permissions: model.Names{"account.roles.write"}},
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "No user",
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+
+			_, r := gin.CreateTestContext(rr)
+
+			r.GET("/admin/roles", func(c *gin.Context) {
+				if tc.user != nil {
+					c.Set("user", tc.user)
+				}
+			}, RequirePermission("account.roles.read"), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			request, _ := http.NewRequest(http.MethodGet, "/admin/roles", http.NoBody)
+			r.ServeHTTP(rr, request)
+
+			assert.Equal(t, tc.status, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// grantTypeJWTBearer is the grant type of RFC 7523 service accounts authenticate with
+const grantTypeJWTBearer = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+
+// grantTypeTokenExchange is the grant type of RFC 8693 service accounts exchange the tokens of users with
+const grantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+
+// clientAssertionTypeJWTBearer is the type of the JWT assertions of RFC 7523 clients authenticate with
+const clientAssertionTypeJWTBearer = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+type oauthTokenReq struct {
+	GrantType string `form:"grant_type"`
+	Assertion string `form:"assertion"`
+	Scope     string `form:"scope"` // space delimited
+
+	// client authentication and parameters of the token exchange grant
+	ClientAssertion     string `form:"client_assertion"`
+	ClientAssertionType string `form:"client_assertion_type"`
+	SubjectToken        string `form:"subject_token"`
+	SubjectTokenType    string `form:"subject_token_type"`
+	RequestedTokenType  string `form:"requested_token_type"`
+	Audience            string `form:"audience"`
+}
+
+// OAuthToken handler is the OAuth 2.0 token endpoint, taking form encoded requests and
+// answering with the token and error responses of RFC 6749
+func (h *Handler) OAuthToken(c *gin.Context) {
+	var req oauthTokenReq
+
+	if c.ContentType() != "application/x-www-form-urlencoded" || c.ShouldBind(&req) != nil {
+		respondOAuthError(c, "invalid_request", "Expected a form encoded token request")
+		return
+	}
+
+	switch {
+	case req.GrantType == grantTypeJWTBearer && h.ServiceAccountService != nil:
+		h.jwtBearerGrant(c, &req)
+	case req.GrantType == grantTypeTokenExchange && h.ServiceAccountService != nil:
+		h.tokenExchangeGrant(c, &req)
+	default:
+		respondOAuthError(c, "unsupported_grant_type", "Unsupported grant_type: "+req.GrantType)
+	}
+}
+
+// jwtBearerGrant issues an access token to the service account a JWT assertion is signed by
+func (h *Handler) jwtBearerGrant(c *gin.Context, req *oauthTokenReq) {
+	if req.Assertion == "" {
+		respondOAuthError(c, "invalid_request", "Missing assertion")
+		return
+	}
+
+	sa, err := h.ServiceAccountService.Authenticate(c, req.Assertion, c.ClientIP())
+	if err != nil {
+		respondGrantError(c, "Failed to authenticate service account", err)
+		return
+	}
+
+	token, err := h.TokenService.NewServiceAccountToken(c, sa, strings.Fields(req.Scope))
+	if err != nil {
+		respondGrantError(c, "Failed to create token for service account", err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, token)
+}
+
+// tokenExchangeGrant exchanges the access token of a user for a token of another audience on behalf of
+// the service account authenticating as the client with a JWT assertion, within its token exchange policy
+func (h *Handler) tokenExchangeGrant(c *gin.Context, req *oauthTokenReq) {
+	if req.ClientAssertionType != clientAssertionTypeJWTBearer || req.ClientAssertion == "" {
+		respondOAuthError(c, "invalid_client", "Expected a client_assertion of the service account")
+		return
+	}
+
+	if req.SubjectToken == "" || (req.SubjectTokenType != model.TokenTypeAccessToken && req.SubjectTokenType != model.TokenTypeJWT) {
+		respondOAuthError(c, "invalid_request", "Expected a subject_token of type "+model.TokenTypeAccessToken)
+		return
+	}
+
+	if req.RequestedTokenType != "" && req.RequestedTokenType != model.TokenTypeAccessToken {
+		respondOAuthError(c, "invalid_request", "Unsupported requested_token_type: "+req.RequestedTokenType)
+		return
+	}
+
+	if req.Audience == "" {
+		respondOAuthError(c, "invalid_target", "Missing audience")
+		return
+	}
+
+	sa, err := h.ServiceAccountService.Authenticate(c, req.ClientAssertion, c.ClientIP())
+	if err != nil {
+		log.Printf("Failed to authenticate service account exchanging a token: %v\n", err)
+		respondOAuthError(c, "invalid_client", "Unable to verify client_assertion")
+		return
+	}
+
+	policy, err := h.ServiceAccountService.ExchangePolicy(c, uuid.Nil, sa.ID)
+	if err != nil {
+		var e *apperrors.Error
+		if errors.As(err, &e) && e.Type == apperrors.NotFound {
+			respondOAuthError(c, "unauthorized_client", "The service account is not allowed to exchange tokens")
+			return
+		}
+
+		respondError(c, "Failed to get token exchange policy of service account", err)
+		return
+	}
+
+	if !policy.Audiences.Contains(req.Audience) {
+		respondOAuthError(c, "invalid_target", "Audience not allowed for the service account: "+req.Audience)
+		return
+	}
+
+	token, err := h.TokenService.ExchangeToken(c, sa, policy, &model.TokenExchange{
+		SubjectToken: req.SubjectToken,
+		Audience:     req.Audience,
+		Scopes:       strings.Fields(req.Scope),
+	})
+	if err != nil {
+		respondGrantError(c, "Failed to exchange token", err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, token)
+}
+
+// respondGrantError responds to a rejected grant with the error of RFC 6749 matching err,
+// or with err itself when the grant failed for another reason than the request
+func respondGrantError(c *gin.Context, msg string, err error) {
+	var e *apperrors.Error
+	if !errors.As(err, &e) {
+		respondError(c, msg, err)
+		return
+	}
+
+	switch e.Type {
+	case apperrors.Authorization:
+		log.Printf("%v: %v\n", msg, err)
+		respondOAuthError(c, "invalid_grant", e.Message)
+	case apperrors.Forbidden:
+		log.Printf("%v: %v\n", msg, err)
+		respondOAuthError(c, "invalid_scope", e.Message)
+	case apperrors.BadRequest:
+		log.Printf("%v: %v\n", msg, err)
+		respondOAuthError(c, "invalid_request", e.Message)
+	default:
+		respondError(c, msg, err)
+	}
+}
+
+// respondOAuthError responds with an error response of RFC 6749 section 5.2
+func respondOAuthError(c *gin.Context, code string, description string) {
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error":             code,
+		"error_description": description,
+	})
+}
@@ -0,0 +1,238 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
+)
+
+func TestOAuthToken(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+
+	setup := func() (*gin.Engine, *mocks.MockServiceAccountService, *mocks.MockTokenService) {
+		router := gin.Default()
+
+		mockServiceAccountService := new(mocks.MockServiceAccountService)
+		mockTokenService := new(mocks.MockTokenService)
+
+		NewHandler(&Config{
+			Router:                router,
+			TokenService:          mockTokenService,
+			ServiceAccountService: mockServiceAccountService,
+		})
+
+		return router, mockServiceAccountService, mockTokenService
+	}
+
+	// post makes a form encoded token request
+	post := func(router *gin.Engine, form url.Values) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(form.Encode()))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		router.ServeHTTP(rr, request)
+
+		return rr
+	}
+
+	// oauthError returns the error code of an error response
+	oauthError := func(rr *httptest.ResponseRecorder) string {
+		var respBody struct {
+			Error string `json:"error"`
+		}
+		_ = json.Unmarshal(rr.Body.Bytes(), &respBody)
+
+		return respBody.Error
+	}
+
+	t.Run("JWT bearer grant", func(t *testing.T) {
+		router, mockServiceAccountService, mockTokenService := setup()
+
+		sa := &model.ServiceAccount{ID: uuid.New(), Scopes: model.Names{"articles.read", "articles.write"}}
+		token := &model.OAuthToken{AccessToken: "accessToken", TokenType: "Bearer", ExpiresIn: 900, Scope: "articles.read"}
+
+		mockServiceAccountService.On("Authenticate", mock.Anything, "assertion", mock.AnythingOfType("string")).Return(sa, nil)
+		mockTokenService.On("NewServiceAccountToken", mock.Anything, sa, model.Names{"articles.read"}).Return(token, nil)
+
+		rr := post(router, url.Values{
+			"grant_type": {grantTypeJWTBearer},
+			"assertion":  {"assertion"},
+			"scope":      {"articles.read"},
+		})
+
+		respBody, _ := json.Marshal(token)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+	})
+
+	t.Run("Rejected assertion", func(t *testing.T) {
+		router, mockServiceAccountService, mockTokenService := setup()
+
+		mockServiceAccountService.On("Authenticate", mock.Anything, "assertion", mock.AnythingOfType("string")).
+			Return(nil, apperrors.NewAuthorization("Unable to verify assertion"))
+
+		rr := post(router, url.Values{
+			"grant_type": {grantTypeJWTBearer},
+			"assertion":  {"assertion"},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, "invalid_grant", oauthError(rr))
+		mockTokenService.AssertNotCalled(t, "NewServiceAccountToken")
+	})
+
+	t.Run("Scope not granted", func(t *testing.T) {
+		router, mockServiceAccountService, mockTokenService := setup()
+
+		sa := &model.ServiceAccount{ID: uuid.New()}
+
+		mockServiceAccountService.On("Authenticate", mock.Anything, "assertion", mock.AnythingOfType("string")).Return(sa, nil)
+		mockTokenService.On("NewServiceAccountToken", mock.Anything, sa, model.Names{"account.roles.write"}).
+			Return(nil, apperrors.NewForbidden("Scope not granted to the service account: account.roles.write"))
+
+		rr := post(router, url.Values{
+			"grant_type": {grantTypeJWTBearer},
+			"assertion":  {"assertion"},
+			"scope":      {"account.roles.write"},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, "invalid_scope", oauthError(rr))
+	})
+
+	t.Run("Token exchange grant", func(t *testing.T) {
+		router, mockServiceAccountService, mockTokenService := setup()
+
+		sa := &model.ServiceAccount{ID: uuid.New()}
+		policy := &model.TokenExchangePolicy{ServiceAccountID: sa.ID, Audiences: model.Names{"billing-api"}}
+		token := &model.OAuthToken{AccessToken: "accessToken", TokenType: "Bearer", ExpiresIn: 600, IssuedTokenType: model.TokenTypeAccessToken}
+
+		mockServiceAccountService.On("Authenticate", mock.Anything, "clientAssertion", mock.AnythingOfType("string")).Return(sa, nil)
+		mockServiceAccountService.On("ExchangePolicy", mock.Anything, uuid.Nil, sa.ID).Return(policy, nil)
+		mockTokenService.On("ExchangeToken", mock.Anything, sa, policy, &model.TokenExchange{
+			SubjectToken: "subjectToken",
+			Audience:     "billing-api",
+			Scopes:       model.Names{"billing.read"},
+		}).Return(token, nil)
+
+		rr := post(router, url.Values{
+			"grant_type":            {grantTypeTokenExchange},
+			"client_assertion_type": {clientAssertionTypeJWTBearer},
+			"client_assertion":      {"clientAssertion"},
+			"subject_token":         {"subjectToken"},
+			"subject_token_type":    {model.TokenTypeAccessToken},
+			"audience":              {"billing-api"},
+			"scope":                 {"billing.read"},
+		})
+
+		respBody, _ := json.Marshal(token)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+	})
+
+	t.Run("Token exchange of a service account without policy", func(t *testing.T) {
+		router, mockServiceAccountService, mockTokenService := setup()
+
+		sa := &model.ServiceAccount{ID: uuid.New()}
+
+		mockServiceAccountService.On("Authenticate", mock.Anything, "clientAssertion", mock.AnythingOfType("string")).Return(sa, nil)
+		mockServiceAccountService.On("ExchangePolicy", mock.Anything, uuid.Nil, sa.ID).
+			Return(nil, apperrors.NewNotFound("token exchange policy", sa.ID.String()))
+
+		rr := post(router, url.Values{
+			"grant_type":            {grantTypeTokenExchange},
+			"client_assertion_type": {clientAssertionTypeJWTBearer},
+			"client_assertion":      {"clientAssertion"},
+			"subject_token":         {"subjectToken"},
+			"subject_token_type":    {model.TokenTypeAccessToken},
+			"audience":              {"billing-api"},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, "unauthorized_client", oauthError(rr))
+		mockTokenService.AssertNotCalled(t, "ExchangeToken")
+	})
+
+	t.Run("Token exchange for an audience not allowed", func(t *testing.T) {
+		router, mockServiceAccountService, mockTokenService := setup()
+
+		sa := &model.ServiceAccount{ID: uuid.New()}
+		policy := &model.TokenExchangePolicy{ServiceAccountID: sa.ID, Audiences: model.Names{"billing-api"}}
+
+		mockServiceAccountService.On("Authenticate", mock.Anything, "clientAssertion", mock.AnythingOfType("string")).Return(sa, nil)
+		mockServiceAccountService.On("ExchangePolicy", mock.Anything, uuid.Nil, sa.ID).Return(policy, nil)
+
+		rr := post(router, url.Values{
+			"grant_type":            {grantTypeTokenExchange},
+			"client_assertion_type": {clientAssertionTypeJWTBearer},
+			"client_assertion":      {"clientAssertion"},
+			"subject_token":         {"subjectToken"},
+			"subject_token_type":    {model.TokenTypeAccessToken},
+			"audience":              {"ledger-api"},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, "invalid_target", oauthError(rr))
+		mockTokenService.AssertNotCalled(t, "ExchangeToken")
+	})
+
+	t.Run("Token exchange without client authentication", func(t *testing.T) {
+		router, mockServiceAccountService, _ := setup()
+
+		rr := post(router, url.Values{
+			"grant_type":         {grantTypeTokenExchange},
+			"subject_token":      {"subjectToken"},
+			"subject_token_type": {model.TokenTypeAccessToken},
+			"audience":           {"billing-api"},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, "invalid_client", oauthError(rr))
+		mockServiceAccountService.AssertNotCalled(t, "Authenticate")
+	})
+
+	t.Run("Unsupported grant type", func(t *testing.T) {
+		router, mockServiceAccountService, _ := setup()
+
+		rr := post(router, url.Values{
+			"grant_type": {"password"},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, "unsupported_grant_type", oauthError(rr))
+		mockServiceAccountService.AssertNotCalled(t, "Authenticate")
+	})
+
+	t.Run("Json request", func(t *testing.T) {
+		router, mockServiceAccountService, _ := setup()
+
+		reqBody, _ := json.Marshal(gin.H{
+			"grant_type": grantTypeJWTBearer,
+			"assertion":  "assertion",
+		})
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, "/oauth/token", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, "invalid_request", oauthError(rr))
+		mockServiceAccountService.AssertNotCalled(t, "Authenticate")
+	})
+}
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/weslleyrsr/auth-engine/account/handler/middleware"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+type organizationReq struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+type inviteReq struct {
+	Email string               `json:"email" binding:"required,email"`
+	Role  model.MembershipRole `json:"role" binding:"required,oneof=owner admin member"`
+}
+
+type invitationReq struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// CreateOrganization handler creates an organization owned by the authenticated user
+func (h *Handler) CreateOrganization(c *gin.Context) {
+	u, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	var req organizationReq
+
+	if ok := BindData(c, &req); !ok {
+		return
+	}
+
+	org := &model.Organization{
+		Name: req.Name,
+	}
+
+	if err := h.OrganizationService.Create(c, u.UID, org); err != nil {
+		respondError(c, "Failed to create organization", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"organization": org,
+	})
+}
+
+// Organizations handler lists the organizations of the authenticated user
+func (h *Handler) Organizations(c *gin.Context) {
+	u, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	orgs, err := h.OrganizationService.ListForUser(c, u.UID)
+	if err != nil {
+		respondError(c, "Failed to list organizations", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"organizations": orgs,
+	})
+}
+
+// Members handler lists the members of an organization
+func (h *Handler) Members(c *gin.Context) {
+	orgID, ok := bindUUIDParam(c, middleware.OrgParam)
+	if !ok {
+		return
+	}
+
+	members, err := h.OrganizationService.ListMembers(c, orgID)
+	if err != nil {
+		respondError(c, "Failed to list members", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"members": members,
+	})
+}
+
+// Invite handler emails an invitation to join an organization
+func (h *Handler) Invite(c *gin.Context) {
+	u, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	orgID, ok := bindUUIDParam(c, middleware.OrgParam)
+	if !ok {
+		return
+	}
+
+	var req inviteReq
+
+	if ok := BindData(c, &req); !ok {
+		return
+	}
+
+	inv, err := h.OrganizationService.Invite(c, orgID, u.UID, req.Email, req.Role)
+	if err != nil {
+		respondError(c, "Failed to invite member", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"invitation": inv,
+	})
+}
+
+// AcceptInvitation handler adds the authenticated user to the organization they were invited to
+func (h *Handler) AcceptInvitation(c *gin.Context) {
+	user, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	var req invitationReq
+
+	if ok := BindData(c, &req); !ok {
+		return
+	}
+
+	// the invitation is matched against the current email address of the user, not the one in their token
+	u, err := h.UserService.Get(c, user.UID)
+	if err != nil {
+		respondError(c, "Failed to accept invitation", err)
+		return
+	}
+
+	m, err := h.OrganizationService.AcceptInvitation(c, u, req.Token)
+	if err != nil {
+		respondError(c, "Failed to accept invitation", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"membership": m,
+	})
+}
+
+// DeclineInvitation handler declines an invitation with the token emailed to the invitee
+func (h *Handler) DeclineInvitation(c *gin.Context) {
+	var req invitationReq
+
+	if ok := BindData(c, &req); !ok {
+		return
+	}
+
+	if err := h.OrganizationService.DeclineInvitation(c, req.Token); err != nil {
+		respondError(c, "Failed to decline invitation", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "invitation declined successfully",
+	})
+}
+
+// SwitchOrganization handler issues a new token pair scoped to an organization of the authenticated user.
+// It expects the membership to have been set to the context by RequireMembership. Only users signed in as
+// themselves can switch, a token pair being a session of its own
+func (h *Handler) SwitchOrganization(c *gin.Context) {
+	user, ok := contextUser(c)
+	if !ok || !h.requireSession(c, user) {
+		return
+	}
+
+	m := c.MustGet("membership").(*model.Membership)
+
+	u, err := h.UserService.Get(c, user.UID)
+	if err != nil {
+		respondError(c, "Failed to switch organization", err)
+		return
+	}
+
+	u.OrgID = m.OrgID
+	u.OrgRole = m.Role
+
+	tokens, err := h.TokenService.NewPairFromUser(c, u, "")
+	if err != nil {
+		respondError(c, "Failed to create tokens for user", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tokens": tokens,
+	})
+}
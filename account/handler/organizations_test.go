@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
+)
+
+func TestOrganizations(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+
+	uid := uuid.New()
+	orgID := uuid.New()
+
+	// setup returns a router serving requests as a member of the organization with the role
+	setup := func(role model.MembershipRole) (*gin.Engine, *mocks.MockOrganizationService, *mocks.MockUserService, *mocks.MockTokenService) {
+		router := gin.Default()
+		router.Use(func(c *gin.Context) {
+			c.Set("user", &model.User{UID: uid})
+		})
+
+		mockOrganizationService := new(mocks.MockOrganizationService)
+		mockOrganizationService.On("GetMembership", mock.Anything, orgID, uid).
+			Return(&model.Membership{OrgID: orgID, UID: uid, Role: role}, nil)
+
+		mockUserService := new(mocks.MockUserService)
+		mockTokenService := new(mocks.MockTokenService)
+
+		NewHandler(&Config{
+			Router:              router,
+			UserService:         mockUserService,
+			TokenService:        mockTokenService,
+			OrganizationService: mockOrganizationService,
+		})
+
+		return router, mockOrganizationService, mockUserService, mockTokenService
+	}
+
+	t.Run("Switch issues tokens scoped to the organization", func(t *testing.T) {
+		router, _, mockUserService, mockTokenService := setup(model.OrgAdmin)
+
+		mockUserService.On("Get", mock.Anything, uid).Return(&model.User{UID: uid, Email: "bob@bob.com"}, nil)
+
+		tokens := &model.TokenPair{
+			AccessToken:  "idToken",
+			RefreshToken: "refreshToken",
+		}
+		mockTokenService.On("NewPairFromUser", mock.Anything, mock.MatchedBy(func(u *model.User) bool {
+			return u.OrgID == orgID && u.OrgRole == model.OrgAdmin
+		}), "").Return(tokens, nil)
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/orgs/%s/switch", orgID), nil)
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(gin.H{
+			"tokens": tokens,
+		})
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockTokenService.AssertExpectations(t)
+	})
+
+	t.Run("Switch requires a session", func(t *testing.T) {
+		for name, u := range map[string]*model.User{
+			"Personal access token": {UID: uid, PersonalAccessTokenID: uuid.New()},
+			"Impersonation":         {UID: uid, Actor: &model.Actor{Subject: uuid.New()}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				router := gin.Default()
+				router.Use(func(c *gin.Context) {
+					c.Set("user", u)
+				})
+
+				mockOrganizationService := new(mocks.MockOrganizationService)
+				mockOrganizationService.On("GetMembership", mock.Anything, orgID, uid).
+					Return(&model.Membership{OrgID: orgID, UID: uid, Role: model.OrgAdmin}, nil)
+				mockTokenService := new(mocks.MockTokenService)
+
+				NewHandler(&Config{
+					Router:              router,
+					UserService:         new(mocks.MockUserService),
+					TokenService:        mockTokenService,
+					OrganizationService: mockOrganizationService,
+				})
+
+				rr := httptest.NewRecorder()
+				request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/orgs/%s/switch", orgID), nil)
+				router.ServeHTTP(rr, request)
+
+				assert.Equal(t, http.StatusForbidden, rr.Code)
+				mockTokenService.AssertNotCalled(t, "NewPairFromUser", mock.Anything, mock.Anything, mock.Anything)
+			})
+		}
+	})
+
+	t.Run("Invite requires an admin", func(t *testing.T) {
+		router, mockOrganizationService, _, _ := setup(model.OrgMember)
+
+		reqBody, _ := json.Marshal(gin.H{
+			"email": "alice@alice.com",
+			"role":  "member",
+		})
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/orgs/%s/invitations", orgID), bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		mockOrganizationService.AssertNotCalled(t, "Invite")
+	})
+
+	t.Run("Invite with an unknown role", func(t *testing.T) {
+		router, mockOrganizationService, _, _ := setup(model.OrgAdmin)
+
+		reqBody, _ := json.Marshal(gin.H{
+			"email": "alice@alice.com",
+			"role":  "superuser",
+		})
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/orgs/%s/invitations", orgID), bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockOrganizationService.AssertNotCalled(t, "Invite")
+	})
+
+	t.Run("Decline an expired invitation", func(t *testing.T) {
+		router, mockOrganizationService, _, _ := setup(model.OrgMember)
+
+		mockErr := apperrors.NewBadRequest("invitation has expired")
+		mockOrganizationService.On("DeclineInvitation", mock.Anything, "token").Return(mockErr)
+
+		reqBody, _ := json.Marshal(gin.H{
+			"token": "token",
+		})
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, "/invitations/decline", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
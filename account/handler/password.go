@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// passwordReq holds the current and new password of an authenticated user.
+// When RevokeSessions is set, RefreshToken identifies the session to keep
+type passwordReq struct {
+	CurrentPassword string `json:"currentPassword" binding:"required"`
+	NewPassword     string `json:"newPassword" binding:"required"`
+	RevokeSessions  bool   `json:"revokeSessions"`
+	RefreshToken    string `json:"refreshToken" binding:"required_if=RevokeSessions true"`
+}
+
+// Password handler changes the password of the authenticated user
+func (h *Handler) Password(c *gin.Context) {
+	user, exists := c.Get("user")
+
+	if !exists {
+		log.Printf("Unable to extract user from request context for unknown reason: %v\n", c)
+		err := apperrors.NewInternal()
+		c.JSON(err.Status(), gin.H{
+			"error": err,
+		})
+
+		return
+	}
+
+	var req passwordReq
+
+	if ok := BindData(c, &req); !ok {
+		return
+	}
+
+	if ok := h.checkPassword(c, "NewPassword", req.NewPassword, user.(*model.User).Email); !ok {
+		return
+	}
+
+	uid := user.(*model.User).UID
+
+	if err := h.UserService.ChangePassword(c, uid, req.CurrentPassword, req.NewPassword); err != nil {
+		log.Printf("Failed to change password for uid: %v\n%v", uid, err)
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	if req.RevokeSessions {
+		if err := h.TokenService.RevokeOtherSessions(c, uid, req.RefreshToken); err != nil {
+			log.Printf("Failed to revoke sessions for uid: %v\n%v", uid, err)
+			c.JSON(apperrors.Status(err), gin.H{
+				"error": err,
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "password updated successfully",
+	})
+}
+
+// expiredPasswordReq holds the credentials of a user whose password has expired and their new password
+type expiredPasswordReq struct {
+	Email           string `json:"email" binding:"required,email"`
+	CurrentPassword string `json:"currentPassword" binding:"required"`
+	NewPassword     string `json:"newPassword" binding:"required"`
+}
+
+// ExpiredPassword handler replaces an expired password, which blocks signin,
+// and signs the user in with their new password
+func (h *Handler) ExpiredPassword(c *gin.Context) {
+	var req expiredPasswordReq
+
+	if ok := BindData(c, &req); !ok {
+		return
+	}
+
+	if ok := h.checkPassword(c, "NewPassword", req.NewPassword, req.Email); !ok {
+		return
+	}
+
+	if ok := h.checkLockout(c, req.Email); !ok {
+		return
+	}
+
+	u := &model.User{
+		Email:    req.Email,
+		Password: req.CurrentPassword,
+	}
+
+	err := h.UserService.ResetExpiredPassword(c, u, req.NewPassword)
+
+	h.recordSignin(c, req.Email, err)
+
+	if err != nil {
+		log.Printf("Failed to reset expired password: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	tokens, err := h.TokenService.NewPairFromUser(c, u, "")
+
+	if err != nil {
+		log.Printf("Failed to create tokens for user: %v\n", err.Error())
+
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tokens": tokens,
+	})
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"github.com/weslleyrsr/auth-engine/account/passwordpolicy"
+)
+
+// checkPassword validates password against the password policy of the application and,
+// when configured, against passwords exposed in data breaches. It responds with the violated
+// rules as invalidArgs like BindData does. Returns false if the password is rejected
+func (h *Handler) checkPassword(c *gin.Context, field string, password string, email string) bool {
+	violations := passwordpolicy.Check(h.settings(c).GetPasswordPolicy(), password, email)
+
+	// only look up passwords which are otherwise acceptable
+	if len(violations) == 0 && h.BreachedPasswordRepository != nil {
+		breached, err := h.BreachedPasswordRepository.IsBreached(c, password)
+
+		// an unavailable corpus must not prevent users from signing up, so fail open
+		if err != nil {
+			log.Printf("Unable to check password against breached passwords: %v\n", err)
+		}
+
+		if breached {
+			violations = append(violations, passwordpolicy.Violation{Rule: passwordpolicy.RuleBreached})
+		}
+	}
+
+	if len(violations) == 0 {
+		return true
+	}
+
+	var invalidArgs []invalidArgument
+
+	for _, v := range violations {
+		// the password itself is never echoed back
+		invalidArgs = append(invalidArgs, invalidArgument{
+			Field: field,
+			Tag:   v.Rule,
+			Param: v.Param,
+		})
+	}
+
+	err := apperrors.NewBadRequest("Password does not satisfy the password policy. See invalidArgs")
+
+	c.JSON(err.Status(), gin.H{
+		"error":       err,
+		"invalidArgs": invalidArgs,
+	})
+
+	return false
+}
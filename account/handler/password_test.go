@@ -0,0 +1,329 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
+)
+
+func TestPassword(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+
+	uid, _ := uuid.NewRandom()
+
+	// setupRouter returns a router whose context already holds the authenticated user
+	setupRouter := func(us model.UserService, ts model.TokenService) *gin.Engine {
+		router := gin.Default()
+		router.Use(func(c *gin.Context) {
+			c.Set("user", &model.User{
+				UID: uid,
+			})
+		})
+
+		NewHandler(&Config{
+			Router:       router,
+			UserService:  us,
+			TokenService: ts,
+		})
+
+		return router
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockUserService := new(mocks.MockUserService)
+		mockTokenService := new(mocks.MockTokenService)
+		mockUserService.On("ChangePassword", mock.AnythingOfType("*gin.Context"), uid, "oldpassword", "tangerine-kayak-79").Return(nil)
+
+		rr := httptest.NewRecorder()
+		router := setupRouter(mockUserService, mockTokenService)
+
+		reqBody, err := json.Marshal(gin.H{
+			"currentPassword": "oldpassword",
+			"newPassword":     "tangerine-kayak-79",
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPut, "/password", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockUserService.AssertExpectations(t)
+		mockTokenService.AssertNotCalled(t, "RevokeOtherSessions", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success revoking other sessions", func(t *testing.T) {
+		mockUserService := new(mocks.MockUserService)
+		mockTokenService := new(mocks.MockTokenService)
+		mockUserService.On("ChangePassword", mock.AnythingOfType("*gin.Context"), uid, "oldpassword", "tangerine-kayak-79").Return(nil)
+		mockTokenService.On("RevokeOtherSessions", mock.AnythingOfType("*gin.Context"), uid, "refresh-token").Return(nil)
+
+		rr := httptest.NewRecorder()
+		router := setupRouter(mockUserService, mockTokenService)
+
+		reqBody, err := json.Marshal(gin.H{
+			"currentPassword": "oldpassword",
+			"newPassword":     "tangerine-kayak-79",
+			"revokeSessions":  true,
+			"refreshToken":    "refresh-token",
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPut, "/password", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockUserService.AssertExpectations(t)
+		mockTokenService.AssertExpectations(t)
+	})
+
+	t.Run("Refresh token required to revoke sessions", func(t *testing.T) {
+		mockUserService := new(mocks.MockUserService)
+		mockTokenService := new(mocks.MockTokenService)
+
+		rr := httptest.NewRecorder()
+		router := setupRouter(mockUserService, mockTokenService)
+
+		reqBody, err := json.Marshal(gin.H{
+			"currentPassword": "oldpassword",
+			"newPassword":     "tangerine-kayak-79",
+			"revokeSessions":  true,
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPut, "/password", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockUserService.AssertNotCalled(t, "ChangePassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("New password too short", func(t *testing.T) {
+		mockUserService := new(mocks.MockUserService)
+		mockTokenService := new(mocks.MockTokenService)
+
+		rr := httptest.NewRecorder()
+		router := setupRouter(mockUserService, mockTokenService)
+
+		reqBody, err := json.Marshal(gin.H{
+			"currentPassword": "oldpassword",
+			"newPassword":     "new",
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPut, "/password", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockUserService.AssertNotCalled(t, "ChangePassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("New password derived from email", func(t *testing.T) {
+		mockUserService := new(mocks.MockUserService)
+		mockTokenService := new(mocks.MockTokenService)
+
+		rr := httptest.NewRecorder()
+		router := gin.Default()
+		router.Use(func(c *gin.Context) {
+			c.Set("user", &model.User{
+				UID:   uid,
+				Email: "rosalind.franklin@kings.ac.uk",
+			})
+		})
+
+		NewHandler(&Config{
+			Router:       router,
+			UserService:  mockUserService,
+			TokenService: mockTokenService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"currentPassword": "oldpassword",
+			"newPassword":     "Franklin!Rosalind",
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPut, "/password", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		var res struct {
+			InvalidArgs []invalidArgument `json:"invalidArgs"`
+		}
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Contains(t, res.InvalidArgs, invalidArgument{Field: "NewPassword", Tag: "email"})
+		mockUserService.AssertNotCalled(t, "ChangePassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("Invalid current password", func(t *testing.T) {
+		mockUserService := new(mocks.MockUserService)
+		mockTokenService := new(mocks.MockTokenService)
+		mockErr := apperrors.NewAuthorization("Invalid current password")
+		mockUserService.On("ChangePassword", mock.AnythingOfType("*gin.Context"), uid, "wrongpassword", "tangerine-kayak-79").Return(mockErr)
+
+		rr := httptest.NewRecorder()
+		router := setupRouter(mockUserService, mockTokenService)
+
+		reqBody, err := json.Marshal(gin.H{
+			"currentPassword": "wrongpassword",
+			"newPassword":     "tangerine-kayak-79",
+			"revokeSessions":  true,
+			"refreshToken":    "refresh-token",
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPut, "/password", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(gin.H{
+			"error": mockErr,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockUserService.AssertExpectations(t)
+		mockTokenService.AssertNotCalled(t, "RevokeOtherSessions", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestExpiredPassword(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+
+	email := "bob@bob.com"
+
+	setupRouter := func(us model.UserService, ts model.TokenService) *gin.Engine {
+		router := gin.Default()
+
+		NewHandler(&Config{
+			Router:       router,
+			UserService:  us,
+			TokenService: ts,
+		})
+
+		return router
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockUserService := new(mocks.MockUserService)
+		mockTokenService := new(mocks.MockTokenService)
+
+		u := &model.User{
+			Email:    email,
+			Password: "oldpassword",
+		}
+		tokens := &model.TokenPair{
+			AccessToken:  "access-token",
+			RefreshToken: "refresh-token",
+		}
+
+		mockUserService.On("ResetExpiredPassword", mock.AnythingOfType("*gin.Context"), u, "tangerine-kayak-79").Return(nil)
+		mockTokenService.On("NewPairFromUser", mock.AnythingOfType("*gin.Context"), u, "").Return(tokens, nil)
+
+		rr := httptest.NewRecorder()
+		router := setupRouter(mockUserService, mockTokenService)
+
+		reqBody, err := json.Marshal(gin.H{
+			"email":           email,
+			"currentPassword": "oldpassword",
+			"newPassword":     "tangerine-kayak-79",
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/password/expired", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		expectedRespBody, err := json.Marshal(gin.H{
+			"tokens": tokens,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, expectedRespBody, rr.Body.Bytes())
+		mockUserService.AssertExpectations(t)
+		mockTokenService.AssertExpectations(t)
+	})
+
+	t.Run("New password violates policy", func(t *testing.T) {
+		mockUserService := new(mocks.MockUserService)
+		mockTokenService := new(mocks.MockTokenService)
+
+		rr := httptest.NewRecorder()
+		router := setupRouter(mockUserService, mockTokenService)
+
+		reqBody, err := json.Marshal(gin.H{
+			"email":           email,
+			"currentPassword": "oldpassword",
+			"newPassword":     "short",
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/password/expired", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockUserService.AssertNotCalled(t, "ResetExpiredPassword", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Password reused", func(t *testing.T) {
+		mockUserService := new(mocks.MockUserService)
+		mockTokenService := new(mocks.MockTokenService)
+		mockErr := apperrors.NewBadRequest("Password has been used recently, choose a different one")
+
+		mockUserService.On("ResetExpiredPassword", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("*model.User"), "tangerine-kayak-79").Return(mockErr)
+
+		rr := httptest.NewRecorder()
+		router := setupRouter(mockUserService, mockTokenService)
+
+		reqBody, err := json.Marshal(gin.H{
+			"email":           email,
+			"currentPassword": "tangerine-kayak-79",
+			"newPassword":     "tangerine-kayak-79",
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/password/expired", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockTokenService.AssertNotCalled(t, "NewPairFromUser", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
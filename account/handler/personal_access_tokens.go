@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+type personalAccessTokenReq struct {
+	Name      string     `json:"name" binding:"required,max=100"`
+	Scopes    []string   `json:"scopes" binding:"dive,required,max=128"`
+	ExpiresAt *time.Time `json:"expiresAt"` // the token never expires when omitted
+}
+
+// CreatePersonalAccessToken handler creates a personal access token for the authenticated user.
+// The token is only ever part of this response
+func (h *Handler) CreatePersonalAccessToken(c *gin.Context) {
+	u, ok := contextUser(c)
+	if !ok || !h.requireSession(c, u) {
+		return
+	}
+
+	var req personalAccessTokenReq
+
+	if ok := BindData(c, &req); !ok {
+		return
+	}
+
+	t := &model.PersonalAccessToken{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	}
+
+	token, err := h.PersonalAccessTokenService.Create(c, u, t)
+	if err != nil {
+		respondError(c, "Failed to create personal access token", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"token":               token,
+		"personalAccessToken": t,
+	})
+}
+
+// PersonalAccessTokens handler lists the personal access tokens of the authenticated user
+func (h *Handler) PersonalAccessTokens(c *gin.Context) {
+	u, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	tokens, err := h.PersonalAccessTokenService.List(c, u.UID)
+	if err != nil {
+		respondError(c, "Failed to list personal access tokens", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"personalAccessTokens": tokens,
+	})
+}
+
+// RevokePersonalAccessToken handler revokes a personal access token of the authenticated user
+func (h *Handler) RevokePersonalAccessToken(c *gin.Context) {
+	u, ok := contextUser(c)
+	if !ok || !h.requireSession(c, u) {
+		return
+	}
+
+	id, ok := bindUUIDParam(c, "tokenId")
+	if !ok {
+		return
+	}
+
+	if err := h.PersonalAccessTokenService.Revoke(c, u.UID, id); err != nil {
+		respondError(c, "Failed to revoke personal access token", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "personal access token revoked successfully",
+	})
+}
+
+// requireSession rejects requests authenticated with a personal access token with a 403, so that
+// a leaked token cannot be used to mint or revoke others or to delete or export the account, as well
+// as requests of service accounts and of admins impersonating the user
+func (h *Handler) requireSession(c *gin.Context, u *model.User) bool {
+	if u.PersonalAccessTokenID == uuid.Nil && u.ServiceAccountID == uuid.Nil && u.Actor == nil {
+		return true
+	}
+
+	err := apperrors.NewForbidden("Only the user signed in as themselves can do this")
+	c.JSON(err.Status(), gin.H{
+		"error": err,
+	})
+
+	return false
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
+)
+
+func TestPersonalAccessTokens(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+
+	// setup returns a router serving requests as the user
+	setup := func(u *model.User) (*gin.Engine, *mocks.MockPersonalAccessTokenService) {
+		router := gin.Default()
+		router.Use(func(c *gin.Context) {
+			c.Set("user", u)
+		})
+
+		mockPersonalAccessTokenService := new(mocks.MockPersonalAccessTokenService)
+
+		NewHandler(&Config{
+			Router:                     router,
+			PersonalAccessTokenService: mockPersonalAccessTokenService,
+		})
+
+		return router, mockPersonalAccessTokenService
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		u := &model.User{UID: uuid.New(), Permissions: model.Names{"articles.read"}}
+		router, mockPersonalAccessTokenService := setup(u)
+
+		mockPersonalAccessTokenService.
+			On("Create", mock.Anything, u, mock.MatchedBy(func(pat *model.PersonalAccessToken) bool {
+				return pat.Name == "deploy script" && pat.Scopes.Contains("articles.read") && pat.ExpiresAt != nil
+			})).
+			Return("pat_secret", nil)
+
+		reqBody, _ := json.Marshal(gin.H{
+			"name":      "deploy script",
+			"scopes":    []string{"articles.read"},
+			"expiresAt": "2030-01-01T00:00:00Z",
+		})
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, "/personal-access-tokens", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		var respBody struct {
+			Token string `json:"token"`
+		}
+		_ = json.Unmarshal(rr.Body.Bytes(), &respBody)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, "pat_secret", respBody.Token)
+	})
+
+	t.Run("Tokens cannot create tokens", func(t *testing.T) {
+		u := &model.User{UID: uuid.New(), PersonalAccessTokenID: uuid.New()}
+		router, mockPersonalAccessTokenService := setup(u)
+
+		reqBody, _ := json.Marshal(gin.H{
+			"name": "escalated",
+		})
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, "/personal-access-tokens", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		mockPersonalAccessTokenService.AssertNotCalled(t, "Create")
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		u := &model.User{UID: uuid.New()}
+		router, mockPersonalAccessTokenService := setup(u)
+
+		id := uuid.New()
+		mockPersonalAccessTokenService.On("Revoke", mock.Anything, u.UID, id).Return(nil)
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodDelete, "/personal-access-tokens/"+id.String(), nil)
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockPersonalAccessTokenService.AssertExpectations(t)
+	})
+}
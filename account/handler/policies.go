@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"github.com/weslleyrsr/auth-engine/account/policy"
+)
+
+type authorizeCheckReq struct {
+	// SubjectToken is the access token of the user the check is made on behalf of
+	SubjectToken string           `json:"subjectToken" binding:"required"`
+	Action       string           `json:"action" binding:"required,max=128"`
+	Resource     model.Attributes `json:"resource"`
+	Context      model.Attributes `json:"context"`
+}
+
+type policyReq struct {
+	Name        string             `json:"name" binding:"required,max=128"`
+	Description string             `json:"description" binding:"max=256"`
+	Effect      model.PolicyEffect `json:"effect" binding:"required,oneof=allow deny"`
+	Actions     []string           `json:"actions" binding:"required,min=1,dive,required,max=128"`
+	Expression  string             `json:"expression" binding:"required,max=4096"`
+}
+
+type dryRunReq struct {
+	// Policy is evaluated in place of the published policy of its name, when set
+	Policy   *policyReq       `json:"policy"`
+	Subject  model.Attributes `json:"subject" binding:"required"`
+	Action   string           `json:"action" binding:"required,max=128"`
+	Resource model.Attributes `json:"resource"`
+	Context  model.Attributes `json:"context"`
+	// At is the time of the evaluation, defaults to now
+	At *time.Time `json:"at"`
+}
+
+// toPolicy returns the policy described by the request
+func (r *policyReq) toPolicy() *model.Policy {
+	return &model.Policy{
+		Name:        r.Name,
+		Description: r.Description,
+		Effect:      r.Effect,
+		Actions:     r.Actions,
+		Expression:  r.Expression,
+	}
+}
+
+// AuthorizeCheck handler lets a service account decide whether a user may perform an action on a resource,
+// the subject of the request being taken from the claims of the access token of the user it is given.
+// The ip and time of the context are set by the engine, overriding those of the caller.
+// A denied request is a successful check, answered with a 200
+func (h *Handler) AuthorizeCheck(c *gin.Context) {
+	caller, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	if caller.ServiceAccountID == uuid.Nil {
+		err := apperrors.NewForbidden("Only service accounts can check authorizations")
+		c.JSON(err.Status(), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	var req authorizeCheckReq
+
+	if ok := BindData(c, &req); !ok {
+		return
+	}
+
+	subject, err := h.TokenService.ValidateIDToken(req.SubjectToken)
+	if err != nil {
+		respondError(c, "Failed to validate subject token", err)
+		return
+	}
+
+	if err := h.TokenService.ValidateUserStatus(c, subject); err != nil {
+		respondError(c, "Subject of authorization check is not active", err)
+		return
+	}
+
+	ctx := model.Attributes{}
+	for k, v := range req.Context {
+		ctx[k] = v
+	}
+	ctx["ip"] = c.ClientIP()
+	ctx["time"] = time.Now().UTC().Format(time.RFC3339)
+
+	d, err := h.PolicyService.Authorize(c, &model.AuthorizationRequest{
+		Subject:  policy.Subject(subject),
+		Action:   req.Action,
+		Resource: req.Resource,
+		Context:  ctx,
+	})
+	if err != nil {
+		respondError(c, "Failed to check authorization", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"decision": d,
+	})
+}
+
+// ListPolicies handler lists the policies of the application in their enforced version
+func (h *Handler) ListPolicies(c *gin.Context) {
+	policies, err := h.PolicyService.List(c)
+	if err != nil {
+		respondError(c, "Failed to list policies", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"policies": policies,
+	})
+}
+
+// PublishPolicy handler publishes a policy, as a new version when one of the same name exists
+func (h *Handler) PublishPolicy(c *gin.Context) {
+	actor, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	var req policyReq
+
+	if ok := BindData(c, &req); !ok {
+		return
+	}
+
+	p := req.toPolicy()
+
+	if err := h.PolicyService.Publish(c, actor.UID, p); err != nil {
+		respondError(c, "Failed to publish policy", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"policy": p,
+	})
+}
+
+// PolicyVersions handler lists the versions of a policy, the latest first
+func (h *Handler) PolicyVersions(c *gin.Context) {
+	policies, err := h.PolicyService.Versions(c, c.Param("name"))
+	if err != nil {
+		respondError(c, "Failed to list policy versions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"policies": policies,
+	})
+}
+
+// RestorePolicy handler publishes a previous version of a policy again
+func (h *Handler) RestorePolicy(c *gin.Context) {
+	actor, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		err := apperrors.NewBadRequest("Invalid version")
+		c.JSON(err.Status(), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	p, err := h.PolicyService.Restore(c, actor.UID, c.Param("name"), version)
+	if err != nil {
+		respondError(c, "Failed to restore policy", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"policy": p,
+	})
+}
+
+// DeletePolicy handler deletes a policy along with its versions
+func (h *Handler) DeletePolicy(c *gin.Context) {
+	actor, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.PolicyService.Delete(c, actor.UID, c.Param("name")); err != nil {
+		respondError(c, "Failed to delete policy", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "policy deleted successfully",
+	})
+}
+
+// DryRunPolicy handler decides a request against the policies of the application, optionally with
+// a candidate policy in place of the published one of its name, without storing anything
+func (h *Handler) DryRunPolicy(c *gin.Context) {
+	var req dryRunReq
+
+	if ok := BindData(c, &req); !ok {
+		return
+	}
+
+	var candidate *model.Policy
+	if req.Policy != nil {
+		candidate = req.Policy.toPolicy()
+	}
+
+	at := time.Now()
+	if req.At != nil {
+		at = *req.At
+	}
+
+	d, err := h.PolicyService.DryRun(c, candidate, &model.AuthorizationRequest{
+		Subject:  req.Subject,
+		Action:   req.Action,
+		Resource: req.Resource,
+		Context:  req.Context,
+	}, at)
+	if err != nil {
+		respondError(c, "Failed to dry run policies", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"decision": d,
+	})
+}
@@ -0,0 +1,272 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
+)
+
+func TestPolicies(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+
+	orgID := uuid.New()
+
+	admin := &model.User{
+		UID:         uuid.New(),
+		Permissions: model.Names{model.PermissionReadPolicies, model.PermissionWritePolicies},
+	}
+	member := &model.User{
+		UID:     uuid.New(),
+		Email:   "bob@bob.com",
+		Roles:   model.Names{"editor"},
+		OrgID:   orgID,
+		OrgRole: model.OrgOwner,
+	}
+	serviceAccount := &model.User{
+		UID:              uuid.New(),
+		ServiceAccountID: uuid.New(),
+	}
+
+	mockTokenService := new(mocks.MockTokenService)
+	mockTokenService.On("ValidateIDToken", "member-token").Return(member, nil)
+	mockTokenService.On("ValidateIDToken", "invalid-token").Return(nil, apperrors.NewAuthorization("Unable to verify user from idToken"))
+	mockTokenService.On("ValidateUserStatus", mock.Anything, member).Return(nil)
+
+	// setup returns a router serving requests as the user
+	setup := func(u *model.User) (*gin.Engine, *mocks.MockPolicyService) {
+		router := gin.Default()
+		router.Use(func(c *gin.Context) {
+			c.Set("user", u)
+		})
+
+		mockPolicyService := new(mocks.MockPolicyService)
+
+		NewHandler(&Config{
+			Router:        router,
+			TokenService:  mockTokenService,
+			PolicyService: mockPolicyService,
+		})
+
+		return router, mockPolicyService
+	}
+
+	t.Run("Authorize check of the subject token", func(t *testing.T) {
+		router, mockPolicyService := setup(serviceAccount)
+
+		decision := &model.AuthorizationDecision{
+			Allowed: true,
+			Matched: []*model.PolicyResult{{Name: "owners-edit", Version: 1, Effect: model.PolicyAllow}},
+		}
+		mockPolicyService.
+			On("Authorize", mock.Anything, mock.MatchedBy(func(req *model.AuthorizationRequest) bool {
+				return req.Subject["id"] == member.UID.String() &&
+					req.Subject["org_id"] == orgID.String() &&
+					req.Subject["org_role"] == "owner" &&
+					req.Action == "documents.edit" &&
+					req.Resource["org_id"] == orgID.String() &&
+					req.Context["ip"] == "192.0.2.1" &&
+					req.Context["time"] != "2000-01-01T00:00:00Z" &&
+					req.Context["mfa"] == true
+			})).
+			Return(decision, nil)
+
+		reqBody, _ := json.Marshal(gin.H{
+			"subjectToken": "member-token",
+			"action":       "documents.edit",
+			"resource":     gin.H{"org_id": orgID.String()},
+			"context":      gin.H{"ip": "10.0.0.1", "time": "2000-01-01T00:00:00Z", "mfa": true},
+		})
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, "/authorize-check", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+		request.RemoteAddr = "192.0.2.1:1234"
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(gin.H{
+			"decision": decision,
+		})
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+		mockPolicyService.AssertExpectations(t)
+	})
+
+	t.Run("Authorize check by a user", func(t *testing.T) {
+		router, mockPolicyService := setup(member)
+
+		reqBody, _ := json.Marshal(gin.H{
+			"subjectToken": "member-token",
+			"action":       "documents.edit",
+		})
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, "/authorize-check", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		mockPolicyService.AssertNotCalled(t, "Authorize")
+	})
+
+	t.Run("Authorize check with an invalid subject token", func(t *testing.T) {
+		router, mockPolicyService := setup(serviceAccount)
+
+		reqBody, _ := json.Marshal(gin.H{
+			"subjectToken": "invalid-token",
+			"action":       "documents.edit",
+		})
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, "/authorize-check", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		mockPolicyService.AssertNotCalled(t, "Authorize")
+	})
+
+	t.Run("Authorize check without action", func(t *testing.T) {
+		router, mockPolicyService := setup(serviceAccount)
+
+		reqBody, _ := json.Marshal(gin.H{
+			"subjectToken": "member-token",
+			"resource":     gin.H{"org_id": orgID.String()},
+		})
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, "/authorize-check", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockPolicyService.AssertNotCalled(t, "Authorize")
+	})
+
+	t.Run("Publish requires the write permission", func(t *testing.T) {
+		router, mockPolicyService := setup(member)
+
+		reqBody, _ := json.Marshal(gin.H{
+			"name":       "owners-edit",
+			"effect":     "allow",
+			"actions":    []string{"documents.edit"},
+			"expression": `subject.org_role == "owner"`,
+		})
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, "/admin/policies", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		mockPolicyService.AssertNotCalled(t, "Publish")
+	})
+
+	t.Run("Publish with an unknown effect", func(t *testing.T) {
+		router, mockPolicyService := setup(admin)
+
+		reqBody, _ := json.Marshal(gin.H{
+			"name":       "owners-edit",
+			"effect":     "maybe",
+			"actions":    []string{"documents.edit"},
+			"expression": `subject.org_role == "owner"`,
+		})
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, "/admin/policies", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockPolicyService.AssertNotCalled(t, "Publish")
+	})
+
+	t.Run("Publish", func(t *testing.T) {
+		router, mockPolicyService := setup(admin)
+
+		mockPolicyService.
+			On("Publish", mock.Anything, admin.UID, mock.MatchedBy(func(p *model.Policy) bool {
+				return p.Name == "owners-edit" && p.Effect == model.PolicyAllow && p.Actions.Contains("documents.edit")
+			})).
+			Return(nil)
+
+		reqBody, _ := json.Marshal(gin.H{
+			"name":       "owners-edit",
+			"effect":     "allow",
+			"actions":    []string{"documents.edit"},
+			"expression": `subject.org_role == "owner"`,
+		})
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, "/admin/policies", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		mockPolicyService.AssertExpectations(t)
+	})
+
+	t.Run("Restore an invalid version", func(t *testing.T) {
+		router, mockPolicyService := setup(admin)
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, "/admin/policies/owners-edit/versions/latest/restore", nil)
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockPolicyService.AssertNotCalled(t, "Restore")
+	})
+
+	t.Run("Dry run at a point in time", func(t *testing.T) {
+		router, mockPolicyService := setup(admin)
+
+		at := time.Date(2024, 5, 6, 20, 0, 0, 0, time.UTC)
+		decision := &model.AuthorizationDecision{Allowed: false, Matched: []*model.PolicyResult{}}
+
+		mockPolicyService.
+			On("DryRun", mock.Anything, mock.MatchedBy(func(p *model.Policy) bool {
+				return p.Name == "owners-edit"
+			}), mock.MatchedBy(func(req *model.AuthorizationRequest) bool {
+				return req.Subject["org_role"] == "owner" && req.Action == "documents.edit"
+			}), mock.MatchedBy(func(t time.Time) bool {
+				return t.Equal(at)
+			})).
+			Return(decision, nil)
+
+		reqBody, _ := json.Marshal(gin.H{
+			"policy": gin.H{
+				"name":       "owners-edit",
+				"effect":     "allow",
+				"actions":    []string{"documents.edit"},
+				"expression": `subject.org_role == "owner" && now.getHours("UTC") < 18`,
+			},
+			"subject": gin.H{"org_role": "owner"},
+			"action":  "documents.edit",
+			"at":      at,
+		})
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, "/admin/policies/dry-run", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(gin.H{
+			"decision": decision,
+		})
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+	})
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/weslleyrsr/auth-engine/account/handler/middleware"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// RateLimits holds the rate limits applied to each group of public routes
+type RateLimits struct {
+	Signup   []middleware.RateLimit
+	Signin   []middleware.RateLimit // signin and expired password reset
+	Recovery []middleware.RateLimit // email verification, email change and account unlock links
+	Tokens   []middleware.RateLimit
+}
+
+// DefaultRateLimits is used when Config.RateLimits is not set
+var DefaultRateLimits = RateLimits{
+	Signup: []middleware.RateLimit{
+		{Name: "signup-ip", Limit: 10, Window: time.Hour, Key: middleware.ByIP},
+	},
+	Signin: []middleware.RateLimit{
+		{Name: "signin-ip", Limit: 30, Window: time.Minute, Key: middleware.ByIP},
+		{Name: "signin-email", Limit: 10, Window: time.Minute, Key: middleware.ByEmail},
+	},
+	Recovery: []middleware.RateLimit{
+		{Name: "recovery-ip", Limit: 20, Window: time.Hour, Key: middleware.ByIP},
+		{Name: "recovery-email", Limit: 5, Window: time.Hour, Key: middleware.ByEmail},
+	},
+	Tokens: []middleware.RateLimit{
+		{Name: "tokens-ip", Limit: 60, Window: time.Minute, Key: middleware.ByIP},
+		// service accounts are counted on their own, and users along with every other user of their application
+		{Name: "tokens-client", Limit: 3000, Window: time.Minute, Key: middleware.ByClient},
+	},
+}
+
+// rateLimiters returns the middlewares applying limits, none when no RateLimiter is configured
+func (h *Handler) rateLimiters(l model.RateLimiter, limits []middleware.RateLimit) []gin.HandlerFunc {
+	if l == nil {
+		return nil
+	}
+
+	handlers := make([]gin.HandlerFunc, 0, len(limits))
+
+	for _, rl := range limits {
+		handlers = append(handlers, middleware.RateLimiter(l, rl))
+	}
+
+	return handlers
+}
@@ -0,0 +1,270 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+type roleReq struct {
+	Name        string `json:"name" binding:"required,max=64"`
+	Description string `json:"description" binding:"max=256"`
+}
+
+type permissionReq struct {
+	Name        string `json:"name" binding:"required,max=128"`
+	Description string `json:"description" binding:"max=256"`
+}
+
+// ListRoles handler lists the roles of the application along with their permissions
+func (h *Handler) ListRoles(c *gin.Context) {
+	roles, err := h.RoleService.ListRoles(c)
+	if err != nil {
+		respondError(c, "Failed to list roles", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"roles": roles,
+	})
+}
+
+// CreateRole handler creates a role without permissions, recording the admin creating it
+func (h *Handler) CreateRole(c *gin.Context) {
+	actor, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	var req roleReq
+
+	if ok := BindData(c, &req); !ok {
+		return
+	}
+
+	r := &model.Role{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+
+	if err := h.RoleService.CreateRole(c, actor.UID, r); err != nil {
+		respondError(c, "Failed to create role", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"role": r,
+	})
+}
+
+// DeleteRole handler deletes a role, unassigning it from every user
+func (h *Handler) DeleteRole(c *gin.Context) {
+	actor, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	roleID, ok := bindUUIDParam(c, "roleId")
+	if !ok {
+		return
+	}
+
+	if err := h.RoleService.DeleteRole(c, actor.UID, roleID); err != nil {
+		respondError(c, "Failed to delete role", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "role deleted successfully",
+	})
+}
+
+// ListPermissions handler lists the permissions of the application
+func (h *Handler) ListPermissions(c *gin.Context) {
+	permissions, err := h.RoleService.ListPermissions(c)
+	if err != nil {
+		respondError(c, "Failed to list permissions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"permissions": permissions,
+	})
+}
+
+// CreatePermission handler creates a permission
+func (h *Handler) CreatePermission(c *gin.Context) {
+	actor, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	var req permissionReq
+
+	if ok := BindData(c, &req); !ok {
+		return
+	}
+
+	p := &model.Permission{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+
+	if err := h.RoleService.CreatePermission(c, actor.UID, p); err != nil {
+		respondError(c, "Failed to create permission", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"permission": p,
+	})
+}
+
+// DeletePermission handler deletes a permission, revoking it from every role
+func (h *Handler) DeletePermission(c *gin.Context) {
+	actor, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	permissionID, ok := bindUUIDParam(c, "permissionId")
+	if !ok {
+		return
+	}
+
+	if err := h.RoleService.DeletePermission(c, actor.UID, permissionID); err != nil {
+		respondError(c, "Failed to delete permission", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "permission deleted successfully",
+	})
+}
+
+// GrantPermission handler adds a permission to a role
+func (h *Handler) GrantPermission(c *gin.Context) {
+	actor, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	roleID, ok := bindUUIDParam(c, "roleId")
+	if !ok {
+		return
+	}
+
+	permissionID, ok := bindUUIDParam(c, "permissionId")
+	if !ok {
+		return
+	}
+
+	if err := h.RoleService.GrantPermission(c, actor.UID, roleID, permissionID); err != nil {
+		respondError(c, "Failed to grant permission", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "permission granted successfully",
+	})
+}
+
+// RevokePermission handler removes a permission from a role
+func (h *Handler) RevokePermission(c *gin.Context) {
+	actor, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	roleID, ok := bindUUIDParam(c, "roleId")
+	if !ok {
+		return
+	}
+
+	permissionID, ok := bindUUIDParam(c, "permissionId")
+	if !ok {
+		return
+	}
+
+	if err := h.RoleService.RevokePermission(c, actor.UID, roleID, permissionID); err != nil {
+		respondError(c, "Failed to revoke permission", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "permission revoked successfully",
+	})
+}
+
+// UserRoles handler lists the roles assigned to a user
+func (h *Handler) UserRoles(c *gin.Context) {
+	uid, ok := bindUUIDParam(c, "uid")
+	if !ok {
+		return
+	}
+
+	roles, err := h.RoleService.UserRoles(c, uid)
+	if err != nil {
+		respondError(c, "Failed to list user roles", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"roles": roles,
+	})
+}
+
+// AssignRole handler assigns a role to a user, recording the admin assigning it
+func (h *Handler) AssignRole(c *gin.Context) {
+	actor, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	uid, ok := bindUUIDParam(c, "uid")
+	if !ok {
+		return
+	}
+
+	roleID, ok := bindUUIDParam(c, "roleId")
+	if !ok {
+		return
+	}
+
+	if err := h.RoleService.AssignRole(c, actor.UID, uid, roleID); err != nil {
+		respondError(c, "Failed to assign role", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "role assigned successfully",
+	})
+}
+
+// UnassignRole handler removes a role from a user, recording the admin removing it
+func (h *Handler) UnassignRole(c *gin.Context) {
+	actor, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	uid, ok := bindUUIDParam(c, "uid")
+	if !ok {
+		return
+	}
+
+	roleID, ok := bindUUIDParam(c, "roleId")
+	if !ok {
+		return
+	}
+
+	if err := h.RoleService.UnassignRole(c, actor.UID, uid, roleID); err != nil {
+		respondError(c, "Failed to unassign role", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "role unassigned successfully",
+	})
+}
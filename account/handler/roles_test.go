@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
+)
+
+func TestRoles(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+
+	admin := &model.User{
+		UID:         uuid.New(),
+		Permissions: model.Names{model.PermissionReadRoles, model.PermissionWriteRoles},
+	}
+	reader := &model.User{
+		UID:         uuid.New(),
+		Permissions: model.Names{model.PermissionReadRoles},
+	}
+
+	// setup returns a router serving requests as the user
+	setup := func(u *model.User) (*gin.Engine, *mocks.MockRoleService) {
+		router := gin.Default()
+		router.Use(func(c *gin.Context) {
+			c.Set("user", u)
+		})
+
+		mockRoleService := new(mocks.MockRoleService)
+
+		NewHandler(&Config{
+			Router:      router,
+			RoleService: mockRoleService,
+		})
+
+		return router, mockRoleService
+	}
+
+	t.Run("List roles", func(t *testing.T) {
+		router, mockRoleService := setup(reader)
+
+		roles := []*model.Role{
+			{ID: uuid.New(), Name: "admin", Permissions: model.Names{model.PermissionReadRoles}},
+		}
+		mockRoleService.On("ListRoles", mock.Anything).Return(roles, nil)
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodGet, "/admin/roles", nil)
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(gin.H{
+			"roles": roles,
+		})
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+	})
+
+	t.Run("Create role requires the write permission", func(t *testing.T) {
+		router, mockRoleService := setup(reader)
+
+		reqBody, _ := json.Marshal(gin.H{
+			"name": "editor",
+		})
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, "/admin/roles", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		mockRoleService.AssertNotCalled(t, "CreateRole")
+	})
+
+	t.Run("Create role", func(t *testing.T) {
+		router, mockRoleService := setup(admin)
+
+		mockRoleService.On("CreateRole", mock.Anything, admin.UID, &model.Role{Name: "editor", Description: "Edits things"}).Return(nil)
+
+		reqBody, _ := json.Marshal(gin.H{
+			"name":        "editor",
+			"description": "Edits things",
+		})
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, "/admin/roles", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		mockRoleService.AssertExpectations(t)
+	})
+
+	t.Run("Create role without a name", func(t *testing.T) {
+		router, mockRoleService := setup(admin)
+
+		reqBody, _ := json.Marshal(gin.H{
+			"description": "Edits things",
+		})
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, "/admin/roles", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockRoleService.AssertNotCalled(t, "CreateRole")
+	})
+
+	t.Run("Assign role", func(t *testing.T) {
+		router, mockRoleService := setup(admin)
+
+		uid := uuid.New()
+		roleID := uuid.New()
+		mockRoleService.On("AssignRole", mock.Anything, admin.UID, uid, roleID).Return(nil)
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/admin/users/%s/roles/%s", uid, roleID), nil)
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockRoleService.AssertExpectations(t)
+	})
+
+	t.Run("Assign role while impersonating", func(t *testing.T) {
+		impersonated := *admin
+		impersonated.Actor = &model.Actor{Subject: uuid.New()}
+		router, mockRoleService := setup(&impersonated)
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/admin/users/%s/roles/%s", admin.UID, uuid.New()), nil)
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		mockRoleService.AssertNotCalled(t, "AssignRole")
+	})
+
+	t.Run("Assign role of another application", func(t *testing.T) {
+		router, mockRoleService := setup(admin)
+
+		uid := uuid.New()
+		roleID := uuid.New()
+		mockRoleService.On("AssignRole", mock.Anything, admin.UID, uid, roleID).Return(apperrors.NewNotFound("user role", roleID.String()))
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/admin/users/%s/roles/%s", uid, roleID), nil)
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("Invalid uid", func(t *testing.T) {
+		router, mockRoleService := setup(admin)
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/admin/users/notauuid/roles/%s", uuid.New()), nil)
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockRoleService.AssertNotCalled(t, "AssignRole")
+	})
+}
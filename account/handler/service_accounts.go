@@ -0,0 +1,283 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/weslleyrsr/auth-engine/account/handler/middleware"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+type serviceAccountReq struct {
+	Name        string     `json:"name" binding:"required,max=100"`
+	Description string     `json:"description" binding:"max=256"`
+	OrgID       *uuid.UUID `json:"orgId"` // ignored on the routes of an organization, which owns the account
+	PublicKey   string     `json:"publicKey" binding:"required,max=8192"`
+	Scopes      []string   `json:"scopes" binding:"dive,required,max=128"`
+}
+
+type serviceAccountKeyReq struct {
+	PublicKey string `json:"publicKey" binding:"required,max=8192"`
+}
+
+type exchangePolicyReq struct {
+	Audiences    []string `json:"audiences" binding:"required,dive,required,max=256"`
+	Scopes       []string `json:"scopes" binding:"dive,required,max=128"`
+	MaxExpiresIn int      `json:"maxExpiresIn" binding:"min=0"`
+}
+
+// serviceAccountOwner returns the organization of the route the request is made to,
+// or uuid.Nil on the admin routes of the application
+func serviceAccountOwner(c *gin.Context) (uuid.UUID, bool) {
+	if c.Param(middleware.OrgParam) == "" {
+		return uuid.Nil, true
+	}
+
+	return bindUUIDParam(c, middleware.OrgParam)
+}
+
+// ServiceAccounts handler lists the service accounts of the application or organization
+func (h *Handler) ServiceAccounts(c *gin.Context) {
+	orgID, ok := serviceAccountOwner(c)
+	if !ok {
+		return
+	}
+
+	accounts, err := h.ServiceAccountService.List(c, orgID)
+	if err != nil {
+		respondError(c, "Failed to list service accounts", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"serviceAccounts": accounts,
+	})
+}
+
+// CreateServiceAccount handler creates a service account with scopes among the permissions of the authenticated user
+func (h *Handler) CreateServiceAccount(c *gin.Context) {
+	u, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	orgID, ok := serviceAccountOwner(c)
+	if !ok {
+		return
+	}
+
+	var req serviceAccountReq
+
+	if ok := BindData(c, &req); !ok {
+		return
+	}
+
+	sa := &model.ServiceAccount{
+		OrgID:       req.OrgID,
+		Name:        req.Name,
+		Description: req.Description,
+		PublicKey:   req.PublicKey,
+		Scopes:      req.Scopes,
+	}
+
+	if orgID != uuid.Nil {
+		sa.OrgID = &orgID
+	}
+
+	if err := h.ServiceAccountService.Create(c, u, sa); err != nil {
+		respondError(c, "Failed to create service account", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"serviceAccount": sa,
+	})
+}
+
+// RotateServiceAccountKey handler replaces the public key of a service account
+func (h *Handler) RotateServiceAccountKey(c *gin.Context) {
+	u, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	orgID, ok := serviceAccountOwner(c)
+	if !ok {
+		return
+	}
+
+	id, ok := bindUUIDParam(c, "serviceAccountId")
+	if !ok {
+		return
+	}
+
+	var req serviceAccountKeyReq
+
+	if ok := BindData(c, &req); !ok {
+		return
+	}
+
+	sa, err := h.ServiceAccountService.RotateKey(c, u.UID, orgID, id, req.PublicKey)
+	if err != nil {
+		respondError(c, "Failed to rotate service account key", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"serviceAccount": sa,
+	})
+}
+
+// DeleteServiceAccount handler deletes a service account
+func (h *Handler) DeleteServiceAccount(c *gin.Context) {
+	u, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	orgID, ok := serviceAccountOwner(c)
+	if !ok {
+		return
+	}
+
+	id, ok := bindUUIDParam(c, "serviceAccountId")
+	if !ok {
+		return
+	}
+
+	if err := h.ServiceAccountService.Delete(c, u.UID, orgID, id); err != nil {
+		respondError(c, "Failed to delete service account", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "service account deleted successfully",
+	})
+}
+
+// ServiceAccountActivity handler lists the latest events of the audit trail of a service account
+func (h *Handler) ServiceAccountActivity(c *gin.Context) {
+	orgID, ok := serviceAccountOwner(c)
+	if !ok {
+		return
+	}
+
+	id, ok := bindUUIDParam(c, "serviceAccountId")
+	if !ok {
+		return
+	}
+
+	events, err := h.ServiceAccountService.Activity(c, orgID, id)
+	if err != nil {
+		respondError(c, "Failed to list service account activity", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": events,
+	})
+}
+
+// ExchangePolicy handler gets the token exchange policy of a service account
+func (h *Handler) ExchangePolicy(c *gin.Context) {
+	orgID, ok := serviceAccountOwner(c)
+	if !ok {
+		return
+	}
+
+	id, ok := bindUUIDParam(c, "serviceAccountId")
+	if !ok {
+		return
+	}
+
+	p, err := h.ServiceAccountService.ExchangePolicy(c, orgID, id)
+	if err != nil {
+		respondError(c, "Failed to get token exchange policy", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"exchangePolicy": p,
+	})
+}
+
+// SetExchangePolicy handler creates or replaces the token exchange policy of a service account
+func (h *Handler) SetExchangePolicy(c *gin.Context) {
+	u, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	orgID, ok := serviceAccountOwner(c)
+	if !ok {
+		return
+	}
+
+	id, ok := bindUUIDParam(c, "serviceAccountId")
+	if !ok {
+		return
+	}
+
+	var req exchangePolicyReq
+
+	if ok := BindData(c, &req); !ok {
+		return
+	}
+
+	p := &model.TokenExchangePolicy{
+		ServiceAccountID: id,
+		Audiences:        req.Audiences,
+		Scopes:           req.Scopes,
+		MaxExpiresIn:     req.MaxExpiresIn,
+	}
+
+	if err := h.ServiceAccountService.SetExchangePolicy(c, u.UID, orgID, p); err != nil {
+		respondError(c, "Failed to set token exchange policy", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"exchangePolicy": p,
+	})
+}
+
+// DeleteExchangePolicy handler deletes the token exchange policy of a service account
+func (h *Handler) DeleteExchangePolicy(c *gin.Context) {
+	u, ok := contextUser(c)
+	if !ok {
+		return
+	}
+
+	orgID, ok := serviceAccountOwner(c)
+	if !ok {
+		return
+	}
+
+	id, ok := bindUUIDParam(c, "serviceAccountId")
+	if !ok {
+		return
+	}
+
+	if err := h.ServiceAccountService.DeleteExchangePolicy(c, u.UID, orgID, id); err != nil {
+		respondError(c, "Failed to delete token exchange policy", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "token exchange policy deleted successfully",
+	})
+}
+
+// serviceAccountRoutes registers the routes managing service accounts on a group,
+// each guarded by the matching middleware
+func (h *Handler) serviceAccountRoutes(g *gin.RouterGroup, canRead gin.HandlerFunc, canWrite gin.HandlerFunc, notImpersonated gin.HandlerFunc) {
+	g.GET("", canRead, h.ServiceAccounts)
+	g.POST("", canWrite, notImpersonated, h.CreateServiceAccount)
+	g.PUT("/:serviceAccountId/key", canWrite, notImpersonated, h.RotateServiceAccountKey)
+	g.DELETE("/:serviceAccountId", canWrite, notImpersonated, h.DeleteServiceAccount)
+	g.GET("/:serviceAccountId/activity", canRead, h.ServiceAccountActivity)
+	g.GET("/:serviceAccountId/exchange-policy", canRead, h.ExchangePolicy)
+	g.PUT("/:serviceAccountId/exchange-policy", canWrite, notImpersonated, h.SetExchangePolicy)
+	g.DELETE("/:serviceAccountId/exchange-policy", canWrite, notImpersonated, h.DeleteExchangePolicy)
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
+)
+
+func TestServiceAccounts(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+
+	orgID := uuid.New()
+
+	// setup returns a router serving requests as the user, an admin of the organization
+	setup := func(u *model.User) (*gin.Engine, *mocks.MockServiceAccountService) {
+		router := gin.Default()
+		router.Use(func(c *gin.Context) {
+			c.Set("user", u)
+		})
+
+		mockOrganizationService := new(mocks.MockOrganizationService)
+		mockOrganizationService.On("GetMembership", mock.Anything, orgID, u.UID).
+			Return(&model.Membership{OrgID: orgID, UID: u.UID, Role: model.OrgAdmin}, nil)
+
+		mockServiceAccountService := new(mocks.MockServiceAccountService)
+
+		NewHandler(&Config{
+			Router:                router,
+			OrganizationService:   mockOrganizationService,
+			ServiceAccountService: mockServiceAccountService,
+		})
+
+		return router, mockServiceAccountService
+	}
+
+	t.Run("Organizations own the accounts created on their routes", func(t *testing.T) {
+		u := &model.User{UID: uuid.New()}
+		router, mockServiceAccountService := setup(u)
+
+		mockServiceAccountService.
+			On("Create", mock.Anything, u, mock.MatchedBy(func(sa *model.ServiceAccount) bool {
+				return sa.OrgID != nil && *sa.OrgID == orgID && sa.Name == "billing sync"
+			})).
+			Return(nil)
+
+		reqBody, _ := json.Marshal(gin.H{
+			"name":      "billing sync",
+			"orgId":     uuid.New(),
+			"publicKey": "-----BEGIN PUBLIC KEY-----",
+		})
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/orgs/%s/service-accounts", orgID), bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		mockServiceAccountService.AssertExpectations(t)
+	})
+
+	t.Run("Admin routes require the read permission", func(t *testing.T) {
+		u := &model.User{UID: uuid.New()}
+		router, mockServiceAccountService := setup(u)
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodGet, "/admin/service-accounts", nil)
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		mockServiceAccountService.AssertNotCalled(t, "List")
+	})
+
+	t.Run("Activity of any account of the application", func(t *testing.T) {
+		u := &model.User{UID: uuid.New(), Permissions: model.Names{model.PermissionReadServiceAccounts}}
+		router, mockServiceAccountService := setup(u)
+
+		id := uuid.New()
+		events := []*model.ServiceAccountEvent{
+			{ID: uuid.New(), ServiceAccountID: id, Type: model.ServiceAccountAuthenticated, IP: "10.0.0.1"},
+		}
+		mockServiceAccountService.On("Activity", mock.Anything, uuid.Nil, id).Return(events, nil)
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/admin/service-accounts/%s/activity", id), nil)
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(gin.H{
+			"events": events,
+		})
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+	})
+	t.Run("Set the exchange policy of an account of the organization", func(t *testing.T) {
+		u := &model.User{UID: uuid.New()}
+		router, mockServiceAccountService := setup(u)
+
+		id := uuid.New()
+		p := &model.TokenExchangePolicy{
+			ServiceAccountID: id,
+			Audiences:        model.Names{"billing-api"},
+			Scopes:           model.Names{"billing.read"},
+			MaxExpiresIn:     300,
+		}
+		mockServiceAccountService.On("SetExchangePolicy", mock.Anything, u.UID, orgID, p).Return(nil)
+
+		reqBody, _ := json.Marshal(gin.H{
+			"audiences":    p.Audiences,
+			"scopes":       p.Scopes,
+			"maxExpiresIn": p.MaxExpiresIn,
+		})
+
+		rr := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/orgs/%s/service-accounts/%s/exchange-policy", orgID, id), bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		respBody, _ := json.Marshal(gin.H{
+			"exchangePolicy": p,
+		})
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+	})
+}
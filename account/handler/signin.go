@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// signinReq is not exported. Password length is not validated as it is
+// governed by the password policy in force when the password was chosen
+type signinReq struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+// Signin used to authenticate extant user
+func (h *Handler) Signin(c *gin.Context) {
+	var req signinReq
+
+	if ok := BindData(c, &req); !ok {
+		return
+	}
+
+	if ok := h.checkLockout(c, req.Email); !ok {
+		return
+	}
+
+	u := &model.User{
+		Email:    req.Email,
+		Password: req.Password,
+	}
+
+	err := h.UserService.Signin(c, u)
+
+	h.recordSignin(c, req.Email, err)
+
+	if err != nil {
+		log.Printf("Failed to sign in user: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	tokens, err := h.TokenService.NewPairFromUser(c, u, "")
+
+	if err != nil {
+		log.Printf("Failed to create tokens for user: %v\n", err.Error())
+
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tokens": tokens,
+	})
+}
@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
+)
+
+func TestSignin(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+
+	// setup mock services, gin engine/router, handler layer
+	mockUserService := new(mocks.MockUserService)
+	mockTokenService := new(mocks.MockTokenService)
+
+	router := gin.Default()
+
+	NewHandler(&Config{
+		Router:       router,
+		UserService:  mockUserService,
+		TokenService: mockTokenService,
+	})
+
+	t.Run("Bad request data", func(t *testing.T) {
+		// a response recorder for getting written http response
+		rr := httptest.NewRecorder()
+
+		// create a request body with invalid fields
+		reqBody, err := json.Marshal(gin.H{
+			"email":    "notanemail",
+			"password": "short",
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/signin", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockUserService.AssertNotCalled(t, "Signin")
+		mockTokenService.AssertNotCalled(t, "NewPairFromUser")
+	})
+
+	t.Run("Error Returned from UserService.Signin", func(t *testing.T) {
+		email := "bob@bob.com"
+		password := "pwdoesnotmatch123"
+
+		mockUSArgs := mock.Arguments{
+			mock.AnythingOfType("*gin.Context"),
+			&model.User{Email: email, Password: password},
+		}
+
+		// so we can check for a known status code
+		mockError := apperrors.NewAuthorization("invalid email/password combo")
+
+		mockUserService.On("Signin", mockUSArgs...).Return(mockError)
+
+		rr := httptest.NewRecorder()
+
+		reqBody, err := json.Marshal(gin.H{
+			"email":    email,
+			"password": password,
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/signin", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		mockUserService.AssertCalled(t, "Signin", mockUSArgs...)
+		mockTokenService.AssertNotCalled(t, "NewPairFromUser")
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+
+	t.Run("Successful Token Creation", func(t *testing.T) {
+		email := "bob@bob.com"
+		password := "pwworksgreat123"
+
+		mockUSArgs := mock.Arguments{
+			mock.AnythingOfType("*gin.Context"),
+			&model.User{Email: email, Password: password},
+		}
+
+		mockUserService.On("Signin", mockUSArgs...).Return(nil)
+
+		mockTSArgs := mock.Arguments{
+			mock.AnythingOfType("*gin.Context"),
+			&model.User{Email: email, Password: password},
+			"",
+		}
+
+		mockTokenPair := &model.TokenPair{
+			AccessToken:  "idToken",
+			RefreshToken: "refreshToken",
+		}
+
+		mockTokenService.On("NewPairFromUser", mockTSArgs...).Return(mockTokenPair, nil)
+
+		rr := httptest.NewRecorder()
+
+		reqBody, err := json.Marshal(gin.H{
+			"email":    email,
+			"password": password,
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/signin", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, request)
+
+		respBody, err := json.Marshal(gin.H{
+			"tokens": mockTokenPair,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, respBody, rr.Body.Bytes())
+
+		mockUserService.AssertCalled(t, "Signin", mockUSArgs...)
+		mockTokenService.AssertCalled(t, "NewPairFromUser", mockTSArgs...)
+	})
+}
+
+func TestSigninLockout(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+
+	email := "bob@bob.com"
+	password := "pwdoesnotmatch123"
+
+	setupRouter := func(us model.UserService, ts model.TokenService, ls model.LockoutService) *gin.Engine {
+		router := gin.Default()
+
+		NewHandler(&Config{
+			Router:         router,
+			UserService:    us,
+			TokenService:   ts,
+			LockoutService: ls,
+		})
+
+		return router
+	}
+
+	newRequest := func(t *testing.T) *http.Request {
+		reqBody, err := json.Marshal(gin.H{
+			"email":    email,
+			"password": password,
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/signin", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+		request.RemoteAddr = "203.0.113.7:41234"
+
+		return request
+	}
+
+	t.Run("Locked", func(t *testing.T) {
+		mockUserService := new(mocks.MockUserService)
+		mockTokenService := new(mocks.MockTokenService)
+		mockLockoutService := new(mocks.MockLockoutService)
+
+		mockLockoutService.On("Check", mock.AnythingOfType("*gin.Context"), email, "203.0.113.7").Return(apperrors.NewLocked(90 * time.Second))
+
+		rr := httptest.NewRecorder()
+		setupRouter(mockUserService, mockTokenService, mockLockoutService).ServeHTTP(rr, newRequest(t))
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Equal(t, "90", rr.Header().Get("Retry-After"))
+		assert.Contains(t, rr.Body.String(), `"retryAfter":90`)
+		mockUserService.AssertNotCalled(t, "Signin", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure is recorded", func(t *testing.T) {
+		mockUserService := new(mocks.MockUserService)
+		mockTokenService := new(mocks.MockTokenService)
+		mockLockoutService := new(mocks.MockLockoutService)
+
+		mockLockoutService.On("Check", mock.AnythingOfType("*gin.Context"), email, "203.0.113.7").Return(nil)
+		mockUserService.On("Signin", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("*model.User")).Return(apperrors.NewAuthorization("invalid email and password combination"))
+		mockLockoutService.On("RecordFailure", mock.AnythingOfType("*gin.Context"), email, "203.0.113.7").Return(nil)
+
+		rr := httptest.NewRecorder()
+		setupRouter(mockUserService, mockTokenService, mockLockoutService).ServeHTTP(rr, newRequest(t))
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		mockLockoutService.AssertExpectations(t)
+	})
+
+	t.Run("Success is recorded", func(t *testing.T) {
+		mockUserService := new(mocks.MockUserService)
+		mockTokenService := new(mocks.MockTokenService)
+		mockLockoutService := new(mocks.MockLockoutService)
+
+		mockLockoutService.On("Check", mock.AnythingOfType("*gin.Context"), email, "203.0.113.7").Return(nil)
+		mockUserService.On("Signin", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("*model.User")).Return(nil)
+		mockLockoutService.On("RecordSuccess", mock.AnythingOfType("*gin.Context"), email).Return(nil)
+		mockTokenService.On("NewPairFromUser", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("*model.User"), "").Return(&model.TokenPair{}, nil)
+
+		rr := httptest.NewRecorder()
+		setupRouter(mockUserService, mockTokenService, mockLockoutService).ServeHTTP(rr, newRequest(t))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockLockoutService.AssertExpectations(t)
+		mockLockoutService.AssertNotCalled(t, "RecordFailure", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unlock", func(t *testing.T) {
+		mockLockoutService := new(mocks.MockLockoutService)
+		mockLockoutService.On("UnlockWithToken", mock.AnythingOfType("*gin.Context"), "unlock-token").Return(nil)
+
+		reqBody, err := json.Marshal(gin.H{
+			"token": "unlock-token",
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/unlock", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+
+		rr := httptest.NewRecorder()
+		setupRouter(new(mocks.MockUserService), new(mocks.MockTokenService), mockLockoutService).ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockLockoutService.AssertExpectations(t)
+	})
+}
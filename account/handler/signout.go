@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Signout handler ends every session of the signed in user by removing their refresh tokens.
+// Users acting through an access token of another kind, or impersonated, cannot sign out
+func (h *Handler) Signout(c *gin.Context) {
+	u, ok := contextUser(c)
+	if !ok || !h.requireSession(c, u) {
+		return
+	}
+
+	if err := h.TokenService.Signout(c, u.UID); err != nil {
+		respondError(c, "Failed to sign out user", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "user signed out successfully",
+	})
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
+)
+
+func TestSignout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	uid, _ := uuid.NewRandom()
+
+	// setupRouter returns a router whose context already holds u
+	setupRouter := func(u *model.User, ts model.TokenService) *gin.Engine {
+		router := gin.Default()
+		router.Use(func(c *gin.Context) {
+			c.Set("user", u)
+		})
+
+		NewHandler(&Config{
+			Router:       router,
+			UserService:  new(mocks.MockUserService),
+			TokenService: ts,
+		})
+
+		return router
+	}
+
+	request := func(router *gin.Engine) *httptest.ResponseRecorder {
+		request, _ := http.NewRequest(http.MethodPost, "/signout", nil)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, request)
+
+		return rr
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockTokenService := new(mocks.MockTokenService)
+		mockTokenService.On("Signout", mock.AnythingOfType("*gin.Context"), uid).Return(nil)
+
+		rr := request(setupRouter(&model.User{UID: uid}, mockTokenService))
+
+		expectedRespBody, _ := json.Marshal(gin.H{
+			"message": "user signed out successfully",
+		})
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, expectedRespBody, rr.Body.Bytes())
+		mockTokenService.AssertExpectations(t)
+	})
+
+	t.Run("Error from TokenService", func(t *testing.T) {
+		mockTokenService := new(mocks.MockTokenService)
+		mockTokenService.On("Signout", mock.AnythingOfType("*gin.Context"), uid).Return(apperrors.NewInternal())
+
+		rr := request(setupRouter(&model.User{UID: uid}, mockTokenService))
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+
+	t.Run("Impersonating admin", func(t *testing.T) {
+		mockTokenService := new(mocks.MockTokenService)
+
+		rr := request(setupRouter(&model.User{UID: uid, Actor: &model.Actor{Subject: uuid.New()}}, mockTokenService))
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		mockTokenService.AssertNotCalled(t, "Signout", mock.Anything, mock.Anything)
+	})
+}
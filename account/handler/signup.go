@@ -11,7 +11,7 @@ import (
 // signupReq is not exported, hence the lowercase name, it is used for validation and json marshalling
 type signupReq struct {
 	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,gte=6,lte=30"`
+	Password string `json:"password" binding:"required"`
 }
 
 // Signup handler
@@ -24,6 +24,10 @@ func (h *Handler) Signup(c *gin.Context) {
 		return
 	}
 
+	if ok := h.checkPassword(c, "Password", req.Password, req.Email); !ok {
+		return
+	}
+
 	user := &model.User{
 		Email:    req.Email,
 		Password: req.Password,
@@ -39,6 +43,14 @@ func (h *Handler) Signup(c *gin.Context) {
 		return
 	}
 
+	// tokens are only handed out once the email address is verified and the user approved
+	if (h.settings(c).RequireVerifiedEmail && !user.EmailVerified) || user.Status == model.UserPending {
+		c.JSON(http.StatusCreated, gin.H{
+			"user": user,
+		})
+		return
+	}
+
 	// create token pair as strings
 	tokens, err := h.TokenService.NewPairFromUser(c, user, "")
 
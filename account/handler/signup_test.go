@@ -3,6 +3,7 @@ package handler
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -11,6 +12,7 @@ import (
 	mocks2 "github.com/weslleyrsr/auth-engine/account/model/mocks"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -137,7 +139,7 @@ func TestSignup(t *testing.T) {
 
 		reqBody, err := json.Marshal(gin.H{
 			"email":    "bob@bob.com",
-			"password": "asbdfoagbfouygasdofgoasdgfaugfp",
+			"password": strings.Repeat("a", model.DefaultPasswordPolicy.MaxLength+1),
 		})
 		assert.NoError(t, err)
 
@@ -153,10 +155,167 @@ func TestSignup(t *testing.T) {
 		mockUserService.AssertNotCalled(t, "Signup")
 	})
 
+	t.Run("Password violates policy", func(t *testing.T) {
+		mockUserService := new(mocks2.MockUserService)
+
+		rr := httptest.NewRecorder()
+
+		router := gin.Default()
+
+		NewHandler(&Config{
+			Router:      router,
+			UserService: mockUserService,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"email":    "bob.smith@bob.com",
+			"password": "bobsmith2024",
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/signup", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		var res struct {
+			InvalidArgs []invalidArgument `json:"invalidArgs"`
+		}
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Contains(t, res.InvalidArgs, invalidArgument{Field: "Password", Tag: "email"})
+		assert.NotContains(t, rr.Body.String(), "bobsmith2024")
+		mockUserService.AssertNotCalled(t, "Signup")
+	})
+
+	t.Run("Password policy of the application", func(t *testing.T) {
+		mockUserService := new(mocks2.MockUserService)
+
+		rr := httptest.NewRecorder()
+
+		router := gin.Default()
+
+		NewHandler(&Config{
+			Router:      router,
+			UserService: mockUserService,
+			AppSettings: model.AppSettings{
+				PasswordPolicy: &model.PasswordPolicy{
+					MinLength:     12,
+					RequireDigit:  true,
+					RequireSymbol: true,
+				},
+			},
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"email":    "bob@bob.com",
+			"password": "horsebattery",
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/signup", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		var res struct {
+			InvalidArgs []invalidArgument `json:"invalidArgs"`
+		}
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, []invalidArgument{
+			{Field: "Password", Tag: "digit"},
+			{Field: "Password", Tag: "symbol"},
+		}, res.InvalidArgs)
+		mockUserService.AssertNotCalled(t, "Signup")
+	})
+
+	t.Run("Breached password", func(t *testing.T) {
+		mockUserService := new(mocks2.MockUserService)
+		mockBreachedPasswordRepository := new(mocks2.MockBreachedPasswordRepository)
+		mockBreachedPasswordRepository.On("IsBreached", mock.AnythingOfType("*gin.Context"), "horse-battery-staple9").Return(true, nil)
+
+		rr := httptest.NewRecorder()
+
+		router := gin.Default()
+
+		NewHandler(&Config{
+			Router:                     router,
+			UserService:                mockUserService,
+			BreachedPasswordRepository: mockBreachedPasswordRepository,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"email":    "bob@bob.com",
+			"password": "horse-battery-staple9",
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/signup", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		var res struct {
+			InvalidArgs []invalidArgument `json:"invalidArgs"`
+		}
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, []invalidArgument{{Field: "Password", Tag: "breached"}}, res.InvalidArgs)
+		mockUserService.AssertNotCalled(t, "Signup")
+	})
+
+	t.Run("Breached passwords unavailable", func(t *testing.T) {
+		mockUserService := new(mocks2.MockUserService)
+		mockUserService.On("Signup", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("*model.User")).Return(nil)
+
+		mockTokenService := new(mocks2.MockTokenService)
+		mockTokenService.On("NewPairFromUser", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("*model.User"), "").Return(&model.TokenPair{}, nil)
+
+		mockBreachedPasswordRepository := new(mocks2.MockBreachedPasswordRepository)
+		mockBreachedPasswordRepository.On("IsBreached", mock.AnythingOfType("*gin.Context"), "horse-battery-staple9").Return(false, fmt.Errorf("connection refused"))
+
+		rr := httptest.NewRecorder()
+
+		router := gin.Default()
+
+		NewHandler(&Config{
+			Router:                     router,
+			UserService:                mockUserService,
+			TokenService:               mockTokenService,
+			BreachedPasswordRepository: mockBreachedPasswordRepository,
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"email":    "bob@bob.com",
+			"password": "horse-battery-staple9",
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/signup", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		mockUserService.AssertExpectations(t)
+	})
+
 	t.Run("Error returned from UserService", func(t *testing.T) {
 		u := &model.User{
 			Email:    "bob@bob.com",
-			Password: "Password123",
+			Password: "horse-battery-staple9",
 		}
 
 		// We just want this to show that it's not called in this case
@@ -195,7 +354,7 @@ func TestSignup(t *testing.T) {
 	t.Run("Successful Token Creation", func(t *testing.T) {
 		u := &model.User{
 			Email:    "bob@bob.com",
-			Password: "Password123",
+			Password: "horse-battery-staple9",
 		}
 
 		mockTokenResp := &model.TokenPair{
@@ -265,7 +424,7 @@ func TestSignup(t *testing.T) {
 	t.Run("Failed Token Creation", func(t *testing.T) {
 		u := &model.User{
 			Email:    "bob@bob.com",
-			Password: "Password123",
+			Password: "horse-battery-staple9",
 		}
 
 		mockErrorResponse := apperrors.NewInternal()
@@ -328,4 +487,107 @@ func TestSignup(t *testing.T) {
 		mockUserService.AssertExpectations(t)
 		mockTokenService.AssertExpectations(t)
 	})
+
+	t.Run("No tokens until email is verified", func(t *testing.T) {
+		u := &model.User{
+			Email:    "bob@bob.com",
+			Password: "horse-battery-staple9",
+		}
+
+		mockUserService := new(mocks2.MockUserService)
+		mockTokenService := new(mocks2.MockTokenService)
+
+		mockUserService.
+			On("Signup",
+				mock.AnythingOfType("*gin.Context"),
+				u,
+			).
+			Return(nil)
+
+		rr := httptest.NewRecorder()
+
+		router := gin.Default()
+
+		NewHandler(&Config{
+			Router:       router,
+			UserService:  mockUserService,
+			TokenService: mockTokenService,
+			AppSettings: model.AppSettings{
+				RequireVerifiedEmail: true,
+			},
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"email":    u.Email,
+			"password": u.Password,
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/signup", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		expectedRespBody, err := json.Marshal(gin.H{
+			"user": u,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, expectedRespBody, rr.Body.Bytes())
+
+		mockUserService.AssertExpectations(t)
+		mockTokenService.AssertNotCalled(t, "NewPairFromUser", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("No tokens until approved", func(t *testing.T) {
+		u := &model.User{
+			Email:    "bob@bob.com",
+			Password: "horse-battery-staple9",
+		}
+
+		mockUserService := new(mocks2.MockUserService)
+		mockTokenService := new(mocks2.MockTokenService)
+
+		mockUserService.
+			On("Signup", mock.AnythingOfType("*gin.Context"), u).
+			Run(func(args mock.Arguments) {
+				args.Get(1).(*model.User).Status = model.UserPending
+			}).
+			Return(nil)
+
+		rr := httptest.NewRecorder()
+
+		router := gin.Default()
+
+		NewHandler(&Config{
+			Router:       router,
+			UserService:  mockUserService,
+			TokenService: mockTokenService,
+			AppSettings: model.AppSettings{
+				RequireApproval: true,
+			},
+		})
+
+		reqBody, err := json.Marshal(gin.H{
+			"email":    u.Email,
+			"password": u.Password,
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/signup", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err)
+
+		request.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(rr, request)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"status":"pending"`)
+
+		mockUserService.AssertExpectations(t)
+		mockTokenService.AssertNotCalled(t, "NewPairFromUser", mock.Anything, mock.Anything, mock.Anything)
+	})
 }
@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// tokensReq is not exported
+type tokensReq struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+// Tokens handler exchanges a refresh token for a new pair of tokens,
+// as long as the user is still allowed to sign in
+func (h *Handler) Tokens(c *gin.Context) {
+	var req tokensReq
+
+	if ok := BindData(c, &req); !ok {
+		return
+	}
+
+	tokens, err := h.TokenService.Refresh(c, req.RefreshToken)
+	if err != nil {
+		respondError(c, "Failed to refresh tokens", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tokens": tokens,
+	})
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
+)
+
+func TestTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// setup returns a router refreshing tokens with mockTokenService
+	setup := func(mockTokenService *mocks.MockTokenService) *gin.Engine {
+		router := gin.Default()
+
+		NewHandler(&Config{
+			Router:       router,
+			UserService:  new(mocks.MockUserService),
+			TokenService: mockTokenService,
+		})
+
+		return router
+	}
+
+	post := func(router *gin.Engine, body gin.H) *httptest.ResponseRecorder {
+		reqBody, _ := json.Marshal(body)
+
+		request, _ := http.NewRequest(http.MethodPost, "/tokens", bytes.NewBuffer(reqBody))
+		request.Header.Set("Content-Type", "application/json")
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, request)
+
+		return rr
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		tokens := &model.TokenPair{AccessToken: "anAccessToken", RefreshToken: "aNewRefreshToken"}
+
+		mockTokenService := new(mocks.MockTokenService)
+		mockTokenService.On("Refresh", mock.AnythingOfType("*gin.Context"), "aRefreshToken").Return(tokens, nil)
+
+		rr := post(setup(mockTokenService), gin.H{"refreshToken": "aRefreshToken"})
+
+		expectedRespBody, _ := json.Marshal(gin.H{
+			"tokens": tokens,
+		})
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, expectedRespBody, rr.Body.Bytes())
+		mockTokenService.AssertExpectations(t)
+	})
+
+	t.Run("Refresh token required", func(t *testing.T) {
+		mockTokenService := new(mocks.MockTokenService)
+
+		rr := post(setup(mockTokenService), gin.H{})
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockTokenService.AssertNotCalled(t, "Refresh", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Disabled user", func(t *testing.T) {
+		mockTokenService := new(mocks.MockTokenService)
+		mockTokenService.On("Refresh", mock.AnythingOfType("*gin.Context"), "aRefreshToken").
+			Return(nil, apperrors.NewAuthorization("Account is disabled"))
+
+		rr := post(setup(mockTokenService), gin.H{"refreshToken": "aRefreshToken"})
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Contains(t, rr.Body.String(), "Account is disabled")
+	})
+}
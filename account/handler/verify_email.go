@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+type verifyEmailReq struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type resendVerificationReq struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// VerifyEmail handler confirms the email address a verification token was sent to
+func (h *Handler) VerifyEmail(c *gin.Context) {
+	var req verifyEmailReq
+
+	if ok := BindData(c, &req); !ok {
+		return
+	}
+
+	if err := h.UserService.VerifyEmail(c, req.Token); err != nil {
+		log.Printf("Failed to verify email: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "email verified successfully",
+	})
+}
+
+// ResendVerificationEmail handler sends a new verification email. The response is the
+// same whether or not an account exists for the email address
+func (h *Handler) ResendVerificationEmail(c *gin.Context) {
+	var req resendVerificationReq
+
+	if ok := BindData(c, &req); !ok {
+		return
+	}
+
+	if err := h.UserService.ResendVerificationEmail(c, req.Email); err != nil {
+		log.Printf("Failed to resend verification email: %v\n", err.Error())
+		c.JSON(apperrors.Status(err), gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "if the account exists and is not verified, a verification email has been sent",
+	})
+}
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/weslleyrsr/auth-engine/account/handler"
+	"github.com/weslleyrsr/auth-engine/account/mailer"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/repository"
+	"github.com/weslleyrsr/auth-engine/account/service"
+)
+
+// services holds the services main runs background work with, on top of serving the router
+type services struct {
+	AdminUserService  model.AdminUserService
+	DataExportService model.DataExportService
+}
+
+// inject initializes the repositories, services and handler layers from the data sources
+// and the environment, returning the router serving the api
+func inject(d *dataSources) (*gin.Engine, *services, error) {
+	log.Println("Injecting data sources")
+
+	/*
+	 * repository layer
+	 */
+	userRepository := repository.NewUserRepository(d.DB)
+	tokenRepository := repository.NewTokenRepository(d.DB)
+	applicationRepository := repository.NewApplicationRepository(d.DB)
+	verificationTokenRepository := repository.NewVerificationTokenRepository(d.DB)
+	passwordHistoryRepository := repository.NewPasswordHistoryRepository(d.DB)
+	loginAttemptRepository := repository.NewLoginAttemptRepository(d.DB)
+	roleRepository := repository.NewRoleRepository(d.DB)
+	organizationRepository := repository.NewOrganizationRepository(d.DB)
+	policyRepository := repository.NewPolicyRepository(d.DB)
+	personalAccessTokenRepository := repository.NewPersonalAccessTokenRepository(d.DB)
+	serviceAccountRepository := repository.NewServiceAccountRepository(d.DB)
+	auditRepository := repository.NewAuditRepository(d.DB)
+	dataExportRepository := repository.NewDataExportRepository(d.DB)
+
+	imageRepository, err := initImageRepository()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var breachedPasswordRepository model.BreachedPasswordRepository
+	if path := os.Getenv("BREACHED_PASSWORDS_FILE"); path != "" {
+		breachedPasswordRepository = repository.NewFileBreachedPasswordRepository(path)
+	} else if url := os.Getenv("BREACHED_PASSWORDS_API"); url != "" {
+		breachedPasswordRepository = repository.NewRangeBreachedPasswordRepository(url)
+	}
+
+	var rateLimiter model.RateLimiter
+	if d.RedisClient != nil {
+		rateLimiter = repository.NewRedisRateLimiter(d.RedisClient)
+	} else {
+		rateLimiter = repository.NewMemoryRateLimiter()
+	}
+
+	var m model.Mailer
+	if host := os.Getenv("SMTP_HOST"); host != "" {
+		m = mailer.NewSMTPMailer(&mailer.SMTPConfig{
+			Host:     host,
+			Port:     os.Getenv("SMTP_PORT"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("SMTP_FROM"),
+		})
+	} else {
+		log.Println("SMTP_HOST is not set, emails are logged instead of sent")
+		m = mailer.NewLogMailer()
+	}
+
+	/*
+	 * service layer
+	 */
+	privKey, pubKey, err := loadKeys()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refreshSecret := os.Getenv("REFRESH_SECRET")
+	if refreshSecret == "" {
+		return nil, nil, fmt.Errorf("REFRESH_SECRET is not set")
+	}
+
+	settings, err := loadAppSettings()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	appURL := os.Getenv("APP_URL")
+
+	userService := service.NewUserService(&service.USConfig{
+		UserRepository:              userRepository,
+		VerificationTokenRepository: verificationTokenRepository,
+		PasswordHistoryRepository:   passwordHistoryRepository,
+		ImageRepository:             imageRepository,
+		Mailer:                      m,
+		AuditRepository:             auditRepository,
+		AppSettings:                 settings,
+		AppURL:                      appURL,
+		AuditSecret:                 os.Getenv("AUDIT_SECRET"),
+	})
+
+	tokenService := service.NewTokenService(&service.TSConfig{
+		TokenRepository:               tokenRepository,
+		ApplicationRepository:         applicationRepository,
+		RoleRepository:                roleRepository,
+		PrivKey:                       privKey,
+		PubKey:                        pubKey,
+		RefreshSecret:                 refreshSecret,
+		AppSettings:                   settings,
+		PersonalAccessTokenRepository: personalAccessTokenRepository,
+		UserRepository:                userRepository,
+		AuditRepository:               auditRepository,
+		OrganizationRepository:        organizationRepository,
+	})
+
+	lockoutService := service.NewLockoutService(&service.LSConfig{
+		LoginAttemptRepository:      loginAttemptRepository,
+		UserRepository:              userRepository,
+		VerificationTokenRepository: verificationTokenRepository,
+		Mailer:                      m,
+		AppSettings:                 settings,
+		AppURL:                      appURL,
+	})
+
+	roleService := service.NewRoleService(&service.RSConfig{
+		RoleRepository:  roleRepository,
+		AuditRepository: auditRepository,
+	})
+
+	organizationService := service.NewOrganizationService(&service.OSConfig{
+		OrganizationRepository: organizationRepository,
+		Mailer:                 m,
+		AppURL:                 appURL,
+	})
+
+	policyService := service.NewPolicyService(&service.PSConfig{
+		PolicyRepository: policyRepository,
+		AuditRepository:  auditRepository,
+	})
+
+	personalAccessTokenService := service.NewPersonalAccessTokenService(&service.PATSConfig{
+		PersonalAccessTokenRepository: personalAccessTokenRepository,
+		AuditRepository:               auditRepository,
+	})
+
+	tokenAudience := os.Getenv("TOKEN_AUDIENCE")
+	if tokenAudience == "" {
+		return nil, nil, fmt.Errorf("TOKEN_AUDIENCE is not set")
+	}
+
+	serviceAccountService := service.NewServiceAccountService(&service.SASConfig{
+		ServiceAccountRepository: serviceAccountRepository,
+		AuditRepository:          auditRepository,
+		Audience:                 tokenAudience,
+	})
+
+	adminUserService := service.NewAdminUserService(&service.AUSConfig{
+		UserRepository:        userRepository,
+		TokenRepository:       tokenRepository,
+		AuditRepository:       auditRepository,
+		ApplicationRepository: applicationRepository,
+		LockoutService:        lockoutService,
+		AppSettings:           settings,
+	})
+
+	dataExportService := service.NewDataExportService(&service.DESConfig{
+		UserRepository:                userRepository,
+		TokenRepository:               tokenRepository,
+		RoleRepository:                roleRepository,
+		OrganizationRepository:        organizationRepository,
+		PersonalAccessTokenRepository: personalAccessTokenRepository,
+		AuditRepository:               auditRepository,
+		DataExportRepository:          dataExportRepository,
+	})
+
+	auditService := service.NewAuditService(&service.ASConfig{
+		AuditRepository: auditRepository,
+	})
+
+	/*
+	 * handler layer
+	 */
+	router := gin.Default()
+
+	// client ips are read from the forwarding headers set by the proxies in TRUSTED_PROXIES only,
+	// rate limits otherwise being counted per remote address
+	if err := router.SetTrustedProxies(trustedProxies()); err != nil {
+		return nil, nil, fmt.Errorf("could not parse TRUSTED_PROXIES: %w", err)
+	}
+
+	handler.NewHandler(&handler.Config{
+		Router:                     router,
+		UserService:                userService,
+		TokenService:               tokenService,
+		AppSettings:                settings,
+		BreachedPasswordRepository: breachedPasswordRepository,
+		LockoutService:             lockoutService,
+		RateLimiter:                rateLimiter,
+		RoleService:                roleService,
+		OrganizationService:        organizationService,
+		PolicyService:              policyService,
+		PersonalAccessTokenService: personalAccessTokenService,
+		ServiceAccountService:      serviceAccountService,
+		AdminUserService:           adminUserService,
+		DataExportService:          dataExportService,
+		AuditService:               auditService,
+		ApplicationRepository:      applicationRepository,
+	})
+
+	return router, &services{
+		AdminUserService:  adminUserService,
+		DataExportService: dataExportService,
+	}, nil
+}
+
+// trustedProxies returns the comma separated ips and cidrs of TRUSTED_PROXIES, nil trusting none
+func trustedProxies() []string {
+	v := os.Getenv("TRUSTED_PROXIES")
+	if v == "" {
+		return nil
+	}
+
+	proxies := []string{}
+	for _, p := range strings.Split(v, ",") {
+		proxies = append(proxies, strings.TrimSpace(p))
+	}
+
+	return proxies
+}
+
+// loadKeys reads the rsa key pair of the engine from the files at PRIV_KEY_FILE and PUB_KEY_FILE
+func loadKeys() (*rsa.PrivateKey, *rsa.PublicKey, error) {
+	priv, err := os.ReadFile(os.Getenv("PRIV_KEY_FILE"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read private key pem file: %w", err)
+	}
+
+	privKey, err := jwt.ParseRSAPrivateKeyFromPEM(priv)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not parse private key: %w", err)
+	}
+
+	pub, err := os.ReadFile(os.Getenv("PUB_KEY_FILE"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read public key pem file: %w", err)
+	}
+
+	pubKey, err := jwt.ParseRSAPublicKeyFromPEM(pub)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not parse public key: %w", err)
+	}
+
+	return privKey, pubKey, nil
+}
+
+// loadAppSettings reads the settings of the default application from the environment,
+// unset variables leaving their defaults
+func loadAppSettings() (model.AppSettings, error) {
+	settings := model.AppSettings{
+		RequireVerifiedEmail: os.Getenv("REQUIRE_VERIFIED_EMAIL") == "true",
+		RequireApproval:      os.Getenv("REQUIRE_APPROVAL") == "true",
+		AppURL:               os.Getenv("APP_URL"),
+	}
+
+	durations := map[string]*time.Duration{
+		"ID_TOKEN_EXP":           &settings.IDTokenExpiry,
+		"REFRESH_TOKEN_EXP":      &settings.RefreshTokenExpiry,
+		"MAX_PASSWORD_AGE":       &settings.MaxPasswordAge,
+		"DELETED_USER_RETENTION": &settings.DeletedUserRetention,
+	}
+
+	for name, d := range durations {
+		v := os.Getenv(name)
+		if v == "" {
+			continue
+		}
+
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return settings, fmt.Errorf("could not parse %s: %w", name, err)
+		}
+
+		*d = parsed
+	}
+
+	return settings, nil
+}
+
+// initImageRepository returns an S3 image repository when S3_BUCKET is set,
+// or one storing images in IMAGE_DIR otherwise
+func initImageRepository() (model.ImageRepository, error) {
+	if bucket := os.Getenv("S3_BUCKET"); bucket != "" {
+		return repository.NewS3ImageRepository(&repository.S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    bucket,
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			PublicURL: os.Getenv("S3_PUBLIC_URL"),
+		}), nil
+	}
+
+	dir := os.Getenv("IMAGE_DIR")
+	if dir == "" {
+		return nil, fmt.Errorf("IMAGE_DIR or S3_BUCKET must be set")
+	}
+
+	return repository.NewFSImageRepository(dir, os.Getenv("IMAGE_BASE_URL")), nil
+}
@@ -0,0 +1,24 @@
+package mailer
+
+import (
+	"context"
+	"log"
+
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// LogMailer is an implementation of the service layer Mailer
+// which only logs emails. Useful for development
+type LogMailer struct{}
+
+// NewLogMailer is a factory for initializing a Log Mailer
+func NewLogMailer() model.Mailer {
+	return &LogMailer{}
+}
+
+// Send logs the email instead of delivering it
+func (m *LogMailer) Send(ctx context.Context, to string, subject string, body string) error {
+	log.Printf("Email to: %v\nSubject: %v\n\n%v\n", to, subject, body)
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/smtp"
+	"strings"
+
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// SMTPMailer is an implementation of the service layer Mailer
+// which delivers emails through an SMTP server
+type SMTPMailer struct {
+	Addr string
+	Auth smtp.Auth
+	From string
+}
+
+// SMTPConfig holds the settings used to reach the SMTP server
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// NewSMTPMailer is a factory for initializing an SMTP Mailer
+func NewSMTPMailer(c *SMTPConfig) model.Mailer {
+	var auth smtp.Auth
+	if c.Username != "" {
+		auth = smtp.PlainAuth("", c.Username, c.Password, c.Host)
+	}
+
+	return &SMTPMailer{
+		Addr: fmt.Sprintf("%s:%s", c.Host, c.Port),
+		Auth: auth,
+		From: c.From,
+	}
+}
+
+// Send delivers a plain text email
+func (m *SMTPMailer) Send(ctx context.Context, to string, subject string, body string) error {
+	msg := strings.Join([]string{
+		fmt.Sprintf("From: %s", m.From),
+		fmt.Sprintf("To: %s", to),
+		fmt.Sprintf("Subject: %s", subject),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=\"utf-8\"",
+		"",
+		body,
+	}, "\r\n")
+
+	if err := smtp.SendMail(m.Addr, m.Auth, m.From, []string{to}, []byte(msg)); err != nil {
+		log.Printf("Could not send email with subject: %v. Reason: %v\n", subject, err)
+		return err
+	}
+
+	return nil
+}
@@ -2,8 +2,6 @@ package main
 
 import (
 	"context"
-	"github.com/weslleyrsr/auth-engine/account/handler"
-	"github.com/weslleyrsr/auth-engine/account/service"
 	"log"
 	"net/http"
 	"os"
@@ -17,21 +15,21 @@ import (
 func main() {
 	log.Println("Starting server...")
 
-	router := gin.Default()
-	//ur := repository.NewUserRepository()
-	us := service.NewUserService(&service.USConfig{})
+	ds, err := initDS()
+	if err != nil {
+		log.Fatalf("Unable to initialize data sources: %v\n", err)
+	}
+
+	router, s, err := inject(ds)
+	if err != nil {
+		log.Fatalf("Failure to inject data sources: %v\n", err)
+	}
 
 	g := router.Group("/")
 	g.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, struct{ Status string }{Status: "OK"})
 	})
 
-	handler.NewHandler(&handler.Config{
-		Router:      router,
-		UserService: us,
-		//UserRepository: ur
-	})
-
 	srv := &http.Server{
 		Addr:    ":8080",
 		Handler: router,
@@ -46,8 +44,12 @@ func main() {
 
 	log.Printf("Listening on port %v\n", srv.Addr)
 
+	// Users deleted longer ago than the retention of their application and expired data exports are purged periodically
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	go purge(purgeCtx, s, purgeInterval)
+
 	// Wait for kill signal of channel
-	quit := make(chan os.Signal)
+	quit := make(chan os.Signal, 1)
 
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
@@ -59,9 +61,43 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	stopPurge()
+
 	// Shutdown server
 	log.Println("Shutting down server...")
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatalf("Server forced to shutdown: %v\n", err)
 	}
+
+	if err := ds.close(); err != nil {
+		log.Fatalf("A problem occurred gracefully shutting down data sources: %v\n", err)
+	}
+}
+
+// purgeInterval is how often users deleted past their retention and expired data exports are purged
+const purgeInterval = time.Hour
+
+// purge purges the deleted users and the expired data exports every interval until ctx is done
+func purge(ctx context.Context, s *services, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := s.AdminUserService.PurgeDeleted(ctx); err != nil {
+				log.Printf("Failed to purge deleted users: %v\n", err)
+			} else if n > 0 {
+				log.Printf("Purged %d deleted users\n", n)
+			}
+
+			if n, err := s.DataExportService.PurgeExpired(ctx); err != nil {
+				log.Printf("Failed to purge expired data exports: %v\n", err)
+			} else if n > 0 {
+				log.Printf("Purged %d expired data exports\n", n)
+			}
+		}
+	}
 }
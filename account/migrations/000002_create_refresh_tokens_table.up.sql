@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    token_id VARCHAR PRIMARY KEY,
+    uid uuid NOT NULL REFERENCES users (uid) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS refresh_tokens_uid_idx ON refresh_tokens (uid);
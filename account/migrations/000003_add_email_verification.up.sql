@@ -0,0 +1,12 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS verification_tokens (
+    token_hash VARCHAR PRIMARY KEY,
+    uid uuid NOT NULL REFERENCES users (uid) ON DELETE CASCADE,
+    purpose VARCHAR NOT NULL,
+    email VARCHAR NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS verification_tokens_uid_purpose_idx ON verification_tokens (uid, purpose, created_at);
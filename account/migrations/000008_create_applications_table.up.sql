@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS applications (
+    id uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+    slug VARCHAR NOT NULL UNIQUE,
+    name VARCHAR NOT NULL DEFAULT '',
+    host VARCHAR NOT NULL DEFAULT '',
+    settings JSONB NOT NULL DEFAULT '{}',
+    private_key VARCHAR NOT NULL DEFAULT '',
+    refresh_secret VARCHAR NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS applications_host_idx ON applications (host) WHERE host <> '';
+
+-- the default application holds the users of requests not made to any application
+INSERT INTO applications (id, slug, name)
+VALUES ('00000000-0000-0000-0000-000000000000', 'default', 'Default')
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tenant_id uuid NOT NULL
+    DEFAULT '00000000-0000-0000-0000-000000000000' REFERENCES applications (id);
+
+-- emails are unique within each application
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+CREATE UNIQUE INDEX IF NOT EXISTS users_tenant_id_email_idx ON users (tenant_id, email);
@@ -0,0 +1,45 @@
+CREATE TABLE IF NOT EXISTS roles (
+    id uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+    tenant_id uuid NOT NULL REFERENCES applications (id) ON DELETE CASCADE,
+    name VARCHAR NOT NULL,
+    description VARCHAR NOT NULL DEFAULT '',
+    UNIQUE (tenant_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+    id uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+    tenant_id uuid NOT NULL REFERENCES applications (id) ON DELETE CASCADE,
+    name VARCHAR NOT NULL,
+    description VARCHAR NOT NULL DEFAULT '',
+    UNIQUE (tenant_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id uuid NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+    permission_id uuid NOT NULL REFERENCES permissions (id) ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    uid uuid NOT NULL REFERENCES users (uid) ON DELETE CASCADE,
+    role_id uuid NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+    PRIMARY KEY (uid, role_id)
+);
+
+CREATE INDEX IF NOT EXISTS user_roles_role_id_idx ON user_roles (role_id);
+
+-- an admin role of the default application allowed to manage roles, to be assigned to its first administrator
+INSERT INTO permissions (tenant_id, name, description) VALUES
+    ('00000000-0000-0000-0000-000000000000', 'account.roles.read', 'List roles, permissions and role assignments'),
+    ('00000000-0000-0000-0000-000000000000', 'account.roles.write', 'Manage roles, permissions and role assignments')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO roles (tenant_id, name, description)
+VALUES ('00000000-0000-0000-0000-000000000000', 'admin', 'Administrator of the application')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r JOIN permissions p ON p.tenant_id = r.tenant_id
+WHERE r.tenant_id = '00000000-0000-0000-0000-000000000000' AND r.name = 'admin'
+    AND p.name IN ('account.roles.read', 'account.roles.write')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS organizations (
+    id uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+    tenant_id uuid NOT NULL REFERENCES applications (id) ON DELETE CASCADE,
+    name VARCHAR NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS organizations_tenant_id_idx ON organizations (tenant_id);
+
+CREATE TABLE IF NOT EXISTS memberships (
+    org_id uuid NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    uid uuid NOT NULL REFERENCES users (uid) ON DELETE CASCADE,
+    role VARCHAR NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (org_id, uid)
+);
+
+CREATE INDEX IF NOT EXISTS memberships_uid_idx ON memberships (uid);
+
+CREATE TABLE IF NOT EXISTS invitations (
+    id uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+    org_id uuid NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    email VARCHAR NOT NULL,
+    role VARCHAR NOT NULL,
+    token_hash VARCHAR NOT NULL UNIQUE,
+    invited_by uuid NOT NULL REFERENCES users (uid) ON DELETE CASCADE,
+    status VARCHAR NOT NULL DEFAULT 'pending',
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS invitations_org_id_idx ON invitations (org_id, created_at);
@@ -0,0 +1,5 @@
+DELETE FROM permissions
+WHERE tenant_id = '00000000-0000-0000-0000-000000000000'
+    AND name IN ('account.policies.read', 'account.policies.write');
+
+DROP TABLE IF EXISTS policies;
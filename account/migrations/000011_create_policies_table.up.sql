@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS policies (
+    id uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+    tenant_id uuid NOT NULL REFERENCES applications (id) ON DELETE CASCADE,
+    name VARCHAR NOT NULL,
+    version INTEGER NOT NULL,
+    description VARCHAR NOT NULL DEFAULT '',
+    effect VARCHAR NOT NULL,
+    actions jsonb NOT NULL DEFAULT '[]',
+    expression TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (tenant_id, name, version)
+);
+
+-- allow the admin role of the default application to manage policies
+INSERT INTO permissions (tenant_id, name, description) VALUES
+    ('00000000-0000-0000-0000-000000000000', 'account.policies.read', 'List access policies and their versions'),
+    ('00000000-0000-0000-0000-000000000000', 'account.policies.write', 'Publish, restore and delete access policies')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r JOIN permissions p ON p.tenant_id = r.tenant_id
+WHERE r.tenant_id = '00000000-0000-0000-0000-000000000000' AND r.name = 'admin'
+    AND p.name IN ('account.policies.read', 'account.policies.write')
+ON CONFLICT DO NOTHING;
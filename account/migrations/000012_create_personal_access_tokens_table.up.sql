@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS personal_access_tokens (
+    id uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+    tenant_id uuid NOT NULL REFERENCES applications (id) ON DELETE CASCADE,
+    uid uuid NOT NULL REFERENCES users (uid) ON DELETE CASCADE,
+    name VARCHAR NOT NULL,
+    prefix VARCHAR NOT NULL,
+    token_hash VARCHAR NOT NULL UNIQUE,
+    scopes jsonb NOT NULL DEFAULT '[]',
+    expires_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    last_used_ip VARCHAR NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (uid, name)
+);
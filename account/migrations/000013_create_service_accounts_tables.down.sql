@@ -0,0 +1,7 @@
+DELETE FROM permissions
+WHERE tenant_id = '00000000-0000-0000-0000-000000000000'
+    AND name IN ('account.service_accounts.read', 'account.service_accounts.write');
+
+DROP TABLE IF EXISTS service_account_events;
+DROP TABLE IF EXISTS service_account_assertions;
+DROP TABLE IF EXISTS service_accounts;
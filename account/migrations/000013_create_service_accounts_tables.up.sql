@@ -0,0 +1,47 @@
+CREATE TABLE IF NOT EXISTS service_accounts (
+    id uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+    tenant_id uuid NOT NULL REFERENCES applications (id) ON DELETE CASCADE,
+    org_id uuid REFERENCES organizations (id) ON DELETE CASCADE,
+    name VARCHAR NOT NULL,
+    description VARCHAR NOT NULL DEFAULT '',
+    public_key TEXT NOT NULL,
+    scopes jsonb NOT NULL DEFAULT '[]',
+    created_by uuid NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS service_accounts_tenant_id_idx ON service_accounts (tenant_id, org_id);
+
+-- identifiers of the assertions service accounts authenticated with, refusing them a second time until they expire
+CREATE TABLE IF NOT EXISTS service_account_assertions (
+    service_account_id uuid NOT NULL REFERENCES service_accounts (id) ON DELETE CASCADE,
+    jti VARCHAR NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (service_account_id, jti)
+);
+
+-- the audit trail outlives the accounts it is about
+CREATE TABLE IF NOT EXISTS service_account_events (
+    id uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+    tenant_id uuid NOT NULL REFERENCES applications (id) ON DELETE CASCADE,
+    service_account_id uuid NOT NULL,
+    type VARCHAR NOT NULL,
+    actor uuid,
+    ip VARCHAR NOT NULL DEFAULT '',
+    detail VARCHAR NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS service_account_events_service_account_id_idx ON service_account_events (service_account_id, created_at);
+
+-- allow the admin role of the default application to manage service accounts
+INSERT INTO permissions (tenant_id, name, description) VALUES
+    ('00000000-0000-0000-0000-000000000000', 'account.service_accounts.read', 'List service accounts and their activity'),
+    ('00000000-0000-0000-0000-000000000000', 'account.service_accounts.write', 'Create, rotate the keys of and delete service accounts')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r JOIN permissions p ON p.tenant_id = r.tenant_id
+WHERE r.tenant_id = '00000000-0000-0000-0000-000000000000' AND r.name = 'admin'
+    AND p.name IN ('account.service_accounts.read', 'account.service_accounts.write')
+ON CONFLICT DO NOTHING;
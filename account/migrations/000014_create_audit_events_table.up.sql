@@ -0,0 +1,25 @@
+-- append only log of actions taken on users, outliving the users it is about
+CREATE TABLE IF NOT EXISTS audit_events (
+    id uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+    tenant_id uuid NOT NULL REFERENCES applications (id) ON DELETE CASCADE,
+    type VARCHAR NOT NULL,
+    actor uuid,
+    target uuid,
+    ip VARCHAR NOT NULL DEFAULT '',
+    detail VARCHAR NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS audit_events_tenant_id_idx ON audit_events (tenant_id, created_at);
+CREATE INDEX IF NOT EXISTS audit_events_target_idx ON audit_events (target, created_at);
+
+-- allow the admin role of the default application to impersonate users
+INSERT INTO permissions (tenant_id, name, description) VALUES
+    ('00000000-0000-0000-0000-000000000000', 'account.users.impersonate', 'Act as other users with short-lived access tokens')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r JOIN permissions p ON p.tenant_id = r.tenant_id
+WHERE r.tenant_id = '00000000-0000-0000-0000-000000000000' AND r.name = 'admin'
+    AND p.name = 'account.users.impersonate'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,9 @@
+-- what each service account may obtain by exchanging the access tokens of users
+CREATE TABLE IF NOT EXISTS token_exchange_policies (
+    service_account_id uuid PRIMARY KEY REFERENCES service_accounts (id) ON DELETE CASCADE,
+    tenant_id uuid NOT NULL REFERENCES applications (id) ON DELETE CASCADE,
+    audiences jsonb NOT NULL DEFAULT '[]',
+    scopes jsonb NOT NULL DEFAULT '[]',
+    max_expires_in INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,9 @@
+DELETE FROM permissions
+WHERE tenant_id = '00000000-0000-0000-0000-000000000000'
+    AND name IN ('account.users.read', 'account.users.write');
+
+DROP INDEX IF EXISTS users_tenant_id_created_at_idx;
+
+ALTER TABLE users DROP COLUMN IF EXISTS created_at;
+ALTER TABLE users DROP COLUMN IF EXISTS password_reset_required;
+ALTER TABLE users DROP COLUMN IF EXISTS status;
@@ -0,0 +1,17 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS status VARCHAR NOT NULL DEFAULT 'active';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_reset_required BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE INDEX IF NOT EXISTS users_tenant_id_created_at_idx ON users (tenant_id, created_at DESC);
+
+-- allow the admin role of the default application to manage users
+INSERT INTO permissions (tenant_id, name, description) VALUES
+    ('00000000-0000-0000-0000-000000000000', 'account.users.read', 'List, search and view users'),
+    ('00000000-0000-0000-0000-000000000000', 'account.users.write', 'Edit, disable, enable and delete users, force password resets and revoke sessions')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r JOIN permissions p ON p.tenant_id = r.tenant_id
+WHERE r.tenant_id = '00000000-0000-0000-0000-000000000000' AND r.name = 'admin'
+    AND p.name IN ('account.users.read', 'account.users.write')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,9 @@
+-- deleted users may share their email address with other users, they are purged right away
+DELETE FROM users WHERE status = 'deleted';
+
+DROP INDEX IF EXISTS users_tenant_id_deleted_idx;
+DROP INDEX IF EXISTS users_tenant_id_email_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS users_tenant_id_email_idx ON users (tenant_id, email);
+
+ALTER TABLE users DROP COLUMN IF EXISTS status_changed_at;
+ALTER TABLE users DROP COLUMN IF EXISTS status_reason;
@@ -0,0 +1,8 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS status_reason VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMPTZ;
+
+-- deleted users no longer hold on to their email address, which can be signed up with again
+DROP INDEX IF EXISTS users_tenant_id_email_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS users_tenant_id_email_idx ON users (tenant_id, email) WHERE status <> 'deleted';
+
+CREATE INDEX IF NOT EXISTS users_tenant_id_deleted_idx ON users (tenant_id, status_changed_at) WHERE status = 'deleted';
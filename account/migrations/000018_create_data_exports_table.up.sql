@@ -0,0 +1,13 @@
+-- archives of the data held about users, exported at their request
+CREATE TABLE IF NOT EXISTS data_exports (
+    id uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+    tenant_id uuid NOT NULL REFERENCES applications (id) ON DELETE CASCADE,
+    uid uuid NOT NULL REFERENCES users (uid) ON DELETE CASCADE,
+    status VARCHAR NOT NULL DEFAULT 'pending',
+    archive BYTEA,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    completed_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS data_exports_uid_idx ON data_exports (uid);
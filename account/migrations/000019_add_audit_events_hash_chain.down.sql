@@ -0,0 +1,13 @@
+DELETE FROM permissions
+WHERE tenant_id = '00000000-0000-0000-0000-000000000000' AND name = 'account.audit.read';
+
+DROP TRIGGER IF EXISTS audit_events_append_only ON audit_events;
+DROP FUNCTION IF EXISTS audit_events_append_only();
+
+DROP INDEX IF EXISTS audit_events_actor_idx;
+DROP INDEX IF EXISTS audit_events_tenant_id_seq_idx;
+
+ALTER TABLE audit_events DROP COLUMN IF EXISTS hash;
+ALTER TABLE audit_events DROP COLUMN IF EXISTS prev_hash;
+ALTER TABLE audit_events DROP COLUMN IF EXISTS user_agent;
+ALTER TABLE audit_events DROP COLUMN IF EXISTS seq;
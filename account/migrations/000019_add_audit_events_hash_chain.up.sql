@@ -0,0 +1,35 @@
+-- number the events of the audit log in the order they are chained in, existing events in the order they were stored.
+-- Events recorded before the chain have no hash, the first chained event of an application chaining to none
+ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS seq BIGSERIAL;
+ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS user_agent VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS prev_hash VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS hash VARCHAR NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX IF NOT EXISTS audit_events_tenant_id_seq_idx ON audit_events (tenant_id, seq);
+CREATE INDEX IF NOT EXISTS audit_events_actor_idx ON audit_events (actor, created_at);
+
+-- the audit log is append only, events are only deleted along with their application
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' AND NOT EXISTS (SELECT 1 FROM applications WHERE id = OLD.tenant_id) THEN
+        RETURN OLD;
+    END IF;
+
+    RAISE EXCEPTION 'audit_events is append only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_events_append_only ON audit_events;
+CREATE TRIGGER audit_events_append_only BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
+-- allow the admin role of the default application to query the audit log
+INSERT INTO permissions (tenant_id, name, description) VALUES
+    ('00000000-0000-0000-0000-000000000000', 'account.audit.read', 'Query the audit log and verify its hash chain')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r JOIN permissions p ON p.tenant_id = r.tenant_id
+WHERE r.tenant_id = '00000000-0000-0000-0000-000000000000' AND r.name = 'admin'
+    AND p.name = 'account.audit.read'
+ON CONFLICT DO NOTHING;
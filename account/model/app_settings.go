@@ -0,0 +1,116 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// defaultIDTokenExpiry and defaultRefreshTokenExpiry are used when token expiries are not set
+const (
+	defaultIDTokenExpiry      = 15 * time.Minute
+	defaultRefreshTokenExpiry = 3 * 24 * time.Hour
+)
+
+// defaultDeletedUserRetention is used when DeletedUserRetention is not set
+const defaultDeletedUserRetention = 30 * 24 * time.Hour
+
+// AppSettings holds behaviour of the engine which can be configured by each application
+type AppSettings struct {
+	// RequireVerifiedEmail blocks signin until the user has verified their email address.
+	// When false, tokens are issued with emailVerified set to false instead
+	RequireVerifiedEmail bool
+	// PasswordPolicy applies to passwords set on signup and password changes.
+	// DefaultPasswordPolicy is used when nil
+	PasswordPolicy *PasswordPolicy
+	// PasswordHistorySize is how many previous passwords of a user are retained and
+	// cannot be reused, on top of the current one. 0 only prevents reusing the current password
+	PasswordHistorySize int
+	// MaxPasswordAge forces users to choose a new password at signin once their
+	// password is older. 0 means passwords never expire
+	MaxPasswordAge time.Duration
+	// LockoutPolicy throttles failed signins. DefaultLockoutPolicy is used when nil
+	LockoutPolicy *LockoutPolicy
+	// IDTokenExpiry is how long id tokens are valid for, 15 minutes when 0
+	IDTokenExpiry time.Duration
+	// RefreshTokenExpiry is how long refresh tokens are valid for, 3 days when 0
+	RefreshTokenExpiry time.Duration
+	// RequireApproval keeps users pending once signed up, unable to sign in until an admin enables them
+	RequireApproval bool
+	// DeletedUserRetention is how long deleted users are kept, and can be restored by admins,
+	// before being purged for good. 30 days when 0
+	DeletedUserRetention time.Duration
+	// AppURL is the base url of the application's frontend links sent by email point to.
+	// The url the services are configured with is used when empty
+	AppURL string
+}
+
+// GetPasswordPolicy returns the configured PasswordPolicy or DefaultPasswordPolicy
+func (s AppSettings) GetPasswordPolicy() PasswordPolicy {
+	if s.PasswordPolicy == nil {
+		return DefaultPasswordPolicy
+	}
+
+	return *s.PasswordPolicy
+}
+
+// GetLockoutPolicy returns the configured LockoutPolicy or DefaultLockoutPolicy
+func (s AppSettings) GetLockoutPolicy() LockoutPolicy {
+	if s.LockoutPolicy == nil {
+		return DefaultLockoutPolicy
+	}
+
+	return *s.LockoutPolicy
+}
+
+// GetIDTokenExpiry returns the configured IDTokenExpiry or its default
+func (s AppSettings) GetIDTokenExpiry() time.Duration {
+	if s.IDTokenExpiry <= 0 {
+		return defaultIDTokenExpiry
+	}
+
+	return s.IDTokenExpiry
+}
+
+// GetRefreshTokenExpiry returns the configured RefreshTokenExpiry or its default
+func (s AppSettings) GetRefreshTokenExpiry() time.Duration {
+	if s.RefreshTokenExpiry <= 0 {
+		return defaultRefreshTokenExpiry
+	}
+
+	return s.RefreshTokenExpiry
+}
+
+// GetDeletedUserRetention returns the configured DeletedUserRetention or its default
+func (s AppSettings) GetDeletedUserRetention() time.Duration {
+	if s.DeletedUserRetention <= 0 {
+		return defaultDeletedUserRetention
+	}
+
+	return s.DeletedUserRetention
+}
+
+// Value stores the settings as json
+func (s AppSettings) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+// Scan reads settings stored as json
+func (s *AppSettings) Scan(src interface{}) error {
+	var b []byte
+
+	switch src := src.(type) {
+	case []byte:
+		b = src
+	case string:
+		b = []byte(src)
+	case nil:
+		*s = AppSettings{}
+		return nil
+	default:
+		return fmt.Errorf("unable to scan %T into AppSettings", src)
+	}
+
+	return json.Unmarshal(b, s)
+}
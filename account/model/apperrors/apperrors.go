@@ -3,7 +3,9 @@ package apperrors
 import (
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"time"
 )
 
 // Type holds a type string and integer code for the error
@@ -14,9 +16,12 @@ const (
 	Authorization        ErrorType = "AUTHORIZATION"          // Authentication Failures -
 	BadRequest           ErrorType = "BADREQUEST"             // Validation errors / BadInput
 	Conflict             ErrorType = "CONFLICT"               // Already exists (eg, create account with existent email) - 409
+	Forbidden            ErrorType = "FORBIDDEN"              // Authenticated but lacking a permission - 403
 	Internal             ErrorType = "INTERNAL"               // Server (500) and fallback errors
 	NotFound             ErrorType = "NOTFOUND"               // For not finding resource
+	PasswordExpired      ErrorType = "PASSWORD_EXPIRED"       // Valid credentials but the password must be changed - 403
 	PayloadTooLarge      ErrorType = "PAYLOADTOOLARGE"        // for uploading tons of JSON, or an image over the limit - 413
+	TooManyRequests      ErrorType = "TOO_MANY_REQUESTS"      // for exceeding a rate limit - 429
 	UnsupportedMediaType ErrorType = "UNSUPPORTED_MEDIA_TYPE" // for http 415
 )
 
@@ -26,6 +31,8 @@ const (
 type Error struct {
 	Type    ErrorType `json:"type"`
 	Message string    `json:"message"`
+	// RetryAfter is the number of seconds after which a rejected request may be retried
+	RetryAfter int `json:"retryAfter,omitempty"`
 }
 
 // Error satisfies standard error interface
@@ -46,12 +53,20 @@ func (e *Error) Status() int {
 		return http.StatusBadRequest
 	case Conflict:
 		return http.StatusConflict
+	case Forbidden:
+		return http.StatusForbidden
 	case Internal:
 		return http.StatusInternalServerError
 	case NotFound:
 		return http.StatusNotFound
+	case PasswordExpired:
+		return http.StatusForbidden
 	case PayloadTooLarge:
 		return http.StatusRequestEntityTooLarge
+	case TooManyRequests:
+		return http.StatusTooManyRequests
+	case UnsupportedMediaType:
+		return http.StatusUnsupportedMediaType
 	default:
 		return http.StatusInternalServerError
 	}
@@ -80,6 +95,17 @@ func NewAuthorization(reason string) *Error {
 	}
 }
 
+// NewLocked to create a 401 when signins are refused until retryAfter has elapsed
+func NewLocked(retryAfter time.Duration) *Error {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+
+	return &Error{
+		Type:       Authorization,
+		Message:    fmt.Sprintf("Too many failed signin attempts. Retry in %v seconds", seconds),
+		RetryAfter: seconds,
+	}
+}
+
 // NewBadRequest to create 400 errors (validation, for example)
 func NewBadRequest(reason string) *Error {
 	return &Error{
@@ -96,6 +122,22 @@ func NewConflict(name string, value string) *Error {
 	}
 }
 
+// NewOutdated to create a 409 error when a resource was modified after the client read it
+func NewOutdated(name string, value string) *Error {
+	return &Error{
+		Type:    Conflict,
+		Message: fmt.Sprintf("resource: %v with value: %v has been modified since it was read", name, value),
+	}
+}
+
+// NewForbidden to create a 403 error when a user is not allowed to perform an action
+func NewForbidden(reason string) *Error {
+	return &Error{
+		Type:    Forbidden,
+		Message: reason,
+	}
+}
+
 // NewInternal for 500 errors and unknown errors
 func NewInternal() *Error {
 	return &Error{
@@ -112,6 +154,14 @@ func NewNotFound(name string, value string) *Error {
 	}
 }
 
+// NewPasswordExpired to create a 403 error when a user has to choose a new password before signing in
+func NewPasswordExpired() *Error {
+	return &Error{
+		Type:    PasswordExpired,
+		Message: "Password has expired and must be changed",
+	}
+}
+
 // NewPayloadTooLarge to create an error for 413
 func NewPayloadTooLarge(maxBodySize int64, contentLength int64) *Error {
 	return &Error{
@@ -120,6 +170,17 @@ func NewPayloadTooLarge(maxBodySize int64, contentLength int64) *Error {
 	}
 }
 
+// NewTooManyRequests to create an error for 429 when a rate limit is exceeded until retryAfter has elapsed
+func NewTooManyRequests(retryAfter time.Duration) *Error {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+
+	return &Error{
+		Type:       TooManyRequests,
+		Message:    fmt.Sprintf("Rate limit exceeded. Retry in %v seconds", seconds),
+		RetryAfter: seconds,
+	}
+}
+
 // NewUnsupportedMediaType to create an error for 415
 func NewUnsupportedMediaType(reason string) *Error {
 	return &Error{
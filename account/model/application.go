@@ -0,0 +1,60 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultApplicationID identifies the application users belong to when a request is not made
+// to any application, which is how the engine behaves when only serving a single application
+var DefaultApplicationID = uuid.Nil
+
+// Application is a tenant of the engine. Users, signing keys and settings are kept per application
+type Application struct {
+	ID       uuid.UUID   `db:"id" json:"id"`
+	Slug     string      `db:"slug" json:"slug"`
+	Name     string      `db:"name" json:"name"`
+	Host     string      `db:"host" json:"host"`
+	Settings AppSettings `db:"settings" json:"settings"`
+	// PrivateKey is the PEM encoded RSA key signing id tokens of the application.
+	// The engine's own key is used when empty
+	PrivateKey string `db:"private_key" json:"-"`
+	// RefreshSecret signs refresh tokens of the application. The engine's own secret is used when empty
+	RefreshSecret string    `db:"refresh_secret" json:"-"`
+	CreatedAt     time.Time `db:"created_at" json:"createdAt"`
+}
+
+// ApplicationContextKey is the key the application a request is made to is stored under. It is a
+// string so it can also be set on the gin context handlers pass on to services as context.Context
+const ApplicationContextKey = "application"
+
+// NewApplicationContext returns a copy of ctx carrying the application a request is made to
+func NewApplicationContext(ctx context.Context, app *Application) context.Context {
+	return context.WithValue(ctx, ApplicationContextKey, app)
+}
+
+// ApplicationFromContext returns the application a request is made to, if any
+func ApplicationFromContext(ctx context.Context) (*Application, bool) {
+	app, ok := ctx.Value(ApplicationContextKey).(*Application)
+	return app, ok && app != nil
+}
+
+// ApplicationID returns the id of the application in ctx, or DefaultApplicationID when there is none
+func ApplicationID(ctx context.Context) uuid.UUID {
+	if app, ok := ApplicationFromContext(ctx); ok {
+		return app.ID
+	}
+
+	return DefaultApplicationID
+}
+
+// ApplicationSettings returns the settings of the application in ctx, or defaults when there is none
+func ApplicationSettings(ctx context.Context, defaults AppSettings) AppSettings {
+	if app, ok := ApplicationFromContext(ctx); ok {
+		return app.Settings
+	}
+
+	return defaults
+}
@@ -0,0 +1,121 @@
+package model
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditEventType names the kind of action an audit event records
+type AuditEventType string
+
+// Types of audit events
+const (
+	AuditUserImpersonated          AuditEventType = "user.impersonated"
+	AuditUserUpdated               AuditEventType = "user.updated"
+	AuditUserDisabled              AuditEventType = "user.disabled"
+	AuditUserEnabled               AuditEventType = "user.enabled"
+	AuditUserPasswordResetRequired AuditEventType = "user.password_reset_required"
+	AuditUserSessionsRevoked       AuditEventType = "user.sessions_revoked"
+	AuditUserUnlocked              AuditEventType = "user.unlocked"
+	AuditUserDeleted               AuditEventType = "user.deleted"
+	AuditUserRestored              AuditEventType = "user.restored"
+	AuditUserSignedIn              AuditEventType = "user.signed_in"
+	AuditUserSigninFailed          AuditEventType = "user.signin_failed"
+	AuditUserSignedUp              AuditEventType = "user.signed_up"
+	AuditUserSignedOut             AuditEventType = "user.signed_out"
+	AuditUserTokenRefreshed        AuditEventType = "user.token_refreshed"
+	AuditUserPasswordChanged       AuditEventType = "user.password_changed"
+	AuditUserEmailChanged          AuditEventType = "user.email_changed"
+	AuditUserEmailChangeReverted   AuditEventType = "user.email_change_reverted"
+	AuditUserRoleAssigned          AuditEventType = "user.role_assigned"
+	AuditUserRoleUnassigned        AuditEventType = "user.role_unassigned"
+	AuditUserTokenCreated          AuditEventType = "user.personal_access_token_created"
+	AuditUserTokenRevoked          AuditEventType = "user.personal_access_token_revoked"
+	AuditRoleCreated               AuditEventType = "role.created"
+	AuditRoleDeleted               AuditEventType = "role.deleted"
+	AuditRolePermissionGranted     AuditEventType = "role.permission_granted"
+	AuditRolePermissionRevoked     AuditEventType = "role.permission_revoked"
+	AuditPermissionCreated         AuditEventType = "permission.created"
+	AuditPermissionDeleted         AuditEventType = "permission.deleted"
+	AuditPolicyPublished           AuditEventType = "policy.published"
+	AuditPolicyRestored            AuditEventType = "policy.restored"
+	AuditPolicyDeleted             AuditEventType = "policy.deleted"
+)
+
+// AuditServiceAccountEvent returns the type of the audit event recording an event of a service account,
+// eg "service_account.authenticated"
+func AuditServiceAccountEvent(t ServiceAccountEventType) AuditEventType {
+	return AuditEventType("service_account." + string(t))
+}
+
+// AuditEvent is an entry of the audit log of an application, recording an actor acting on a target user
+// or service account. Events about the configuration of the application, such as its roles and policies,
+// have no target.
+// Detail is free form context of the action, such as the reason given for it.
+// The events of an application are chained in the order of Seq, the Hash of each covering the event
+// along with the Hash of the previous one, so altering or removing an event breaks the chain after it
+type AuditEvent struct {
+	ID        uuid.UUID      `db:"id" json:"id"`
+	TenantID  uuid.UUID      `db:"tenant_id" json:"-"`
+	Seq       int64          `db:"seq" json:"seq"`
+	Type      AuditEventType `db:"type" json:"type"`
+	Actor     *uuid.UUID     `db:"actor" json:"actor"`
+	Target    *uuid.UUID     `db:"target" json:"target"`
+	IP        string         `db:"ip" json:"ip"`
+	UserAgent string         `db:"user_agent" json:"userAgent"`
+	Detail    string         `db:"detail" json:"detail"`
+	CreatedAt time.Time      `db:"created_at" json:"createdAt"`
+	PrevHash  string         `db:"prev_hash" json:"prevHash"`
+	Hash      string         `db:"hash" json:"hash"`
+}
+
+// ComputeHash returns the hex encoded sha256 of the event chained to PrevHash, which is what Hash holds
+// for an untampered event. Seq is left out as it is assigned by the database once the event is hashed
+func (e *AuditEvent) ComputeHash() string {
+	// a struct is encoded with its fields in order, so the same event always hashes the same
+	content, _ := json.Marshal(struct {
+		PrevHash  string         `json:"prevHash"`
+		ID        uuid.UUID      `json:"id"`
+		TenantID  uuid.UUID      `json:"tenantId"`
+		Type      AuditEventType `json:"type"`
+		Actor     *uuid.UUID     `json:"actor"`
+		Target    *uuid.UUID     `json:"target"`
+		IP        string         `json:"ip"`
+		UserAgent string         `json:"userAgent"`
+		Detail    string         `json:"detail"`
+		CreatedAt string         `json:"createdAt"`
+	}{e.PrevHash, e.ID, e.TenantID, e.Type, e.Actor, e.Target, e.IP, e.UserAgent, e.Detail, e.CreatedAt.UTC().Format(time.RFC3339Nano)})
+
+	sum := sha256.Sum256(content)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// ClientContextKey is the key the client a request is made from is stored under, read when recording audit
+// events. Like ApplicationContextKey it is a string so it can be set on the gin context
+const ClientContextKey = "client"
+
+// Client identifies where a request is made from
+type Client struct {
+	IP        string
+	UserAgent string
+}
+
+// NewClientContext returns a copy of ctx carrying the client a request is made from
+func NewClientContext(ctx context.Context, c *Client) context.Context {
+	return context.WithValue(ctx, ClientContextKey, c)
+}
+
+// ClientFromContext returns the client a request is made from, which is empty when unknown
+func ClientFromContext(ctx context.Context) *Client {
+	if c, ok := ctx.Value(ClientContextKey).(*Client); ok && c != nil {
+		return c
+	}
+
+	return &Client{}
+}
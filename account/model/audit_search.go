@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PermissionReadAudit guards the admin routes querying the audit log of an application
+const PermissionReadAudit = "account.audit.read"
+
+// AuditFilter selects events of the audit log of an application, the most recent first.
+// Zero fields match every event
+type AuditFilter struct {
+	Type          AuditEventType
+	Actor         *uuid.UUID
+	Target        *uuid.UUID
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Limit         int
+	Offset        int
+}
+
+// AuditPage is a page of the audit events matching a filter, along with how many match it in total
+type AuditPage struct {
+	Events []*AuditEvent `json:"events"`
+	Total  int           `json:"total"`
+	Limit  int           `json:"limit"`
+	Offset int           `json:"offset"`
+}
+
+// AuditVerification is the outcome of checking the hash chain of the audit log of an application.
+// When the chain is broken BrokenAt is the first event which does not chain to the ones before it
+type AuditVerification struct {
+	Valid    bool        `json:"valid"`
+	Checked  int         `json:"checked"`
+	BrokenAt *AuditEvent `json:"brokenAt,omitempty"`
+}
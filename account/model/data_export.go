@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DataExportStatus tells whether the archive of a data export is ready
+type DataExportStatus string
+
+// "Set" of valid data export statuses
+const (
+	DataExportPending DataExportStatus = "pending"
+	DataExportReady   DataExportStatus = "ready"
+	DataExportFailed  DataExportStatus = "failed"
+)
+
+// DataExport is an archive of the data held about a user, requested by the user themselves. The archive
+// of large accounts is generated in the background, and kept until the export expires
+type DataExport struct {
+	ID          uuid.UUID        `db:"id" json:"id"`
+	TenantID    uuid.UUID        `db:"tenant_id" json:"-"`
+	UID         uuid.UUID        `db:"uid" json:"-"`
+	Status      DataExportStatus `db:"status" json:"status"`
+	Archive     []byte           `db:"archive" json:"-"` // json encoded UserData, once ready
+	CreatedAt   time.Time        `db:"created_at" json:"createdAt"`
+	CompletedAt *time.Time       `db:"completed_at" json:"completedAt,omitempty"`
+	ExpiresAt   time.Time        `db:"expires_at" json:"expiresAt"`
+}
+
+// UserData is the data held about a user, as archived by a DataExport
+type UserData struct {
+	ExportedAt           time.Time              `json:"exportedAt"`
+	Profile              *User                  `json:"profile"`
+	Sessions             []*Session             `json:"sessions"`
+	LoginHistory         []*AuditEvent          `json:"loginHistory"`
+	Roles                []*Role                `json:"roles"`
+	Organizations        []*Organization        `json:"organizations"`
+	Memberships          []*Membership          `json:"memberships"`
+	PersonalAccessTokens []*PersonalAccessToken `json:"personalAccessTokens"`
+	AuditEvents          []*AuditEvent          `json:"auditEvents"`
+}
+
+// Session is a signed in session of a user, held by a refresh token
+type Session struct {
+	ID        string    `db:"token_id" json:"id"`
+	ExpiresAt time.Time `db:"expires_at" json:"expiresAt"`
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// ImageVariants maps the size in pixels of a square variant of a profile image to its url
+type ImageVariants map[int]string
+
+// Value stores the variants as json
+func (v ImageVariants) Value() (driver.Value, error) {
+	if v == nil {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(v)
+}
+
+// Scan reads variants stored as json
+func (v *ImageVariants) Scan(src interface{}) error {
+	var b []byte
+
+	switch src := src.(type) {
+	case []byte:
+		b = src
+	case string:
+		b = []byte(src)
+	case nil:
+		*v = nil
+		return nil
+	default:
+		return fmt.Errorf("unable to scan %T into ImageVariants", src)
+	}
+
+	return json.Unmarshal(b, v)
+}
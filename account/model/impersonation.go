@@ -0,0 +1,14 @@
+package model
+
+import "github.com/google/uuid"
+
+// PermissionImpersonateUsers allows admins to be issued access tokens acting as other users of their application
+const PermissionImpersonateUsers = "account.users.impersonate"
+
+// Actor is the party acting on behalf of the subject of an access token, carried in its "act" claim
+// as defined by RFC 8693. Act is the actor the actor itself was acting for, if any
+type Actor struct {
+	Subject uuid.UUID `json:"sub"`
+	Email   string    `json:"email,omitempty"`
+	Act     *Actor    `json:"act,omitempty"`
+}
@@ -2,6 +2,9 @@ package model
 
 import (
 	"context"
+	"io"
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -9,15 +12,301 @@ import (
 type UserService interface {
 	Get(ctx context.Context, uid uuid.UUID) (*User, error)
 	Signup(ctx context.Context, u *User) error
+	Signin(ctx context.Context, u *User) error
+	UpdateDetails(ctx context.Context, uid uuid.UUID, version int, d *UserDetails) (*User, error)
+	SetProfileImage(ctx context.Context, uid uuid.UUID, image io.Reader) (*User, error)
+	ClearProfileImage(ctx context.Context, uid uuid.UUID) (*User, error)
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerificationEmail(ctx context.Context, email string) error
+	ChangePassword(ctx context.Context, uid uuid.UUID, currentPassword string, newPassword string) error
+	ResetExpiredPassword(ctx context.Context, u *User, newPassword string) error
+	RequestEmailChange(ctx context.Context, uid uuid.UUID, password string, newEmail string) error
+	ConfirmEmailChange(ctx context.Context, token string) (*User, error)
+	RevertEmailChange(ctx context.Context, token string) (*User, error)
+	DeleteAccount(ctx context.Context, uid uuid.UUID, password string) error
+	RestoreAccount(ctx context.Context, token string) (*User, error)
+}
+
+// LockoutService defines methods the handler layer expects to interact with
+// in order to throttle failed signins
+type LockoutService interface {
+	Check(ctx context.Context, email string, ip string) error
+	RecordFailure(ctx context.Context, email string, ip string) error
+	RecordSuccess(ctx context.Context, email string) error
+	Unlock(ctx context.Context, email string) error
+	UnlockWithToken(ctx context.Context, token string) error
+}
+
+// RoleService defines methods the handler layer expects to interact with
+// in order to manage the roles and permissions of an application
+type RoleService interface {
+	ListRoles(ctx context.Context) ([]*Role, error)
+	CreateRole(ctx context.Context, actor uuid.UUID, r *Role) error
+	DeleteRole(ctx context.Context, actor uuid.UUID, id uuid.UUID) error
+	ListPermissions(ctx context.Context) ([]*Permission, error)
+	CreatePermission(ctx context.Context, actor uuid.UUID, p *Permission) error
+	DeletePermission(ctx context.Context, actor uuid.UUID, id uuid.UUID) error
+	GrantPermission(ctx context.Context, actor uuid.UUID, roleID uuid.UUID, permissionID uuid.UUID) error
+	RevokePermission(ctx context.Context, actor uuid.UUID, roleID uuid.UUID, permissionID uuid.UUID) error
+	UserRoles(ctx context.Context, uid uuid.UUID) ([]*Role, error)
+	AssignRole(ctx context.Context, actor uuid.UUID, uid uuid.UUID, roleID uuid.UUID) error
+	UnassignRole(ctx context.Context, actor uuid.UUID, uid uuid.UUID, roleID uuid.UUID) error
+}
+
+// OrganizationService defines methods the handler layer expects to interact with
+// in order to manage organizations, their members and invitations
+type OrganizationService interface {
+	Create(ctx context.Context, uid uuid.UUID, org *Organization) error
+	ListForUser(ctx context.Context, uid uuid.UUID) ([]*Organization, error)
+	GetMembership(ctx context.Context, orgID uuid.UUID, uid uuid.UUID) (*Membership, error)
+	ListMembers(ctx context.Context, orgID uuid.UUID) ([]*Membership, error)
+	Invite(ctx context.Context, orgID uuid.UUID, inviter uuid.UUID, email string, role MembershipRole) (*Invitation, error)
+	AcceptInvitation(ctx context.Context, u *User, token string) (*Membership, error)
+	DeclineInvitation(ctx context.Context, token string) error
+}
+
+// PolicyService defines methods the handler layer expects to interact with
+// in order to manage the access policies of an application and evaluate requests against them
+type PolicyService interface {
+	List(ctx context.Context) ([]*Policy, error)
+	Versions(ctx context.Context, name string) ([]*Policy, error)
+	Publish(ctx context.Context, actor uuid.UUID, p *Policy) error
+	Restore(ctx context.Context, actor uuid.UUID, name string, version int) (*Policy, error)
+	Delete(ctx context.Context, actor uuid.UUID, name string) error
+	Authorize(ctx context.Context, req *AuthorizationRequest) (*AuthorizationDecision, error)
+	DryRun(ctx context.Context, candidate *Policy, req *AuthorizationRequest, at time.Time) (*AuthorizationDecision, error)
 }
 
 // TokenService defines methods the handler layers expects to interact with in regard to producing JWTs as string
 type TokenService interface {
 	NewPairFromUser(ctx context.Context, u *User, prevTokenID string) (*TokenPair, error)
+	ValidateIDToken(tokenString string) (*User, error)
+	RevokeOtherSessions(ctx context.Context, uid uuid.UUID, refreshTokenString string) error
+	Signout(ctx context.Context, uid uuid.UUID) error
+	ValidatePersonalAccessToken(ctx context.Context, token string, ip string) (*User, error)
+	NewServiceAccountToken(ctx context.Context, sa *ServiceAccount, scopes Names) (*OAuthToken, error)
+	NewImpersonationToken(ctx context.Context, actor *User, target *User, reason string, ip string) (*OAuthToken, error)
+	ExchangeToken(ctx context.Context, client *ServiceAccount, policy *TokenExchangePolicy, e *TokenExchange) (*OAuthToken, error)
+	Refresh(ctx context.Context, refreshTokenString string) (*TokenPair, error)
+	ValidateUserStatus(ctx context.Context, u *User) error
+}
+
+// ServiceAccountService defines methods the handler layer expects to interact with
+// in order to manage service accounts and authenticate them.
+// Methods taking an orgID are limited to the service accounts of that organization, unless it is uuid.Nil
+type ServiceAccountService interface {
+	List(ctx context.Context, orgID uuid.UUID) ([]*ServiceAccount, error)
+	Create(ctx context.Context, creator *User, sa *ServiceAccount) error
+	RotateKey(ctx context.Context, actor uuid.UUID, orgID uuid.UUID, id uuid.UUID, publicKey string) (*ServiceAccount, error)
+	Delete(ctx context.Context, actor uuid.UUID, orgID uuid.UUID, id uuid.UUID) error
+	Activity(ctx context.Context, orgID uuid.UUID, id uuid.UUID) ([]*ServiceAccountEvent, error)
+	Authenticate(ctx context.Context, assertion string, ip string) (*ServiceAccount, error)
+	ExchangePolicy(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*TokenExchangePolicy, error)
+	SetExchangePolicy(ctx context.Context, actor uuid.UUID, orgID uuid.UUID, p *TokenExchangePolicy) error
+	DeleteExchangePolicy(ctx context.Context, actor uuid.UUID, orgID uuid.UUID, id uuid.UUID) error
+}
+
+// AdminUserService defines methods the handler layer expects to interact with
+// in order to let admins manage the users of an application. Changes are recorded in the audit log
+// along with the admin making them
+type AdminUserService interface {
+	Search(ctx context.Context, f *UserFilter) (*UserPage, error)
+	Update(ctx context.Context, actor uuid.UUID, uid uuid.UUID, version int, d *UserDetails) (*User, error)
+	Disable(ctx context.Context, actor uuid.UUID, uid uuid.UUID, reason string) (*User, error)
+	Enable(ctx context.Context, actor uuid.UUID, uid uuid.UUID) (*User, error)
+	RequirePasswordReset(ctx context.Context, actor uuid.UUID, uid uuid.UUID) error
+	RevokeSessions(ctx context.Context, actor uuid.UUID, uid uuid.UUID) error
+	Unlock(ctx context.Context, actor uuid.UUID, uid uuid.UUID) error
+	Delete(ctx context.Context, actor uuid.UUID, uid uuid.UUID) error
+	PurgeDeleted(ctx context.Context) (int, error)
+}
+
+// PersonalAccessTokenService defines methods the handler layer expects to interact with
+// in order to manage the personal access tokens of users
+type PersonalAccessTokenService interface {
+	Create(ctx context.Context, u *User, t *PersonalAccessToken) (string, error)
+	List(ctx context.Context, uid uuid.UUID) ([]*PersonalAccessToken, error)
+	Revoke(ctx context.Context, uid uuid.UUID, id uuid.UUID) error
+}
+
+// AuditService defines methods the handler layer expects to interact with
+// in order to let admins query the audit log of an application and check it was not tampered with
+type AuditService interface {
+	Search(ctx context.Context, f *AuditFilter) (*AuditPage, error)
+	Verify(ctx context.Context) (*AuditVerification, error)
+}
+
+// DataExportService defines methods the handler layer expects to interact with
+// in order to export the data held about users to themselves
+type DataExportService interface {
+	Export(ctx context.Context, uid uuid.UUID) (*DataExport, error)
+	Get(ctx context.Context, uid uuid.UUID, id uuid.UUID) (*DataExport, error)
+	PurgeExpired(ctx context.Context) (int, error)
 }
 
 // UserRepository defined methods the service layer expects any repository it interacts with to implement
 type UserRepository interface {
 	FindByID(ctx context.Context, uid uuid.UUID) (*User, error)
+	FindByEmail(ctx context.Context, email string) (*User, error)
 	Create(ctx context.Context, u *User) error
+	Update(ctx context.Context, uid uuid.UUID, version int, d *UserDetails) (*User, error)
+	UpdatePassword(ctx context.Context, uid uuid.UUID, password string) error
+	UpdatePasswordHash(ctx context.Context, uid uuid.UUID, oldPassword string, password string) error
+	UpdateImage(ctx context.Context, uid uuid.UUID, imageURL string, variants ImageVariants) (*User, error)
+	SetEmailVerified(ctx context.Context, uid uuid.UUID, email string) error
+	UpdateEmail(ctx context.Context, uid uuid.UUID, email string) (*User, error)
+	Search(ctx context.Context, f *UserFilter) ([]*User, int, error)
+	SetStatus(ctx context.Context, uid uuid.UUID, status UserStatus, reason string) (*User, error)
+	RequirePasswordReset(ctx context.Context, uid uuid.UUID) error
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, error)
+}
+
+// ApplicationRepository defines methods the handler and service layers expect any repository they interact with to implement
+// in order to resolve the applications served by the engine
+type ApplicationRepository interface {
+	FindByID(ctx context.Context, id uuid.UUID) (*Application, error)
+	FindBySlug(ctx context.Context, slug string) (*Application, error)
+	FindByHost(ctx context.Context, host string) (*Application, error)
+	FindAll(ctx context.Context) ([]*Application, error)
+}
+
+// RoleRepository defines methods the service layer expects any repository it interacts with to implement
+// in order to store the roles and permissions of applications and the roles assigned to users
+type RoleRepository interface {
+	FindRoles(ctx context.Context) ([]*Role, error)
+	CreateRole(ctx context.Context, r *Role) error
+	DeleteRole(ctx context.Context, id uuid.UUID) error
+	FindPermissions(ctx context.Context) ([]*Permission, error)
+	CreatePermission(ctx context.Context, p *Permission) error
+	DeletePermission(ctx context.Context, id uuid.UUID) error
+	GrantPermission(ctx context.Context, roleID uuid.UUID, permissionID uuid.UUID) error
+	RevokePermission(ctx context.Context, roleID uuid.UUID, permissionID uuid.UUID) error
+	FindUserRoles(ctx context.Context, uid uuid.UUID) ([]*Role, error)
+	AssignRole(ctx context.Context, uid uuid.UUID, roleID uuid.UUID) error
+	UnassignRole(ctx context.Context, uid uuid.UUID, roleID uuid.UUID) error
+}
+
+// OrganizationRepository defines methods the service layer expects any repository it interacts with to implement
+// in order to store organizations, their members and invitations
+type OrganizationRepository interface {
+	Create(ctx context.Context, org *Organization, owner uuid.UUID) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Organization, error)
+	FindByUser(ctx context.Context, uid uuid.UUID) ([]*Organization, error)
+	FindMembership(ctx context.Context, orgID uuid.UUID, uid uuid.UUID) (*Membership, error)
+	FindMembers(ctx context.Context, orgID uuid.UUID) ([]*Membership, error)
+	AddMember(ctx context.Context, m *Membership) error
+	CreateInvitation(ctx context.Context, inv *Invitation) error
+	FindInvitation(ctx context.Context, tokenHash string) (*Invitation, error)
+	ResolveInvitation(ctx context.Context, id uuid.UUID, status InvitationStatus) error
+}
+
+// PolicyRepository defines methods the service layer expects any repository it interacts with to implement
+// in order to store the versions of the access policies of applications
+type PolicyRepository interface {
+	FindActive(ctx context.Context) ([]*Policy, error)
+	FindVersions(ctx context.Context, name string) ([]*Policy, error)
+	FindVersion(ctx context.Context, name string, version int) (*Policy, error)
+	Create(ctx context.Context, p *Policy) error
+	Delete(ctx context.Context, name string) error
+}
+
+// PersonalAccessTokenRepository defines methods the service layer expects any repository it interacts with to implement
+// in order to store the hashes of personal access tokens
+type PersonalAccessTokenRepository interface {
+	Create(ctx context.Context, t *PersonalAccessToken) error
+	FindByUser(ctx context.Context, uid uuid.UUID) ([]*PersonalAccessToken, error)
+	FindByHash(ctx context.Context, tokenHash string) (*PersonalAccessToken, error)
+	Delete(ctx context.Context, uid uuid.UUID, id uuid.UUID) error
+	Touch(ctx context.Context, id uuid.UUID, ip string, at time.Time) error
+}
+
+// ServiceAccountRepository defines methods the service layer expects any repository it interacts with to implement
+// in order to store service accounts, the assertions they used, their token exchange policies and their audit trail
+type ServiceAccountRepository interface {
+	Create(ctx context.Context, sa *ServiceAccount) error
+	FindByID(ctx context.Context, id uuid.UUID) (*ServiceAccount, error)
+	FindAll(ctx context.Context, orgID uuid.UUID) ([]*ServiceAccount, error)
+	UpdateKey(ctx context.Context, id uuid.UUID, publicKey string) (*ServiceAccount, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	UseAssertion(ctx context.Context, id uuid.UUID, jti string, expiresAt time.Time) error
+	AddEvent(ctx context.Context, e *ServiceAccountEvent) error
+	FindEvents(ctx context.Context, id uuid.UUID, limit int) ([]*ServiceAccountEvent, error)
+	FindExchangePolicy(ctx context.Context, id uuid.UUID) (*TokenExchangePolicy, error)
+	SetExchangePolicy(ctx context.Context, p *TokenExchangePolicy) error
+	DeleteExchangePolicy(ctx context.Context, id uuid.UUID) error
+}
+
+// AuditRepository defines methods the service layer expects any repository it interacts with to implement
+// in order to append to and query the audit log of applications
+type AuditRepository interface {
+	Create(ctx context.Context, e *AuditEvent) error
+	FindByUser(ctx context.Context, uid uuid.UUID, limit int) ([]*AuditEvent, error)
+	Search(ctx context.Context, f *AuditFilter) ([]*AuditEvent, int, error)
+	FindChain(ctx context.Context, afterSeq int64, limit int) ([]*AuditEvent, error)
+}
+
+// DataExportRepository defines methods the service layer expects any repository it interacts with to implement
+// in order to store the archives of data exports
+type DataExportRepository interface {
+	Create(ctx context.Context, e *DataExport) error
+	FindByID(ctx context.Context, uid uuid.UUID, id uuid.UUID) (*DataExport, error)
+	FindPending(ctx context.Context, uid uuid.UUID) (*DataExport, error)
+	Complete(ctx context.Context, id uuid.UUID, status DataExportStatus, archive []byte) error
+	DeleteExpired(ctx context.Context) (int, error)
+}
+
+// PasswordHistoryRepository defines methods the service layer expects any repository it interacts with to implement
+// in order to retain the previous password hashes of users
+type PasswordHistoryRepository interface {
+	Add(ctx context.Context, uid uuid.UUID, password string, keep int) error
+	FindRecent(ctx context.Context, uid uuid.UUID, limit int) ([]string, error)
+}
+
+// LoginAttemptRepository defines methods the service layer expects any repository it interacts with to implement
+// in order to count failed signins
+type LoginAttemptRepository interface {
+	Get(ctx context.Context, key string) (*LoginAttempts, error)
+	RecordFailure(ctx context.Context, key string, window time.Duration) (*LoginAttempts, error)
+	Reset(ctx context.Context, key string) error
+}
+
+// TokenRepository defines methods the service layer expects any repository it interacts with to implement
+// in order to keep track of issued refresh tokens
+type TokenRepository interface {
+	SetRefreshToken(ctx context.Context, uid uuid.UUID, tokenID string, expiresIn time.Duration) error
+	DeleteRefreshToken(ctx context.Context, uid uuid.UUID, prevTokenID string) error
+	DeleteUserRefreshTokens(ctx context.Context, uid uuid.UUID, keepTokenID string) error
+	FindUserRefreshTokens(ctx context.Context, uid uuid.UUID) ([]*Session, error)
+}
+
+// VerificationTokenRepository defines methods the service layer expects any repository it interacts with to implement
+// in order to store single use verification tokens
+type VerificationTokenRepository interface {
+	Create(ctx context.Context, t *VerificationToken) error
+	Consume(ctx context.Context, purpose VerificationPurpose, tokenHash string) (*VerificationToken, error)
+	FindLatest(ctx context.Context, uid uuid.UUID, purpose VerificationPurpose) (*VerificationToken, error)
+}
+
+// ImageRepository defines methods the service layer expects any object storage it interacts with to implement
+// in order to store user images
+type ImageRepository interface {
+	Upload(ctx context.Context, objName string, contentType string, image io.Reader) (string, error)
+	Delete(ctx context.Context, objName string) error
+}
+
+// Mailer defines methods the service layer expects any email provider it interacts with to implement
+type Mailer interface {
+	Send(ctx context.Context, to string, subject string, body string) error
+}
+
+// BreachedPasswordRepository defines methods the handler layer expects any corpus of
+// passwords exposed in data breaches it interacts with to implement
+type BreachedPasswordRepository interface {
+	IsBreached(ctx context.Context, password string) (bool, error)
+}
+
+// RateLimiter defines methods the handler layer expects any store of request counters it interacts with to implement
+// in order to limit how many requests are made for a key within a window
+type RateLimiter interface {
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (*RateLimitResult, error)
 }
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// LoginAttempts counts the consecutive failed signins of an account or client ip
+type LoginAttempts struct {
+	Key         string    `db:"key"`
+	Failures    int       `db:"failures"`
+	LastFailure time.Time `db:"last_failure"`
+}
+
+// LockoutPolicy defines how failed signins are throttled. Once DelayAfter consecutive
+// failures are reached, each further attempt has to wait BaseDelay, doubled after every
+// failure up to MaxDelay. Reaching MaxFailures locks the account for LockoutDuration
+type LockoutPolicy struct {
+	DelayAfter      int
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+	MaxFailures     int
+	LockoutDuration time.Duration
+	// Window after which failures are forgotten when no new failure happened
+	Window time.Duration
+	// IPDelayAfter and IPMaxFailures apply the same throttling to every signin coming from
+	// a client ip, against attacks spread over many accounts. They are higher as many users
+	// can share an ip
+	IPDelayAfter  int
+	IPMaxFailures int
+}
+
+// DefaultLockoutPolicy is used by applications which do not configure a LockoutPolicy
+var DefaultLockoutPolicy = LockoutPolicy{
+	DelayAfter:      3,
+	BaseDelay:       time.Second,
+	MaxDelay:        time.Minute,
+	MaxFailures:     10,
+	LockoutDuration: 15 * time.Minute,
+	Window:          time.Hour,
+	IPDelayAfter:    20,
+	IPMaxFailures:   100,
+}
@@ -0,0 +1,147 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// MockAdminUserService is a mock type for model.AdminUserService
+type MockAdminUserService struct {
+	mock.Mock
+}
+
+// Search is mock of AdminUserService Search
+func (m *MockAdminUserService) Search(ctx context.Context, f *model.UserFilter) (*model.UserPage, error) {
+	ret := m.Called(ctx, f)
+
+	var r0 *model.UserPage
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.UserPage)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// Update is mock of AdminUserService Update
+func (m *MockAdminUserService) Update(ctx context.Context, actor uuid.UUID, uid uuid.UUID, version int, d *model.UserDetails) (*model.User, error) {
+	ret := m.Called(ctx, actor, uid, version, d)
+
+	var r0 *model.User
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.User)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// Disable is mock of AdminUserService Disable
+func (m *MockAdminUserService) Disable(ctx context.Context, actor uuid.UUID, uid uuid.UUID, reason string) (*model.User, error) {
+	ret := m.Called(ctx, actor, uid, reason)
+
+	var r0 *model.User
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.User)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// Enable is mock of AdminUserService Enable
+func (m *MockAdminUserService) Enable(ctx context.Context, actor uuid.UUID, uid uuid.UUID) (*model.User, error) {
+	ret := m.Called(ctx, actor, uid)
+
+	var r0 *model.User
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.User)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// RequirePasswordReset is mock of AdminUserService RequirePasswordReset
+func (m *MockAdminUserService) RequirePasswordReset(ctx context.Context, actor uuid.UUID, uid uuid.UUID) error {
+	ret := m.Called(ctx, actor, uid)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// RevokeSessions is mock of AdminUserService RevokeSessions
+func (m *MockAdminUserService) RevokeSessions(ctx context.Context, actor uuid.UUID, uid uuid.UUID) error {
+	ret := m.Called(ctx, actor, uid)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// Unlock is mock of AdminUserService Unlock
+func (m *MockAdminUserService) Unlock(ctx context.Context, actor uuid.UUID, uid uuid.UUID) error {
+	ret := m.Called(ctx, actor, uid)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// Delete is mock of AdminUserService Delete
+func (m *MockAdminUserService) Delete(ctx context.Context, actor uuid.UUID, uid uuid.UUID) error {
+	ret := m.Called(ctx, actor, uid)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// PurgeDeleted is mock of AdminUserService PurgeDeleted
+func (m *MockAdminUserService) PurgeDeleted(ctx context.Context) (int, error) {
+	ret := m.Called(ctx)
+
+	var r0 int
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
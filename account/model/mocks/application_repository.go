@@ -0,0 +1,82 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// MockApplicationRepository is a mock type for model.ApplicationRepository
+type MockApplicationRepository struct {
+	mock.Mock
+}
+
+// FindByID is mock of ApplicationRepository FindByID
+func (m *MockApplicationRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Application, error) {
+	ret := m.Called(ctx, id)
+
+	var r0 *model.Application
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.Application)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// FindBySlug is mock of ApplicationRepository FindBySlug
+func (m *MockApplicationRepository) FindBySlug(ctx context.Context, slug string) (*model.Application, error) {
+	ret := m.Called(ctx, slug)
+
+	var r0 *model.Application
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.Application)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// FindByHost is mock of ApplicationRepository FindByHost
+func (m *MockApplicationRepository) FindByHost(ctx context.Context, host string) (*model.Application, error) {
+	ret := m.Called(ctx, host)
+
+	var r0 *model.Application
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.Application)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// FindAll is mock of ApplicationRepository FindAll
+func (m *MockApplicationRepository) FindAll(ctx context.Context) ([]*model.Application, error) {
+	ret := m.Called(ctx)
+
+	var r0 []*model.Application
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]*model.Application)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,82 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// MockAuditRepository is a mock type for model.AuditRepository
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+// Create is mock of AuditRepository Create
+func (m *MockAuditRepository) Create(ctx context.Context, e *model.AuditEvent) error {
+	ret := m.Called(ctx, e)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// FindByUser is mock of AuditRepository FindByUser
+func (m *MockAuditRepository) FindByUser(ctx context.Context, uid uuid.UUID, limit int) ([]*model.AuditEvent, error) {
+	ret := m.Called(ctx, uid, limit)
+
+	var r0 []*model.AuditEvent
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]*model.AuditEvent)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// Search is mock of AuditRepository Search
+func (m *MockAuditRepository) Search(ctx context.Context, f *model.AuditFilter) ([]*model.AuditEvent, int, error) {
+	ret := m.Called(ctx, f)
+
+	var r0 []*model.AuditEvent
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]*model.AuditEvent)
+	}
+
+	var r1 int
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if ret.Get(2) != nil {
+		r2 = ret.Get(2).(error)
+	}
+
+	return r0, r1, r2
+}
+
+// FindChain is mock of AuditRepository FindChain
+func (m *MockAuditRepository) FindChain(ctx context.Context, afterSeq int64, limit int) ([]*model.AuditEvent, error) {
+	ret := m.Called(ctx, afterSeq, limit)
+
+	var r0 []*model.AuditEvent
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]*model.AuditEvent)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,47 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// MockAuditService is a mock type for model.AuditService
+type MockAuditService struct {
+	mock.Mock
+}
+
+// Search is mock of AuditService Search
+func (m *MockAuditService) Search(ctx context.Context, f *model.AuditFilter) (*model.AuditPage, error) {
+	ret := m.Called(ctx, f)
+
+	var r0 *model.AuditPage
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.AuditPage)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// Verify is mock of AuditService Verify
+func (m *MockAuditService) Verify(ctx context.Context) (*model.AuditVerification, error) {
+	ret := m.Called(ctx)
+
+	var r0 *model.AuditVerification
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.AuditVerification)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
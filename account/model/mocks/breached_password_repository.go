@@ -0,0 +1,29 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockBreachedPasswordRepository is a mock type for model.BreachedPasswordRepository
+type MockBreachedPasswordRepository struct {
+	mock.Mock
+}
+
+// IsBreached is mock of BreachedPasswordRepository IsBreached
+func (m *MockBreachedPasswordRepository) IsBreached(ctx context.Context, password string) (bool, error) {
+	ret := m.Called(ctx, password)
+
+	var r0 bool
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,89 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// MockDataExportRepository is a mock type for model.DataExportRepository
+type MockDataExportRepository struct {
+	mock.Mock
+}
+
+// Create is mock of DataExportRepository Create
+func (m *MockDataExportRepository) Create(ctx context.Context, e *model.DataExport) error {
+	ret := m.Called(ctx, e)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// FindByID is mock of DataExportRepository FindByID
+func (m *MockDataExportRepository) FindByID(ctx context.Context, uid uuid.UUID, id uuid.UUID) (*model.DataExport, error) {
+	ret := m.Called(ctx, uid, id)
+
+	var r0 *model.DataExport
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.DataExport)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// FindPending is mock of DataExportRepository FindPending
+func (m *MockDataExportRepository) FindPending(ctx context.Context, uid uuid.UUID) (*model.DataExport, error) {
+	ret := m.Called(ctx, uid)
+
+	var r0 *model.DataExport
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.DataExport)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// Complete is mock of DataExportRepository Complete
+func (m *MockDataExportRepository) Complete(ctx context.Context, id uuid.UUID, status model.DataExportStatus, archive []byte) error {
+	ret := m.Called(ctx, id, status, archive)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// DeleteExpired is mock of DataExportRepository DeleteExpired
+func (m *MockDataExportRepository) DeleteExpired(ctx context.Context) (int, error) {
+	ret := m.Called(ctx)
+
+	var r0 int
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,65 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// MockDataExportService is a mock type for model.DataExportService
+type MockDataExportService struct {
+	mock.Mock
+}
+
+// Export is mock of DataExportService Export
+func (m *MockDataExportService) Export(ctx context.Context, uid uuid.UUID) (*model.DataExport, error) {
+	ret := m.Called(ctx, uid)
+
+	var r0 *model.DataExport
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.DataExport)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// Get is mock of DataExportService Get
+func (m *MockDataExportService) Get(ctx context.Context, uid uuid.UUID, id uuid.UUID) (*model.DataExport, error) {
+	ret := m.Called(ctx, uid, id)
+
+	var r0 *model.DataExport
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.DataExport)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// PurgeExpired is mock of DataExportService PurgeExpired
+func (m *MockDataExportService) PurgeExpired(ctx context.Context) (int, error) {
+	ret := m.Called(ctx)
+
+	var r0 int
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,42 @@
+package mocks
+
+import (
+	"context"
+	"io"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockImageRepository is a mock type for model.ImageRepository
+type MockImageRepository struct {
+	mock.Mock
+}
+
+// Upload is mock of ImageRepository Upload
+func (m *MockImageRepository) Upload(ctx context.Context, objName string, contentType string, image io.Reader) (string, error) {
+	ret := m.Called(ctx, objName, contentType, image)
+
+	var r0 string
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// Delete is mock of ImageRepository Delete
+func (m *MockImageRepository) Delete(ctx context.Context, objName string) error {
+	ret := m.Called(ctx, objName)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
@@ -0,0 +1,72 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockLockoutService is a mock type for model.LockoutService
+type MockLockoutService struct {
+	mock.Mock
+}
+
+// Check is mock of LockoutService Check
+func (m *MockLockoutService) Check(ctx context.Context, email string, ip string) error {
+	ret := m.Called(ctx, email, ip)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// RecordFailure is mock of LockoutService RecordFailure
+func (m *MockLockoutService) RecordFailure(ctx context.Context, email string, ip string) error {
+	ret := m.Called(ctx, email, ip)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// RecordSuccess is mock of LockoutService RecordSuccess
+func (m *MockLockoutService) RecordSuccess(ctx context.Context, email string) error {
+	ret := m.Called(ctx, email)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// Unlock is mock of LockoutService Unlock
+func (m *MockLockoutService) Unlock(ctx context.Context, email string) error {
+	ret := m.Called(ctx, email)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// UnlockWithToken is mock of LockoutService UnlockWithToken
+func (m *MockLockoutService) UnlockWithToken(ctx context.Context, token string) error {
+	ret := m.Called(ctx, token)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
@@ -0,0 +1,24 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockMailer is a mock type for model.Mailer
+type MockMailer struct {
+	mock.Mock
+}
+
+// Send is mock of Mailer Send
+func (m *MockMailer) Send(ctx context.Context, to string, subject string, body string) error {
+	ret := m.Called(ctx, to, subject, body)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
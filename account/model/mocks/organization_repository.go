@@ -0,0 +1,147 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// MockOrganizationRepository is a mock type for model.OrganizationRepository
+type MockOrganizationRepository struct {
+	mock.Mock
+}
+
+// Create is mock of OrganizationRepository Create
+func (m *MockOrganizationRepository) Create(ctx context.Context, org *model.Organization, owner uuid.UUID) error {
+	ret := m.Called(ctx, org, owner)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// FindByID is mock of OrganizationRepository FindByID
+func (m *MockOrganizationRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Organization, error) {
+	ret := m.Called(ctx, id)
+
+	var r0 *model.Organization
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.Organization)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// FindByUser is mock of OrganizationRepository FindByUser
+func (m *MockOrganizationRepository) FindByUser(ctx context.Context, uid uuid.UUID) ([]*model.Organization, error) {
+	ret := m.Called(ctx, uid)
+
+	var r0 []*model.Organization
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]*model.Organization)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// FindMembership is mock of OrganizationRepository FindMembership
+func (m *MockOrganizationRepository) FindMembership(ctx context.Context, orgID uuid.UUID, uid uuid.UUID) (*model.Membership, error) {
+	ret := m.Called(ctx, orgID, uid)
+
+	var r0 *model.Membership
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.Membership)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// FindMembers is mock of OrganizationRepository FindMembers
+func (m *MockOrganizationRepository) FindMembers(ctx context.Context, orgID uuid.UUID) ([]*model.Membership, error) {
+	ret := m.Called(ctx, orgID)
+
+	var r0 []*model.Membership
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]*model.Membership)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// AddMember is mock of OrganizationRepository AddMember
+func (m *MockOrganizationRepository) AddMember(ctx context.Context, membership *model.Membership) error {
+	ret := m.Called(ctx, membership)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// CreateInvitation is mock of OrganizationRepository CreateInvitation
+func (m *MockOrganizationRepository) CreateInvitation(ctx context.Context, inv *model.Invitation) error {
+	ret := m.Called(ctx, inv)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// FindInvitation is mock of OrganizationRepository FindInvitation
+func (m *MockOrganizationRepository) FindInvitation(ctx context.Context, tokenHash string) (*model.Invitation, error) {
+	ret := m.Called(ctx, tokenHash)
+
+	var r0 *model.Invitation
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.Invitation)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// ResolveInvitation is mock of OrganizationRepository ResolveInvitation
+func (m *MockOrganizationRepository) ResolveInvitation(ctx context.Context, id uuid.UUID, status model.InvitationStatus) error {
+	ret := m.Called(ctx, id, status)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
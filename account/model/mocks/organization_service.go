@@ -0,0 +1,123 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// MockOrganizationService is a mock type for model.OrganizationService
+type MockOrganizationService struct {
+	mock.Mock
+}
+
+// Create is mock of OrganizationService Create
+func (m *MockOrganizationService) Create(ctx context.Context, uid uuid.UUID, org *model.Organization) error {
+	ret := m.Called(ctx, uid, org)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// ListForUser is mock of OrganizationService ListForUser
+func (m *MockOrganizationService) ListForUser(ctx context.Context, uid uuid.UUID) ([]*model.Organization, error) {
+	ret := m.Called(ctx, uid)
+
+	var r0 []*model.Organization
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]*model.Organization)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// GetMembership is mock of OrganizationService GetMembership
+func (m *MockOrganizationService) GetMembership(ctx context.Context, orgID uuid.UUID, uid uuid.UUID) (*model.Membership, error) {
+	ret := m.Called(ctx, orgID, uid)
+
+	var r0 *model.Membership
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.Membership)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// ListMembers is mock of OrganizationService ListMembers
+func (m *MockOrganizationService) ListMembers(ctx context.Context, orgID uuid.UUID) ([]*model.Membership, error) {
+	ret := m.Called(ctx, orgID)
+
+	var r0 []*model.Membership
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]*model.Membership)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// Invite is mock of OrganizationService Invite
+func (m *MockOrganizationService) Invite(ctx context.Context, orgID uuid.UUID, inviter uuid.UUID, email string, role model.MembershipRole) (*model.Invitation, error) {
+	ret := m.Called(ctx, orgID, inviter, email, role)
+
+	var r0 *model.Invitation
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.Invitation)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// AcceptInvitation is mock of OrganizationService AcceptInvitation
+func (m *MockOrganizationService) AcceptInvitation(ctx context.Context, u *model.User, token string) (*model.Membership, error) {
+	ret := m.Called(ctx, u, token)
+
+	var r0 *model.Membership
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.Membership)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// DeclineInvitation is mock of OrganizationService DeclineInvitation
+func (m *MockOrganizationService) DeclineInvitation(ctx context.Context, token string) error {
+	ret := m.Called(ctx, token)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
@@ -0,0 +1,42 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPasswordHistoryRepository is a mock type for model.PasswordHistoryRepository
+type MockPasswordHistoryRepository struct {
+	mock.Mock
+}
+
+// Add is mock of PasswordHistoryRepository Add
+func (m *MockPasswordHistoryRepository) Add(ctx context.Context, uid uuid.UUID, password string, keep int) error {
+	ret := m.Called(ctx, uid, password, keep)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// FindRecent is mock of PasswordHistoryRepository FindRecent
+func (m *MockPasswordHistoryRepository) FindRecent(ctx context.Context, uid uuid.UUID, limit int) ([]string, error) {
+	ret := m.Called(ctx, uid, limit)
+
+	var r0 []string
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]string)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,85 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// MockPersonalAccessTokenRepository is a mock type for model.PersonalAccessTokenRepository
+type MockPersonalAccessTokenRepository struct {
+	mock.Mock
+}
+
+// Create is mock of PersonalAccessTokenRepository Create
+func (m *MockPersonalAccessTokenRepository) Create(ctx context.Context, t *model.PersonalAccessToken) error {
+	ret := m.Called(ctx, t)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// FindByUser is mock of PersonalAccessTokenRepository FindByUser
+func (m *MockPersonalAccessTokenRepository) FindByUser(ctx context.Context, uid uuid.UUID) ([]*model.PersonalAccessToken, error) {
+	ret := m.Called(ctx, uid)
+
+	var r0 []*model.PersonalAccessToken
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]*model.PersonalAccessToken)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// FindByHash is mock of PersonalAccessTokenRepository FindByHash
+func (m *MockPersonalAccessTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*model.PersonalAccessToken, error) {
+	ret := m.Called(ctx, tokenHash)
+
+	var r0 *model.PersonalAccessToken
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.PersonalAccessToken)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// Delete is mock of PersonalAccessTokenRepository Delete
+func (m *MockPersonalAccessTokenRepository) Delete(ctx context.Context, uid uuid.UUID, id uuid.UUID) error {
+	ret := m.Called(ctx, uid, id)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// Touch is mock of PersonalAccessTokenRepository Touch
+func (m *MockPersonalAccessTokenRepository) Touch(ctx context.Context, id uuid.UUID, ip string, at time.Time) error {
+	ret := m.Called(ctx, id, ip, at)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
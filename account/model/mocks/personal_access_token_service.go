@@ -0,0 +1,60 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// MockPersonalAccessTokenService is a mock type for model.PersonalAccessTokenService
+type MockPersonalAccessTokenService struct {
+	mock.Mock
+}
+
+// Create is mock of PersonalAccessTokenService Create
+func (m *MockPersonalAccessTokenService) Create(ctx context.Context, u *model.User, t *model.PersonalAccessToken) (string, error) {
+	ret := m.Called(ctx, u, t)
+
+	var r0 string
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// List is mock of PersonalAccessTokenService List
+func (m *MockPersonalAccessTokenService) List(ctx context.Context, uid uuid.UUID) ([]*model.PersonalAccessToken, error) {
+	ret := m.Called(ctx, uid)
+
+	var r0 []*model.PersonalAccessToken
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]*model.PersonalAccessToken)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// Revoke is mock of PersonalAccessTokenService Revoke
+func (m *MockPersonalAccessTokenService) Revoke(ctx context.Context, uid uuid.UUID, id uuid.UUID) error {
+	ret := m.Called(ctx, uid, id)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
@@ -0,0 +1,88 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// MockPolicyRepository is a mock type for model.PolicyRepository
+type MockPolicyRepository struct {
+	mock.Mock
+}
+
+// FindActive is mock of PolicyRepository FindActive
+func (m *MockPolicyRepository) FindActive(ctx context.Context) ([]*model.Policy, error) {
+	ret := m.Called(ctx)
+
+	var r0 []*model.Policy
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]*model.Policy)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// FindVersions is mock of PolicyRepository FindVersions
+func (m *MockPolicyRepository) FindVersions(ctx context.Context, name string) ([]*model.Policy, error) {
+	ret := m.Called(ctx, name)
+
+	var r0 []*model.Policy
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]*model.Policy)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// FindVersion is mock of PolicyRepository FindVersion
+func (m *MockPolicyRepository) FindVersion(ctx context.Context, name string, version int) (*model.Policy, error) {
+	ret := m.Called(ctx, name, version)
+
+	var r0 *model.Policy
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.Policy)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// Create is mock of PolicyRepository Create
+func (m *MockPolicyRepository) Create(ctx context.Context, p *model.Policy) error {
+	ret := m.Called(ctx, p)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// Delete is mock of PolicyRepository Delete
+func (m *MockPolicyRepository) Delete(ctx context.Context, name string) error {
+	ret := m.Called(ctx, name)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
@@ -0,0 +1,124 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// MockPolicyService is a mock type for model.PolicyService
+type MockPolicyService struct {
+	mock.Mock
+}
+
+// List is mock of PolicyService List
+func (m *MockPolicyService) List(ctx context.Context) ([]*model.Policy, error) {
+	ret := m.Called(ctx)
+
+	var r0 []*model.Policy
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]*model.Policy)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// Versions is mock of PolicyService Versions
+func (m *MockPolicyService) Versions(ctx context.Context, name string) ([]*model.Policy, error) {
+	ret := m.Called(ctx, name)
+
+	var r0 []*model.Policy
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]*model.Policy)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// Publish is mock of PolicyService Publish
+func (m *MockPolicyService) Publish(ctx context.Context, actor uuid.UUID, p *model.Policy) error {
+	ret := m.Called(ctx, actor, p)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// Restore is mock of PolicyService Restore
+func (m *MockPolicyService) Restore(ctx context.Context, actor uuid.UUID, name string, version int) (*model.Policy, error) {
+	ret := m.Called(ctx, actor, name, version)
+
+	var r0 *model.Policy
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.Policy)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// Delete is mock of PolicyService Delete
+func (m *MockPolicyService) Delete(ctx context.Context, actor uuid.UUID, name string) error {
+	ret := m.Called(ctx, actor, name)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// Authorize is mock of PolicyService Authorize
+func (m *MockPolicyService) Authorize(ctx context.Context, req *model.AuthorizationRequest) (*model.AuthorizationDecision, error) {
+	ret := m.Called(ctx, req)
+
+	var r0 *model.AuthorizationDecision
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.AuthorizationDecision)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// DryRun is mock of PolicyService DryRun
+func (m *MockPolicyService) DryRun(ctx context.Context, candidate *model.Policy, req *model.AuthorizationRequest, at time.Time) (*model.AuthorizationDecision, error) {
+	ret := m.Called(ctx, candidate, req, at)
+
+	var r0 *model.AuthorizationDecision
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.AuthorizationDecision)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,161 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// MockRoleRepository is a mock type for model.RoleRepository
+type MockRoleRepository struct {
+	mock.Mock
+}
+
+// FindRoles is mock of RoleRepository FindRoles
+func (m *MockRoleRepository) FindRoles(ctx context.Context) ([]*model.Role, error) {
+	ret := m.Called(ctx)
+
+	var r0 []*model.Role
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]*model.Role)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// CreateRole is mock of RoleRepository CreateRole
+func (m *MockRoleRepository) CreateRole(ctx context.Context, r *model.Role) error {
+	ret := m.Called(ctx, r)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// DeleteRole is mock of RoleRepository DeleteRole
+func (m *MockRoleRepository) DeleteRole(ctx context.Context, id uuid.UUID) error {
+	ret := m.Called(ctx, id)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// FindPermissions is mock of RoleRepository FindPermissions
+func (m *MockRoleRepository) FindPermissions(ctx context.Context) ([]*model.Permission, error) {
+	ret := m.Called(ctx)
+
+	var r0 []*model.Permission
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]*model.Permission)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// CreatePermission is mock of RoleRepository CreatePermission
+func (m *MockRoleRepository) CreatePermission(ctx context.Context, p *model.Permission) error {
+	ret := m.Called(ctx, p)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// DeletePermission is mock of RoleRepository DeletePermission
+func (m *MockRoleRepository) DeletePermission(ctx context.Context, id uuid.UUID) error {
+	ret := m.Called(ctx, id)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// GrantPermission is mock of RoleRepository GrantPermission
+func (m *MockRoleRepository) GrantPermission(ctx context.Context, roleID uuid.UUID, permissionID uuid.UUID) error {
+	ret := m.Called(ctx, roleID, permissionID)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// RevokePermission is mock of RoleRepository RevokePermission
+func (m *MockRoleRepository) RevokePermission(ctx context.Context, roleID uuid.UUID, permissionID uuid.UUID) error {
+	ret := m.Called(ctx, roleID, permissionID)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// FindUserRoles is mock of RoleRepository FindUserRoles
+func (m *MockRoleRepository) FindUserRoles(ctx context.Context, uid uuid.UUID) ([]*model.Role, error) {
+	ret := m.Called(ctx, uid)
+
+	var r0 []*model.Role
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]*model.Role)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// AssignRole is mock of RoleRepository AssignRole
+func (m *MockRoleRepository) AssignRole(ctx context.Context, uid uuid.UUID, roleID uuid.UUID) error {
+	ret := m.Called(ctx, uid, roleID)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// UnassignRole is mock of RoleRepository UnassignRole
+func (m *MockRoleRepository) UnassignRole(ctx context.Context, uid uuid.UUID, roleID uuid.UUID) error {
+	ret := m.Called(ctx, uid, roleID)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
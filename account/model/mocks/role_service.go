@@ -0,0 +1,161 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// MockRoleService is a mock type for model.RoleService
+type MockRoleService struct {
+	mock.Mock
+}
+
+// ListRoles is mock of RoleService ListRoles
+func (m *MockRoleService) ListRoles(ctx context.Context) ([]*model.Role, error) {
+	ret := m.Called(ctx)
+
+	var r0 []*model.Role
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]*model.Role)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// CreateRole is mock of RoleService CreateRole
+func (m *MockRoleService) CreateRole(ctx context.Context, actor uuid.UUID, r *model.Role) error {
+	ret := m.Called(ctx, actor, r)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// DeleteRole is mock of RoleService DeleteRole
+func (m *MockRoleService) DeleteRole(ctx context.Context, actor uuid.UUID, id uuid.UUID) error {
+	ret := m.Called(ctx, actor, id)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// ListPermissions is mock of RoleService ListPermissions
+func (m *MockRoleService) ListPermissions(ctx context.Context) ([]*model.Permission, error) {
+	ret := m.Called(ctx)
+
+	var r0 []*model.Permission
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]*model.Permission)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// CreatePermission is mock of RoleService CreatePermission
+func (m *MockRoleService) CreatePermission(ctx context.Context, actor uuid.UUID, p *model.Permission) error {
+	ret := m.Called(ctx, actor, p)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// DeletePermission is mock of RoleService DeletePermission
+func (m *MockRoleService) DeletePermission(ctx context.Context, actor uuid.UUID, id uuid.UUID) error {
+	ret := m.Called(ctx, actor, id)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// GrantPermission is mock of RoleService GrantPermission
+func (m *MockRoleService) GrantPermission(ctx context.Context, actor uuid.UUID, roleID uuid.UUID, permissionID uuid.UUID) error {
+	ret := m.Called(ctx, actor, roleID, permissionID)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// RevokePermission is mock of RoleService RevokePermission
+func (m *MockRoleService) RevokePermission(ctx context.Context, actor uuid.UUID, roleID uuid.UUID, permissionID uuid.UUID) error {
+	ret := m.Called(ctx, actor, roleID, permissionID)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// UserRoles is mock of RoleService UserRoles
+func (m *MockRoleService) UserRoles(ctx context.Context, uid uuid.UUID) ([]*model.Role, error) {
+	ret := m.Called(ctx, uid)
+
+	var r0 []*model.Role
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]*model.Role)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// AssignRole is mock of RoleService AssignRole
+func (m *MockRoleService) AssignRole(ctx context.Context, actor uuid.UUID, uid uuid.UUID, roleID uuid.UUID) error {
+	ret := m.Called(ctx, actor, uid, roleID)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// UnassignRole is mock of RoleService UnassignRole
+func (m *MockRoleService) UnassignRole(ctx context.Context, actor uuid.UUID, uid uuid.UUID, roleID uuid.UUID) error {
+	ret := m.Called(ctx, actor, uid, roleID)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
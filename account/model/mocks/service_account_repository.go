@@ -0,0 +1,172 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// MockServiceAccountRepository is a mock type for model.ServiceAccountRepository
+type MockServiceAccountRepository struct {
+	mock.Mock
+}
+
+// Create is mock of ServiceAccountRepository Create
+func (m *MockServiceAccountRepository) Create(ctx context.Context, sa *model.ServiceAccount) error {
+	ret := m.Called(ctx, sa)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// FindByID is mock of ServiceAccountRepository FindByID
+func (m *MockServiceAccountRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.ServiceAccount, error) {
+	ret := m.Called(ctx, id)
+
+	var r0 *model.ServiceAccount
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.ServiceAccount)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// FindAll is mock of ServiceAccountRepository FindAll
+func (m *MockServiceAccountRepository) FindAll(ctx context.Context, orgID uuid.UUID) ([]*model.ServiceAccount, error) {
+	ret := m.Called(ctx, orgID)
+
+	var r0 []*model.ServiceAccount
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]*model.ServiceAccount)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// UpdateKey is mock of ServiceAccountRepository UpdateKey
+func (m *MockServiceAccountRepository) UpdateKey(ctx context.Context, id uuid.UUID, publicKey string) (*model.ServiceAccount, error) {
+	ret := m.Called(ctx, id, publicKey)
+
+	var r0 *model.ServiceAccount
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.ServiceAccount)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// Delete is mock of ServiceAccountRepository Delete
+func (m *MockServiceAccountRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	ret := m.Called(ctx, id)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// UseAssertion is mock of ServiceAccountRepository UseAssertion
+func (m *MockServiceAccountRepository) UseAssertion(ctx context.Context, id uuid.UUID, jti string, expiresAt time.Time) error {
+	ret := m.Called(ctx, id, jti, expiresAt)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// AddEvent is mock of ServiceAccountRepository AddEvent
+func (m *MockServiceAccountRepository) AddEvent(ctx context.Context, e *model.ServiceAccountEvent) error {
+	ret := m.Called(ctx, e)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// FindEvents is mock of ServiceAccountRepository FindEvents
+func (m *MockServiceAccountRepository) FindEvents(ctx context.Context, id uuid.UUID, limit int) ([]*model.ServiceAccountEvent, error) {
+	ret := m.Called(ctx, id, limit)
+
+	var r0 []*model.ServiceAccountEvent
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]*model.ServiceAccountEvent)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// FindExchangePolicy is mock of ServiceAccountRepository FindExchangePolicy
+func (m *MockServiceAccountRepository) FindExchangePolicy(ctx context.Context, id uuid.UUID) (*model.TokenExchangePolicy, error) {
+	ret := m.Called(ctx, id)
+
+	var r0 *model.TokenExchangePolicy
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.TokenExchangePolicy)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// SetExchangePolicy is mock of ServiceAccountRepository SetExchangePolicy
+func (m *MockServiceAccountRepository) SetExchangePolicy(ctx context.Context, p *model.TokenExchangePolicy) error {
+	ret := m.Called(ctx, p)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// DeleteExchangePolicy is mock of ServiceAccountRepository DeleteExchangePolicy
+func (m *MockServiceAccountRepository) DeleteExchangePolicy(ctx context.Context, id uuid.UUID) error {
+	ret := m.Called(ctx, id)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
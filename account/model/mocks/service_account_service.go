@@ -0,0 +1,147 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// MockServiceAccountService is a mock type for model.ServiceAccountService
+type MockServiceAccountService struct {
+	mock.Mock
+}
+
+// List is mock of ServiceAccountService List
+func (m *MockServiceAccountService) List(ctx context.Context, orgID uuid.UUID) ([]*model.ServiceAccount, error) {
+	ret := m.Called(ctx, orgID)
+
+	var r0 []*model.ServiceAccount
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]*model.ServiceAccount)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// Create is mock of ServiceAccountService Create
+func (m *MockServiceAccountService) Create(ctx context.Context, creator *model.User, sa *model.ServiceAccount) error {
+	ret := m.Called(ctx, creator, sa)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// RotateKey is mock of ServiceAccountService RotateKey
+func (m *MockServiceAccountService) RotateKey(ctx context.Context, actor uuid.UUID, orgID uuid.UUID, id uuid.UUID, publicKey string) (*model.ServiceAccount, error) {
+	ret := m.Called(ctx, actor, orgID, id, publicKey)
+
+	var r0 *model.ServiceAccount
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.ServiceAccount)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// Delete is mock of ServiceAccountService Delete
+func (m *MockServiceAccountService) Delete(ctx context.Context, actor uuid.UUID, orgID uuid.UUID, id uuid.UUID) error {
+	ret := m.Called(ctx, actor, orgID, id)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// Activity is mock of ServiceAccountService Activity
+func (m *MockServiceAccountService) Activity(ctx context.Context, orgID uuid.UUID, id uuid.UUID) ([]*model.ServiceAccountEvent, error) {
+	ret := m.Called(ctx, orgID, id)
+
+	var r0 []*model.ServiceAccountEvent
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]*model.ServiceAccountEvent)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// Authenticate is mock of ServiceAccountService Authenticate
+func (m *MockServiceAccountService) Authenticate(ctx context.Context, assertion string, ip string) (*model.ServiceAccount, error) {
+	ret := m.Called(ctx, assertion, ip)
+
+	var r0 *model.ServiceAccount
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.ServiceAccount)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// ExchangePolicy is mock of ServiceAccountService ExchangePolicy
+func (m *MockServiceAccountService) ExchangePolicy(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*model.TokenExchangePolicy, error) {
+	ret := m.Called(ctx, orgID, id)
+
+	var r0 *model.TokenExchangePolicy
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.TokenExchangePolicy)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// SetExchangePolicy is mock of ServiceAccountService SetExchangePolicy
+func (m *MockServiceAccountService) SetExchangePolicy(ctx context.Context, actor uuid.UUID, orgID uuid.UUID, p *model.TokenExchangePolicy) error {
+	ret := m.Called(ctx, actor, orgID, p)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// DeleteExchangePolicy is mock of ServiceAccountService DeleteExchangePolicy
+func (m *MockServiceAccountService) DeleteExchangePolicy(ctx context.Context, actor uuid.UUID, orgID uuid.UUID, id uuid.UUID) error {
+	ret := m.Called(ctx, actor, orgID, id)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
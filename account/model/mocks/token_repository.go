@@ -0,0 +1,68 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// MockTokenRepository is a mock type for model.TokenRepository
+type MockTokenRepository struct {
+	mock.Mock
+}
+
+// SetRefreshToken is a mock of TokenRepository SetRefreshToken
+func (m *MockTokenRepository) SetRefreshToken(ctx context.Context, uid uuid.UUID, tokenID string, expiresIn time.Duration) error {
+	ret := m.Called(ctx, uid, tokenID, expiresIn)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// DeleteRefreshToken is a mock of TokenRepository DeleteRefreshToken
+func (m *MockTokenRepository) DeleteRefreshToken(ctx context.Context, uid uuid.UUID, prevTokenID string) error {
+	ret := m.Called(ctx, uid, prevTokenID)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// DeleteUserRefreshTokens is a mock of TokenRepository DeleteUserRefreshTokens
+func (m *MockTokenRepository) DeleteUserRefreshTokens(ctx context.Context, uid uuid.UUID, keepTokenID string) error {
+	ret := m.Called(ctx, uid, keepTokenID)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// FindUserRefreshTokens is a mock of TokenRepository FindUserRefreshTokens
+func (m *MockTokenRepository) FindUserRefreshTokens(ctx context.Context, uid uuid.UUID) ([]*model.Session, error) {
+	ret := m.Called(ctx, uid)
+
+	var r0 []*model.Session
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]*model.Session)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 	"github.com/weslleyrsr/auth-engine/account/model"
 )
@@ -29,3 +30,143 @@ func (m *MockTokenService) NewPairFromUser(ctx context.Context, u *model.User, p
 
 	return r0, r1
 }
+
+// ValidateIDToken mocks concrete ValidateIDToken
+func (m *MockTokenService) ValidateIDToken(tokenString string) (*model.User, error) {
+	ret := m.Called(tokenString)
+
+	// first value passed to "Return"
+	var r0 *model.User
+	if ret.Get(0) != nil {
+		// we can just return this if we know we won't be passing function to "Return"
+		r0 = ret.Get(0).(*model.User)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// RevokeOtherSessions mocks concrete RevokeOtherSessions
+func (m *MockTokenService) RevokeOtherSessions(ctx context.Context, uid uuid.UUID, refreshTokenString string) error {
+	ret := m.Called(ctx, uid, refreshTokenString)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// Signout mocks concrete Signout
+func (m *MockTokenService) Signout(ctx context.Context, uid uuid.UUID) error {
+	ret := m.Called(ctx, uid)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// ValidatePersonalAccessToken mocks concrete ValidatePersonalAccessToken
+func (m *MockTokenService) ValidatePersonalAccessToken(ctx context.Context, token string, ip string) (*model.User, error) {
+	ret := m.Called(ctx, token, ip)
+
+	var r0 *model.User
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.User)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// NewServiceAccountToken mocks concrete NewServiceAccountToken
+func (m *MockTokenService) NewServiceAccountToken(ctx context.Context, sa *model.ServiceAccount, scopes model.Names) (*model.OAuthToken, error) {
+	ret := m.Called(ctx, sa, scopes)
+
+	var r0 *model.OAuthToken
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.OAuthToken)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// NewImpersonationToken mocks concrete NewImpersonationToken
+func (m *MockTokenService) NewImpersonationToken(ctx context.Context, actor *model.User, target *model.User, reason string, ip string) (*model.OAuthToken, error) {
+	ret := m.Called(ctx, actor, target, reason, ip)
+
+	var r0 *model.OAuthToken
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.OAuthToken)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// ExchangeToken mocks concrete ExchangeToken
+func (m *MockTokenService) ExchangeToken(ctx context.Context, client *model.ServiceAccount, policy *model.TokenExchangePolicy, e *model.TokenExchange) (*model.OAuthToken, error) {
+	ret := m.Called(ctx, client, policy, e)
+
+	var r0 *model.OAuthToken
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.OAuthToken)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// Refresh mocks concrete Refresh
+func (m *MockTokenService) Refresh(ctx context.Context, refreshTokenString string) (*model.TokenPair, error) {
+	ret := m.Called(ctx, refreshTokenString)
+
+	var r0 *model.TokenPair
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.TokenPair)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// ValidateUserStatus mocks concrete ValidateUserStatus
+func (m *MockTokenService) ValidateUserStatus(ctx context.Context, u *model.User) error {
+	ret := m.Called(ctx, u)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
@@ -60,3 +61,161 @@ func (m *MockUserRepository) FindByEmail(ctx context.Context, email string) (*mo
 
 	return r0, r1
 }
+
+// UpdatePassword is mock of UserRepository UpdatePassword
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, uid uuid.UUID, password string) error {
+	ret := m.Called(ctx, uid, password)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// UpdatePasswordHash is mock of UserRepository UpdatePasswordHash
+func (m *MockUserRepository) UpdatePasswordHash(ctx context.Context, uid uuid.UUID, oldPassword string, password string) error {
+	ret := m.Called(ctx, uid, oldPassword, password)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// SetEmailVerified is mock of UserRepository SetEmailVerified
+func (m *MockUserRepository) SetEmailVerified(ctx context.Context, uid uuid.UUID, email string) error {
+	ret := m.Called(ctx, uid, email)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// UpdateEmail is mock of UserRepository UpdateEmail
+func (m *MockUserRepository) UpdateEmail(ctx context.Context, uid uuid.UUID, email string) (*model.User, error) {
+	ret := m.Called(ctx, uid, email)
+
+	var r0 *model.User
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.User)
+	}
+
+	var r1 error
+
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// Update is mock of UserRepository Update
+func (m *MockUserRepository) Update(ctx context.Context, uid uuid.UUID, version int, d *model.UserDetails) (*model.User, error) {
+	ret := m.Called(ctx, uid, version, d)
+
+	var r0 *model.User
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.User)
+	}
+
+	var r1 error
+
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// UpdateImage is mock of UserRepository UpdateImage
+func (m *MockUserRepository) UpdateImage(ctx context.Context, uid uuid.UUID, imageURL string, variants model.ImageVariants) (*model.User, error) {
+	ret := m.Called(ctx, uid, imageURL, variants)
+
+	var r0 *model.User
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.User)
+	}
+
+	var r1 error
+
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// Search is mock of UserRepository Search
+func (m *MockUserRepository) Search(ctx context.Context, f *model.UserFilter) ([]*model.User, int, error) {
+	ret := m.Called(ctx, f)
+
+	var r0 []*model.User
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]*model.User)
+	}
+
+	var r1 int
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if ret.Get(2) != nil {
+		r2 = ret.Get(2).(error)
+	}
+
+	return r0, r1, r2
+}
+
+// SetStatus is mock of UserRepository SetStatus
+func (m *MockUserRepository) SetStatus(ctx context.Context, uid uuid.UUID, status model.UserStatus, reason string) (*model.User, error) {
+	ret := m.Called(ctx, uid, status, reason)
+
+	var r0 *model.User
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.User)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// RequirePasswordReset is mock of UserRepository RequirePasswordReset
+func (m *MockUserRepository) RequirePasswordReset(ctx context.Context, uid uuid.UUID) error {
+	ret := m.Called(ctx, uid)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// PurgeDeleted is mock of UserRepository PurgeDeleted
+func (m *MockUserRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, error) {
+	ret := m.Called(ctx, deletedBefore)
+
+	var r0 int
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
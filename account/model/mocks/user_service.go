@@ -5,6 +5,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 	"github.com/weslleyrsr/auth-engine/account/model"
+	"io"
 )
 
 type MockUserService struct {
@@ -38,3 +39,189 @@ func (m *MockUserService) Signup(ctx context.Context, u *model.User) error {
 
 	return r0
 }
+
+// ChangePassword is a UserService.ChangePassword mock
+func (m *MockUserService) ChangePassword(ctx context.Context, uid uuid.UUID, currentPassword string, newPassword string) error {
+	res := m.Called(ctx, uid, currentPassword, newPassword)
+
+	var r0 error
+	if res.Get(0) != nil {
+		r0 = res.Get(0).(error)
+	}
+
+	return r0
+}
+
+// ResetExpiredPassword is a mock of UserService.ResetExpiredPassword
+func (m *MockUserService) ResetExpiredPassword(ctx context.Context, u *model.User, newPassword string) error {
+	res := m.Called(ctx, u, newPassword)
+
+	var r0 error
+	if res.Get(0) != nil {
+		r0 = res.Get(0).(error)
+	}
+
+	return r0
+}
+
+// Signin is a UserService.Signin mock
+func (m *MockUserService) Signin(ctx context.Context, u *model.User) error {
+	res := m.Called(ctx, u)
+
+	var r0 error
+	if res.Get(0) != nil {
+		r0 = res.Get(0).(error)
+	}
+
+	return r0
+}
+
+// VerifyEmail is a UserService.VerifyEmail mock
+func (m *MockUserService) VerifyEmail(ctx context.Context, token string) error {
+	res := m.Called(ctx, token)
+
+	var r0 error
+	if res.Get(0) != nil {
+		r0 = res.Get(0).(error)
+	}
+
+	return r0
+}
+
+// ResendVerificationEmail is a UserService.ResendVerificationEmail mock
+func (m *MockUserService) ResendVerificationEmail(ctx context.Context, email string) error {
+	res := m.Called(ctx, email)
+
+	var r0 error
+	if res.Get(0) != nil {
+		r0 = res.Get(0).(error)
+	}
+
+	return r0
+}
+
+// RequestEmailChange is a UserService.RequestEmailChange mock
+func (m *MockUserService) RequestEmailChange(ctx context.Context, uid uuid.UUID, password string, newEmail string) error {
+	res := m.Called(ctx, uid, password, newEmail)
+
+	var r0 error
+	if res.Get(0) != nil {
+		r0 = res.Get(0).(error)
+	}
+
+	return r0
+}
+
+// ConfirmEmailChange is a UserService.ConfirmEmailChange mock
+func (m *MockUserService) ConfirmEmailChange(ctx context.Context, token string) (*model.User, error) {
+	res := m.Called(ctx, token)
+
+	var r0 *model.User
+	if res.Get(0) != nil {
+		r0 = res.Get(0).(*model.User)
+	}
+
+	var r1 error
+	if res.Get(1) != nil {
+		r1 = res.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// RevertEmailChange is a UserService.RevertEmailChange mock
+func (m *MockUserService) RevertEmailChange(ctx context.Context, token string) (*model.User, error) {
+	res := m.Called(ctx, token)
+
+	var r0 *model.User
+	if res.Get(0) != nil {
+		r0 = res.Get(0).(*model.User)
+	}
+
+	var r1 error
+	if res.Get(1) != nil {
+		r1 = res.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// UpdateDetails is a UserService.UpdateDetails mock
+func (m *MockUserService) UpdateDetails(ctx context.Context, uid uuid.UUID, version int, d *model.UserDetails) (*model.User, error) {
+	res := m.Called(ctx, uid, version, d)
+
+	var r0 *model.User
+	if res.Get(0) != nil {
+		r0 = res.Get(0).(*model.User)
+	}
+
+	var r1 error
+	if res.Get(1) != nil {
+		r1 = res.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// SetProfileImage is a UserService.SetProfileImage mock
+func (m *MockUserService) SetProfileImage(ctx context.Context, uid uuid.UUID, image io.Reader) (*model.User, error) {
+	res := m.Called(ctx, uid, image)
+
+	var r0 *model.User
+	if res.Get(0) != nil {
+		r0 = res.Get(0).(*model.User)
+	}
+
+	var r1 error
+	if res.Get(1) != nil {
+		r1 = res.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// ClearProfileImage is a UserService.ClearProfileImage mock
+func (m *MockUserService) ClearProfileImage(ctx context.Context, uid uuid.UUID) (*model.User, error) {
+	res := m.Called(ctx, uid)
+
+	var r0 *model.User
+	if res.Get(0) != nil {
+		r0 = res.Get(0).(*model.User)
+	}
+
+	var r1 error
+	if res.Get(1) != nil {
+		r1 = res.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// DeleteAccount is a UserService.DeleteAccount mock
+func (m *MockUserService) DeleteAccount(ctx context.Context, uid uuid.UUID, password string) error {
+	res := m.Called(ctx, uid, password)
+
+	var r0 error
+	if res.Get(0) != nil {
+		r0 = res.Get(0).(error)
+	}
+
+	return r0
+}
+
+// RestoreAccount is a UserService.RestoreAccount mock
+func (m *MockUserService) RestoreAccount(ctx context.Context, token string) (*model.User, error) {
+	res := m.Called(ctx, token)
+
+	var r0 *model.User
+	if res.Get(0) != nil {
+		r0 = res.Get(0).(*model.User)
+	}
+
+	var r1 error
+	if res.Get(1) != nil {
+		r1 = res.Get(1).(error)
+	}
+
+	return r0, r1
+}
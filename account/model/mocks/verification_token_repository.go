@@ -0,0 +1,60 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// MockVerificationTokenRepository is a mock type for model.VerificationTokenRepository
+type MockVerificationTokenRepository struct {
+	mock.Mock
+}
+
+// Create is mock of VerificationTokenRepository Create
+func (m *MockVerificationTokenRepository) Create(ctx context.Context, t *model.VerificationToken) error {
+	ret := m.Called(ctx, t)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(error)
+	}
+
+	return r0
+}
+
+// Consume is mock of VerificationTokenRepository Consume
+func (m *MockVerificationTokenRepository) Consume(ctx context.Context, purpose model.VerificationPurpose, tokenHash string) (*model.VerificationToken, error) {
+	ret := m.Called(ctx, purpose, tokenHash)
+
+	var r0 *model.VerificationToken
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.VerificationToken)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
+
+// FindLatest is mock of VerificationTokenRepository FindLatest
+func (m *MockVerificationTokenRepository) FindLatest(ctx context.Context, uid uuid.UUID, purpose model.VerificationPurpose) (*model.VerificationToken, error) {
+	ret := m.Called(ctx, uid, purpose)
+
+	var r0 *model.VerificationToken
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.VerificationToken)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(error)
+	}
+
+	return r0, r1
+}
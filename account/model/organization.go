@@ -0,0 +1,75 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MembershipRole is the role of a member within an organization
+type MembershipRole string
+
+// "Set" of valid membership roles, from the most to the least privileged
+const (
+	OrgOwner  MembershipRole = "owner"
+	OrgAdmin  MembershipRole = "admin" // may invite members
+	OrgMember MembershipRole = "member"
+)
+
+// membershipRoleRanks orders membership roles by privilege
+var membershipRoleRanks = map[MembershipRole]int{
+	OrgOwner:  3,
+	OrgAdmin:  2,
+	OrgMember: 1,
+}
+
+// Valid reports whether r is one of the membership roles
+func (r MembershipRole) Valid() bool {
+	_, ok := membershipRoleRanks[r]
+	return ok
+}
+
+// AtLeast reports whether r is as privileged as min or more
+func (r MembershipRole) AtLeast(min MembershipRole) bool {
+	return r.Valid() && membershipRoleRanks[r] >= membershipRoleRanks[min]
+}
+
+// Organization groups users of an application, eg the customers of a B2B product
+type Organization struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	TenantID  uuid.UUID `db:"tenant_id" json:"-"`
+	Name      string    `db:"name" json:"name"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+}
+
+// Membership is the belonging of a user to an organization
+type Membership struct {
+	OrgID     uuid.UUID      `db:"org_id" json:"orgId"`
+	UID       uuid.UUID      `db:"uid" json:"uid"`
+	Role      MembershipRole `db:"role" json:"role"`
+	CreatedAt time.Time      `db:"created_at" json:"createdAt"`
+}
+
+// InvitationStatus is the state of an invitation to join an organization
+type InvitationStatus string
+
+// "Set" of valid invitation statuses
+const (
+	InvitationPending  InvitationStatus = "pending"
+	InvitationAccepted InvitationStatus = "accepted"
+	InvitationDeclined InvitationStatus = "declined"
+)
+
+// Invitation to join an organization, emailed to the invitee along with a token which
+// accepts or declines it. Only a hash of the token is ever stored
+type Invitation struct {
+	ID        uuid.UUID        `db:"id" json:"id"`
+	OrgID     uuid.UUID        `db:"org_id" json:"orgId"`
+	Email     string           `db:"email" json:"email"`
+	Role      MembershipRole   `db:"role" json:"role"`
+	TokenHash string           `db:"token_hash" json:"-"`
+	InvitedBy uuid.UUID        `db:"invited_by" json:"invitedBy"`
+	Status    InvitationStatus `db:"status" json:"status"`
+	ExpiresAt time.Time        `db:"expires_at" json:"expiresAt"`
+	CreatedAt time.Time        `db:"created_at" json:"createdAt"`
+}
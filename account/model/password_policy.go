@@ -0,0 +1,24 @@
+package model
+
+// PasswordPolicy holds the rules passwords chosen by users must satisfy
+type PasswordPolicy struct {
+	MinLength        int  // minimum number of characters
+	MaxLength        int  // maximum number of characters, 0 means no limit
+	RequireUppercase bool // at least one uppercase letter
+	RequireLowercase bool // at least one lowercase letter
+	RequireDigit     bool // at least one digit
+	RequireSymbol    bool // at least one character which is neither a letter nor a digit
+	// MinStrength is the minimum zxcvbn score, from 0 (too guessable) to 4 (very unguessable)
+	MinStrength int
+	// DisallowEmail rejects passwords containing the user's email address or parts of it
+	DisallowEmail bool
+}
+
+// DefaultPasswordPolicy is used by applications which do not configure a PasswordPolicy.
+// It follows NIST SP 800-63B, favouring length and strength over character classes
+var DefaultPasswordPolicy = PasswordPolicy{
+	MinLength:     8,
+	MaxLength:     128,
+	MinStrength:   2,
+	DisallowEmail: true,
+}
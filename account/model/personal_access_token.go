@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PersonalAccessTokenPrefix starts every personal access token, telling them apart from id tokens
+// and making them recognizable, eg by secret scanners
+const PersonalAccessTokenPrefix = "pat_"
+
+// PersonalAccessTokenPrefixLength is how many leading characters of a token are stored for its owner to recognize it
+const PersonalAccessTokenPrefixLength = len(PersonalAccessTokenPrefix) + 8
+
+// PersonalAccessToken is a long lived token a user creates for scripts and command line tools.
+// Only a hash of the token is stored, along with its first characters.
+// Scopes are the permissions of the user the token is limited to
+type PersonalAccessToken struct {
+	ID         uuid.UUID  `db:"id" json:"id"`
+	TenantID   uuid.UUID  `db:"tenant_id" json:"-"`
+	UID        uuid.UUID  `db:"uid" json:"-"`
+	Name       string     `db:"name" json:"name"`
+	Prefix     string     `db:"prefix" json:"prefix"`
+	TokenHash  string     `db:"token_hash" json:"-"`
+	Scopes     Names      `db:"scopes" json:"scopes"`
+	ExpiresAt  *time.Time `db:"expires_at" json:"expiresAt"` // nil for tokens which never expire
+	LastUsedAt *time.Time `db:"last_used_at" json:"lastUsedAt"`
+	LastUsedIP string     `db:"last_used_ip" json:"lastUsedIp"`
+	CreatedAt  time.Time  `db:"created_at" json:"createdAt"`
+}
+
+// Expired reports whether the token has expired at now
+func (t *PersonalAccessToken) Expired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Permissions guarding the admin routes managing the access policies of an application
+const (
+	PermissionReadPolicies  = "account.policies.read"
+	PermissionWritePolicies = "account.policies.write"
+)
+
+// PolicyEffect is what a policy decides for the requests it matches
+type PolicyEffect string
+
+// "Set" of valid policy effects. A request is allowed when an allow policy matches it
+// and no deny policy does
+const (
+	PolicyAllow PolicyEffect = "allow"
+	PolicyDeny  PolicyEffect = "deny"
+)
+
+// Policy is a version of an attribute based access rule of an application. Publishing a policy under
+// an existing name adds a version, the latest of which is enforced.
+// Expression is a CEL expression over the subject, action, resource, context and now variables
+type Policy struct {
+	ID          uuid.UUID    `db:"id" json:"id"`
+	TenantID    uuid.UUID    `db:"tenant_id" json:"-"`
+	Name        string       `db:"name" json:"name"`
+	Version     int          `db:"version" json:"version"`
+	Description string       `db:"description" json:"description"`
+	Effect      PolicyEffect `db:"effect" json:"effect"`
+	Actions     Names        `db:"actions" json:"actions"` // "*" matches every action
+	Expression  string       `db:"expression" json:"expression"`
+	CreatedAt   time.Time    `db:"created_at" json:"createdAt"`
+}
+
+// AppliesTo reports whether the policy is evaluated for action
+func (p *Policy) AppliesTo(action string) bool {
+	return p.Actions.Contains("*") || p.Actions.Contains(action)
+}
+
+// Attributes describe the subject, resource or context of an authorization request
+type Attributes map[string]interface{}
+
+// AuthorizationRequest asks whether a subject may perform an action on a resource
+type AuthorizationRequest struct {
+	Subject  Attributes `json:"subject"`
+	Action   string     `json:"action"`
+	Resource Attributes `json:"resource"`
+	Context  Attributes `json:"context"`
+}
+
+// PolicyResult is the outcome of a policy which matched an authorization request or failed to evaluate
+type PolicyResult struct {
+	Name    string       `json:"name"`
+	Version int          `json:"version"`
+	Effect  PolicyEffect `json:"effect"`
+	Error   string       `json:"error,omitempty"`
+}
+
+// AuthorizationDecision is the answer to an AuthorizationRequest, along with the policies which led to it
+type AuthorizationDecision struct {
+	Allowed bool            `json:"allowed"`
+	Matched []*PolicyResult `json:"matched"`
+	Errors  []*PolicyResult `json:"errors,omitempty"`
+}
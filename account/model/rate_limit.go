@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// RateLimitResult is the outcome of counting a request against a rate limit
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int           // requests still allowed in the current window
+	Reset      time.Duration // until the quota is fully replenished
+	RetryAfter time.Duration // until the next request is allowed, when not Allowed
+}
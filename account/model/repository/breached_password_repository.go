@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// hashPrefixLength is the number of hex characters of the SHA-1 hash sent to range APIs.
+// Only this prefix leaves the engine, which is shared by hundreds of hashes (k-anonymity)
+const hashPrefixLength = 5
+
+// FileBreachedPasswordRepository is an implementation of handler layer BreachedPasswordRepository
+// which searches a local copy of the Have I Been Pwned passwords, in the "ordered by hash"
+// SHA-1 format: one "HASH:COUNT" line per password, sorted by hash. Passwords never leave the engine
+type FileBreachedPasswordRepository struct {
+	Path string
+}
+
+// NewFileBreachedPasswordRepository is a factory for initializing local file Breached Password Repositories
+func NewFileBreachedPasswordRepository(path string) model.BreachedPasswordRepository {
+	return &FileBreachedPasswordRepository{
+		Path: path,
+	}
+}
+
+// IsBreached binary searches the file for the SHA-1 hash of the password
+func (r *FileBreachedPasswordRepository) IsBreached(ctx context.Context, password string) (bool, error) {
+	f, err := os.Open(r.Path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return false, err
+	}
+
+	hash := sha1Hex(password)
+
+	// invariant: the line holding hash, if any, starts within [lo, hi)
+	lo, hi := int64(0), info.Size()
+
+	for lo < hi {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+
+		mid := lo + (hi-lo)/2
+
+		start, end, line, err := lineFrom(f, mid, info.Size())
+		if err != nil {
+			return false, err
+		}
+
+		// no line starts between mid and hi
+		if start >= hi {
+			hi = mid
+			continue
+		}
+
+		lineHash, count, err := parseHashLine(line)
+		if err != nil {
+			return false, fmt.Errorf("%s at offset %d: %w", r.Path, start, err)
+		}
+
+		switch {
+		case lineHash == hash:
+			return count > 0, nil
+		case lineHash < hash:
+			lo = end
+		default:
+			hi = mid
+		}
+	}
+
+	return false, nil
+}
+
+// lineFrom returns the first line of f starting at or after offset,
+// along with the offsets of its start and of the start of the next line
+func lineFrom(f *os.File, offset int64, size int64) (int64, int64, string, error) {
+	start := offset
+
+	if offset > 0 {
+		// skip the rest of the line offset falls in, unless offset is the start of a line
+		rd := bufio.NewReader(io.NewSectionReader(f, offset-1, size-offset+1))
+
+		skipped, err := rd.ReadString('\n')
+		if err == io.EOF {
+			return size, size, "", nil
+		}
+		if err != nil {
+			return 0, 0, "", err
+		}
+
+		start = offset - 1 + int64(len(skipped))
+	}
+
+	if start >= size {
+		return size, size, "", nil
+	}
+
+	rd := bufio.NewReader(io.NewSectionReader(f, start, size-start))
+
+	line, err := rd.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return 0, 0, "", err
+	}
+
+	return start, start + int64(len(line)), strings.TrimRight(line, "\r\n"), nil
+}
+
+// RangeBreachedPasswordRepository is an implementation of handler layer BreachedPasswordRepository
+// which queries a range API compatible with https://api.pwnedpasswords.com/range/{prefix}.
+// Only the first 5 characters of the SHA-1 hash of the password are sent
+type RangeBreachedPasswordRepository struct {
+	Client  *http.Client
+	BaseURL string
+}
+
+// NewRangeBreachedPasswordRepository is a factory for initializing range API Breached Password Repositories
+func NewRangeBreachedPasswordRepository(baseURL string) model.BreachedPasswordRepository {
+	return &RangeBreachedPasswordRepository{
+		Client:  &http.Client{Timeout: 5 * time.Second},
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// IsBreached fetches every hash sharing the prefix of the hash of the password
+// and looks for the rest of the hash among them
+func (r *RangeBreachedPasswordRepository) IsBreached(ctx context.Context, password string) (bool, error) {
+	hash := sha1Hex(password)
+	prefix, suffix := hash[:hashPrefixLength], hash[hashPrefixLength:]
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/range/%s", r.BaseURL, prefix), http.NoBody)
+	if err != nil {
+		return false, err
+	}
+
+	// padding hides the number of matching hashes from observers of the response size
+	req.Header.Set("Add-Padding", "true")
+
+	res, err := r.Client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status %d from range API", res.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(res.Body)
+
+	for scanner.Scan() {
+		lineSuffix, count, err := parseHashLine(scanner.Text())
+		if err != nil {
+			return false, err
+		}
+
+		// padding entries have a count of 0
+		if lineSuffix == suffix {
+			return count > 0, nil
+		}
+	}
+
+	return false, scanner.Err()
+}
+
+// parseHashLine parses a "HASH:COUNT" line, returning the hash in uppercase
+func parseHashLine(line string) (string, int, error) {
+	hash, countStr, found := strings.Cut(strings.TrimSpace(line), ":")
+	if !found {
+		return "", 0, fmt.Errorf("invalid hash line: %q", line)
+	}
+
+	count, err := strconv.Atoi(countStr)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid count in hash line: %q", line)
+	}
+
+	return strings.ToUpper(hash), count, nil
+}
+
+func sha1Hex(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// breachedPasswords and how many times they appeared in breaches
+var breachedPasswords = map[string]int{
+	"password":                  9545824,
+	"123456":                    37359195,
+	"qwerty":                    3946737,
+	"iloveyou":                  1645337,
+	"letmein":                   721343,
+	"sunshine":                  479816,
+	"dragon":                    1068695,
+	"monkey1":                   158446,
+	"trustno1":                  175911,
+	"correcthorsebatterystaple": 369,
+}
+
+// breachedHashLines returns the "HASH:COUNT" lines of breachedPasswords, sorted by hash
+func breachedHashLines() []string {
+	var lines []string
+	for pw, count := range breachedPasswords {
+		lines = append(lines, fmt.Sprintf("%s:%d", sha1Hex(pw), count))
+	}
+
+	// enough lines for the binary search to take several steps
+	for i := 0; i < 5000; i++ {
+		lines = append(lines, fmt.Sprintf("%s:1", sha1Hex(fmt.Sprintf("filler-%d", i))))
+	}
+
+	// a hash listed with a count of 0 is not breached
+	lines = append(lines, fmt.Sprintf("%s:0", sha1Hex("notbreached")))
+
+	sort.Strings(lines)
+	return lines
+}
+
+// rangeAPIStandIn is a minimal stand-in of the Have I Been Pwned range API
+func rangeAPIStandIn(t *testing.T) *httptest.Server {
+	lines := breachedHashLines()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		prefix := strings.TrimPrefix(r.URL.Path, "/range/")
+
+		if len(prefix) != hashPrefixLength || prefix == r.URL.Path {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		assert.Equal(t, "true", r.Header.Get("Add-Padding"))
+
+		for _, line := range lines {
+			if strings.HasPrefix(line, strings.ToUpper(prefix)) {
+				fmt.Fprintf(w, "%s\r\n", line[hashPrefixLength:])
+			}
+		}
+
+		// padding entry
+		fmt.Fprintf(w, "%s:0\r\n", strings.Repeat("0", 35))
+	}))
+}
+
+func TestFileBreachedPasswordRepository(t *testing.T) {
+	lines := breachedHashLines()
+
+	path := filepath.Join(t.TempDir(), "pwned-passwords-sha1-ordered-by-hash.txt")
+	assert.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\r\n")+"\r\n"), 0o644))
+
+	r := NewFileBreachedPasswordRepository(path)
+
+	t.Run("Breached passwords", func(t *testing.T) {
+		for pw := range breachedPasswords {
+			breached, err := r.IsBreached(context.TODO(), pw)
+
+			assert.NoError(t, err)
+			assert.True(t, breached, pw)
+		}
+	})
+
+	t.Run("Passwords not in the file", func(t *testing.T) {
+		for _, pw := range []string{"tangerine-kayak-79", "Password", "", "notbreached"} {
+			breached, err := r.IsBreached(context.TODO(), pw)
+
+			assert.NoError(t, err)
+			assert.False(t, breached, pw)
+		}
+	})
+
+	t.Run("File without trailing newline", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "hashes.txt")
+		assert.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o644))
+
+		breached, err := NewFileBreachedPasswordRepository(path).IsBreached(context.TODO(), "123456")
+
+		assert.NoError(t, err)
+		assert.True(t, breached)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := NewFileBreachedPasswordRepository(filepath.Join(t.TempDir(), "missing.txt")).IsBreached(context.TODO(), "123456")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestRangeBreachedPasswordRepository(t *testing.T) {
+	server := rangeAPIStandIn(t)
+	defer server.Close()
+
+	r := NewRangeBreachedPasswordRepository(server.URL + "/")
+
+	t.Run("Breached passwords", func(t *testing.T) {
+		for pw := range breachedPasswords {
+			breached, err := r.IsBreached(context.TODO(), pw)
+
+			assert.NoError(t, err)
+			assert.True(t, breached, pw)
+		}
+	})
+
+	t.Run("Passwords not in the corpus", func(t *testing.T) {
+		for _, pw := range []string{"tangerine-kayak-79", "notbreached"} {
+			breached, err := r.IsBreached(context.TODO(), pw)
+
+			assert.NoError(t, err)
+			assert.False(t, breached, pw)
+		}
+	})
+
+	t.Run("Unavailable API", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer failing.Close()
+
+		_, err := NewRangeBreachedPasswordRepository(failing.URL).IsBreached(context.TODO(), "123456")
+
+		assert.Error(t, err)
+	})
+}
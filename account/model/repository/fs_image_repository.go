@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// FSImageRepository is an implementation of service layer ImageRepository
+// which stores images in a local directory. The directory is expected to be
+// served at BaseURL
+type FSImageRepository struct {
+	Dir     string
+	BaseURL string
+}
+
+// NewFSImageRepository is a factory for initializing local filesystem Image Repositories
+func NewFSImageRepository(dir string, baseURL string) model.ImageRepository {
+	return &FSImageRepository{
+		Dir:     dir,
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// Upload writes the image to the directory and returns the url it is served from
+func (r *FSImageRepository) Upload(ctx context.Context, objName string, contentType string, image io.Reader) (string, error) {
+	// only keep the base name so objName can never escape the directory
+	objName = filepath.Base(objName)
+
+	if err := os.MkdirAll(r.Dir, 0o755); err != nil {
+		log.Printf("Unable to create image directory: %v. Reason: %v\n", r.Dir, err)
+		return "", apperrors.NewInternal()
+	}
+
+	f, err := os.Create(filepath.Join(r.Dir, objName))
+	if err != nil {
+		log.Printf("Unable to create image file: %v. Reason: %v\n", objName, err)
+		return "", apperrors.NewInternal()
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, image); err != nil {
+		log.Printf("Unable to write image file: %v. Reason: %v\n", objName, err)
+		return "", apperrors.NewInternal()
+	}
+
+	return fmt.Sprintf("%s/%s", r.BaseURL, objName), nil
+}
+
+// Delete removes the image from the directory. Deleting a missing image is not an error
+func (r *FSImageRepository) Delete(ctx context.Context, objName string) error {
+	err := os.Remove(filepath.Join(r.Dir, filepath.Base(objName)))
+
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Printf("Unable to delete image file: %v. Reason: %v\n", objName, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFSImageRepository(t *testing.T) {
+	dir := t.TempDir()
+	r := NewFSImageRepository(dir, "http://localhost/images/")
+
+	t.Run("Upload and delete", func(t *testing.T) {
+		imageURL, err := r.Upload(context.TODO(), "image.png", "image/png", bytes.NewReader([]byte("image")))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "http://localhost/images/image.png", imageURL)
+
+		content, err := os.ReadFile(filepath.Join(dir, "image.png"))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("image"), content)
+
+		assert.NoError(t, r.Delete(context.TODO(), "image.png"))
+
+		_, err = os.Stat(filepath.Join(dir, "image.png"))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("Object names cannot escape the directory", func(t *testing.T) {
+		_, err := r.Upload(context.TODO(), "../escaped.png", "image/png", bytes.NewReader([]byte("image")))
+		assert.NoError(t, err)
+
+		_, err = os.Stat(filepath.Join(dir, "escaped.png"))
+		assert.NoError(t, err)
+
+		_, err = os.Stat(filepath.Join(filepath.Dir(dir), "escaped.png"))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("Deleting a missing image", func(t *testing.T) {
+		assert.NoError(t, r.Delete(context.TODO(), "missing.png"))
+	})
+}
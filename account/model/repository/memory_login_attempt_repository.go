@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// MemoryLoginAttemptRepository is an implementation of service layer LoginAttemptRepository
+// which keeps counters in memory. Counters are lost on restart and not shared between
+// instances, so it is meant for tests and single instance deployments
+type MemoryLoginAttemptRepository struct {
+	mu       sync.Mutex
+	attempts map[string]model.LoginAttempts
+}
+
+// NewMemoryLoginAttemptRepository is a factory for initializing in-memory Login Attempt Repositories
+func NewMemoryLoginAttemptRepository() model.LoginAttemptRepository {
+	return &MemoryLoginAttemptRepository{
+		attempts: make(map[string]model.LoginAttempts),
+	}
+}
+
+// Get returns the failed attempts of key, with no failures if there are none
+func (r *MemoryLoginAttemptRepository) Get(ctx context.Context, key string) (*model.LoginAttempts, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a, ok := r.attempts[key]
+	if !ok {
+		return &model.LoginAttempts{Key: key}, nil
+	}
+
+	return &a, nil
+}
+
+// RecordFailure increments the failures of key, starting over when the last failure is older than window
+func (r *MemoryLoginAttemptRepository) RecordFailure(ctx context.Context, key string, window time.Duration) (*model.LoginAttempts, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+
+	a, ok := r.attempts[key]
+	if !ok || now.Sub(a.LastFailure) > window {
+		a = model.LoginAttempts{Key: key}
+	}
+
+	a.Failures++
+	a.LastFailure = now
+
+	r.attempts[key] = a
+
+	return &a, nil
+}
+
+// Reset forgets the failures of key
+func (r *MemoryLoginAttemptRepository) Reset(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.attempts, key)
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// windowCounter holds the counts of the current and previous fixed windows of a key
+type windowCounter struct {
+	start  time.Time
+	window time.Duration
+	prev   int64
+	curr   int64
+}
+
+// MemoryRateLimiter is an implementation of handler layer RateLimiter using sliding window
+// counters kept in memory. Counters are not shared between instances, so it is meant for
+// tests and single instance deployments
+type MemoryRateLimiter struct {
+	mu        sync.Mutex
+	counters  map[string]*windowCounter
+	lastSweep time.Time
+}
+
+// NewMemoryRateLimiter is a factory for initializing in-memory Rate Limiters
+func NewMemoryRateLimiter() model.RateLimiter {
+	return &MemoryRateLimiter{
+		counters:  make(map[string]*windowCounter),
+		lastSweep: time.Now(),
+	}
+}
+
+// Allow counts a request for key if it fits within limit requests per sliding window
+func (r *MemoryRateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (*model.RateLimitResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	start := now.Truncate(window)
+
+	r.sweep(now)
+
+	c, ok := r.counters[key]
+	if !ok {
+		c = &windowCounter{start: start, window: window}
+		r.counters[key] = c
+	}
+
+	// roll the windows forward
+	if !c.start.Equal(start) {
+		if c.start.Add(window).Equal(start) {
+			c.prev = c.curr
+		} else {
+			c.prev = 0
+		}
+		c.curr = 0
+		c.start = start
+	}
+
+	elapsed := now.Sub(start)
+
+	allowed := slidingWindowEstimate(c.prev, c.curr, window, elapsed)+1 <= float64(limit)
+	if allowed {
+		c.curr++
+	}
+
+	return slidingWindowResult(allowed, c.prev, c.curr, limit, window, elapsed), nil
+}
+
+// sweep removes counters which no longer affect any request, at most once a minute
+func (r *MemoryRateLimiter) sweep(now time.Time) {
+	if now.Sub(r.lastSweep) < time.Minute {
+		return
+	}
+
+	for key, c := range r.counters {
+		if now.Sub(c.start) > 2*c.window {
+			delete(r.counters, key)
+		}
+	}
+
+	r.lastSweep = now
+}
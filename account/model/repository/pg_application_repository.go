@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// PGApplicationRepository is data/repository implementation
+// of handler and service layer ApplicationRepository
+type PGApplicationRepository struct {
+	DB *sqlx.DB
+}
+
+// NewApplicationRepository is a factory for initializing Application Repositories
+func NewApplicationRepository(db *sqlx.DB) model.ApplicationRepository {
+	return &PGApplicationRepository{
+		DB: db,
+	}
+}
+
+// FindByID fetches an application by id
+func (r *PGApplicationRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Application, error) {
+	app := &model.Application{}
+
+	query := "SELECT * FROM applications WHERE id=$1"
+
+	if err := r.DB.GetContext(ctx, app, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.NewNotFound("application", id.String())
+		}
+
+		log.Printf("Unable to get application with id: %v. Err: %v\n", id, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return app, nil
+}
+
+// FindBySlug fetches an application by its slug
+func (r *PGApplicationRepository) FindBySlug(ctx context.Context, slug string) (*model.Application, error) {
+	app := &model.Application{}
+
+	query := "SELECT * FROM applications WHERE slug=$1"
+
+	if err := r.DB.GetContext(ctx, app, query, slug); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.NewNotFound("application", slug)
+		}
+
+		log.Printf("Unable to get application with slug: %v. Err: %v\n", slug, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return app, nil
+}
+
+// FindByHost fetches the application served on a host
+func (r *PGApplicationRepository) FindByHost(ctx context.Context, host string) (*model.Application, error) {
+	app := &model.Application{}
+
+	query := "SELECT * FROM applications WHERE host=$1"
+
+	if err := r.DB.GetContext(ctx, app, query, host); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.NewNotFound("application", host)
+		}
+
+		log.Printf("Unable to get application with host: %v. Err: %v\n", host, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return app, nil
+}
+
+// FindAll fetches every application
+func (r *PGApplicationRepository) FindAll(ctx context.Context) ([]*model.Application, error) {
+	apps := []*model.Application{}
+
+	query := "SELECT * FROM applications ORDER BY created_at"
+
+	if err := r.DB.SelectContext(ctx, &apps, query); err != nil {
+		log.Printf("Unable to get applications. Err: %v\n", err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return apps, nil
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// PGAuditRepository is data/repository implementation
+// of service layer AuditRepository. Every query is scoped to the application in context
+type PGAuditRepository struct {
+	DB *sqlx.DB
+}
+
+// NewAuditRepository is a factory for initializing Audit Repositories
+func NewAuditRepository(db *sqlx.DB) model.AuditRepository {
+	return &PGAuditRepository{
+		DB: db,
+	}
+}
+
+// Create appends an event to the audit log, chaining it to the last event of the application.
+// Appends to the log of an application are serialized so that each event chains to the one before it
+func (r *PGAuditRepository) Create(ctx context.Context, e *model.AuditEvent) error {
+	tenantID := model.ApplicationID(ctx)
+
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Could not begin transaction recording %v audit event. Reason: %v\n", e.Type, err)
+		return apperrors.NewInternal()
+	}
+	defer tx.Rollback()
+
+	// the lock is held until the transaction ends, by which time the event is the last of the chain
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", tenantID.String()); err != nil {
+		log.Printf("Could not lock audit log to record %v audit event. Reason: %v\n", e.Type, err)
+		return apperrors.NewInternal()
+	}
+
+	var prevHash string
+
+	err = tx.GetContext(ctx, &prevHash, "SELECT hash FROM audit_events WHERE tenant_id=$1 ORDER BY seq DESC LIMIT 1", tenantID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("Could not get last audit event to record %v audit event. Reason: %v\n", e.Type, err)
+		return apperrors.NewInternal()
+	}
+
+	// the event is hashed before being stored, so its id and time are set here rather than defaulted.
+	// Times are stored to the microsecond and must hash the same once read back
+	e.ID = uuid.New()
+	e.TenantID = tenantID
+	e.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	e.PrevHash = prevHash
+	e.Hash = e.ComputeHash()
+
+	query := `INSERT INTO audit_events (id, tenant_id, type, actor, target, ip, user_agent, detail, created_at, prev_hash, hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING *`
+
+	if err := tx.GetContext(ctx, e, query, e.ID, e.TenantID, e.Type, e.Actor, e.Target, e.IP, e.UserAgent, e.Detail,
+		e.CreatedAt, e.PrevHash, e.Hash); err != nil {
+		log.Printf("Could not record %v audit event. Reason: %v\n", e.Type, err)
+		return apperrors.NewInternal()
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Could not commit %v audit event. Reason: %v\n", e.Type, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// FindByUser fetches the events of the audit log a user is the actor or target of, the most recent first.
+// Every event is fetched when limit is 0
+func (r *PGAuditRepository) FindByUser(ctx context.Context, uid uuid.UUID, limit int) ([]*model.AuditEvent, error) {
+	events := []*model.AuditEvent{}
+
+	query := `SELECT * FROM audit_events WHERE tenant_id=$1 AND (actor=$2 OR target=$2)
+		ORDER BY seq DESC LIMIT NULLIF($3, 0)`
+
+	if err := r.DB.SelectContext(ctx, &events, query, model.ApplicationID(ctx), uid, limit); err != nil {
+		log.Printf("Could not get audit events of uid: %v. Reason: %v\n", uid, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return events, nil
+}
+
+// Search fetches a page of the events of the audit log matching a filter, the most recent first,
+// along with how many events match it in total
+func (r *PGAuditRepository) Search(ctx context.Context, f *model.AuditFilter) ([]*model.AuditEvent, int, error) {
+	conditions := []string{"tenant_id=$1"}
+	args := []interface{}{model.ApplicationID(ctx)}
+
+	where := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if f.Type != "" {
+		where("type=$%d", f.Type)
+	}
+	if f.Actor != nil {
+		where("actor=$%d", *f.Actor)
+	}
+	if f.Target != nil {
+		where("target=$%d", *f.Target)
+	}
+	if f.CreatedAfter != nil {
+		where("created_at>=$%d", *f.CreatedAfter)
+	}
+	if f.CreatedBefore != nil {
+		where("created_at<$%d", *f.CreatedBefore)
+	}
+
+	var total int
+
+	countQuery := "SELECT count(*) FROM audit_events WHERE " + strings.Join(conditions, " AND ")
+
+	if err := r.DB.GetContext(ctx, &total, countQuery, args...); err != nil {
+		log.Printf("Could not count audit events. Reason: %v\n", err)
+		return nil, 0, apperrors.NewInternal()
+	}
+
+	events := []*model.AuditEvent{}
+
+	query := fmt.Sprintf("SELECT * FROM audit_events WHERE %s ORDER BY seq DESC LIMIT $%d OFFSET $%d",
+		strings.Join(conditions, " AND "), len(args)+1, len(args)+2)
+
+	if err := r.DB.SelectContext(ctx, &events, query, append(args, f.Limit, f.Offset)...); err != nil {
+		log.Printf("Could not search audit events. Reason: %v\n", err)
+		return nil, 0, apperrors.NewInternal()
+	}
+
+	return events, total, nil
+}
+
+// FindChain fetches up to limit events of the audit log following the event numbered afterSeq,
+// in the order they are chained in
+func (r *PGAuditRepository) FindChain(ctx context.Context, afterSeq int64, limit int) ([]*model.AuditEvent, error) {
+	events := []*model.AuditEvent{}
+
+	query := "SELECT * FROM audit_events WHERE tenant_id=$1 AND seq>$2 ORDER BY seq LIMIT $3"
+
+	if err := r.DB.SelectContext(ctx, &events, query, model.ApplicationID(ctx), afterSeq, limit); err != nil {
+		log.Printf("Could not get audit events after seq: %v. Reason: %v\n", afterSeq, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return events, nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// PGDataExportRepository is data/repository implementation
+// of service layer DataExportRepository. Every query is scoped to the application in context
+type PGDataExportRepository struct {
+	DB *sqlx.DB
+}
+
+// NewDataExportRepository is a factory for initializing Data Export Repositories
+func NewDataExportRepository(db *sqlx.DB) model.DataExportRepository {
+	return &PGDataExportRepository{
+		DB: db,
+	}
+}
+
+// Create stores a new data export of a user, replacing their previous exports
+func (r *PGDataExportRepository) Create(ctx context.Context, e *model.DataExport) error {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Could not begin transaction creating data export for uid: %v. Reason: %v\n", e.UID, err)
+		return apperrors.NewInternal()
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM data_exports WHERE uid=$1 AND tenant_id=$2", e.UID, model.ApplicationID(ctx)); err != nil {
+		log.Printf("Could not delete previous data exports of uid: %v. Reason: %v\n", e.UID, err)
+		return apperrors.NewInternal()
+	}
+
+	query := `INSERT INTO data_exports (tenant_id, uid, status, archive, completed_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING *`
+
+	if err := tx.GetContext(ctx, e, query, model.ApplicationID(ctx), e.UID, e.Status, e.Archive, e.CompletedAt, e.ExpiresAt); err != nil {
+		log.Printf("Could not create data export for uid: %v. Reason: %v\n", e.UID, err)
+		return apperrors.NewInternal()
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Could not commit data export for uid: %v. Reason: %v\n", e.UID, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// FindByID fetches a data export of a user which has not expired yet
+func (r *PGDataExportRepository) FindByID(ctx context.Context, uid uuid.UUID, id uuid.UUID) (*model.DataExport, error) {
+	e := &model.DataExport{}
+
+	query := "SELECT * FROM data_exports WHERE id=$1 AND uid=$2 AND tenant_id=$3 AND expires_at > now()"
+
+	if err := r.DB.GetContext(ctx, e, query, id, uid, model.ApplicationID(ctx)); err != nil {
+		return nil, apperrors.NewNotFound("export", id.String())
+	}
+
+	return e, nil
+}
+
+// FindPending fetches the data export of a user still being generated, if any
+func (r *PGDataExportRepository) FindPending(ctx context.Context, uid uuid.UUID) (*model.DataExport, error) {
+	e := &model.DataExport{}
+
+	query := "SELECT * FROM data_exports WHERE uid=$1 AND tenant_id=$2 AND status=$3 AND expires_at > now()"
+
+	if err := r.DB.GetContext(ctx, e, query, uid, model.ApplicationID(ctx), model.DataExportPending); err != nil {
+		return nil, apperrors.NewNotFound("pending export of uid", uid.String())
+	}
+
+	return e, nil
+}
+
+// Complete sets the outcome of the generation of a data export, along with its archive once ready
+func (r *PGDataExportRepository) Complete(ctx context.Context, id uuid.UUID, status model.DataExportStatus, archive []byte) error {
+	query := "UPDATE data_exports SET status=$1, archive=$2, completed_at=now() WHERE id=$3 AND tenant_id=$4"
+
+	res, err := r.DB.ExecContext(ctx, query, status, archive, id, model.ApplicationID(ctx))
+	if err != nil {
+		log.Printf("Could not complete data export: %v. Reason: %v\n", id, err)
+		return apperrors.NewInternal()
+	}
+
+	if n, err := res.RowsAffected(); err != nil || n < 1 {
+		return apperrors.NewNotFound("export", id.String())
+	}
+
+	return nil
+}
+
+// DeleteExpired deletes the exports of every application which expired, along with the copy of the data of
+// their users held by their archive, returning how many were. Expiry does not depend on the application,
+// so unlike other queries it is not scoped to the application in context
+func (r *PGDataExportRepository) DeleteExpired(ctx context.Context) (int, error) {
+	res, err := r.DB.ExecContext(ctx, "DELETE FROM data_exports WHERE expires_at < now()")
+	if err != nil {
+		log.Printf("Could not delete expired data exports. Reason: %v\n", err)
+		return 0, apperrors.NewInternal()
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("Could not count deleted data exports. Reason: %v\n", err)
+		return 0, apperrors.NewInternal()
+	}
+
+	return int(n), nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// execDriver is a database/sql driver recording the statements executed through it,
+// each of which affects rowsAffected rows or fails with err
+type execDriver struct {
+	queries      []string
+	rowsAffected int64
+	err          error
+}
+
+func (d *execDriver) Open(name string) (driver.Conn, error) {
+	return &execConn{d}, nil
+}
+
+type execConn struct {
+	d *execDriver
+}
+
+func (c *execConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c *execConn) Close() error {
+	return nil
+}
+
+func (c *execConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+func (c *execConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.d.queries = append(c.d.queries, query)
+
+	if c.d.err != nil {
+		return nil, c.d.err
+	}
+
+	return driver.RowsAffected(c.d.rowsAffected), nil
+}
+
+// openExecDB returns a database executing statements through d
+func openExecDB(t *testing.T, d *execDriver) *sqlx.DB {
+	t.Helper()
+
+	db := sql.OpenDB(&execConnector{d})
+	t.Cleanup(func() { db.Close() })
+
+	return sqlx.NewDb(db, "postgres")
+}
+
+type execConnector struct {
+	d *execDriver
+}
+
+func (c *execConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return c.d.Open("")
+}
+
+func (c *execConnector) Driver() driver.Driver {
+	return c.d
+}
+
+func TestDataExportDeleteExpired(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		d := &execDriver{rowsAffected: 3}
+		r := NewDataExportRepository(openExecDB(t, d))
+
+		n, err := r.DeleteExpired(context.TODO())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, n)
+		assert.Equal(t, []string{"DELETE FROM data_exports WHERE expires_at < now()"}, d.queries)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		d := &execDriver{err: errors.New("connection refused")}
+		r := NewDataExportRepository(openExecDB(t, d))
+
+		n, err := r.DeleteExpired(context.TODO())
+
+		assert.Equal(t, 0, n)
+		assert.Equal(t, apperrors.Internal, err.(*apperrors.Error).Type)
+	})
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// PGLoginAttemptRepository is data/repository implementation
+// of service layer LoginAttemptRepository
+type PGLoginAttemptRepository struct {
+	DB *sqlx.DB
+}
+
+// NewLoginAttemptRepository is a factory for initializing Login Attempt Repositories
+func NewLoginAttemptRepository(db *sqlx.DB) model.LoginAttemptRepository {
+	return &PGLoginAttemptRepository{
+		DB: db,
+	}
+}
+
+// Get returns the failed attempts of key, with no failures if there are none
+func (r *PGLoginAttemptRepository) Get(ctx context.Context, key string) (*model.LoginAttempts, error) {
+	a := &model.LoginAttempts{}
+
+	query := "SELECT * FROM login_attempts WHERE key=$1"
+
+	if err := r.DB.GetContext(ctx, a, query, key); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &model.LoginAttempts{Key: key}, nil
+		}
+
+		log.Printf("Could not fetch login attempts for key: %v. Reason: %v\n", key, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return a, nil
+}
+
+// RecordFailure atomically increments the failures of key, starting over when
+// the last failure is older than window
+func (r *PGLoginAttemptRepository) RecordFailure(ctx context.Context, key string, window time.Duration) (*model.LoginAttempts, error) {
+	a := &model.LoginAttempts{}
+
+	query := `INSERT INTO login_attempts (key, failures, last_failure) VALUES ($1, 1, now())
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE
+				WHEN login_attempts.last_failure < now() - make_interval(secs => $2) THEN 1
+				ELSE login_attempts.failures + 1
+			END,
+			last_failure = now()
+		RETURNING *`
+
+	if err := r.DB.GetContext(ctx, a, query, key, window.Seconds()); err != nil {
+		log.Printf("Could not record failed login attempt for key: %v. Reason: %v\n", key, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return a, nil
+}
+
+// Reset forgets the failures of key
+func (r *PGLoginAttemptRepository) Reset(ctx context.Context, key string) error {
+	if _, err := r.DB.ExecContext(ctx, "DELETE FROM login_attempts WHERE key=$1", key); err != nil {
+		log.Printf("Could not reset login attempts for key: %v. Reason: %v\n", key, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// PGOrganizationRepository is data/repository implementation
+// of service layer OrganizationRepository. Every query is scoped to the application in context
+type PGOrganizationRepository struct {
+	DB *sqlx.DB
+}
+
+// NewOrganizationRepository is a factory for initializing Organization Repositories
+func NewOrganizationRepository(db *sqlx.DB) model.OrganizationRepository {
+	return &PGOrganizationRepository{
+		DB: db,
+	}
+}
+
+// Create creates an organization along with the membership of its owner
+func (r *PGOrganizationRepository) Create(ctx context.Context, org *model.Organization, owner uuid.UUID) error {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Could not begin transaction for organization: %v. Reason: %v\n", org.Name, err)
+		return apperrors.NewInternal()
+	}
+	defer tx.Rollback()
+
+	query := "INSERT INTO organizations (tenant_id, name) VALUES ($1, $2) RETURNING *"
+
+	if err := tx.GetContext(ctx, org, query, model.ApplicationID(ctx), org.Name); err != nil {
+		log.Printf("Could not create organization: %v. Reason: %v\n", org.Name, err)
+		return apperrors.NewInternal()
+	}
+
+	query = "INSERT INTO memberships (org_id, uid, role) VALUES ($1, $2, $3)"
+
+	if _, err := tx.ExecContext(ctx, query, org.ID, owner, model.OrgOwner); err != nil {
+		log.Printf("Could not add owner: %v to organization: %v. Reason: %v\n", owner, org.ID, err)
+		return apperrors.NewInternal()
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Could not commit organization: %v. Reason: %v\n", org.Name, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// FindByID fetches an organization by id
+func (r *PGOrganizationRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Organization, error) {
+	org := &model.Organization{}
+
+	query := "SELECT * FROM organizations WHERE id=$1 AND tenant_id=$2"
+
+	if err := r.DB.GetContext(ctx, org, query, id, model.ApplicationID(ctx)); err != nil {
+		return nil, apperrors.NewNotFound("organization", id.String())
+	}
+
+	return org, nil
+}
+
+// FindByUser fetches the organizations a user is a member of
+func (r *PGOrganizationRepository) FindByUser(ctx context.Context, uid uuid.UUID) ([]*model.Organization, error) {
+	orgs := []*model.Organization{}
+
+	query := `SELECT o.* FROM organizations o JOIN memberships m ON m.org_id = o.id
+		WHERE m.uid=$1 AND o.tenant_id=$2 ORDER BY o.name`
+
+	if err := r.DB.SelectContext(ctx, &orgs, query, uid, model.ApplicationID(ctx)); err != nil {
+		log.Printf("Could not get organizations of uid: %v. Reason: %v\n", uid, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return orgs, nil
+}
+
+// FindMembership fetches the membership of a user to an organization
+func (r *PGOrganizationRepository) FindMembership(ctx context.Context, orgID uuid.UUID, uid uuid.UUID) (*model.Membership, error) {
+	m := &model.Membership{}
+
+	query := `SELECT m.* FROM memberships m JOIN organizations o ON o.id = m.org_id
+		WHERE m.org_id=$1 AND m.uid=$2 AND o.tenant_id=$3`
+
+	if err := r.DB.GetContext(ctx, m, query, orgID, uid, model.ApplicationID(ctx)); err != nil {
+		return nil, apperrors.NewNotFound("membership", orgID.String()+"/"+uid.String())
+	}
+
+	return m, nil
+}
+
+// FindMembers fetches the memberships of an organization
+func (r *PGOrganizationRepository) FindMembers(ctx context.Context, orgID uuid.UUID) ([]*model.Membership, error) {
+	members := []*model.Membership{}
+
+	query := `SELECT m.* FROM memberships m JOIN organizations o ON o.id = m.org_id
+		WHERE m.org_id=$1 AND o.tenant_id=$2 ORDER BY m.created_at`
+
+	if err := r.DB.SelectContext(ctx, &members, query, orgID, model.ApplicationID(ctx)); err != nil {
+		log.Printf("Could not get members of organization: %v. Reason: %v\n", orgID, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return members, nil
+}
+
+// AddMember adds a user to an organization
+func (r *PGOrganizationRepository) AddMember(ctx context.Context, m *model.Membership) error {
+	query := `INSERT INTO memberships (org_id, uid, role)
+		SELECT o.id, $2, $3 FROM organizations o WHERE o.id=$1 AND o.tenant_id=$4
+		RETURNING *`
+
+	if err := r.DB.GetContext(ctx, m, query, m.OrgID, m.UID, m.Role, model.ApplicationID(ctx)); err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			return apperrors.NewConflict("membership", m.OrgID.String()+"/"+m.UID.String())
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.NewNotFound("organization", m.OrgID.String())
+		}
+
+		log.Printf("Could not add uid: %v to organization: %v. Reason: %v\n", m.UID, m.OrgID, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// CreateInvitation stores an invitation to join an organization
+func (r *PGOrganizationRepository) CreateInvitation(ctx context.Context, inv *model.Invitation) error {
+	query := `INSERT INTO invitations (org_id, email, role, token_hash, invited_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING *`
+
+	if err := r.DB.GetContext(ctx, inv, query, inv.OrgID, inv.Email, inv.Role, inv.TokenHash, inv.InvitedBy, inv.ExpiresAt); err != nil {
+		log.Printf("Could not create invitation to organization: %v. Reason: %v\n", inv.OrgID, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// FindInvitation fetches an invitation to an organization of the application by the hash of its token
+func (r *PGOrganizationRepository) FindInvitation(ctx context.Context, tokenHash string) (*model.Invitation, error) {
+	inv := &model.Invitation{}
+
+	query := `SELECT i.* FROM invitations i JOIN organizations o ON o.id = i.org_id
+		WHERE i.token_hash=$1 AND o.tenant_id=$2`
+
+	if err := r.DB.GetContext(ctx, inv, query, tokenHash, model.ApplicationID(ctx)); err != nil {
+		return nil, apperrors.NewNotFound("invitation", "token")
+	}
+
+	return inv, nil
+}
+
+// ResolveInvitation sets the status of a pending invitation, so it can only be accepted or declined once
+func (r *PGOrganizationRepository) ResolveInvitation(ctx context.Context, id uuid.UUID, status model.InvitationStatus) error {
+	query := "UPDATE invitations SET status=$1 WHERE id=$2 AND status=$3"
+
+	res, err := r.DB.ExecContext(ctx, query, status, id, model.InvitationPending)
+	if err != nil {
+		log.Printf("Could not resolve invitation: %v. Reason: %v\n", id, err)
+		return apperrors.NewInternal()
+	}
+
+	if n, err := res.RowsAffected(); err != nil || n < 1 {
+		return apperrors.NewNotFound("invitation", id.String())
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// PGPasswordHistoryRepository is data/repository implementation
+// of service layer PasswordHistoryRepository
+type PGPasswordHistoryRepository struct {
+	DB *sqlx.DB
+}
+
+// NewPasswordHistoryRepository is a factory for initializing Password History Repositories
+func NewPasswordHistoryRepository(db *sqlx.DB) model.PasswordHistoryRepository {
+	return &PGPasswordHistoryRepository{
+		DB: db,
+	}
+}
+
+// Add stores a previous password hash of a user, then removes all but the keep most recent ones
+func (r *PGPasswordHistoryRepository) Add(ctx context.Context, uid uuid.UUID, password string, keep int) error {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Could not begin transaction for password history of uid: %v. Reason: %v\n", uid, err)
+		return apperrors.NewInternal()
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "INSERT INTO password_history (uid, password) VALUES ($1, $2)", uid, password); err != nil {
+		log.Printf("Could not add password history for uid: %v. Reason: %v\n", uid, err)
+		return apperrors.NewInternal()
+	}
+
+	query := `DELETE FROM password_history WHERE uid=$1 AND id NOT IN (
+		SELECT id FROM password_history WHERE uid=$1 ORDER BY id DESC LIMIT $2
+	)`
+
+	if _, err := tx.ExecContext(ctx, query, uid, keep); err != nil {
+		log.Printf("Could not prune password history for uid: %v. Reason: %v\n", uid, err)
+		return apperrors.NewInternal()
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Could not commit password history for uid: %v. Reason: %v\n", uid, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// FindRecent fetches up to limit of the most recent previous password hashes of a user
+func (r *PGPasswordHistoryRepository) FindRecent(ctx context.Context, uid uuid.UUID, limit int) ([]string, error) {
+	passwords := []string{}
+
+	query := "SELECT password FROM password_history WHERE uid=$1 ORDER BY id DESC LIMIT $2"
+
+	if err := r.DB.SelectContext(ctx, &passwords, query, uid, limit); err != nil {
+		log.Printf("Could not fetch password history for uid: %v. Reason: %v\n", uid, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return passwords, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// PGPersonalAccessTokenRepository is data/repository implementation
+// of service layer PersonalAccessTokenRepository. Every query is scoped to the application in context
+type PGPersonalAccessTokenRepository struct {
+	DB *sqlx.DB
+}
+
+// NewPersonalAccessTokenRepository is a factory for initializing Personal Access Token Repositories
+func NewPersonalAccessTokenRepository(db *sqlx.DB) model.PersonalAccessTokenRepository {
+	return &PGPersonalAccessTokenRepository{
+		DB: db,
+	}
+}
+
+// Create stores the hash of a personal access token
+func (r *PGPersonalAccessTokenRepository) Create(ctx context.Context, t *model.PersonalAccessToken) error {
+	query := `INSERT INTO personal_access_tokens (tenant_id, uid, name, prefix, token_hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING *`
+
+	if err := r.DB.GetContext(ctx, t, query, model.ApplicationID(ctx), t.UID, t.Name, t.Prefix, t.TokenHash, t.Scopes, t.ExpiresAt); err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			return apperrors.NewConflict("personal access token", t.Name)
+		}
+
+		log.Printf("Could not create personal access token: %v for uid: %v. Reason: %v\n", t.Name, t.UID, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// FindByUser fetches the personal access tokens of a user, the latest first
+func (r *PGPersonalAccessTokenRepository) FindByUser(ctx context.Context, uid uuid.UUID) ([]*model.PersonalAccessToken, error) {
+	tokens := []*model.PersonalAccessToken{}
+
+	query := "SELECT * FROM personal_access_tokens WHERE uid=$1 AND tenant_id=$2 ORDER BY created_at DESC"
+
+	if err := r.DB.SelectContext(ctx, &tokens, query, uid, model.ApplicationID(ctx)); err != nil {
+		log.Printf("Could not get personal access tokens of uid: %v. Reason: %v\n", uid, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return tokens, nil
+}
+
+// FindByHash fetches the personal access token of a hash
+func (r *PGPersonalAccessTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*model.PersonalAccessToken, error) {
+	t := &model.PersonalAccessToken{}
+
+	query := "SELECT * FROM personal_access_tokens WHERE token_hash=$1 AND tenant_id=$2"
+
+	if err := r.DB.GetContext(ctx, t, query, tokenHash, model.ApplicationID(ctx)); err != nil {
+		return nil, apperrors.NewNotFound("personal access token", "hash")
+	}
+
+	return t, nil
+}
+
+// Delete deletes a personal access token of a user
+func (r *PGPersonalAccessTokenRepository) Delete(ctx context.Context, uid uuid.UUID, id uuid.UUID) error {
+	query := "DELETE FROM personal_access_tokens WHERE id=$1 AND uid=$2 AND tenant_id=$3"
+
+	res, err := r.DB.ExecContext(ctx, query, id, uid, model.ApplicationID(ctx))
+	if err != nil {
+		log.Printf("Could not delete personal access token: %v. Reason: %v\n", id, err)
+		return apperrors.NewInternal()
+	}
+
+	if n, err := res.RowsAffected(); err != nil || n < 1 {
+		return apperrors.NewNotFound("personal access token", id.String())
+	}
+
+	return nil
+}
+
+// Touch records the time and ip address a personal access token was last used at
+func (r *PGPersonalAccessTokenRepository) Touch(ctx context.Context, id uuid.UUID, ip string, at time.Time) error {
+	query := "UPDATE personal_access_tokens SET last_used_at=$2, last_used_ip=$3 WHERE id=$1"
+
+	if _, err := r.DB.ExecContext(ctx, query, id, at, ip); err != nil {
+		log.Printf("Could not record use of personal access token: %v. Reason: %v\n", id, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
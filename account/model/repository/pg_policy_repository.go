@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// PGPolicyRepository is data/repository implementation
+// of service layer PolicyRepository. Every query is scoped to the application in context
+type PGPolicyRepository struct {
+	DB *sqlx.DB
+}
+
+// NewPolicyRepository is a factory for initializing Policy Repositories
+func NewPolicyRepository(db *sqlx.DB) model.PolicyRepository {
+	return &PGPolicyRepository{
+		DB: db,
+	}
+}
+
+// FindActive fetches the latest version of each policy of the application
+func (r *PGPolicyRepository) FindActive(ctx context.Context) ([]*model.Policy, error) {
+	policies := []*model.Policy{}
+
+	query := `SELECT DISTINCT ON (name) * FROM policies WHERE tenant_id=$1
+		ORDER BY name, version DESC`
+
+	if err := r.DB.SelectContext(ctx, &policies, query, model.ApplicationID(ctx)); err != nil {
+		log.Printf("Could not get policies. Reason: %v\n", err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return policies, nil
+}
+
+// FindVersions fetches every version of a policy, the latest first
+func (r *PGPolicyRepository) FindVersions(ctx context.Context, name string) ([]*model.Policy, error) {
+	policies := []*model.Policy{}
+
+	query := "SELECT * FROM policies WHERE tenant_id=$1 AND name=$2 ORDER BY version DESC"
+
+	if err := r.DB.SelectContext(ctx, &policies, query, model.ApplicationID(ctx), name); err != nil {
+		log.Printf("Could not get versions of policy: %v. Reason: %v\n", name, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	if len(policies) == 0 {
+		return nil, apperrors.NewNotFound("policy", name)
+	}
+
+	return policies, nil
+}
+
+// FindVersion fetches a version of a policy
+func (r *PGPolicyRepository) FindVersion(ctx context.Context, name string, version int) (*model.Policy, error) {
+	p := &model.Policy{}
+
+	query := "SELECT * FROM policies WHERE tenant_id=$1 AND name=$2 AND version=$3"
+
+	if err := r.DB.GetContext(ctx, p, query, model.ApplicationID(ctx), name, version); err != nil {
+		return nil, apperrors.NewNotFound("policy version", name+"@"+strconv.Itoa(version))
+	}
+
+	return p, nil
+}
+
+// Create adds a version to a policy, following its latest one. Versions published
+// concurrently conflict rather than one silently replacing the other
+func (r *PGPolicyRepository) Create(ctx context.Context, p *model.Policy) error {
+	query := `INSERT INTO policies (tenant_id, name, version, description, effect, actions, expression)
+		SELECT $1, $2, COALESCE(MAX(version), 0) + 1, $3, $4, $5, $6
+		FROM policies WHERE tenant_id=$1 AND name=$2
+		RETURNING *`
+
+	if err := r.DB.GetContext(ctx, p, query, model.ApplicationID(ctx), p.Name, p.Description, p.Effect, p.Actions, p.Expression); err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			return apperrors.NewConflict("policy", p.Name)
+		}
+
+		log.Printf("Could not create policy: %v. Reason: %v\n", p.Name, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// Delete deletes every version of a policy
+func (r *PGPolicyRepository) Delete(ctx context.Context, name string) error {
+	query := "DELETE FROM policies WHERE tenant_id=$1 AND name=$2"
+
+	res, err := r.DB.ExecContext(ctx, query, model.ApplicationID(ctx), name)
+	if err != nil {
+		log.Printf("Could not delete policy: %v. Reason: %v\n", name, err)
+		return apperrors.NewInternal()
+	}
+
+	if n, err := res.RowsAffected(); err != nil || n < 1 {
+		return apperrors.NewNotFound("policy", name)
+	}
+
+	return nil
+}
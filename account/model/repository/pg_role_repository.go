@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// selectRoles selects roles along with the names of their permissions, to be completed with a WHERE clause
+// on roles r and followed by groupRoles
+const selectRoles = `SELECT r.id, r.tenant_id, r.name, r.description,
+	COALESCE(json_agg(p.name ORDER BY p.name) FILTER (WHERE p.id IS NOT NULL), '[]') AS permissions
+	FROM roles r
+	LEFT JOIN role_permissions rp ON rp.role_id = r.id
+	LEFT JOIN permissions p ON p.id = rp.permission_id`
+
+const groupRoles = " GROUP BY r.id ORDER BY r.name"
+
+// PGRoleRepository is data/repository implementation
+// of service layer RoleRepository. Every query is scoped to the application in context
+type PGRoleRepository struct {
+	DB *sqlx.DB
+}
+
+// NewRoleRepository is a factory for initializing Role Repositories
+func NewRoleRepository(db *sqlx.DB) model.RoleRepository {
+	return &PGRoleRepository{
+		DB: db,
+	}
+}
+
+// FindRoles fetches the roles of the application along with their permissions
+func (r *PGRoleRepository) FindRoles(ctx context.Context) ([]*model.Role, error) {
+	roles := []*model.Role{}
+
+	query := selectRoles + " WHERE r.tenant_id=$1" + groupRoles
+
+	if err := r.DB.SelectContext(ctx, &roles, query, model.ApplicationID(ctx)); err != nil {
+		log.Printf("Could not get roles. Reason: %v\n", err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return roles, nil
+}
+
+// CreateRole creates a role without permissions
+func (r *PGRoleRepository) CreateRole(ctx context.Context, role *model.Role) error {
+	query := `INSERT INTO roles (tenant_id, name, description) VALUES ($1, $2, $3)
+		RETURNING id, tenant_id, name, description`
+
+	if err := r.DB.GetContext(ctx, role, query, model.ApplicationID(ctx), role.Name, role.Description); err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			return apperrors.NewConflict("role", role.Name)
+		}
+
+		log.Printf("Could not create role: %v. Reason: %v\n", role.Name, err)
+		return apperrors.NewInternal()
+	}
+
+	role.Permissions = model.Names{}
+
+	return nil
+}
+
+// DeleteRole deletes a role, unassigning it from every user
+func (r *PGRoleRepository) DeleteRole(ctx context.Context, id uuid.UUID) error {
+	query := "DELETE FROM roles WHERE id=$1 AND tenant_id=$2"
+
+	res, err := r.DB.ExecContext(ctx, query, id, model.ApplicationID(ctx))
+	if err != nil {
+		log.Printf("Could not delete role: %v. Reason: %v\n", id, err)
+		return apperrors.NewInternal()
+	}
+
+	if n, err := res.RowsAffected(); err != nil || n < 1 {
+		return apperrors.NewNotFound("role", id.String())
+	}
+
+	return nil
+}
+
+// FindPermissions fetches the permissions of the application
+func (r *PGRoleRepository) FindPermissions(ctx context.Context) ([]*model.Permission, error) {
+	permissions := []*model.Permission{}
+
+	query := "SELECT * FROM permissions WHERE tenant_id=$1 ORDER BY name"
+
+	if err := r.DB.SelectContext(ctx, &permissions, query, model.ApplicationID(ctx)); err != nil {
+		log.Printf("Could not get permissions. Reason: %v\n", err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return permissions, nil
+}
+
+// CreatePermission creates a permission
+func (r *PGRoleRepository) CreatePermission(ctx context.Context, p *model.Permission) error {
+	query := "INSERT INTO permissions (tenant_id, name, description) VALUES ($1, $2, $3) RETURNING *"
+
+	if err := r.DB.GetContext(ctx, p, query, model.ApplicationID(ctx), p.Name, p.Description); err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			return apperrors.NewConflict("permission", p.Name)
+		}
+
+		log.Printf("Could not create permission: %v. Reason: %v\n", p.Name, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// DeletePermission deletes a permission, revoking it from every role
+func (r *PGRoleRepository) DeletePermission(ctx context.Context, id uuid.UUID) error {
+	query := "DELETE FROM permissions WHERE id=$1 AND tenant_id=$2"
+
+	res, err := r.DB.ExecContext(ctx, query, id, model.ApplicationID(ctx))
+	if err != nil {
+		log.Printf("Could not delete permission: %v. Reason: %v\n", id, err)
+		return apperrors.NewInternal()
+	}
+
+	if n, err := res.RowsAffected(); err != nil || n < 1 {
+		return apperrors.NewNotFound("permission", id.String())
+	}
+
+	return nil
+}
+
+// GrantPermission adds a permission to a role, both of the application
+func (r *PGRoleRepository) GrantPermission(ctx context.Context, roleID uuid.UUID, permissionID uuid.UUID) error {
+	// the no-op update on conflict makes granting a permission twice still affect a row
+	query := `INSERT INTO role_permissions (role_id, permission_id)
+		SELECT r.id, p.id FROM roles r JOIN permissions p ON p.tenant_id = r.tenant_id
+		WHERE r.id=$1 AND p.id=$2 AND r.tenant_id=$3
+		ON CONFLICT (role_id, permission_id) DO UPDATE SET role_id=EXCLUDED.role_id`
+
+	res, err := r.DB.ExecContext(ctx, query, roleID, permissionID, model.ApplicationID(ctx))
+	if err != nil {
+		log.Printf("Could not grant permission: %v to role: %v. Reason: %v\n", permissionID, roleID, err)
+		return apperrors.NewInternal()
+	}
+
+	if n, err := res.RowsAffected(); err != nil || n < 1 {
+		return apperrors.NewNotFound("role permission", roleID.String()+"/"+permissionID.String())
+	}
+
+	return nil
+}
+
+// RevokePermission removes a permission from a role
+func (r *PGRoleRepository) RevokePermission(ctx context.Context, roleID uuid.UUID, permissionID uuid.UUID) error {
+	query := `DELETE FROM role_permissions WHERE permission_id=$2
+		AND role_id IN (SELECT id FROM roles WHERE id=$1 AND tenant_id=$3)`
+
+	res, err := r.DB.ExecContext(ctx, query, roleID, permissionID, model.ApplicationID(ctx))
+	if err != nil {
+		log.Printf("Could not revoke permission: %v from role: %v. Reason: %v\n", permissionID, roleID, err)
+		return apperrors.NewInternal()
+	}
+
+	if n, err := res.RowsAffected(); err != nil || n < 1 {
+		return apperrors.NewNotFound("role permission", roleID.String()+"/"+permissionID.String())
+	}
+
+	return nil
+}
+
+// FindUserRoles fetches the roles assigned to a user along with their permissions
+func (r *PGRoleRepository) FindUserRoles(ctx context.Context, uid uuid.UUID) ([]*model.Role, error) {
+	roles := []*model.Role{}
+
+	query := selectRoles + " WHERE r.tenant_id=$1 AND r.id IN (SELECT role_id FROM user_roles WHERE uid=$2)" + groupRoles
+
+	if err := r.DB.SelectContext(ctx, &roles, query, model.ApplicationID(ctx), uid); err != nil {
+		log.Printf("Could not get roles of uid: %v. Reason: %v\n", uid, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return roles, nil
+}
+
+// AssignRole assigns a role to a user, both of the application
+func (r *PGRoleRepository) AssignRole(ctx context.Context, uid uuid.UUID, roleID uuid.UUID) error {
+	// the no-op update on conflict makes assigning a role twice still affect a row
+	query := `INSERT INTO user_roles (uid, role_id)
+		SELECT u.uid, r.id FROM users u JOIN roles r ON r.tenant_id = u.tenant_id
+		WHERE u.uid=$1 AND r.id=$2 AND r.tenant_id=$3
+		ON CONFLICT (uid, role_id) DO UPDATE SET role_id=EXCLUDED.role_id`
+
+	res, err := r.DB.ExecContext(ctx, query, uid, roleID, model.ApplicationID(ctx))
+	if err != nil {
+		log.Printf("Could not assign role: %v to uid: %v. Reason: %v\n", roleID, uid, err)
+		return apperrors.NewInternal()
+	}
+
+	if n, err := res.RowsAffected(); err != nil || n < 1 {
+		return apperrors.NewNotFound("user role", uid.String()+"/"+roleID.String())
+	}
+
+	return nil
+}
+
+// UnassignRole removes a role from a user
+func (r *PGRoleRepository) UnassignRole(ctx context.Context, uid uuid.UUID, roleID uuid.UUID) error {
+	query := `DELETE FROM user_roles WHERE uid=$1
+		AND role_id IN (SELECT id FROM roles WHERE id=$2 AND tenant_id=$3)`
+
+	res, err := r.DB.ExecContext(ctx, query, uid, roleID, model.ApplicationID(ctx))
+	if err != nil {
+		log.Printf("Could not unassign role: %v from uid: %v. Reason: %v\n", roleID, uid, err)
+		return apperrors.NewInternal()
+	}
+
+	if n, err := res.RowsAffected(); err != nil || n < 1 {
+		return apperrors.NewNotFound("user role", uid.String()+"/"+roleID.String())
+	}
+
+	return nil
+}
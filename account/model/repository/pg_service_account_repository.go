@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// PGServiceAccountRepository is data/repository implementation
+// of service layer ServiceAccountRepository. Every query is scoped to the application in context
+type PGServiceAccountRepository struct {
+	DB *sqlx.DB
+}
+
+// NewServiceAccountRepository is a factory for initializing Service Account Repositories
+func NewServiceAccountRepository(db *sqlx.DB) model.ServiceAccountRepository {
+	return &PGServiceAccountRepository{
+		DB: db,
+	}
+}
+
+// Create creates a service account
+func (r *PGServiceAccountRepository) Create(ctx context.Context, sa *model.ServiceAccount) error {
+	query := `INSERT INTO service_accounts (tenant_id, org_id, name, description, public_key, scopes, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING *`
+
+	if err := r.DB.GetContext(ctx, sa, query, model.ApplicationID(ctx), sa.OrgID, sa.Name, sa.Description, sa.PublicKey, sa.Scopes, sa.CreatedBy); err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "foreign_key_violation" {
+			return apperrors.NewNotFound("organization", sa.OrgID.String())
+		}
+
+		log.Printf("Could not create service account: %v. Reason: %v\n", sa.Name, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// FindByID fetches a service account by id
+func (r *PGServiceAccountRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.ServiceAccount, error) {
+	sa := &model.ServiceAccount{}
+
+	query := "SELECT * FROM service_accounts WHERE id=$1 AND tenant_id=$2"
+
+	if err := r.DB.GetContext(ctx, sa, query, id, model.ApplicationID(ctx)); err != nil {
+		return nil, apperrors.NewNotFound("service account", id.String())
+	}
+
+	return sa, nil
+}
+
+// FindAll fetches the service accounts of an organization, or of the whole application when orgID is uuid.Nil
+func (r *PGServiceAccountRepository) FindAll(ctx context.Context, orgID uuid.UUID) ([]*model.ServiceAccount, error) {
+	accounts := []*model.ServiceAccount{}
+
+	query := `SELECT * FROM service_accounts WHERE tenant_id=$1 AND ($2 = $3 OR org_id=$2)
+		ORDER BY name`
+
+	if err := r.DB.SelectContext(ctx, &accounts, query, model.ApplicationID(ctx), orgID, uuid.Nil); err != nil {
+		log.Printf("Could not get service accounts of organization: %v. Reason: %v\n", orgID, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return accounts, nil
+}
+
+// UpdateKey replaces the public key of a service account
+func (r *PGServiceAccountRepository) UpdateKey(ctx context.Context, id uuid.UUID, publicKey string) (*model.ServiceAccount, error) {
+	sa := &model.ServiceAccount{}
+
+	query := "UPDATE service_accounts SET public_key=$3 WHERE id=$1 AND tenant_id=$2 RETURNING *"
+
+	if err := r.DB.GetContext(ctx, sa, query, id, model.ApplicationID(ctx), publicKey); err != nil {
+		return nil, apperrors.NewNotFound("service account", id.String())
+	}
+
+	return sa, nil
+}
+
+// Delete deletes a service account, leaving its audit trail
+func (r *PGServiceAccountRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := "DELETE FROM service_accounts WHERE id=$1 AND tenant_id=$2"
+
+	res, err := r.DB.ExecContext(ctx, query, id, model.ApplicationID(ctx))
+	if err != nil {
+		log.Printf("Could not delete service account: %v. Reason: %v\n", id, err)
+		return apperrors.NewInternal()
+	}
+
+	if n, err := res.RowsAffected(); err != nil || n < 1 {
+		return apperrors.NewNotFound("service account", id.String())
+	}
+
+	return nil
+}
+
+// UseAssertion records the identifier of an assertion a service account authenticated with, failing with
+// a conflict when it was already used. Identifiers of expired assertions are forgotten along the way
+func (r *PGServiceAccountRepository) UseAssertion(ctx context.Context, id uuid.UUID, jti string, expiresAt time.Time) error {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Could not begin transaction. Reason: %v\n", err)
+		return apperrors.NewInternal()
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM service_account_assertions WHERE service_account_id=$1 AND expires_at < now()", id); err != nil {
+		log.Printf("Could not delete expired assertions of service account: %v. Reason: %v\n", id, err)
+		return apperrors.NewInternal()
+	}
+
+	query := "INSERT INTO service_account_assertions (service_account_id, jti, expires_at) VALUES ($1, $2, $3)"
+
+	if _, err := tx.ExecContext(ctx, query, id, jti, expiresAt); err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			return apperrors.NewConflict("assertion", jti)
+		}
+
+		log.Printf("Could not record assertion of service account: %v. Reason: %v\n", id, err)
+		return apperrors.NewInternal()
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Could not commit assertion of service account: %v. Reason: %v\n", id, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// AddEvent appends an event to the audit trail of a service account
+func (r *PGServiceAccountRepository) AddEvent(ctx context.Context, e *model.ServiceAccountEvent) error {
+	query := `INSERT INTO service_account_events (tenant_id, service_account_id, type, actor, ip, detail)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING *`
+
+	if err := r.DB.GetContext(ctx, e, query, model.ApplicationID(ctx), e.ServiceAccountID, e.Type, e.Actor, e.IP, e.Detail); err != nil {
+		log.Printf("Could not record %v event of service account: %v. Reason: %v\n", e.Type, e.ServiceAccountID, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// FindEvents fetches the latest events of the audit trail of a service account, the latest first
+func (r *PGServiceAccountRepository) FindEvents(ctx context.Context, id uuid.UUID, limit int) ([]*model.ServiceAccountEvent, error) {
+	events := []*model.ServiceAccountEvent{}
+
+	query := `SELECT * FROM service_account_events WHERE service_account_id=$1 AND tenant_id=$2
+		ORDER BY created_at DESC LIMIT $3`
+
+	if err := r.DB.SelectContext(ctx, &events, query, id, model.ApplicationID(ctx), limit); err != nil {
+		log.Printf("Could not get events of service account: %v. Reason: %v\n", id, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return events, nil
+}
+
+// FindExchangePolicy fetches the token exchange policy of a service account
+func (r *PGServiceAccountRepository) FindExchangePolicy(ctx context.Context, id uuid.UUID) (*model.TokenExchangePolicy, error) {
+	p := &model.TokenExchangePolicy{}
+
+	query := "SELECT * FROM token_exchange_policies WHERE service_account_id=$1 AND tenant_id=$2"
+
+	if err := r.DB.GetContext(ctx, p, query, id, model.ApplicationID(ctx)); err != nil {
+		return nil, apperrors.NewNotFound("token exchange policy", id.String())
+	}
+
+	return p, nil
+}
+
+// SetExchangePolicy creates or replaces the token exchange policy of a service account
+func (r *PGServiceAccountRepository) SetExchangePolicy(ctx context.Context, p *model.TokenExchangePolicy) error {
+	query := `INSERT INTO token_exchange_policies (service_account_id, tenant_id, audiences, scopes, max_expires_in)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (service_account_id) DO UPDATE
+		SET audiences=excluded.audiences, scopes=excluded.scopes, max_expires_in=excluded.max_expires_in, updated_at=now()
+		RETURNING *`
+
+	if err := r.DB.GetContext(ctx, p, query, p.ServiceAccountID, model.ApplicationID(ctx), p.Audiences, p.Scopes, p.MaxExpiresIn); err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "foreign_key_violation" {
+			return apperrors.NewNotFound("service account", p.ServiceAccountID.String())
+		}
+
+		log.Printf("Could not set token exchange policy of service account: %v. Reason: %v\n", p.ServiceAccountID, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// DeleteExchangePolicy deletes the token exchange policy of a service account
+func (r *PGServiceAccountRepository) DeleteExchangePolicy(ctx context.Context, id uuid.UUID) error {
+	query := "DELETE FROM token_exchange_policies WHERE service_account_id=$1 AND tenant_id=$2"
+
+	res, err := r.DB.ExecContext(ctx, query, id, model.ApplicationID(ctx))
+	if err != nil {
+		log.Printf("Could not delete token exchange policy of service account: %v. Reason: %v\n", id, err)
+		return apperrors.NewInternal()
+	}
+
+	if n, err := res.RowsAffected(); err != nil || n < 1 {
+		return apperrors.NewNotFound("token exchange policy", id.String())
+	}
+
+	return nil
+}
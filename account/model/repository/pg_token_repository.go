@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// PGTokenRepository is data/repository implementation
+// of service layer TokenRepository
+type PGTokenRepository struct {
+	DB *sqlx.DB
+}
+
+// NewTokenRepository is a factory for initializing Token Repositories
+func NewTokenRepository(db *sqlx.DB) model.TokenRepository {
+	return &PGTokenRepository{
+		DB: db,
+	}
+}
+
+// SetRefreshToken stores a refresh token id along with its expiration
+func (r *PGTokenRepository) SetRefreshToken(ctx context.Context, uid uuid.UUID, tokenID string, expiresIn time.Duration) error {
+	query := "INSERT INTO refresh_tokens (token_id, uid, expires_at) VALUES ($1, $2, $3)"
+
+	if _, err := r.DB.ExecContext(ctx, query, tokenID, uid, time.Now().Add(expiresIn)); err != nil {
+		log.Printf("Could not set refresh token for uid: %v, tokenID: %v. Reason: %v\n", uid, tokenID, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// DeleteRefreshToken removes a refresh token, returning an authorization error
+// if the token does not exist (it was already used or revoked)
+func (r *PGTokenRepository) DeleteRefreshToken(ctx context.Context, uid uuid.UUID, prevTokenID string) error {
+	query := "DELETE FROM refresh_tokens WHERE token_id=$1 AND uid=$2 AND expires_at > now()"
+
+	res, err := r.DB.ExecContext(ctx, query, prevTokenID, uid)
+	if err != nil {
+		log.Printf("Could not delete refresh token for uid: %v, tokenID: %v. Reason: %v\n", uid, prevTokenID, err)
+		return apperrors.NewInternal()
+	}
+
+	if n, err := res.RowsAffected(); err != nil || n < 1 {
+		log.Printf("Refresh token for uid: %v, tokenID: %v does not exist\n", uid, prevTokenID)
+		return apperrors.NewAuthorization("Invalid refresh token")
+	}
+
+	return nil
+}
+
+// DeleteUserRefreshTokens removes all of a user's refresh tokens except keepTokenID.
+// An empty keepTokenID removes every refresh token of the user
+func (r *PGTokenRepository) DeleteUserRefreshTokens(ctx context.Context, uid uuid.UUID, keepTokenID string) error {
+	query := "DELETE FROM refresh_tokens WHERE uid=$1 AND token_id <> $2"
+
+	if _, err := r.DB.ExecContext(ctx, query, uid, keepTokenID); err != nil {
+		log.Printf("Could not delete refresh tokens for uid: %v. Reason: %v\n", uid, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// FindUserRefreshTokens fetches the refresh tokens of a user which have not expired yet, the latest to expire first
+func (r *PGTokenRepository) FindUserRefreshTokens(ctx context.Context, uid uuid.UUID) ([]*model.Session, error) {
+	sessions := []*model.Session{}
+
+	query := "SELECT token_id, expires_at FROM refresh_tokens WHERE uid=$1 AND expires_at > now() ORDER BY expires_at DESC"
+
+	if err := r.DB.SelectContext(ctx, &sessions, query, uid); err != nil {
+		log.Printf("Could not get refresh tokens for uid: %v. Reason: %v\n", uid, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return sessions, nil
+}
@@ -2,7 +2,12 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -12,7 +17,7 @@ import (
 )
 
 // PGUserRepository is data/repository implementation
-// of service layer UserRepository
+// of service layer UserRepository. Every query is scoped to the application in context
 type PGUserRepository struct {
 	DB *sqlx.DB
 }
@@ -26,9 +31,9 @@ func NewUserRepository(db *sqlx.DB) model.UserRepository {
 
 // Create reaches out to database SQLX api
 func (r *PGUserRepository) Create(ctx context.Context, u *model.User) error {
-	query := "INSERT INTO users (email, password) VALUES ($1, $2) RETURNING *"
+	query := "INSERT INTO users (tenant_id, email, password, status) VALUES ($1, $2, $3, $4) RETURNING *"
 
-	if err := r.DB.Get(u, query, u.Email, u.Password); err != nil {
+	if err := r.DB.Get(u, query, model.ApplicationID(ctx), u.Email, u.Password, u.Status); err != nil {
 		// check unique constraint
 		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
 			log.Printf("Could not create a user with email: %v. Reason: %v\n", u.Email, err.Code.Name())
@@ -45,12 +50,268 @@ func (r *PGUserRepository) Create(ctx context.Context, u *model.User) error {
 func (r *PGUserRepository) FindByID(ctx context.Context, uid uuid.UUID) (*model.User, error) {
 	user := &model.User{}
 
-	query := "SELECT * FROM users WHERE uid=$1"
+	query := "SELECT * FROM users WHERE uid=$1 AND tenant_id=$2"
 
 	// we need to actually check errors as it could be something other than not found
-	if err := r.DB.Get(user, query, uid); err != nil {
+	if err := r.DB.Get(user, query, uid, model.ApplicationID(ctx)); err != nil {
 		return user, apperrors.NewNotFound("uid", uid.String())
 	}
 
 	return user, nil
 }
+
+// UpdatePassword replaces the password of a user, restarting its age and
+// fulfilling any password reset required of the user
+func (r *PGUserRepository) UpdatePassword(ctx context.Context, uid uuid.UUID, password string) error {
+	query := `UPDATE users SET password=$1, password_changed_at=now(), password_reset_required=false
+		WHERE uid=$2 AND tenant_id=$3`
+
+	res, err := r.DB.ExecContext(ctx, query, password, uid, model.ApplicationID(ctx))
+	if err != nil {
+		log.Printf("Could not update password for uid: %v. Reason: %v\n", uid, err)
+		return apperrors.NewInternal()
+	}
+
+	if n, err := res.RowsAffected(); err != nil || n < 1 {
+		return apperrors.NewNotFound("uid", uid.String())
+	}
+
+	return nil
+}
+
+// UpdatePasswordHash replaces the stored hash of the same password, eg with a stronger one,
+// leaving the age of the password untouched. The hash is only replaced while it still is oldPassword,
+// a password changed in the meantime being left as it is
+func (r *PGUserRepository) UpdatePasswordHash(ctx context.Context, uid uuid.UUID, oldPassword string, password string) error {
+	query := "UPDATE users SET password=$1 WHERE uid=$2 AND tenant_id=$3 AND password=$4"
+
+	if _, err := r.DB.ExecContext(ctx, query, password, uid, model.ApplicationID(ctx), oldPassword); err != nil {
+		log.Printf("Could not update password for uid: %v. Reason: %v\n", uid, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// FindByEmail retrieves user row by email address. Deleted users no longer hold on to their
+// email address and are not found by it
+func (r *PGUserRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
+	user := &model.User{}
+
+	query := "SELECT * FROM users WHERE email=$1 AND tenant_id=$2 AND status<>'deleted'"
+
+	if err := r.DB.GetContext(ctx, user, query, email, model.ApplicationID(ctx)); err != nil {
+		log.Printf("Unable to get user with email address: %v. Err: %v\n", email, err)
+		return user, apperrors.NewNotFound("email", email)
+	}
+
+	return user, nil
+}
+
+// SetEmailVerified marks the email address of a user as verified, as long as
+// the user's email is still the one that was verified
+func (r *PGUserRepository) SetEmailVerified(ctx context.Context, uid uuid.UUID, email string) error {
+	query := "UPDATE users SET email_verified=true WHERE uid=$1 AND email=$2 AND tenant_id=$3"
+
+	res, err := r.DB.ExecContext(ctx, query, uid, email, model.ApplicationID(ctx))
+	if err != nil {
+		log.Printf("Could not verify email for uid: %v. Reason: %v\n", uid, err)
+		return apperrors.NewInternal()
+	}
+
+	if n, err := res.RowsAffected(); err != nil || n < 1 {
+		return apperrors.NewNotFound("email", email)
+	}
+
+	return nil
+}
+
+// UpdateEmail replaces the email address of a user with an address the user has
+// proven to own, returning the updated user
+func (r *PGUserRepository) UpdateEmail(ctx context.Context, uid uuid.UUID, email string) (*model.User, error) {
+	user := &model.User{}
+
+	query := "UPDATE users SET email=$1, email_verified=true WHERE uid=$2 AND tenant_id=$3 RETURNING *"
+
+	if err := r.DB.GetContext(ctx, user, query, email, uid, model.ApplicationID(ctx)); err != nil {
+		// check unique constraint
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			log.Printf("Could not update email of uid: %v to: %v. Reason: %v\n", uid, email, err.Code.Name())
+			return nil, apperrors.NewConflict("email", email)
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.NewNotFound("uid", uid.String())
+		}
+
+		log.Printf("Could not update email of uid: %v to: %v. Reason: %v\n", uid, email, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return user, nil
+}
+
+// Update sets the provided profile fields of a user, as long as the user is still at
+// the given version, and returns the updated row
+func (r *PGUserRepository) Update(ctx context.Context, uid uuid.UUID, version int, d *model.UserDetails) (*model.User, error) {
+	user := &model.User{}
+
+	query := `UPDATE users SET name=COALESCE($1, name), website=COALESCE($2, website), version=version+1
+		WHERE uid=$3 AND version=$4 AND tenant_id=$5 RETURNING *`
+
+	if err := r.DB.GetContext(ctx, user, query, d.Name, d.Website, uid, version, model.ApplicationID(ctx)); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Could not update details of uid: %v. Reason: %v\n", uid, err)
+			return nil, apperrors.NewInternal()
+		}
+
+		// either the user does not exist or it was updated by someone else
+		if _, err := r.FindByID(ctx, uid); err != nil {
+			return nil, err
+		}
+
+		return nil, apperrors.NewOutdated("user", uid.String())
+	}
+
+	return user, nil
+}
+
+// UpdateImage sets the urls of the profile image of a user and returns the updated row
+func (r *PGUserRepository) UpdateImage(ctx context.Context, uid uuid.UUID, imageURL string, variants model.ImageVariants) (*model.User, error) {
+	user := &model.User{}
+
+	query := "UPDATE users SET image_url=$1, image_variants=$2 WHERE uid=$3 AND tenant_id=$4 RETURNING *"
+
+	if err := r.DB.GetContext(ctx, user, query, imageURL, variants, uid, model.ApplicationID(ctx)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.NewNotFound("uid", uid.String())
+		}
+
+		log.Printf("Could not update image of uid: %v. Reason: %v\n", uid, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return user, nil
+}
+
+// Search fetches a page of the users matching a filter, the most recently created first,
+// along with how many users match it in total
+func (r *PGUserRepository) Search(ctx context.Context, f *model.UserFilter) ([]*model.User, int, error) {
+	conditions := []string{"tenant_id=$1"}
+	args := []interface{}{model.ApplicationID(ctx)}
+
+	where := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if f.Query != "" {
+		where("(email ILIKE $%[1]d OR name ILIKE $%[1]d)", "%"+likeEscaper.Replace(f.Query)+"%")
+	}
+	if f.Status != "" {
+		where("status=$%d", f.Status)
+	} else {
+		where("status<>$%d", model.UserDeleted)
+	}
+	if f.EmailVerified != nil {
+		where("email_verified=$%d", *f.EmailVerified)
+	}
+	if f.CreatedAfter != nil {
+		where("created_at>=$%d", *f.CreatedAfter)
+	}
+	if f.CreatedBefore != nil {
+		where("created_at<$%d", *f.CreatedBefore)
+	}
+
+	var total int
+
+	countQuery := "SELECT count(*) FROM users WHERE " + strings.Join(conditions, " AND ")
+
+	if err := r.DB.GetContext(ctx, &total, countQuery, args...); err != nil {
+		log.Printf("Could not count users. Reason: %v\n", err)
+		return nil, 0, apperrors.NewInternal()
+	}
+
+	users := []*model.User{}
+
+	query := fmt.Sprintf("SELECT * FROM users WHERE %s ORDER BY created_at DESC, uid LIMIT $%d OFFSET $%d",
+		strings.Join(conditions, " AND "), len(args)+1, len(args)+2)
+
+	if err := r.DB.SelectContext(ctx, &users, query, append(args, f.Limit, f.Offset)...); err != nil {
+		log.Printf("Could not search users. Reason: %v\n", err)
+		return nil, 0, apperrors.NewInternal()
+	}
+
+	return users, total, nil
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns
+var likeEscaper = strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_")
+
+// SetStatus sets the status of a user along with the reason for it and returns the updated row.
+// Restoring a deleted user whose email address was signed up with since is a conflict
+func (r *PGUserRepository) SetStatus(ctx context.Context, uid uuid.UUID, status model.UserStatus, reason string) (*model.User, error) {
+	user := &model.User{}
+
+	query := `UPDATE users SET status=$1, status_reason=$2, status_changed_at=now()
+		WHERE uid=$3 AND tenant_id=$4 RETURNING *`
+
+	if err := r.DB.GetContext(ctx, user, query, status, reason, uid, model.ApplicationID(ctx)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.NewNotFound("uid", uid.String())
+		}
+
+		// check unique constraint, the email address of the user being taken by another one
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			log.Printf("Could not set status of uid: %v to: %v. Reason: %v\n", uid, status, err.Code.Name())
+
+			if u, err := r.FindByID(ctx, uid); err == nil {
+				return nil, apperrors.NewConflict("email", u.Email)
+			}
+
+			return nil, apperrors.NewInternal()
+		}
+
+		log.Printf("Could not set status of uid: %v. Reason: %v\n", uid, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return user, nil
+}
+
+// RequirePasswordReset requires a user to change their password when next signing in
+func (r *PGUserRepository) RequirePasswordReset(ctx context.Context, uid uuid.UUID) error {
+	query := "UPDATE users SET password_reset_required=true WHERE uid=$1 AND tenant_id=$2"
+
+	res, err := r.DB.ExecContext(ctx, query, uid, model.ApplicationID(ctx))
+	if err != nil {
+		log.Printf("Could not require password reset of uid: %v. Reason: %v\n", uid, err)
+		return apperrors.NewInternal()
+	}
+
+	if n, err := res.RowsAffected(); err != nil || n < 1 {
+		return apperrors.NewNotFound("uid", uid.String())
+	}
+
+	return nil
+}
+
+// PurgeDeleted deletes the users deleted before a time for good, along with their refresh tokens,
+// roles, memberships and other rows of their own, returning how many were
+func (r *PGUserRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, error) {
+	query := "DELETE FROM users WHERE tenant_id=$1 AND status=$2 AND status_changed_at<$3"
+
+	res, err := r.DB.ExecContext(ctx, query, model.ApplicationID(ctx), model.UserDeleted, deletedBefore)
+	if err != nil {
+		log.Printf("Could not purge deleted users. Reason: %v\n", err)
+		return 0, apperrors.NewInternal()
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("Could not count purged users. Reason: %v\n", err)
+		return 0, apperrors.NewInternal()
+	}
+
+	return int(n), nil
+}
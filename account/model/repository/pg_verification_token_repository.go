@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// PGVerificationTokenRepository is data/repository implementation
+// of service layer VerificationTokenRepository
+type PGVerificationTokenRepository struct {
+	DB *sqlx.DB
+}
+
+// NewVerificationTokenRepository is a factory for initializing Verification Token Repositories
+func NewVerificationTokenRepository(db *sqlx.DB) model.VerificationTokenRepository {
+	return &PGVerificationTokenRepository{
+		DB: db,
+	}
+}
+
+// Create stores a verification token
+func (r *PGVerificationTokenRepository) Create(ctx context.Context, t *model.VerificationToken) error {
+	query := `INSERT INTO verification_tokens (token_hash, uid, purpose, email, expires_at)
+		VALUES ($1, $2, $3, $4, $5) RETURNING *`
+
+	if err := r.DB.GetContext(ctx, t, query, t.TokenHash, t.UID, t.Purpose, t.Email, t.ExpiresAt); err != nil {
+		log.Printf("Could not create %v token for uid: %v. Reason: %v\n", t.Purpose, t.UID, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// Consume deletes a verification token and returns it, so a token can only ever be used once
+func (r *PGVerificationTokenRepository) Consume(ctx context.Context, purpose model.VerificationPurpose, tokenHash string) (*model.VerificationToken, error) {
+	t := &model.VerificationToken{}
+
+	query := "DELETE FROM verification_tokens WHERE token_hash=$1 AND purpose=$2 RETURNING *"
+
+	if err := r.DB.GetContext(ctx, t, query, tokenHash, purpose); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.NewNotFound("token", string(purpose))
+		}
+
+		log.Printf("Could not consume %v token. Reason: %v\n", purpose, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return t, nil
+}
+
+// FindLatest fetches the most recently created token of a user for the given purpose
+func (r *PGVerificationTokenRepository) FindLatest(ctx context.Context, uid uuid.UUID, purpose model.VerificationPurpose) (*model.VerificationToken, error) {
+	t := &model.VerificationToken{}
+
+	query := "SELECT * FROM verification_tokens WHERE uid=$1 AND purpose=$2 ORDER BY created_at DESC LIMIT 1"
+
+	if err := r.DB.GetContext(ctx, t, query, uid, purpose); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.NewNotFound("token", string(purpose))
+		}
+
+		log.Printf("Could not find %v token for uid: %v. Reason: %v\n", purpose, uid, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return t, nil
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"math"
+	"time"
+
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// slidingWindowEstimate approximates the number of requests of the last window from the counts of
+// the previous and current fixed windows, weighting the previous one by how much of it still overlaps
+func slidingWindowEstimate(prev int64, curr int64, window time.Duration, elapsed time.Duration) float64 {
+	return float64(prev)*float64(window-elapsed)/float64(window) + float64(curr)
+}
+
+// slidingWindowResult builds the result of a request given the counts after it was counted
+func slidingWindowResult(allowed bool, prev int64, curr int64, limit int, window time.Duration, elapsed time.Duration) *model.RateLimitResult {
+	estimate := slidingWindowEstimate(prev, curr, window, elapsed)
+
+	res := &model.RateLimitResult{
+		Allowed:   allowed,
+		Limit:     limit,
+		Remaining: max(limit-int(math.Ceil(estimate)), 0),
+		Reset:     window - elapsed,
+	}
+
+	if allowed {
+		return res
+	}
+
+	// find when the estimate, decreasing as the previous window slides out, leaves room for one request
+	room := float64(limit - 1)
+
+	switch {
+	case limit < 1:
+		res.RetryAfter = window - elapsed
+	case curr <= int64(limit-1) && prev > 0:
+		res.RetryAfter = time.Duration((1-(room-float64(curr))/float64(prev))*float64(window)) - elapsed
+	default:
+		// the current window alone is full, wait for it to become the previous one and slide out
+		next := time.Duration((1 - room/float64(curr)) * float64(window))
+		res.RetryAfter = window - elapsed + next
+	}
+
+	if res.RetryAfter < time.Second {
+		res.RetryAfter = time.Second
+	}
+
+	return res
+}
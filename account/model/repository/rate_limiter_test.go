@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+func TestRateLimiters(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	limiters := map[string]model.RateLimiter{
+		"memory": NewMemoryRateLimiter(),
+		"redis":  NewRedisRateLimiter(redis.NewClient(&redis.Options{Addr: mr.Addr()})),
+	}
+
+	for name, l := range limiters {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			for i := 1; i <= 3; i++ {
+				res, err := l.Allow(ctx, "signin:ip:1.2.3.4", 3, time.Minute)
+
+				assert.NoError(t, err)
+				assert.True(t, res.Allowed)
+				assert.Equal(t, 3, res.Limit)
+				assert.Equal(t, 3-i, res.Remaining)
+			}
+
+			res, err := l.Allow(ctx, "signin:ip:1.2.3.4", 3, time.Minute)
+
+			assert.NoError(t, err)
+			assert.False(t, res.Allowed)
+			assert.Equal(t, 0, res.Remaining)
+			assert.GreaterOrEqual(t, res.RetryAfter, time.Second)
+			assert.LessOrEqual(t, res.RetryAfter, 2*time.Minute)
+
+			// other keys have their own counters
+			res, err = l.Allow(ctx, "signin:ip:5.6.7.8", 3, time.Minute)
+
+			assert.NoError(t, err)
+			assert.True(t, res.Allowed)
+		})
+	}
+}
+
+func TestSlidingWindowEstimate(t *testing.T) {
+	// a quarter into the current window, three quarters of the previous window still count
+	estimate := slidingWindowEstimate(8, 2, time.Minute, 15*time.Second)
+
+	assert.Equal(t, float64(8), estimate)
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// slidingWindowScript atomically checks and counts a request against the counters of the
+// previous (KEYS[1]) and current (KEYS[2]) fixed windows, so every instance shares the limit
+var slidingWindowScript = redis.NewScript(`
+local prev = tonumber(redis.call('GET', KEYS[1]) or '0')
+local curr = tonumber(redis.call('GET', KEYS[2]) or '0')
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local elapsed = tonumber(ARGV[3])
+
+if prev * (window - elapsed) / window + curr + 1 > limit then
+	return {0, prev, curr}
+end
+
+curr = redis.call('INCR', KEYS[2])
+redis.call('PEXPIRE', KEYS[2], window * 2)
+
+return {1, prev, curr}
+`)
+
+// RedisRateLimiter is an implementation of handler layer RateLimiter using sliding window
+// counters stored in Redis, shared by every instance of the engine
+type RedisRateLimiter struct {
+	Redis *redis.Client
+}
+
+// NewRedisRateLimiter is a factory for initializing Redis Rate Limiters
+func NewRedisRateLimiter(client *redis.Client) model.RateLimiter {
+	return &RedisRateLimiter{
+		Redis: client,
+	}
+}
+
+// Allow counts a request for key if it fits within limit requests per sliding window
+func (r *RedisRateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (*model.RateLimitResult, error) {
+	now := time.Now()
+	start := now.Truncate(window)
+	elapsed := now.Sub(start)
+
+	prevKey := fmt.Sprintf("ratelimit:%s:%d", key, start.Add(-window).UnixMilli())
+	currKey := fmt.Sprintf("ratelimit:%s:%d", key, start.UnixMilli())
+
+	res, err := slidingWindowScript.Run(ctx, r.Redis, []string{prevKey, currKey}, limit, window.Milliseconds(), elapsed.Milliseconds()).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("unable to count request for key %s: %w", key, err)
+	}
+
+	return slidingWindowResult(res[0] == 1, res[1], res[2], limit, window, elapsed), nil
+}
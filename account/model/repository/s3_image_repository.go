@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// S3ImageRepository is an implementation of service layer ImageRepository
+// which stores images in a bucket of any S3 compatible object storage (AWS S3, MinIO, ...).
+// Requests use path-style addressing and are signed with AWS Signature Version 4
+type S3ImageRepository struct {
+	Client    *http.Client
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PublicURL string
+}
+
+// S3Config holds the settings used to reach the object storage
+type S3Config struct {
+	Endpoint  string // eg, https://s3.us-east-1.amazonaws.com or http://localhost:9000
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PublicURL string // base url objects are served from, defaults to Endpoint/Bucket
+}
+
+// NewS3ImageRepository is a factory for initializing S3 Image Repositories
+func NewS3ImageRepository(c *S3Config) model.ImageRepository {
+	endpoint := strings.TrimSuffix(c.Endpoint, "/")
+
+	publicURL := strings.TrimSuffix(c.PublicURL, "/")
+	if publicURL == "" {
+		publicURL = fmt.Sprintf("%s/%s", endpoint, c.Bucket)
+	}
+
+	return &S3ImageRepository{
+		Client:    &http.Client{Timeout: 30 * time.Second},
+		Endpoint:  endpoint,
+		Region:    c.Region,
+		Bucket:    c.Bucket,
+		AccessKey: c.AccessKey,
+		SecretKey: c.SecretKey,
+		PublicURL: publicURL,
+	}
+}
+
+// Upload puts the image in the bucket and returns its public url
+func (r *S3ImageRepository) Upload(ctx context.Context, objName string, contentType string, image io.Reader) (string, error) {
+	// the payload must be hashed for signing, images are small enough to be buffered
+	payload, err := io.ReadAll(image)
+	if err != nil {
+		log.Printf("Unable to read image: %v. Reason: %v\n", objName, err)
+		return "", apperrors.NewInternal()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, r.objectURL(objName), bytes.NewReader(payload))
+	if err != nil {
+		log.Printf("Unable to create upload request for image: %v. Reason: %v\n", objName, err)
+		return "", apperrors.NewInternal()
+	}
+
+	req.Header.Set("Content-Type", contentType)
+
+	if err := r.do(req, payload); err != nil {
+		log.Printf("Unable to upload image: %v. Reason: %v\n", objName, err)
+		return "", apperrors.NewInternal()
+	}
+
+	return fmt.Sprintf("%s/%s", r.PublicURL, url.PathEscape(objName)), nil
+}
+
+// Delete removes the image from the bucket. S3 does not report deleting a missing object as an error
+func (r *S3ImageRepository) Delete(ctx context.Context, objName string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, r.objectURL(objName), http.NoBody)
+	if err != nil {
+		log.Printf("Unable to create delete request for image: %v. Reason: %v\n", objName, err)
+		return apperrors.NewInternal()
+	}
+
+	if err := r.do(req, nil); err != nil {
+		log.Printf("Unable to delete image: %v. Reason: %v\n", objName, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+func (r *S3ImageRepository) objectURL(objName string) string {
+	return fmt.Sprintf("%s/%s/%s", r.Endpoint, url.PathEscape(r.Bucket), url.PathEscape(objName))
+}
+
+// do signs and sends the request, returning an error for any non 2xx response
+func (r *S3ImageRepository) do(req *http.Request, payload []byte) error {
+	signV4(req, payload, r.AccessKey, r.SecretKey, r.Region, time.Now())
+
+	res, err := r.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("unexpected status %d: %s", res.StatusCode, body)
+	}
+
+	return nil
+}
+
+// signV4 adds the headers of AWS Signature Version 4 for the s3 service to the request.
+// See https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html
+func signV4(req *http.Request, payload []byte, accessKey string, secretKey string, region string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := fmt.Sprintf("host:%s\nx-amz-content-sha256:%s\nx-amz-date:%s\n", req.URL.Host, payloadHash, amzDate)
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, region)
+
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+secretKey), date)
+	signingKey = hmacSHA256(signingKey, region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature,
+	))
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testAccessKey = "minioadmin"
+	testSecretKey = "minioadmin-secret"
+	testRegion    = "us-east-1"
+	testBucket    = "avatars"
+)
+
+// s3StandIn is a minimal in-memory stand-in of an S3 compatible server (such as MinIO)
+// which verifies request signatures and supports putting and deleting objects
+type s3StandIn struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func (s *s3StandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	if !validSignature(r, body) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		s.objects[r.URL.Path] = body
+		s.types[r.URL.Path] = r.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusOK)
+	case http.MethodDelete:
+		delete(s.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// validSignature signs a copy of the request with the known secret and compares signatures
+func validSignature(r *http.Request, body []byte) bool {
+	amzDate, err := time.Parse("20060102T150405Z", r.Header.Get("X-Amz-Date"))
+	if err != nil {
+		return false
+	}
+
+	u := *r.URL
+	u.Host = r.Host
+
+	expected := &http.Request{Method: r.Method, URL: &u, Header: http.Header{}}
+	signV4(expected, body, testAccessKey, testSecretKey, testRegion, amzDate)
+
+	return expected.Header.Get("Authorization") == r.Header.Get("Authorization")
+}
+
+func TestS3ImageRepository(t *testing.T) {
+	standIn := &s3StandIn{
+		objects: map[string][]byte{},
+		types:   map[string]string{},
+	}
+	server := httptest.NewServer(standIn)
+	defer server.Close()
+
+	r := NewS3ImageRepository(&S3Config{
+		Endpoint:  server.URL,
+		Region:    testRegion,
+		Bucket:    testBucket,
+		AccessKey: testAccessKey,
+		SecretKey: testSecretKey,
+	})
+
+	t.Run("Upload and delete", func(t *testing.T) {
+		imageURL, err := r.Upload(context.TODO(), "image.png", "image/png", bytes.NewReader([]byte("image")))
+
+		assert.NoError(t, err)
+		assert.Equal(t, server.URL+"/avatars/image.png", imageURL)
+		assert.Equal(t, []byte("image"), standIn.objects["/avatars/image.png"])
+		assert.Equal(t, "image/png", standIn.types["/avatars/image.png"])
+
+		assert.NoError(t, r.Delete(context.TODO(), "image.png"))
+		assert.NotContains(t, standIn.objects, "/avatars/image.png")
+	})
+
+	t.Run("Public url", func(t *testing.T) {
+		r := NewS3ImageRepository(&S3Config{
+			Endpoint:  server.URL,
+			Region:    testRegion,
+			Bucket:    testBucket,
+			AccessKey: testAccessKey,
+			SecretKey: testSecretKey,
+			PublicURL: "https://cdn.test/avatars/",
+		})
+
+		imageURL, err := r.Upload(context.TODO(), "image.png", "image/png", bytes.NewReader([]byte("image")))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "https://cdn.test/avatars/image.png", imageURL)
+	})
+
+	t.Run("Rejected credentials", func(t *testing.T) {
+		r := NewS3ImageRepository(&S3Config{
+			Endpoint:  server.URL,
+			Region:    testRegion,
+			Bucket:    testBucket,
+			AccessKey: testAccessKey,
+			SecretKey: "wrong-secret",
+		})
+
+		_, err := r.Upload(context.TODO(), "image.png", "image/png", strings.NewReader("image"))
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Permissions guarding the admin routes of the account service itself
+const (
+	PermissionReadRoles  = "account.roles.read"
+	PermissionWriteRoles = "account.roles.write"
+)
+
+// Role is a named set of permissions which can be assigned to users of an application
+type Role struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	TenantID    uuid.UUID `db:"tenant_id" json:"-"`
+	Name        string    `db:"name" json:"name"`
+	Description string    `db:"description" json:"description"`
+	Permissions Names     `db:"permissions" json:"permissions"`
+}
+
+// Permission names an action users of an application may be allowed to perform
+type Permission struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	TenantID    uuid.UUID `db:"tenant_id" json:"-"`
+	Name        string    `db:"name" json:"name"`
+	Description string    `db:"description" json:"description"`
+}
+
+// Names is a list of names, stored as a json array
+type Names []string
+
+// Value stores the names as json
+func (n Names) Value() (driver.Value, error) {
+	if n == nil {
+		return []byte("[]"), nil
+	}
+
+	return json.Marshal(n)
+}
+
+// Scan reads names stored as json
+func (n *Names) Scan(src interface{}) error {
+	var b []byte
+
+	switch src := src.(type) {
+	case []byte:
+		b = src
+	case string:
+		b = []byte(src)
+	case nil:
+		*n = nil
+		return nil
+	default:
+		return fmt.Errorf("unable to scan %T into Names", src)
+	}
+
+	return json.Unmarshal(b, n)
+}
+
+// Contains reports whether name is one of the names
+func (n Names) Contains(name string) bool {
+	for _, v := range n {
+		if v == name {
+			return true
+		}
+	}
+
+	return false
+}
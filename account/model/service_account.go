@@ -0,0 +1,56 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Permissions guarding the admin routes managing the service accounts of an application
+const (
+	PermissionReadServiceAccounts  = "account.service_accounts.read"
+	PermissionWriteServiceAccounts = "account.service_accounts.write"
+)
+
+// ServiceAccount is a non human principal of an application, owned by one of its organizations or by
+// the application itself when OrgID is nil. It authenticates with JWT assertions signed by the private key
+// of its PublicKey and is issued access tokens limited to its Scopes
+type ServiceAccount struct {
+	ID          uuid.UUID  `db:"id" json:"id"`
+	TenantID    uuid.UUID  `db:"tenant_id" json:"-"`
+	OrgID       *uuid.UUID `db:"org_id" json:"orgId"`
+	Name        string     `db:"name" json:"name"`
+	Description string     `db:"description" json:"description"`
+	PublicKey   string     `db:"public_key" json:"publicKey"` // PEM encoded RSA or EC public key
+	Scopes      Names      `db:"scopes" json:"scopes"`
+	CreatedBy   uuid.UUID  `db:"created_by" json:"createdBy"`
+	CreatedAt   time.Time  `db:"created_at" json:"createdAt"`
+}
+
+// ServiceAccountEventType identifies an entry of the audit trail of a service account
+type ServiceAccountEventType string
+
+// "Set" of valid service account event types
+const (
+	ServiceAccountCreated       ServiceAccountEventType = "created"
+	ServiceAccountKeyRotated    ServiceAccountEventType = "key_rotated"
+	ServiceAccountDeleted       ServiceAccountEventType = "deleted"
+	ServiceAccountAuthenticated ServiceAccountEventType = "authenticated"
+	ServiceAccountRejected      ServiceAccountEventType = "assertion_rejected"
+
+	ServiceAccountExchangePolicyUpdated ServiceAccountEventType = "exchange_policy_updated"
+	ServiceAccountExchangePolicyDeleted ServiceAccountEventType = "exchange_policy_deleted"
+)
+
+// ServiceAccountEvent is an entry of the audit trail of a service account, kept after the account is deleted.
+// Actor is the user who made a change to the account, and is nil for the account's own activity
+type ServiceAccountEvent struct {
+	ID               uuid.UUID               `db:"id" json:"id"`
+	TenantID         uuid.UUID               `db:"tenant_id" json:"-"`
+	ServiceAccountID uuid.UUID               `db:"service_account_id" json:"serviceAccountId"`
+	Type             ServiceAccountEventType `db:"type" json:"type"`
+	Actor            *uuid.UUID              `db:"actor" json:"actor"`
+	IP               string                  `db:"ip" json:"ip"`
+	Detail           string                  `db:"detail" json:"detail"`
+	CreatedAt        time.Time               `db:"created_at" json:"createdAt"`
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Token types of RFC 8693 subject tokens are given as and exchanged tokens are issued as
+const (
+	TokenTypeAccessToken = "urn:ietf:params:oauth:token-type:access_token"
+	TokenTypeJWT         = "urn:ietf:params:oauth:token-type:jwt"
+)
+
+// TokenExchangePolicy limits the tokens a service account may obtain by exchanging the access tokens
+// of users, as of RFC 8693. Service accounts without one are not allowed to exchange tokens
+type TokenExchangePolicy struct {
+	ServiceAccountID uuid.UUID `db:"service_account_id" json:"serviceAccountId"`
+	TenantID         uuid.UUID `db:"tenant_id" json:"-"`
+	Audiences        Names     `db:"audiences" json:"audiences"`         // audiences tokens may be exchanged for
+	Scopes           Names     `db:"scopes" json:"scopes"`               // permissions of subjects exchanged tokens may keep
+	MaxExpiresIn     int       `db:"max_expires_in" json:"maxExpiresIn"` // seconds, the id token expiry when 0
+	UpdatedAt        time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+// TokenExchange is a request of a service account to exchange the access token of a subject for a token
+// of an audience. Scopes narrow the permissions of the subject, which are kept as far as the policy of
+// the service account allows when empty
+type TokenExchange struct {
+	SubjectToken string
+	Audience     string
+	Scopes       Names
+}
@@ -5,3 +5,14 @@ type TokenPair struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
 }
+
+// OAuthToken is an access token issued by the token endpoint, in the shape of an OAuth 2.0 token response
+type OAuthToken struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"` // seconds
+	Scope       string `json:"scope,omitempty"`
+
+	// IssuedTokenType is the type of the token issued by a token exchange, and is left out of other responses
+	IssuedTokenType string `json:"issued_token_type,omitempty"`
+}
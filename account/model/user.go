@@ -1,15 +1,77 @@
 package model
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
+// UserStatus tells whether a user may sign in
+type UserStatus string
+
+// "Set" of valid user statuses. Pending users are waiting for an admin to approve their signup,
+// and deleted users are kept for the retention period of their application before being purged
+const (
+	UserActive   UserStatus = "active"
+	UserDisabled UserStatus = "disabled"
+	UserPending  UserStatus = "pending"
+	UserDeleted  UserStatus = "deleted"
+)
+
 // User defines domain model and its json and db representations
 type User struct {
-	UID      uuid.UUID `db:"uid" json:"uid"`
-	Email    string    `db:"email" json:"email"`
-	Password string    `db:"password" json:"-"`
-	Name     string    `db:"name" json:"name"`
-	ImageURL string    `db:"image_url" json:"imageUrl"`
-	Website  string    `db:"website" json:"website"`
+	UID           uuid.UUID     `db:"uid" json:"uid"`
+	TenantID      uuid.UUID     `db:"tenant_id" json:"tenantId"`
+	Email         string        `db:"email" json:"email"`
+	Password      string        `db:"password" json:"-"`
+	Name          string        `db:"name" json:"name"`
+	ImageURL      string        `db:"image_url" json:"imageUrl"`
+	ImageVariants ImageVariants `db:"image_variants" json:"imageVariants"`
+	Website       string        `db:"website" json:"website"`
+	EmailVerified bool          `db:"email_verified" json:"emailVerified"`
+	Version       int           `db:"version" json:"version"`
+	Status        UserStatus    `db:"status" json:"status"`
+	CreatedAt     time.Time     `db:"created_at" json:"createdAt"`
+
+	// StatusReason is the reason the status of the user was last changed for, such as the one given by an admin,
+	// and StatusChangedAt when it was. StatusChangedAt is nil for users whose status never changed
+	StatusReason    string     `db:"status_reason" json:"statusReason,omitempty"`
+	StatusChangedAt *time.Time `db:"status_changed_at" json:"statusChangedAt,omitempty"`
+
+	PasswordChangedAt time.Time `db:"password_changed_at" json:"-"`
+	// PasswordResetRequired is set by admins forcing the user to change their password when next signing in
+	PasswordResetRequired bool `db:"password_reset_required" json:"passwordResetRequired"`
+
+	// Roles and Permissions the user has been granted within their application. They are
+	// carried as claims of their own in id tokens rather than as part of the user
+	Roles       Names `db:"-" json:"-"`
+	Permissions Names `db:"-" json:"-"`
+
+	// OrgID and OrgRole are the organization an id token was issued for and the role of the
+	// user within it, carried as claims of their own. OrgID is uuid.Nil for tokens of no organization
+	OrgID   uuid.UUID      `db:"-" json:"-"`
+	OrgRole MembershipRole `db:"-" json:"-"`
+
+	// PersonalAccessTokenID is the personal access token the user authenticated with, or uuid.Nil
+	// when they authenticated with an id token
+	PersonalAccessTokenID uuid.UUID `db:"-" json:"-"`
+
+	// ServiceAccountID is set, along with UID, when the principal of an access token is a service account
+	// rather than a user. Only the ids, tenant, organization and permissions of such users are set
+	ServiceAccountID uuid.UUID `db:"-" json:"-"`
+
+	// Actor is the admin acting as the user when the access token was issued by impersonating them, or nil.
+	// Downstream services are expected to refuse destructive operations to impersonated users
+	Actor *Actor `db:"-" json:"-"`
+
+	// Audience is the audience of an access token obtained by token exchange, meant for the services
+	// of that audience only. It is empty for tokens meant for the engine itself
+	Audience Names `db:"-" json:"-"`
+}
+
+// UserDetails holds the profile fields of a user which can be updated.
+// Nil fields are left unchanged
+type UserDetails struct {
+	Name    *string
+	Website *string
 }
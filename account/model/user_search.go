@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// Permissions guarding the admin routes managing the users of an application
+const (
+	PermissionReadUsers  = "account.users.read"
+	PermissionWriteUsers = "account.users.write"
+)
+
+// UserFilter selects users of an application, the most recently created first. Zero fields match every user,
+// except for deleted users which are only matched when filtering by their status
+type UserFilter struct {
+	Query         string // matched against the email and name of users
+	Status        UserStatus
+	EmailVerified *bool
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Limit         int
+	Offset        int
+}
+
+// UserPage is a page of the users matching a filter, along with how many match it in total
+type UserPage struct {
+	Users  []*User `json:"users"`
+	Total  int     `json:"total"`
+	Limit  int     `json:"limit"`
+	Offset int     `json:"offset"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VerificationPurpose identifies the action a VerificationToken confirms
+type VerificationPurpose string
+
+// "Set" of valid verification purposes
+const (
+	VerifyEmail VerificationPurpose = "verify_email"
+	ChangeEmail VerificationPurpose = "change_email" // sent to the new address to confirm the change
+	RevertEmail VerificationPurpose = "revert_email" // sent to the old address to undo the change
+	Unlock      VerificationPurpose = "unlock"       // sent when an account gets locked after failed signins
+	// RestoreAccount is sent when users delete their account, undoing it until the account is purged
+	RestoreAccount VerificationPurpose = "restore_account"
+)
+
+// VerificationToken is a single use token emailed to a user in order to confirm an action.
+// Only a hash of the token is ever stored
+type VerificationToken struct {
+	TokenHash string              `db:"token_hash"`
+	UID       uuid.UUID           `db:"uid"`
+	Purpose   VerificationPurpose `db:"purpose"`
+	Email     string              `db:"email"`
+	ExpiresAt time.Time           `db:"expires_at"`
+	CreatedAt time.Time           `db:"created_at"`
+}
@@ -0,0 +1,169 @@
+// Package passwordpolicy checks passwords against a model.PasswordPolicy
+package passwordpolicy
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ccojocar/zxcvbn-go"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"golang.org/x/net/publicsuffix"
+)
+
+// Rules reported in violations, used as the tag of invalid arguments
+const (
+	RuleMinLength = "min"
+	RuleMaxLength = "max"
+	RuleUppercase = "uppercase"
+	RuleLowercase = "lowercase"
+	RuleDigit     = "digit"
+	RuleSymbol    = "symbol"
+	RuleStrength  = "strength"
+	RuleEmail     = "email"
+	// RuleBreached is reported by callers checking passwords against breach corpora
+	RuleBreached = "breached"
+)
+
+// minEmailPartLength ignores parts of an email address too short to be meaningful
+const minEmailPartLength = 3
+
+// Violation is a rule of the policy a password does not satisfy.
+// Param holds the value of the rule, eg the minimum length
+type Violation struct {
+	Rule  string
+	Param string
+}
+
+// Check returns every rule of the policy the password violates. email is the
+// address of the user the password is for and may be empty when unknown
+func Check(p model.PasswordPolicy, password string, email string) []Violation {
+	var violations []Violation
+
+	length := utf8.RuneCountInString(password)
+
+	if length < p.MinLength {
+		violations = append(violations, Violation{RuleMinLength, strconv.Itoa(p.MinLength)})
+	}
+
+	if p.MaxLength > 0 && length > p.MaxLength {
+		// do not spend time scoring overly long passwords
+		return append(violations, Violation{RuleMaxLength, strconv.Itoa(p.MaxLength)})
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsLetter(r):
+			// letters without case, eg CJK, count as neither upper nor lowercase
+		default:
+			symbol = true
+		}
+	}
+
+	if p.RequireUppercase && !upper {
+		violations = append(violations, Violation{Rule: RuleUppercase})
+	}
+	if p.RequireLowercase && !lower {
+		violations = append(violations, Violation{Rule: RuleLowercase})
+	}
+	if p.RequireDigit && !digit {
+		violations = append(violations, Violation{Rule: RuleDigit})
+	}
+	if p.RequireSymbol && !symbol {
+		violations = append(violations, Violation{Rule: RuleSymbol})
+	}
+
+	if p.DisallowEmail && derivedFromEmail(password, email) {
+		violations = append(violations, Violation{Rule: RuleEmail})
+	}
+
+	if p.MinStrength > 0 && Strength(password, email) < p.MinStrength {
+		violations = append(violations, Violation{RuleStrength, strconv.Itoa(p.MinStrength)})
+	}
+
+	return violations
+}
+
+// Strength returns the zxcvbn score of the password, from 0 (too guessable)
+// to 4 (very unguessable). The email address and its parts are penalized
+// like dictionary words
+func Strength(password string, email string) int {
+	return zxcvbn.PasswordStrength(password, emailParts(email)).Score
+}
+
+// derivedFromEmail reports whether the password contains the email address,
+// its local part or domain name, ignoring case and separators
+func derivedFromEmail(password string, email string) bool {
+	normalized := normalize(password)
+
+	for _, part := range emailParts(email) {
+		part = normalize(part)
+
+		if len(part) < minEmailPartLength {
+			continue
+		}
+
+		if strings.Contains(normalized, part) || strings.Contains(normalized, reverse(part)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// emailParts splits an email address into its local part, the words of the
+// local part and the name of its registrable domain, leaving out subdomains and
+// public suffixes such as com.au, which would forbid common words like "com"
+func emailParts(email string) []string {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return nil
+	}
+
+	local, domain := email[:at], email[at+1:]
+
+	parts := []string{local}
+
+	words := strings.FieldsFunc(local, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > 1 {
+		parts = append(parts, words...)
+	}
+
+	// domains which are public suffixes themselves have no name of their own
+	registrable, err := publicsuffix.EffectiveTLDPlusOne(domain)
+	if err != nil {
+		return parts
+	}
+
+	return append(parts, registrable[:strings.Index(registrable, ".")])
+}
+
+// normalize lowercases s and drops everything but letters and digits
+func normalize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, s)
+}
+
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
@@ -0,0 +1,92 @@
+package passwordpolicy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+func TestCheck(t *testing.T) {
+	t.Run("Default policy accepts strong passwords", func(t *testing.T) {
+		violations := Check(model.DefaultPasswordPolicy, "tangerine-kayak-79", "bob@bob.com")
+
+		assert.Empty(t, violations)
+	})
+
+	t.Run("Long passphrases", func(t *testing.T) {
+		violations := Check(model.DefaultPasswordPolicy, "the quick onyx goblin jumps over the lazy dwarf", "bob@bob.com")
+
+		assert.Empty(t, violations)
+	})
+
+	t.Run("Length", func(t *testing.T) {
+		p := model.PasswordPolicy{MinLength: 8, MaxLength: 16}
+
+		assert.Equal(t, []Violation{{RuleMinLength, "8"}}, Check(p, "short", ""))
+		assert.Equal(t, []Violation{{RuleMaxLength, "16"}}, Check(p, strings.Repeat("a", 17), ""))
+		// length is counted in characters, not bytes
+		assert.Empty(t, Check(p, "пароль-ок", ""))
+	})
+
+	t.Run("Character classes", func(t *testing.T) {
+		p := model.PasswordPolicy{
+			RequireUppercase: true,
+			RequireLowercase: true,
+			RequireDigit:     true,
+			RequireSymbol:    true,
+		}
+
+		assert.Equal(t, []Violation{
+			{Rule: RuleUppercase},
+			{Rule: RuleDigit},
+			{Rule: RuleSymbol},
+		}, Check(p, "lowercase", ""))
+
+		assert.Empty(t, Check(p, "Aa1!", ""))
+	})
+
+	t.Run("Strength", func(t *testing.T) {
+		p := model.PasswordPolicy{MinStrength: 3}
+
+		assert.Equal(t, []Violation{{RuleStrength, "3"}}, Check(p, "password1", ""))
+		assert.Empty(t, Check(p, "wobbly-ferret-sandals-84", ""))
+	})
+
+	t.Run("Email derived", func(t *testing.T) {
+		p := model.PasswordPolicy{DisallowEmail: true}
+		email := "Jane.Doe@Example.com"
+
+		for _, password := range []string{
+			"jane.doe@example.com",
+			"JaneDoe123",
+			"doe-is-my-name",
+			"eodenaj!!",
+			"example2024",
+		} {
+			assert.Equal(t, []Violation{{Rule: RuleEmail}}, Check(p, password, email), password)
+		}
+
+		assert.Empty(t, Check(p, "tangerine-kayak-79", email))
+		// parts which are too short are ignored
+		assert.Empty(t, Check(p, "tangerine-kayak-79", "ta@ka.io"))
+	})
+
+	t.Run("Email derived from the registrable domain only", func(t *testing.T) {
+		p := model.PasswordPolicy{DisallowEmail: true}
+		email := "jane@mail.example.com.au"
+
+		assert.Equal(t, []Violation{{Rule: RuleEmail}}, Check(p, "example2024", email))
+		// subdomains and public suffixes are not derived from the email
+		assert.Empty(t, Check(p, "welcome-tangerine-79", email))
+		assert.Empty(t, Check(p, "mailbox-tangerine-79", email))
+		assert.Empty(t, Check(p, "tangerine-kayak-79", "jane@com.au"))
+	})
+}
+
+func TestStrength(t *testing.T) {
+	assert.Less(t, Strength("password", ""), 2)
+	assert.Less(t, Strength("quentinzarkov", "quentin.zarkov@bob.com"), Strength("quentinzarkov", ""))
+	assert.Equal(t, 4, Strength("wobbly-ferret-sandals-84", ""))
+}
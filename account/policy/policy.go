@@ -0,0 +1,206 @@
+// Package policy evaluates attribute based access policies, written as CEL expressions, against
+// authorization requests. It is used by the account service and can be embedded by other services
+// to authorize requests in process
+package policy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/uuid"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// Variables available to policy expressions
+const (
+	VarSubject  = "subject"  // attributes of the user, see Subject
+	VarAction   = "action"   // name of the action requested
+	VarResource = "resource" // attributes of the resource the action is requested on
+	VarContext  = "context"  // attributes of the request, eg the ip address it is made from
+	VarNow      = "now"      // timestamp of the evaluation
+)
+
+// maxEvaluationCost bounds the cost of evaluating an expression, which grows with the size of the
+// attributes it iterates over. Those of the resource and context are sent by clients, so that an
+// expression evaluated over large attributes fails rather than taking up the cpu of the service
+const maxEvaluationCost = 100000
+
+// evaluationTimeout bounds the time the expressions of a request are evaluated for
+const evaluationTimeout = 100 * time.Millisecond
+
+// interruptCheckFrequency is how many iterations of a comprehension are run between checks of the deadline
+const interruptCheckFrequency = 100
+
+// env declares the variables of policy expressions
+var env *cel.Env
+
+func init() {
+	attributes := cel.MapType(cel.StringType, cel.DynType)
+
+	var err error
+	env, err = cel.NewEnv(
+		cel.Variable(VarSubject, attributes),
+		cel.Variable(VarAction, cel.StringType),
+		cel.Variable(VarResource, attributes),
+		cel.Variable(VarContext, attributes),
+		cel.Variable(VarNow, cel.TimestampType),
+		// json numbers are doubles, which should compare to the integers written in expressions
+		cel.CrossTypeNumericComparisons(true),
+	)
+	if err != nil {
+		panic(fmt.Sprintf("policy: could not declare the environment of expressions: %v", err))
+	}
+}
+
+// Program is a compiled policy, ready to be evaluated
+type Program struct {
+	Policy *model.Policy
+	prg    cel.Program
+}
+
+// Compile checks the expression of a policy evaluates to a bool and compiles it, bounding the cost of its evaluation
+func Compile(p *model.Policy) (*Program, error) {
+	ast, iss := env.Compile(p.Expression)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+
+	if !ast.OutputType().IsExactType(cel.BoolType) {
+		return nil, fmt.Errorf("expression must evaluate to a bool, not %v", ast.OutputType())
+	}
+
+	prg, err := env.Program(ast,
+		cel.CostLimit(maxEvaluationCost),
+		cel.InterruptCheckFrequency(interruptCheckFrequency),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Program{Policy: p, prg: prg}, nil
+}
+
+// Evaluate decides an authorization request at a point in time. The request is allowed when
+// an allow policy matches it and no deny policy does. A deny policy failing to evaluate, eg for
+// an attribute missing from the request or exceeding its cost or the evaluationTimeout, denies it
+// so that leaving attributes out or inflating them cannot bypass it
+func Evaluate(ctx context.Context, programs []*Program, req *model.AuthorizationRequest, now time.Time) *model.AuthorizationDecision {
+	ctx, cancel := context.WithTimeout(ctx, evaluationTimeout)
+	defer cancel()
+
+	d := &model.AuthorizationDecision{
+		Matched: []*model.PolicyResult{},
+	}
+
+	vars := map[string]interface{}{
+		VarSubject:  attributes(req.Subject),
+		VarAction:   req.Action,
+		VarResource: attributes(req.Resource),
+		VarContext:  attributes(req.Context),
+		VarNow:      now,
+	}
+
+	var allowed, denied bool
+
+	for _, p := range programs {
+		if !p.Policy.AppliesTo(req.Action) {
+			continue
+		}
+
+		result := &model.PolicyResult{
+			Name:    p.Policy.Name,
+			Version: p.Policy.Version,
+			Effect:  p.Policy.Effect,
+		}
+
+		out, _, err := p.prg.ContextEval(ctx, vars)
+		if err != nil {
+			result.Error = err.Error()
+			d.Errors = append(d.Errors, result)
+			denied = denied || p.Policy.Effect == model.PolicyDeny
+			continue
+		}
+
+		if match, ok := out.Value().(bool); !ok || !match {
+			continue
+		}
+
+		d.Matched = append(d.Matched, result)
+
+		switch p.Policy.Effect {
+		case model.PolicyAllow:
+			allowed = true
+		case model.PolicyDeny:
+			denied = true
+		}
+	}
+
+	d.Allowed = allowed && !denied
+
+	return d
+}
+
+// Subject returns the attributes of a user from the claims of their id token
+func Subject(u *model.User) model.Attributes {
+	s := model.Attributes{
+		"id":          u.UID.String(),
+		"email":       u.Email,
+		"tenant_id":   u.TenantID.String(),
+		"roles":       names(u.Roles),
+		"permissions": names(u.Permissions),
+	}
+
+	if u.OrgID != uuid.Nil {
+		s["org_id"] = u.OrgID.String()
+		s["org_role"] = string(u.OrgRole)
+	}
+
+	return s
+}
+
+// Engine holds the compiled policies of a service embedding the package
+type Engine struct {
+	programs []*Program
+}
+
+// NewEngine compiles policies, failing on the first invalid one
+func NewEngine(policies []*model.Policy) (*Engine, error) {
+	programs := make([]*Program, 0, len(policies))
+
+	for _, p := range policies {
+		prg, err := Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("policy %v: %w", p.Name, err)
+		}
+
+		programs = append(programs, prg)
+	}
+
+	return &Engine{programs: programs}, nil
+}
+
+// Authorize decides an authorization request against the policies of the engine
+func (e *Engine) Authorize(ctx context.Context, req *model.AuthorizationRequest) *model.AuthorizationDecision {
+	return Evaluate(ctx, e.programs, req, time.Now())
+}
+
+// attributes returns empty attributes rather than nil ones, which expressions cannot index
+func attributes(a model.Attributes) map[string]interface{} {
+	if a == nil {
+		return map[string]interface{}{}
+	}
+
+	return a
+}
+
+// names converts names to a list expressions can use with the in operator
+func names(n model.Names) []interface{} {
+	l := make([]interface{}, len(n))
+	for i, v := range n {
+		l[i] = v
+	}
+
+	return l
+}
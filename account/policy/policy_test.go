@@ -0,0 +1,193 @@
+package policy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+func TestCompile(t *testing.T) {
+	t.Run("Valid expression", func(t *testing.T) {
+		_, err := Compile(&model.Policy{Expression: `"admin" in subject.roles`})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Syntax error", func(t *testing.T) {
+		_, err := Compile(&model.Policy{Expression: `subject.roles contains "admin"`})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Undeclared variable", func(t *testing.T) {
+		_, err := Compile(&model.Policy{Expression: `user.id == resource.owner`})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Expression not evaluating to a bool", func(t *testing.T) {
+		_, err := Compile(&model.Policy{Expression: `subject.email`})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestEvaluate(t *testing.T) {
+	orgID := uuid.New()
+
+	owner := Subject(&model.User{
+		UID:     uuid.New(),
+		Email:   "bob@bob.com",
+		Roles:   model.Names{"editor"},
+		OrgID:   orgID,
+		OrgRole: model.OrgOwner,
+	})
+
+	// owners can edit resources in their org during business hours
+	ownersEdit := &model.Policy{
+		Name:       "owners-edit",
+		Version:    2,
+		Effect:     model.PolicyAllow,
+		Actions:    model.Names{"documents.edit"},
+		Expression: `subject.org_role == "owner" && resource.org_id == subject.org_id && now.getHours("UTC") >= 9 && now.getHours("UTC") < 18`,
+	}
+	blockedIPs := &model.Policy{
+		Name:       "blocked-ips",
+		Version:    1,
+		Effect:     model.PolicyDeny,
+		Actions:    model.Names{"*"},
+		Expression: `context.ip in ["10.0.0.66"]`,
+	}
+	largeFiles := &model.Policy{
+		Name:       "large-files",
+		Version:    1,
+		Effect:     model.PolicyDeny,
+		Actions:    model.Names{"documents.edit"},
+		Expression: `resource.size > 1000`,
+	}
+
+	engine, err := NewEngine([]*model.Policy{ownersEdit, blockedIPs})
+	assert.NoError(t, err)
+	programs := engine.programs
+
+	noon := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+	night := time.Date(2024, 5, 6, 23, 0, 0, 0, time.UTC)
+
+	request := func(resourceOrg uuid.UUID, ip string) *model.AuthorizationRequest {
+		return &model.AuthorizationRequest{
+			Subject:  owner,
+			Action:   "documents.edit",
+			Resource: model.Attributes{"org_id": resourceOrg.String(), "size": float64(10)},
+			Context:  model.Attributes{"ip": ip},
+		}
+	}
+
+	t.Run("Allowed", func(t *testing.T) {
+		d := Evaluate(context.TODO(), programs, request(orgID, "10.0.0.1"), noon)
+
+		assert.True(t, d.Allowed)
+		assert.Equal(t, []*model.PolicyResult{{Name: "owners-edit", Version: 2, Effect: model.PolicyAllow}}, d.Matched)
+		assert.Empty(t, d.Errors)
+	})
+
+	t.Run("Outside business hours", func(t *testing.T) {
+		d := Evaluate(context.TODO(), programs, request(orgID, "10.0.0.1"), night)
+
+		assert.False(t, d.Allowed)
+		assert.Empty(t, d.Matched)
+	})
+
+	t.Run("Resource of another org", func(t *testing.T) {
+		d := Evaluate(context.TODO(), programs, request(uuid.New(), "10.0.0.1"), noon)
+
+		assert.False(t, d.Allowed)
+	})
+
+	t.Run("Deny overrides allow", func(t *testing.T) {
+		d := Evaluate(context.TODO(), programs, request(orgID, "10.0.0.66"), noon)
+
+		assert.False(t, d.Allowed)
+		assert.Len(t, d.Matched, 2)
+	})
+
+	t.Run("Action without policies is denied", func(t *testing.T) {
+		req := request(orgID, "10.0.0.1")
+		req.Action = "documents.delete"
+
+		d := Evaluate(context.TODO(), programs, req, noon)
+
+		assert.False(t, d.Allowed)
+		assert.Empty(t, d.Matched)
+	})
+
+	t.Run("Deny policy failing to evaluate denies", func(t *testing.T) {
+		req := request(orgID, "10.0.0.1")
+		req.Context = nil
+
+		d := Evaluate(context.TODO(), programs, req, noon)
+
+		assert.False(t, d.Allowed)
+		assert.Len(t, d.Errors, 1)
+		assert.Equal(t, "blocked-ips", d.Errors[0].Name)
+	})
+
+	t.Run("Numbers of json attributes compare to integers", func(t *testing.T) {
+		prg, err := Compile(largeFiles)
+		assert.NoError(t, err)
+
+		req := request(orgID, "10.0.0.1")
+		req.Resource["size"] = float64(2048)
+
+		d := Evaluate(context.TODO(), append(programs, prg), req, noon)
+
+		assert.False(t, d.Allowed)
+		assert.Len(t, d.Matched, 2)
+	})
+
+	t.Run("Policy exceeding its evaluation cost", func(t *testing.T) {
+		prg, err := Compile(&model.Policy{
+			Name:       "quadratic",
+			Version:    1,
+			Effect:     model.PolicyDeny,
+			Actions:    model.Names{"*"},
+			Expression: `resource.items.all(x, resource.items.all(y, y == x))`,
+		})
+		assert.NoError(t, err)
+
+		items := make([]interface{}, 2000)
+		for i := range items {
+			items[i] = "item"
+		}
+
+		req := request(orgID, "10.0.0.1")
+		req.Resource["items"] = items
+
+		start := time.Now()
+		d := Evaluate(context.TODO(), append(programs, prg), req, noon)
+
+		assert.Less(t, time.Since(start), time.Second)
+		assert.False(t, d.Allowed)
+		assert.Len(t, d.Errors, 1)
+		assert.Equal(t, "quadratic", d.Errors[0].Name)
+		assert.Contains(t, d.Errors[0].Error, "cost")
+	})
+
+	t.Run("Subject of no organization", func(t *testing.T) {
+		s := Subject(&model.User{UID: uuid.New(), Roles: model.Names{"editor"}})
+
+		assert.NotContains(t, s, "org_id")
+		assert.Equal(t, []interface{}{"editor"}, s["roles"])
+	})
+}
+
+func TestNewEngine(t *testing.T) {
+	_, err := NewEngine([]*model.Policy{
+		{Name: "broken", Effect: model.PolicyAllow, Expression: `subject.`},
+	})
+
+	assert.ErrorContains(t, err, "policy broken")
+}
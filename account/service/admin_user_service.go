@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// defaultUserPageSize and maxUserPageSize bound how many users are listed per page
+const (
+	defaultUserPageSize = 20
+	maxUserPageSize     = 100
+)
+
+// AdminUserService acts as a struct for injecting implementations of UserRepository, TokenRepository,
+// AuditRepository, ApplicationRepository and LockoutService for use in service methods
+type AdminUserService struct {
+	UserRepository        model.UserRepository
+	TokenRepository       model.TokenRepository
+	AuditRepository       model.AuditRepository
+	ApplicationRepository model.ApplicationRepository
+	LockoutService        model.LockoutService
+	AppSettings           model.AppSettings
+}
+
+// AUSConfig will hold repositories that will eventually be injected into this
+// service layer
+type AUSConfig struct {
+	UserRepository  model.UserRepository
+	TokenRepository model.TokenRepository
+	// AuditRepository records the changes admins make to users, which are not recorded when nil
+	AuditRepository model.AuditRepository
+	// ApplicationRepository lists the applications whose deleted users are purged. Only the users
+	// of the default application are purged when nil
+	ApplicationRepository model.ApplicationRepository
+	AppSettings           model.AppSettings // retention of deleted users of the default application
+	// LockoutService unlocks the accounts of users locked out by failed signins, which cannot be unlocked
+	// by admins when nil
+	LockoutService model.LockoutService
+}
+
+// NewAdminUserService is a factory function for
+// initializing an AdminUserService with its repository layer dependencies
+func NewAdminUserService(c *AUSConfig) model.AdminUserService {
+	return &AdminUserService{
+		UserRepository:        c.UserRepository,
+		TokenRepository:       c.TokenRepository,
+		AuditRepository:       c.AuditRepository,
+		ApplicationRepository: c.ApplicationRepository,
+		LockoutService:        c.LockoutService,
+		AppSettings:           c.AppSettings,
+	}
+}
+
+// Search retrieves a page of the users of the application matching a filter, of
+// defaultUserPageSize users unless a limit of at most maxUserPageSize is given
+func (s *AdminUserService) Search(ctx context.Context, f *model.UserFilter) (*model.UserPage, error) {
+	if f.Limit <= 0 {
+		f.Limit = defaultUserPageSize
+	}
+
+	if f.Limit > maxUserPageSize {
+		f.Limit = maxUserPageSize
+	}
+
+	if f.Offset < 0 {
+		f.Offset = 0
+	}
+
+	users, total, err := s.UserRepository.Search(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.UserPage{
+		Users:  users,
+		Total:  total,
+		Limit:  f.Limit,
+		Offset: f.Offset,
+	}, nil
+}
+
+// Update sets the provided profile fields of a user, as long as the user is still at the given version
+func (s *AdminUserService) Update(ctx context.Context, actor uuid.UUID, uid uuid.UUID, version int, d *model.UserDetails) (*model.User, error) {
+	u, err := s.UserRepository.Update(ctx, uid, version, d)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, s.AuditRepository, model.AuditUserUpdated, &actor, &uid, "")
+
+	return u, nil
+}
+
+// Disable prevents a user from signing in and revokes their sessions, recording the reason given.
+// Access tokens already issued to them are refused once their status is read again. Admins cannot disable themselves
+func (s *AdminUserService) Disable(ctx context.Context, actor uuid.UUID, uid uuid.UUID, reason string) (*model.User, error) {
+	if actor == uid {
+		return nil, apperrors.NewBadRequest("Unable to disable yourself")
+	}
+
+	u, err := s.UserRepository.SetStatus(ctx, uid, model.UserDisabled, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.TokenRepository.DeleteUserRefreshTokens(ctx, uid, ""); err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, s.AuditRepository, model.AuditUserDisabled, &actor, &uid, reason)
+
+	return u, nil
+}
+
+// Enable allows a disabled user to sign in again, approves a pending user or restores a deleted
+// user which was not purged yet
+func (s *AdminUserService) Enable(ctx context.Context, actor uuid.UUID, uid uuid.UUID) (*model.User, error) {
+	u, err := s.UserRepository.SetStatus(ctx, uid, model.UserActive, "")
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, s.AuditRepository, model.AuditUserEnabled, &actor, &uid, "")
+
+	return u, nil
+}
+
+// RequirePasswordReset requires a user to change their password when next signing in,
+// and revokes their sessions so that they have to sign in again
+func (s *AdminUserService) RequirePasswordReset(ctx context.Context, actor uuid.UUID, uid uuid.UUID) error {
+	if err := s.UserRepository.RequirePasswordReset(ctx, uid); err != nil {
+		return err
+	}
+
+	if err := s.TokenRepository.DeleteUserRefreshTokens(ctx, uid, ""); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.AuditRepository, model.AuditUserPasswordResetRequired, &actor, &uid, "")
+
+	return nil
+}
+
+// RevokeSessions removes every refresh token of a user of the application.
+// Access tokens already issued to them remain valid until they expire
+func (s *AdminUserService) RevokeSessions(ctx context.Context, actor uuid.UUID, uid uuid.UUID) error {
+	// refresh tokens are not scoped to applications, the user is looked up within the application first
+	if _, err := s.UserRepository.FindByID(ctx, uid); err != nil {
+		return err
+	}
+
+	if err := s.TokenRepository.DeleteUserRefreshTokens(ctx, uid, ""); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.AuditRepository, model.AuditUserSessionsRevoked, &actor, &uid, "")
+
+	return nil
+}
+
+// Unlock lifts the lockout of a user after too many failed signins, without waiting for it to expire
+func (s *AdminUserService) Unlock(ctx context.Context, actor uuid.UUID, uid uuid.UUID) error {
+	if s.LockoutService == nil {
+		log.Printf("Unable to unlock uid: %v, no lockout service configured\n", uid)
+		return apperrors.NewInternal()
+	}
+
+	u, err := s.UserRepository.FindByID(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	if err := s.LockoutService.Unlock(ctx, u.Email); err != nil {
+		log.Printf("Unable to unlock uid: %v. Reason: %v\n", uid, err)
+		return apperrors.NewInternal()
+	}
+
+	recordAudit(ctx, s.AuditRepository, model.AuditUserUnlocked, &actor, &uid, "")
+
+	return nil
+}
+
+// Delete soft-deletes a user and revokes their sessions. The user is kept, and can be restored by enabling
+// them, for the DeletedUserRetention of the application before being purged. Their email address can be
+// signed up with again in the meantime. Admins cannot delete themselves
+func (s *AdminUserService) Delete(ctx context.Context, actor uuid.UUID, uid uuid.UUID) error {
+	if actor == uid {
+		return apperrors.NewBadRequest("Unable to delete yourself")
+	}
+
+	u, err := s.UserRepository.FindByID(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	// deleting a user again would postpone their purge
+	if u.Status == model.UserDeleted {
+		return nil
+	}
+
+	if _, err := s.UserRepository.SetStatus(ctx, uid, model.UserDeleted, ""); err != nil {
+		return err
+	}
+
+	if err := s.TokenRepository.DeleteUserRefreshTokens(ctx, uid, ""); err != nil {
+		return err
+	}
+
+	// the event outlives the purge of the user, so it identifies them by uid only
+	recordAudit(ctx, s.AuditRepository, model.AuditUserDeleted, &actor, &uid, "")
+
+	return nil
+}
+
+// PurgeDeleted deletes for good the users of every application which were deleted longer ago than the
+// DeletedUserRetention of their application, returning how many were. It is meant to be run periodically
+func (s *AdminUserService) PurgeDeleted(ctx context.Context) (int, error) {
+	ctxs := []context.Context{ctx}
+
+	if s.ApplicationRepository != nil {
+		apps, err := s.ApplicationRepository.FindAll(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, app := range apps {
+			// the default application is configured through AppSettings rather than its row
+			if app.ID != model.DefaultApplicationID {
+				ctxs = append(ctxs, model.NewApplicationContext(ctx, app))
+			}
+		}
+	}
+
+	purged := 0
+
+	for _, ctx := range ctxs {
+		retention := model.ApplicationSettings(ctx, s.AppSettings).GetDeletedUserRetention()
+
+		n, err := s.UserRepository.PurgeDeleted(ctx, time.Now().Add(-retention))
+		if err != nil {
+			return purged, err
+		}
+
+		if n > 0 {
+			log.Printf("Purged %v deleted users of application: %v\n", n, model.ApplicationID(ctx))
+		}
+
+		purged += n
+	}
+
+	return purged, nil
+}
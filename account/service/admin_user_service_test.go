@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
+)
+
+func TestSearchUsers(t *testing.T) {
+	t.Run("Default page size", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		aus := NewAdminUserService(&AUSConfig{UserRepository: mockUserRepository})
+
+		users := []*model.User{{UID: uuid.New(), Email: "bob@bob.com"}}
+		mockUserRepository.
+			On("Search", mock.Anything, &model.UserFilter{Query: "bob", Limit: defaultUserPageSize}).
+			Return(users, 21, nil)
+
+		page, err := aus.Search(context.TODO(), &model.UserFilter{Query: "bob"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.UserPage{Users: users, Total: 21, Limit: defaultUserPageSize}, page)
+	})
+
+	t.Run("Page size capped", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		aus := NewAdminUserService(&AUSConfig{UserRepository: mockUserRepository})
+
+		mockUserRepository.
+			On("Search", mock.Anything, &model.UserFilter{Limit: maxUserPageSize, Offset: 100}).
+			Return([]*model.User{}, 0, nil)
+
+		page, err := aus.Search(context.TODO(), &model.UserFilter{Limit: 1000, Offset: 100})
+
+		assert.NoError(t, err)
+		assert.Equal(t, maxUserPageSize, page.Limit)
+	})
+}
+
+func TestDisableUser(t *testing.T) {
+	actor := uuid.New()
+	uid := uuid.New()
+
+	t.Run("Success", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockTokenRepository := new(mocks.MockTokenRepository)
+		mockAuditRepository := new(mocks.MockAuditRepository)
+		aus := NewAdminUserService(&AUSConfig{
+			UserRepository:  mockUserRepository,
+			TokenRepository: mockTokenRepository,
+			AuditRepository: mockAuditRepository,
+		})
+
+		disabled := &model.User{UID: uid, Status: model.UserDisabled}
+		ctx := model.NewClientContext(context.TODO(), &model.Client{IP: "10.0.0.1"})
+
+		mockUserRepository.On("SetStatus", mock.Anything, uid, model.UserDisabled, "left the company").Return(disabled, nil)
+		mockTokenRepository.On("DeleteUserRefreshTokens", mock.Anything, uid, "").Return(nil)
+		mockAuditRepository.On("Create", mock.Anything, &model.AuditEvent{
+			Type:   model.AuditUserDisabled,
+			Actor:  &actor,
+			Target: &uid,
+			IP:     "10.0.0.1",
+			Detail: "left the company",
+		}).Return(nil)
+
+		u, err := aus.Disable(ctx, actor, uid, "left the company")
+
+		assert.NoError(t, err)
+		assert.Equal(t, disabled, u)
+		mockTokenRepository.AssertExpectations(t)
+		mockAuditRepository.AssertExpectations(t)
+	})
+
+	t.Run("Disabling yourself", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		aus := NewAdminUserService(&AUSConfig{UserRepository: mockUserRepository})
+
+		u, err := aus.Disable(context.TODO(), actor, actor, "")
+
+		assert.Nil(t, u)
+		assert.Equal(t, apperrors.BadRequest, err.(*apperrors.Error).Type)
+		mockUserRepository.AssertNotCalled(t, "SetStatus")
+	})
+}
+
+func TestRevokeUserSessions(t *testing.T) {
+	actor := uuid.New()
+	uid := uuid.New()
+
+	t.Run("Success", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockTokenRepository := new(mocks.MockTokenRepository)
+		aus := NewAdminUserService(&AUSConfig{
+			UserRepository:  mockUserRepository,
+			TokenRepository: mockTokenRepository,
+		})
+
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(&model.User{UID: uid}, nil)
+		mockTokenRepository.On("DeleteUserRefreshTokens", mock.Anything, uid, "").Return(nil)
+
+		err := aus.RevokeSessions(context.TODO(), actor, uid)
+
+		assert.NoError(t, err)
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("User of another application", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockTokenRepository := new(mocks.MockTokenRepository)
+		aus := NewAdminUserService(&AUSConfig{
+			UserRepository:  mockUserRepository,
+			TokenRepository: mockTokenRepository,
+		})
+
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(nil, apperrors.NewNotFound("uid", uid.String()))
+
+		err := aus.RevokeSessions(context.TODO(), actor, uid)
+
+		assert.Equal(t, apperrors.NotFound, err.(*apperrors.Error).Type)
+		mockTokenRepository.AssertNotCalled(t, "DeleteUserRefreshTokens")
+	})
+}
+
+func TestUnlockUser(t *testing.T) {
+	actor := uuid.New()
+	uid := uuid.New()
+
+	t.Run("Success", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockLockoutService := new(mocks.MockLockoutService)
+		mockAuditRepository := new(mocks.MockAuditRepository)
+		aus := NewAdminUserService(&AUSConfig{
+			UserRepository:  mockUserRepository,
+			AuditRepository: mockAuditRepository,
+			LockoutService:  mockLockoutService,
+		})
+
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(&model.User{UID: uid, Email: "bob@bob.com"}, nil)
+		mockLockoutService.On("Unlock", mock.Anything, "bob@bob.com").Return(nil)
+		mockAuditRepository.On("Create", mock.Anything, &model.AuditEvent{
+			Type:   model.AuditUserUnlocked,
+			Actor:  &actor,
+			Target: &uid,
+		}).Return(nil)
+
+		err := aus.Unlock(context.TODO(), actor, uid)
+
+		assert.NoError(t, err)
+		mockLockoutService.AssertExpectations(t)
+		mockAuditRepository.AssertExpectations(t)
+	})
+
+	t.Run("User of another application", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockLockoutService := new(mocks.MockLockoutService)
+		aus := NewAdminUserService(&AUSConfig{
+			UserRepository: mockUserRepository,
+			LockoutService: mockLockoutService,
+		})
+
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(nil, apperrors.NewNotFound("uid", uid.String()))
+
+		err := aus.Unlock(context.TODO(), actor, uid)
+
+		assert.Equal(t, apperrors.NotFound, err.(*apperrors.Error).Type)
+		mockLockoutService.AssertNotCalled(t, "Unlock", mock.Anything, mock.Anything)
+	})
+}
+
+func TestDeleteUser(t *testing.T) {
+	actor := uuid.New()
+	uid := uuid.New()
+
+	t.Run("Soft-deletes the user", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockTokenRepository := new(mocks.MockTokenRepository)
+		mockAuditRepository := new(mocks.MockAuditRepository)
+		aus := NewAdminUserService(&AUSConfig{
+			UserRepository:  mockUserRepository,
+			TokenRepository: mockTokenRepository,
+			AuditRepository: mockAuditRepository,
+		})
+
+		mockUserRepository.On("FindByID", mock.Anything, uid).
+			Return(&model.User{UID: uid, Email: "bob@bob.com", Status: model.UserActive}, nil)
+		mockUserRepository.On("SetStatus", mock.Anything, uid, model.UserDeleted, "").
+			Return(&model.User{UID: uid, Status: model.UserDeleted}, nil)
+		mockTokenRepository.On("DeleteUserRefreshTokens", mock.Anything, uid, "").Return(nil)
+		mockAuditRepository.
+			On("Create", mock.Anything, mock.MatchedBy(func(e *model.AuditEvent) bool {
+				return e.Type == model.AuditUserDeleted && *e.Target == uid && e.Detail == ""
+			})).
+			Return(nil)
+
+		err := aus.Delete(context.TODO(), actor, uid)
+
+		assert.NoError(t, err)
+		mockUserRepository.AssertExpectations(t)
+		mockTokenRepository.AssertExpectations(t)
+		mockAuditRepository.AssertExpectations(t)
+	})
+
+	t.Run("Already deleted", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		aus := NewAdminUserService(&AUSConfig{UserRepository: mockUserRepository})
+
+		mockUserRepository.On("FindByID", mock.Anything, uid).
+			Return(&model.User{UID: uid, Status: model.UserDeleted}, nil)
+
+		err := aus.Delete(context.TODO(), actor, uid)
+
+		assert.NoError(t, err)
+		mockUserRepository.AssertNotCalled(t, "SetStatus")
+	})
+}
+
+func TestPurgeDeletedUsers(t *testing.T) {
+	t.Run("Retention of each application", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockApplicationRepository := new(mocks.MockApplicationRepository)
+		aus := NewAdminUserService(&AUSConfig{
+			UserRepository:        mockUserRepository,
+			ApplicationRepository: mockApplicationRepository,
+			AppSettings:           model.AppSettings{DeletedUserRetention: time.Hour},
+		})
+
+		acme := &model.Application{ID: uuid.New(), Slug: "acme", Settings: model.AppSettings{DeletedUserRetention: 48 * time.Hour}}
+
+		mockApplicationRepository.On("FindAll", mock.Anything).
+			Return([]*model.Application{{ID: model.DefaultApplicationID, Slug: "default"}, acme}, nil)
+
+		retained := func(retention time.Duration) interface{} {
+			return mock.MatchedBy(func(before time.Time) bool {
+				return time.Since(before)-retention < time.Minute && time.Since(before) >= retention
+			})
+		}
+
+		mockUserRepository.
+			On("PurgeDeleted", mock.MatchedBy(func(ctx context.Context) bool {
+				return model.ApplicationID(ctx) == model.DefaultApplicationID
+			}), retained(time.Hour)).
+			Return(2, nil)
+		mockUserRepository.
+			On("PurgeDeleted", mock.MatchedBy(func(ctx context.Context) bool {
+				return model.ApplicationID(ctx) == acme.ID
+			}), retained(48*time.Hour)).
+			Return(1, nil)
+
+		n, err := aus.PurgeDeleted(context.TODO())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, n)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("Default retention", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		aus := NewAdminUserService(&AUSConfig{UserRepository: mockUserRepository})
+
+		mockUserRepository.
+			On("PurgeDeleted", mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+				return time.Since(before) >= 30*24*time.Hour
+			})).
+			Return(0, nil)
+
+		n, err := aus.PurgeDeleted(context.TODO())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+		mockUserRepository.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// defaultAuditPageSize and maxAuditPageSize bound how many audit events are listed per page
+const (
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 200
+)
+
+// auditVerifyBatchSize is how many events are read at once while verifying the hash chain of the audit log
+const auditVerifyBatchSize = 1000
+
+// recordAudit appends an event to the audit log, made from the client of the request in ctx. Failing to
+// do so is logged by the repository rather than failing the operation the event is about.
+// Nothing is recorded when no repository is configured
+func recordAudit(ctx context.Context, r model.AuditRepository, t model.AuditEventType, actor *uuid.UUID, target *uuid.UUID, detail string) {
+	if r == nil {
+		return
+	}
+
+	client := model.ClientFromContext(ctx)
+
+	_ = r.Create(ctx, &model.AuditEvent{
+		Type:      t,
+		Actor:     actor,
+		Target:    target,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+		Detail:    detail,
+	})
+}
+
+// hashAuditEmail returns a keyed hash of an email address, so that events about the same address can be
+// correlated without the address outliving the erasure of personal data in the append-only audit log.
+// Nothing is returned without a secret, an unkeyed hash of an address being easily reversed
+func hashAuditEmail(secret string, email string) string {
+	if secret == "" {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strings.ToLower(strings.TrimSpace(email))))
+
+	return "email:" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// AuditService acts as a struct for injecting an implementation of AuditRepository
+// for use in service methods
+type AuditService struct {
+	AuditRepository model.AuditRepository
+}
+
+// ASConfig will hold repositories that will eventually be injected into this
+// service layer
+type ASConfig struct {
+	AuditRepository model.AuditRepository
+}
+
+// NewAuditService is a factory function for
+// initializing an AuditService with its repository layer dependencies
+func NewAuditService(c *ASConfig) model.AuditService {
+	return &AuditService{
+		AuditRepository: c.AuditRepository,
+	}
+}
+
+// Search lists a page of the events of the audit log matching the filter, of
+// defaultAuditPageSize events unless a limit of at most maxAuditPageSize is given
+func (s *AuditService) Search(ctx context.Context, f *model.AuditFilter) (*model.AuditPage, error) {
+	if f.Limit <= 0 {
+		f.Limit = defaultAuditPageSize
+	}
+
+	if f.Limit > maxAuditPageSize {
+		f.Limit = maxAuditPageSize
+	}
+
+	if f.Offset < 0 {
+		f.Offset = 0
+	}
+
+	events, total, err := s.AuditRepository.Search(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.AuditPage{
+		Events: events,
+		Total:  total,
+		Limit:  f.Limit,
+		Offset: f.Offset,
+	}, nil
+}
+
+// Verify walks the audit log of the application in order, checking each event hashes to its Hash and chains
+// to the event before it. Events recorded before the log was chained have no hash and are only allowed
+// before the first chained event
+func (s *AuditService) Verify(ctx context.Context) (*model.AuditVerification, error) {
+	v := &model.AuditVerification{Valid: true}
+
+	var seq int64
+	prevHash := ""
+	chained := false
+
+	for {
+		events, err := s.AuditRepository.FindChain(ctx, seq, auditVerifyBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range events {
+			v.Checked++
+			seq = e.Seq
+
+			if e.Hash == "" && !chained {
+				continue
+			}
+
+			chained = true
+
+			if e.PrevHash != prevHash || e.ComputeHash() != e.Hash {
+				v.Valid = false
+				v.BrokenAt = e
+				return v, nil
+			}
+
+			prevHash = e.Hash
+		}
+
+		if len(events) < auditVerifyBatchSize {
+			return v, nil
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
+)
+
+// auditChain returns n events chained like the repository chains them, after the events of legacy
+// which were recorded before the chain
+func auditChain(legacy int, n int) []*model.AuditEvent {
+	events := []*model.AuditEvent{}
+	prevHash := ""
+
+	for i := 0; i < legacy+n; i++ {
+		uid := uuid.New()
+		e := &model.AuditEvent{
+			ID:        uuid.New(),
+			Seq:       int64(i + 1),
+			Type:      model.AuditUserSignedIn,
+			Actor:     &uid,
+			Target:    &uid,
+			IP:        "127.0.0.1",
+			UserAgent: "curl/8.0",
+			CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+		}
+
+		if i >= legacy {
+			e.PrevHash = prevHash
+			e.Hash = e.ComputeHash()
+			prevHash = e.Hash
+		}
+
+		events = append(events, e)
+	}
+
+	return events
+}
+
+func TestRecordAudit(t *testing.T) {
+	actor := uuid.New()
+	target := uuid.New()
+
+	mockAuditRepository := new(mocks.MockAuditRepository)
+	mockAuditRepository.On("Create", mock.Anything, &model.AuditEvent{
+		Type:      model.AuditUserDisabled,
+		Actor:     &actor,
+		Target:    &target,
+		IP:        "127.0.0.1",
+		UserAgent: "curl/8.0",
+		Detail:    "spam",
+	}).Return(nil)
+
+	ctx := model.NewClientContext(context.TODO(), &model.Client{IP: "127.0.0.1", UserAgent: "curl/8.0"})
+
+	recordAudit(ctx, mockAuditRepository, model.AuditUserDisabled, &actor, &target, "spam")
+
+	mockAuditRepository.AssertExpectations(t)
+}
+
+func TestSearchAuditEvents(t *testing.T) {
+	t.Run("Default page size", func(t *testing.T) {
+		mockAuditRepository := new(mocks.MockAuditRepository)
+		as := NewAuditService(&ASConfig{AuditRepository: mockAuditRepository})
+
+		events := auditChain(0, 2)
+		mockAuditRepository.
+			On("Search", mock.Anything, &model.AuditFilter{Type: model.AuditUserSignedIn, Limit: defaultAuditPageSize}).
+			Return(events, 2, nil)
+
+		page, err := as.Search(context.TODO(), &model.AuditFilter{Type: model.AuditUserSignedIn})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.AuditPage{Events: events, Total: 2, Limit: defaultAuditPageSize}, page)
+	})
+
+	t.Run("Page size capped", func(t *testing.T) {
+		mockAuditRepository := new(mocks.MockAuditRepository)
+		as := NewAuditService(&ASConfig{AuditRepository: mockAuditRepository})
+
+		mockAuditRepository.
+			On("Search", mock.Anything, &model.AuditFilter{Limit: maxAuditPageSize, Offset: 400}).
+			Return([]*model.AuditEvent{}, 0, nil)
+
+		page, err := as.Search(context.TODO(), &model.AuditFilter{Limit: 10000, Offset: 400})
+
+		assert.NoError(t, err)
+		assert.Equal(t, maxAuditPageSize, page.Limit)
+	})
+}
+
+func TestVerifyAuditLog(t *testing.T) {
+	t.Run("Intact chain", func(t *testing.T) {
+		mockAuditRepository := new(mocks.MockAuditRepository)
+		as := NewAuditService(&ASConfig{AuditRepository: mockAuditRepository})
+
+		events := auditChain(2, 3)
+		mockAuditRepository.On("FindChain", mock.Anything, int64(0), auditVerifyBatchSize).Return(events, nil)
+
+		v, err := as.Verify(context.TODO())
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.AuditVerification{Valid: true, Checked: 5}, v)
+	})
+
+	t.Run("Chain read in batches", func(t *testing.T) {
+		mockAuditRepository := new(mocks.MockAuditRepository)
+		as := NewAuditService(&ASConfig{AuditRepository: mockAuditRepository})
+
+		events := auditChain(0, auditVerifyBatchSize+1)
+		mockAuditRepository.
+			On("FindChain", mock.Anything, int64(0), auditVerifyBatchSize).
+			Return(events[:auditVerifyBatchSize], nil)
+		mockAuditRepository.
+			On("FindChain", mock.Anything, int64(auditVerifyBatchSize), auditVerifyBatchSize).
+			Return(events[auditVerifyBatchSize:], nil)
+
+		v, err := as.Verify(context.TODO())
+
+		assert.NoError(t, err)
+		assert.True(t, v.Valid)
+		assert.Equal(t, auditVerifyBatchSize+1, v.Checked)
+	})
+
+	t.Run("Altered event", func(t *testing.T) {
+		mockAuditRepository := new(mocks.MockAuditRepository)
+		as := NewAuditService(&ASConfig{AuditRepository: mockAuditRepository})
+
+		events := auditChain(0, 3)
+		events[1].Detail = "covered up"
+		mockAuditRepository.On("FindChain", mock.Anything, int64(0), auditVerifyBatchSize).Return(events, nil)
+
+		v, err := as.Verify(context.TODO())
+
+		assert.NoError(t, err)
+		assert.False(t, v.Valid)
+		assert.Equal(t, events[1], v.BrokenAt)
+	})
+
+	t.Run("Removed event", func(t *testing.T) {
+		mockAuditRepository := new(mocks.MockAuditRepository)
+		as := NewAuditService(&ASConfig{AuditRepository: mockAuditRepository})
+
+		events := auditChain(0, 3)
+		mockAuditRepository.
+			On("FindChain", mock.Anything, int64(0), auditVerifyBatchSize).
+			Return([]*model.AuditEvent{events[0], events[2]}, nil)
+
+		v, err := as.Verify(context.TODO())
+
+		assert.NoError(t, err)
+		assert.False(t, v.Valid)
+		assert.Equal(t, events[2], v.BrokenAt)
+	})
+
+	t.Run("Hash cleared after the chain started", func(t *testing.T) {
+		mockAuditRepository := new(mocks.MockAuditRepository)
+		as := NewAuditService(&ASConfig{AuditRepository: mockAuditRepository})
+
+		events := auditChain(0, 3)
+		events[2].PrevHash, events[2].Hash = "", ""
+		mockAuditRepository.On("FindChain", mock.Anything, int64(0), auditVerifyBatchSize).Return(events, nil)
+
+		v, err := as.Verify(context.TODO())
+
+		assert.NoError(t, err)
+		assert.False(t, v.Valid)
+		assert.Equal(t, events[2], v.BrokenAt)
+	})
+}
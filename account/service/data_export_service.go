@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+const (
+	// syncExportMaxEvents is how many audit events an account may have for its data to be exported right away,
+	// the data of larger accounts being exported in the background
+	syncExportMaxEvents = 1000
+	// dataExportExpiry is how long the archive of a data export generated in the background is kept
+	dataExportExpiry = 7 * 24 * time.Hour
+	// dataExportTimeout bounds the background generation of a data export
+	dataExportTimeout = 5 * time.Minute
+)
+
+// DataExportService acts as a struct for injecting implementations of the repositories holding data about users,
+// along with DataExportRepository, for use in service methods
+type DataExportService struct {
+	UserRepository                model.UserRepository
+	TokenRepository               model.TokenRepository
+	RoleRepository                model.RoleRepository
+	OrganizationRepository        model.OrganizationRepository
+	PersonalAccessTokenRepository model.PersonalAccessTokenRepository
+	AuditRepository               model.AuditRepository
+	DataExportRepository          model.DataExportRepository
+}
+
+// DESConfig will hold repositories that will eventually be injected into this
+// service layer. The data of repositories which are nil is left out of exports
+type DESConfig struct {
+	UserRepository                model.UserRepository
+	TokenRepository               model.TokenRepository
+	RoleRepository                model.RoleRepository
+	OrganizationRepository        model.OrganizationRepository
+	PersonalAccessTokenRepository model.PersonalAccessTokenRepository
+	AuditRepository               model.AuditRepository
+	// DataExportRepository stores the exports of large accounts, which cannot be exported when nil
+	DataExportRepository model.DataExportRepository
+}
+
+// NewDataExportService is a factory function for
+// initializing a DataExportService with its repository layer dependencies
+func NewDataExportService(c *DESConfig) model.DataExportService {
+	return &DataExportService{
+		UserRepository:                c.UserRepository,
+		TokenRepository:               c.TokenRepository,
+		RoleRepository:                c.RoleRepository,
+		OrganizationRepository:        c.OrganizationRepository,
+		PersonalAccessTokenRepository: c.PersonalAccessTokenRepository,
+		AuditRepository:               c.AuditRepository,
+		DataExportRepository:          c.DataExportRepository,
+	}
+}
+
+// Export exports the data held about a user. The data of accounts with at most syncExportMaxEvents audit events
+// is archived right away, in a ready export which is not stored. Larger accounts are exported in the background,
+// and the pending export is returned to be fetched with Get once ready. An export already pending is returned as is
+func (s *DataExportService) Export(ctx context.Context, uid uuid.UUID) (*model.DataExport, error) {
+	events := []*model.AuditEvent{}
+
+	if s.AuditRepository != nil {
+		var err error
+		if events, err = s.AuditRepository.FindByUser(ctx, uid, syncExportMaxEvents+1); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(events) <= syncExportMaxEvents {
+		archive, err := s.archive(ctx, uid, events)
+		if err != nil {
+			return nil, err
+		}
+
+		now := time.Now()
+
+		return &model.DataExport{
+			TenantID:    model.ApplicationID(ctx),
+			UID:         uid,
+			Status:      model.DataExportReady,
+			Archive:     archive,
+			CreatedAt:   now,
+			CompletedAt: &now,
+		}, nil
+	}
+
+	if s.DataExportRepository == nil {
+		log.Printf("Unable to export data of uid: %v in the background, no data export repository is configured\n", uid)
+		return nil, apperrors.NewInternal()
+	}
+
+	if pending, err := s.DataExportRepository.FindPending(ctx, uid); err == nil {
+		if !stalled(pending) {
+			return pending, nil
+		}
+
+		// the generation of the export was interrupted, e.g. by a restart, so it is failed and queued again
+		log.Printf("Data export: %v of uid: %v stalled, queuing it again\n", pending.ID, uid)
+		if err := s.DataExportRepository.Complete(ctx, pending.ID, model.DataExportFailed, nil); err != nil {
+			log.Printf("Unable to fail stalled data export: %v of uid: %v. Reason: %v\n", pending.ID, uid, err)
+		}
+	}
+
+	e := &model.DataExport{
+		UID:       uid,
+		Status:    model.DataExportPending,
+		ExpiresAt: time.Now().Add(dataExportExpiry),
+	}
+
+	if err := s.DataExportRepository.Create(ctx, e); err != nil {
+		return nil, err
+	}
+
+	go s.generate(detachApplication(ctx), e.ID, uid)
+
+	return e, nil
+}
+
+// Get returns a data export of a user generated in the background, which is NotFound once expired
+func (s *DataExportService) Get(ctx context.Context, uid uuid.UUID, id uuid.UUID) (*model.DataExport, error) {
+	if s.DataExportRepository == nil {
+		return nil, apperrors.NewNotFound("export", id.String())
+	}
+
+	e, err := s.DataExportRepository.FindByID(ctx, uid, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// an export whose generation outlived its timeout will never complete
+	if stalled(e) {
+		e.Status = model.DataExportFailed
+	}
+
+	return e, nil
+}
+
+// PurgeExpired deletes the expired data exports of every application, the archives of which hold the data
+// of their users, returning how many were. It is meant to be run periodically
+func (s *DataExportService) PurgeExpired(ctx context.Context) (int, error) {
+	if s.DataExportRepository == nil {
+		return 0, nil
+	}
+
+	return s.DataExportRepository.DeleteExpired(ctx)
+}
+
+// stalled tells whether a data export is still pending past the timeout of its generation
+func stalled(e *model.DataExport) bool {
+	return e.Status == model.DataExportPending && e.CreatedAt.Before(time.Now().Add(-dataExportTimeout))
+}
+
+// generate archives the data of a large account. It runs after the request has been answered so it is given
+// a context detached from the request, and failures are recorded on the export for the user to try again
+func (s *DataExportService) generate(ctx context.Context, id uuid.UUID, uid uuid.UUID) {
+	ctx, cancel := context.WithTimeout(ctx, dataExportTimeout)
+	defer cancel()
+
+	status := model.DataExportReady
+
+	events, err := s.AuditRepository.FindByUser(ctx, uid, 0)
+
+	var archive []byte
+	if err == nil {
+		archive, err = s.archive(ctx, uid, events)
+	}
+
+	if err != nil {
+		log.Printf("Unable to export data of uid: %v. Reason: %v\n", uid, err)
+		status, archive = model.DataExportFailed, nil
+	}
+
+	if err := s.DataExportRepository.Complete(ctx, id, status, archive); err != nil {
+		log.Printf("Unable to complete data export: %v of uid: %v. Reason: %v\n", id, uid, err)
+	}
+}
+
+// archive collects the data held about a user along with their audit events as json
+func (s *DataExportService) archive(ctx context.Context, uid uuid.UUID, events []*model.AuditEvent) ([]byte, error) {
+	u, err := s.UserRepository.FindByID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &model.UserData{
+		ExportedAt:           time.Now(),
+		Profile:              u,
+		Sessions:             []*model.Session{},
+		LoginHistory:         []*model.AuditEvent{},
+		Roles:                []*model.Role{},
+		Organizations:        []*model.Organization{},
+		Memberships:          []*model.Membership{},
+		PersonalAccessTokens: []*model.PersonalAccessToken{},
+		AuditEvents:          events,
+	}
+
+	for _, e := range events {
+		if e.Type == model.AuditUserSignedIn {
+			data.LoginHistory = append(data.LoginHistory, e)
+		}
+	}
+
+	if s.TokenRepository != nil {
+		if data.Sessions, err = s.TokenRepository.FindUserRefreshTokens(ctx, uid); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.RoleRepository != nil {
+		if data.Roles, err = s.RoleRepository.FindUserRoles(ctx, uid); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.OrganizationRepository != nil {
+		if data.Organizations, err = s.OrganizationRepository.FindByUser(ctx, uid); err != nil {
+			return nil, err
+		}
+
+		for _, org := range data.Organizations {
+			m, err := s.OrganizationRepository.FindMembership(ctx, org.ID, uid)
+			if err != nil {
+				return nil, err
+			}
+
+			data.Memberships = append(data.Memberships, m)
+		}
+	}
+
+	if s.PersonalAccessTokenRepository != nil {
+		if data.PersonalAccessTokens, err = s.PersonalAccessTokenRepository.FindByUser(ctx, uid); err != nil {
+			return nil, err
+		}
+	}
+
+	archive, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("Unable to encode data of uid: %v. Reason: %v\n", uid, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return archive, nil
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
+)
+
+func TestExportData(t *testing.T) {
+	uid, _ := uuid.NewRandom()
+	u := &model.User{UID: uid, Email: "bob@bob.com"}
+
+	t.Run("Small account exported right away", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockAuditRepository := new(mocks.MockAuditRepository)
+		mockDataExportRepository := new(mocks.MockDataExportRepository)
+		des := NewDataExportService(&DESConfig{
+			UserRepository:       mockUserRepository,
+			AuditRepository:      mockAuditRepository,
+			DataExportRepository: mockDataExportRepository,
+		})
+
+		events := []*model.AuditEvent{
+			{ID: uuid.New(), Type: model.AuditUserSignedIn, Actor: &uid, Target: &uid},
+			{ID: uuid.New(), Type: model.AuditUserUpdated, Actor: &uid, Target: &uid},
+		}
+
+		mockAuditRepository.On("FindByUser", mock.Anything, uid, syncExportMaxEvents+1).Return(events, nil)
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(u, nil)
+
+		e, err := des.Export(context.TODO(), uid)
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.DataExportReady, e.Status)
+
+		data := &model.UserData{}
+		assert.NoError(t, json.Unmarshal(e.Archive, data))
+		assert.Equal(t, u.Email, data.Profile.Email)
+		assert.Len(t, data.AuditEvents, 2)
+		assert.Len(t, data.LoginHistory, 1)
+		assert.Equal(t, model.AuditUserSignedIn, data.LoginHistory[0].Type)
+		mockDataExportRepository.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Large account exported in the background", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockAuditRepository := new(mocks.MockAuditRepository)
+		mockDataExportRepository := new(mocks.MockDataExportRepository)
+		des := NewDataExportService(&DESConfig{
+			UserRepository:       mockUserRepository,
+			AuditRepository:      mockAuditRepository,
+			DataExportRepository: mockDataExportRepository,
+		})
+
+		events := make([]*model.AuditEvent, syncExportMaxEvents+1)
+		for i := range events {
+			events[i] = &model.AuditEvent{ID: uuid.New(), Type: model.AuditUserUpdated, Actor: &uid, Target: &uid}
+		}
+
+		id := uuid.New()
+		completed := make(chan []byte, 1)
+
+		mockAuditRepository.On("FindByUser", mock.Anything, uid, syncExportMaxEvents+1).Return(events, nil)
+		mockAuditRepository.On("FindByUser", mock.Anything, uid, 0).Return(events, nil)
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(u, nil)
+		mockDataExportRepository.On("FindPending", mock.Anything, uid).Return(nil, apperrors.NewNotFound("export", uid.String()))
+		mockDataExportRepository.
+			On("Create", mock.Anything, mock.AnythingOfType("*model.DataExport")).
+			Run(func(args mock.Arguments) {
+				args.Get(1).(*model.DataExport).ID = id
+			}).Return(nil)
+		mockDataExportRepository.
+			On("Complete", mock.Anything, id, model.DataExportReady, mock.AnythingOfType("[]uint8")).
+			Run(func(args mock.Arguments) {
+				completed <- args.Get(3).([]byte)
+			}).Return(nil)
+
+		e, err := des.Export(context.TODO(), uid)
+
+		assert.NoError(t, err)
+		assert.Equal(t, id, e.ID)
+		assert.Equal(t, model.DataExportPending, e.Status)
+		assert.Nil(t, e.Archive)
+
+		select {
+		case archive := <-completed:
+			data := &model.UserData{}
+			assert.NoError(t, json.Unmarshal(archive, data))
+			assert.Len(t, data.AuditEvents, syncExportMaxEvents+1)
+		case <-time.After(time.Second):
+			t.Fatal("export was not completed")
+		}
+	})
+
+	t.Run("Pending export reused", func(t *testing.T) {
+		mockAuditRepository := new(mocks.MockAuditRepository)
+		mockDataExportRepository := new(mocks.MockDataExportRepository)
+		des := NewDataExportService(&DESConfig{
+			AuditRepository:      mockAuditRepository,
+			DataExportRepository: mockDataExportRepository,
+		})
+
+		pending := &model.DataExport{ID: uuid.New(), UID: uid, Status: model.DataExportPending, CreatedAt: time.Now()}
+
+		mockAuditRepository.
+			On("FindByUser", mock.Anything, uid, syncExportMaxEvents+1).
+			Return(make([]*model.AuditEvent, syncExportMaxEvents+1), nil)
+		mockDataExportRepository.On("FindPending", mock.Anything, uid).Return(pending, nil)
+
+		e, err := des.Export(context.TODO(), uid)
+
+		assert.NoError(t, err)
+		assert.Equal(t, pending, e)
+		mockDataExportRepository.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Stalled pending export queued again", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockAuditRepository := new(mocks.MockAuditRepository)
+		mockDataExportRepository := new(mocks.MockDataExportRepository)
+		des := NewDataExportService(&DESConfig{
+			UserRepository:       mockUserRepository,
+			AuditRepository:      mockAuditRepository,
+			DataExportRepository: mockDataExportRepository,
+		})
+
+		events := make([]*model.AuditEvent, syncExportMaxEvents+1)
+		for i := range events {
+			events[i] = &model.AuditEvent{ID: uuid.New(), Type: model.AuditUserUpdated, Actor: &uid, Target: &uid}
+		}
+
+		stalledExport := &model.DataExport{
+			ID:        uuid.New(),
+			UID:       uid,
+			Status:    model.DataExportPending,
+			CreatedAt: time.Now().Add(-dataExportTimeout - time.Minute),
+		}
+		id := uuid.New()
+		completed := make(chan struct{}, 1)
+
+		mockAuditRepository.On("FindByUser", mock.Anything, uid, syncExportMaxEvents+1).Return(events, nil)
+		mockAuditRepository.On("FindByUser", mock.Anything, uid, 0).Return(events, nil)
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(u, nil)
+		mockDataExportRepository.On("FindPending", mock.Anything, uid).Return(stalledExport, nil)
+		mockDataExportRepository.On("Complete", mock.Anything, stalledExport.ID, model.DataExportFailed, []byte(nil)).Return(nil)
+		mockDataExportRepository.
+			On("Create", mock.Anything, mock.AnythingOfType("*model.DataExport")).
+			Run(func(args mock.Arguments) {
+				args.Get(1).(*model.DataExport).ID = id
+			}).Return(nil)
+		mockDataExportRepository.
+			On("Complete", mock.Anything, id, model.DataExportReady, mock.AnythingOfType("[]uint8")).
+			Run(func(args mock.Arguments) {
+				completed <- struct{}{}
+			}).Return(nil)
+
+		e, err := des.Export(context.TODO(), uid)
+
+		assert.NoError(t, err)
+		assert.Equal(t, id, e.ID)
+		assert.Equal(t, model.DataExportPending, e.Status)
+		mockDataExportRepository.AssertCalled(t, "Complete", mock.Anything, stalledExport.ID, model.DataExportFailed, []byte(nil))
+
+		select {
+		case <-completed:
+		case <-time.After(time.Second):
+			t.Fatal("export was not completed")
+		}
+	})
+}
+
+func TestGetDataExport(t *testing.T) {
+	uid, _ := uuid.NewRandom()
+
+	t.Run("Stalled pending export reported as failed", func(t *testing.T) {
+		mockDataExportRepository := new(mocks.MockDataExportRepository)
+		des := NewDataExportService(&DESConfig{
+			DataExportRepository: mockDataExportRepository,
+		})
+
+		e := &model.DataExport{
+			ID:        uuid.New(),
+			UID:       uid,
+			Status:    model.DataExportPending,
+			CreatedAt: time.Now().Add(-dataExportTimeout - time.Minute),
+		}
+
+		mockDataExportRepository.On("FindByID", mock.Anything, uid, e.ID).Return(e, nil)
+
+		got, err := des.Get(context.TODO(), uid, e.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.DataExportFailed, got.Status)
+	})
+}
+
+func TestPurgeExpiredDataExports(t *testing.T) {
+	mockDataExportRepository := new(mocks.MockDataExportRepository)
+	des := NewDataExportService(&DESConfig{
+		DataExportRepository: mockDataExportRepository,
+	})
+
+	mockDataExportRepository.On("DeleteExpired", mock.Anything).Return(2, nil)
+
+	n, err := des.PurgeExpired(context.TODO())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	mockDataExportRepository.AssertExpectations(t)
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// appURL returns the base url of the frontend of the application in ctx, used for links
+// sent by email, or defaultURL when the application has none configured
+func appURL(ctx context.Context, defaultURL string) string {
+	if app, ok := model.ApplicationFromContext(ctx); ok && app.Settings.AppURL != "" {
+		return app.Settings.AppURL
+	}
+
+	return defaultURL
+}
+
+// verificationEmail builds the subject and body of the email sent to confirm an email address
+func verificationEmail(baseURL string, token string) (string, string) {
+	link := fmt.Sprintf("%s/verify-email?token=%s", baseURL, url.QueryEscape(token))
+
+	subject := "Verify your email address"
+	body := fmt.Sprintf("Please confirm your email address by following the link below:\n\n%s\n\nIf you did not create an account, you can ignore this email.", link)
+
+	return subject, body
+}
+
+// changeEmailConfirmation builds the email sent to a new address to confirm an email change
+func changeEmailConfirmation(baseURL string, token string) (string, string) {
+	link := fmt.Sprintf("%s/confirm-email?token=%s", baseURL, url.QueryEscape(token))
+
+	subject := "Confirm your new email address"
+	body := fmt.Sprintf("A request was made to use this address for your account. Please confirm it by following the link below:\n\n%s\n\nIf you did not request this change, you can ignore this email.", link)
+
+	return subject, body
+}
+
+// emailChangedNotification builds the email sent to the previous address once an email change is completed,
+// without a link reverting the change when token is empty
+func emailChangedNotification(baseURL string, newEmail string, token string) (string, string) {
+	subject := "Your email address was changed"
+
+	if token == "" {
+		body := fmt.Sprintf("The email address of your account was changed to %s.\n\nIf you did not make this change, contact support right away to restore this address and secure your account.", newEmail)
+
+		return subject, body
+	}
+
+	link := fmt.Sprintf("%s/revert-email?token=%s", baseURL, url.QueryEscape(token))
+	body := fmt.Sprintf("The email address of your account was changed to %s.\n\nIf you did not make this change, follow the link below to restore this address and sign out of every session, then change your password:\n\n%s", newEmail, link)
+
+	return subject, body
+}
+
+// unlockEmail builds the email sent when an account gets locked after too many failed signins
+func unlockEmail(baseURL string, token string) (string, string) {
+	link := fmt.Sprintf("%s/unlock?token=%s", baseURL, url.QueryEscape(token))
+
+	subject := "Your account has been locked"
+	body := fmt.Sprintf("Signing in to your account was blocked after too many failed attempts. If this was you, follow the link below to unlock your account:\n\n%s\n\nIf it was not you, someone may be trying to guess your password. Your account will unlock automatically, consider changing your password.", link)
+
+	return subject, body
+}
+
+// accountDeletedEmail builds the email sent to users deleting their account, which can be restored until purgeAt
+func accountDeletedEmail(baseURL string, token string, purgeAt time.Time) (string, string) {
+	link := fmt.Sprintf("%s/restore-account?token=%s", baseURL, url.QueryEscape(token))
+
+	subject := "Your account has been deleted"
+	body := fmt.Sprintf("Your account was deleted and will be erased for good on %s. Until then, you can restore it by following the link below:\n\n%s\n\nIf you did not delete your account, restore it and change your password.", purgeAt.UTC().Format("January 2, 2006"), link)
+
+	return subject, body
+}
+
+// invitationEmail builds the email inviting someone to join an organization
+func invitationEmail(baseURL string, orgName string, token string) (string, string) {
+	link := fmt.Sprintf("%s/invitations?token=%s", baseURL, url.QueryEscape(token))
+
+	subject := fmt.Sprintf("You have been invited to join %s", orgName)
+	body := fmt.Sprintf("You have been invited to join %s. Follow the link below to accept or decline the invitation:\n\n%s\n\nIf you were not expecting this invitation, you can ignore this email.", orgName, link)
+
+	return subject, body
+}
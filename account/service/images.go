@@ -0,0 +1,110 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/url"
+	"path"
+
+	"github.com/disintegration/imaging"
+	"github.com/google/uuid"
+
+	// registers the webp decoder with the image package
+	_ "golang.org/x/image/webp"
+)
+
+// defaultImageSizes are the sizes in pixels of the square variants generated for a profile image
+var defaultImageSizes = []int{64, 256, 512}
+
+// maxImageDimension guards against decoding images with huge dimensions
+// (decompression bombs) which are small once compressed
+const maxImageDimension = 8192
+
+// imageVariant is an encoded square variant of an image
+type imageVariant struct {
+	Size        int
+	Data        []byte
+	ContentType string
+	Ext         string
+}
+
+// processImage decodes an image, applies its EXIF orientation, and center crops and resizes it
+// into a square variant for each size. Variants are re-encoded, which strips every metadata
+// (EXIF, GPS, ...) of the original. Opaque images are encoded as JPEG, others as PNG to keep transparency
+func processImage(r io.Reader, sizes []int) ([]imageVariant, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode image config: %w", err)
+	}
+
+	if cfg.Width > maxImageDimension || cfg.Height > maxImageDimension {
+		return nil, fmt.Errorf("image dimensions %vx%v exceed %v pixels", cfg.Width, cfg.Height, maxImageDimension)
+	}
+
+	img, err := imaging.Decode(bytes.NewReader(data), imaging.AutoOrientation(true))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode image: %w", err)
+	}
+
+	opaque := isOpaque(img)
+
+	variants := make([]imageVariant, 0, len(sizes))
+
+	for _, size := range sizes {
+		resized := imaging.Fill(img, size, size, imaging.Center, imaging.Lanczos)
+
+		buf := &bytes.Buffer{}
+		variant := imageVariant{Size: size}
+
+		if opaque {
+			err = jpeg.Encode(buf, resized, &jpeg.Options{Quality: 85})
+			variant.ContentType, variant.Ext = "image/jpeg", ".jpg"
+		} else {
+			err = png.Encode(buf, resized)
+			variant.ContentType, variant.Ext = "image/png", ".png"
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode %vpx variant: %w", size, err)
+		}
+
+		variant.Data = buf.Bytes()
+		variants = append(variants, variant)
+	}
+
+	return variants, nil
+}
+
+// isOpaque reports whether an image has no transparent pixels
+func isOpaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+
+	return false
+}
+
+// imageObjectName returns the object name of a variant of a new image of the user.
+// Every upload uses a new id so cached urls of previous images are never served stale
+func imageObjectName(uid uuid.UUID, id uuid.UUID, v imageVariant) string {
+	return fmt.Sprintf("%s_%s_%d%s", uid, id, v.Size, v.Ext)
+}
+
+// imageObjectNameFromURL extracts the object name of a stored image from its url
+func imageObjectNameFromURL(imageURL string) string {
+	u, err := url.Parse(imageURL)
+	if err != nil {
+		return path.Base(imageURL)
+	}
+
+	return path.Base(u.Path)
+}
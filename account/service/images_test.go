@@ -0,0 +1,125 @@
+package service
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testImage returns an opaque png of the given dimensions
+func testImage(t *testing.T, width int, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: color.RGBA{R: 200, G: 100, B: 50, A: 255}}, image.Point{}, draw.Src)
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, png.Encode(buf, img))
+
+	return buf.Bytes()
+}
+
+// withOrientation inserts an EXIF segment holding the given orientation right after the SOI marker of a jpeg
+func withOrientation(t *testing.T, jpg []byte, orientation uint16) []byte {
+	t.Helper()
+
+	tiff := &bytes.Buffer{}
+	tiff.WriteString("MM\x00\x2a")                          // big endian tiff header
+	_ = binary.Write(tiff, binary.BigEndian, uint32(8))     // offset of first IFD
+	_ = binary.Write(tiff, binary.BigEndian, uint16(1))     // number of entries
+	_ = binary.Write(tiff, binary.BigEndian, uint16(0x112)) // orientation tag
+	_ = binary.Write(tiff, binary.BigEndian, uint16(3))     // SHORT
+	_ = binary.Write(tiff, binary.BigEndian, uint32(1))     // count
+	_ = binary.Write(tiff, binary.BigEndian, orientation)
+	_ = binary.Write(tiff, binary.BigEndian, uint16(0)) // padding
+	_ = binary.Write(tiff, binary.BigEndian, uint32(0)) // no next IFD
+
+	payload := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+
+	segment := &bytes.Buffer{}
+	segment.Write([]byte{0xFF, 0xE1})
+	_ = binary.Write(segment, binary.BigEndian, uint16(len(payload)+2))
+	segment.Write(payload)
+
+	out := append([]byte{}, jpg[:2]...)
+	out = append(out, segment.Bytes()...)
+	return append(out, jpg[2:]...)
+}
+
+func TestProcessImage(t *testing.T) {
+	t.Run("Square variants of each size", func(t *testing.T) {
+		variants, err := processImage(bytes.NewReader(testImage(t, 800, 600)), []int{64, 256})
+
+		assert.NoError(t, err)
+		assert.Len(t, variants, 2)
+
+		for i, size := range []int{64, 256} {
+			assert.Equal(t, size, variants[i].Size)
+			assert.Equal(t, "image/jpeg", variants[i].ContentType)
+
+			cfg, format, err := image.DecodeConfig(bytes.NewReader(variants[i].Data))
+			assert.NoError(t, err)
+			assert.Equal(t, "jpeg", format)
+			assert.Equal(t, size, cfg.Width)
+			assert.Equal(t, size, cfg.Height)
+		}
+	})
+
+	t.Run("Transparent images stay png", func(t *testing.T) {
+		img := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+
+		buf := &bytes.Buffer{}
+		assert.NoError(t, png.Encode(buf, img))
+
+		variants, err := processImage(buf, []int{8})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "image/png", variants[0].ContentType)
+		assert.Equal(t, ".png", variants[0].Ext)
+	})
+
+	t.Run("Applies EXIF orientation and strips metadata", func(t *testing.T) {
+		// left half red, right half blue
+		img := image.NewRGBA(image.Rect(0, 0, 64, 32))
+		draw.Draw(img, image.Rect(0, 0, 32, 32), &image.Uniform{C: color.RGBA{R: 255, A: 255}}, image.Point{}, draw.Src)
+		draw.Draw(img, image.Rect(32, 0, 64, 32), &image.Uniform{C: color.RGBA{B: 255, A: 255}}, image.Point{}, draw.Src)
+
+		buf := &bytes.Buffer{}
+		assert.NoError(t, jpeg.Encode(buf, img, &jpeg.Options{Quality: 100}))
+
+		// orientation 6 means the image has to be rotated 90 degrees clockwise,
+		// which moves the red half to the top
+		variants, err := processImage(bytes.NewReader(withOrientation(t, buf.Bytes(), 6)), []int{32})
+		assert.NoError(t, err)
+
+		assert.NotContains(t, string(variants[0].Data), "Exif")
+
+		out, err := jpeg.Decode(bytes.NewReader(variants[0].Data))
+		assert.NoError(t, err)
+
+		r, _, b, _ := out.At(16, 2).RGBA()
+		assert.Greater(t, r, b, "top of the image should be red")
+
+		r, _, b, _ = out.At(16, 29).RGBA()
+		assert.Greater(t, b, r, "bottom of the image should be blue")
+	})
+
+	t.Run("Rejects huge dimensions", func(t *testing.T) {
+		_, err := processImage(bytes.NewReader(testImage(t, maxImageDimension+1, 1)), []int{64})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Rejects non images", func(t *testing.T) {
+		_, err := processImage(bytes.NewReader([]byte("not an image")), []int{64})
+
+		assert.Error(t, err)
+	})
+}
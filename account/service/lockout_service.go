@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// unlockTokenExpiry is how long the link emailed to unlock an account stays valid
+const unlockTokenExpiry = 24 * time.Hour
+
+// LockoutService counts failed signins per account and per client ip, delaying further
+// attempts exponentially and locking accounts once the LockoutPolicy threshold is reached
+type LockoutService struct {
+	LoginAttemptRepository      model.LoginAttemptRepository
+	UserRepository              model.UserRepository
+	VerificationTokenRepository model.VerificationTokenRepository
+	Mailer                      model.Mailer
+	AppSettings                 model.AppSettings
+	AppURL                      string
+}
+
+// LSConfig will hold repositories that will eventually be injected into this service layer
+type LSConfig struct {
+	LoginAttemptRepository      model.LoginAttemptRepository
+	UserRepository              model.UserRepository
+	VerificationTokenRepository model.VerificationTokenRepository
+	Mailer                      model.Mailer
+	AppSettings                 model.AppSettings
+	AppURL                      string // base url of the client application, used for links sent by email
+}
+
+// NewLockoutService is a factory function for initializing a LockoutService with its repository layer dependencies
+func NewLockoutService(c *LSConfig) model.LockoutService {
+	return &LockoutService{
+		LoginAttemptRepository:      c.LoginAttemptRepository,
+		UserRepository:              c.UserRepository,
+		VerificationTokenRepository: c.VerificationTokenRepository,
+		Mailer:                      c.Mailer,
+		AppSettings:                 c.AppSettings,
+		AppURL:                      c.AppURL,
+	}
+}
+
+// Check returns a locked error holding how long to wait when a signin
+// for the email address or from the ip is not allowed yet
+func (s *LockoutService) Check(ctx context.Context, email string, ip string) error {
+	p := model.ApplicationSettings(ctx, s.AppSettings).GetLockoutPolicy()
+
+	ua, err := s.LoginAttemptRepository.Get(ctx, userAttemptsKey(ctx, email))
+	if err != nil {
+		return err
+	}
+
+	if wait := retryAfter(ua, p, p.DelayAfter, p.MaxFailures); wait > 0 {
+		return apperrors.NewLocked(wait)
+	}
+
+	if ip == "" {
+		return nil
+	}
+
+	ia, err := s.LoginAttemptRepository.Get(ctx, ipAttemptsKey(ip))
+	if err != nil {
+		return err
+	}
+
+	if wait := retryAfter(ia, p, p.IPDelayAfter, p.IPMaxFailures); wait > 0 {
+		return apperrors.NewLocked(wait)
+	}
+
+	return nil
+}
+
+// RecordFailure counts a failed signin for the email address and the ip.
+// The owner of the account is emailed an unlock link when it gets locked
+func (s *LockoutService) RecordFailure(ctx context.Context, email string, ip string) error {
+	p := model.ApplicationSettings(ctx, s.AppSettings).GetLockoutPolicy()
+
+	ua, err := s.LoginAttemptRepository.RecordFailure(ctx, userAttemptsKey(ctx, email), p.Window)
+	if err != nil {
+		return err
+	}
+
+	// only email once, when the threshold is crossed
+	if p.MaxFailures > 0 && ua.Failures == p.MaxFailures {
+		if err := s.sendUnlockEmail(ctx, email); err != nil {
+			log.Printf("Unable to send unlock email to: %v. Reason: %v\n", email, err)
+		}
+	}
+
+	if ip == "" {
+		return nil
+	}
+
+	_, err = s.LoginAttemptRepository.RecordFailure(ctx, ipAttemptsKey(ip), p.Window)
+
+	return err
+}
+
+// RecordSuccess forgets the failed signins of the email address. Failures of the ip are kept
+// so an attacker cannot reset them by signing in to an account of their own
+func (s *LockoutService) RecordSuccess(ctx context.Context, email string) error {
+	return s.LoginAttemptRepository.Reset(ctx, userAttemptsKey(ctx, email))
+}
+
+// Unlock forgets the failed signins of the email address, eg on behalf of an administrator
+func (s *LockoutService) Unlock(ctx context.Context, email string) error {
+	return s.LoginAttemptRepository.Reset(ctx, userAttemptsKey(ctx, email))
+}
+
+// UnlockWithToken unlocks the account an unlock token was emailed for
+func (s *LockoutService) UnlockWithToken(ctx context.Context, token string) error {
+	t, err := s.VerificationTokenRepository.Consume(ctx, model.Unlock, hashVerificationToken(token))
+	if err != nil {
+		return apperrors.NewBadRequest("Invalid or expired unlock token")
+	}
+
+	if time.Now().After(t.ExpiresAt) {
+		return apperrors.NewBadRequest("Invalid or expired unlock token")
+	}
+
+	return s.Unlock(ctx, t.Email)
+}
+
+// sendUnlockEmail emails an unlock link to the owner of the account, if the account exists
+func (s *LockoutService) sendUnlockEmail(ctx context.Context, email string) error {
+	u, err := s.UserRepository.FindByEmail(ctx, email)
+	if err != nil {
+		return nil
+	}
+
+	token, tokenHash, err := generateVerificationToken()
+	if err != nil {
+		return err
+	}
+
+	if err := s.VerificationTokenRepository.Create(ctx, &model.VerificationToken{
+		TokenHash: tokenHash,
+		UID:       u.UID,
+		Purpose:   model.Unlock,
+		Email:     u.Email,
+		ExpiresAt: time.Now().Add(unlockTokenExpiry),
+	}); err != nil {
+		return err
+	}
+
+	subject, body := unlockEmail(appURL(ctx, s.AppURL), token)
+
+	return s.Mailer.Send(ctx, u.Email, subject, body)
+}
+
+// retryAfter returns how long to wait before the next attempt is allowed, 0 if it is allowed now
+func retryAfter(a *model.LoginAttempts, p model.LockoutPolicy, delayAfter int, maxFailures int) time.Duration {
+	if a.Failures == 0 || time.Since(a.LastFailure) > p.Window {
+		return 0
+	}
+
+	var until time.Time
+
+	switch {
+	case maxFailures > 0 && a.Failures >= maxFailures:
+		until = a.LastFailure.Add(p.LockoutDuration)
+	case delayAfter > 0 && a.Failures >= delayAfter:
+		until = a.LastFailure.Add(backoff(a.Failures-delayAfter, p))
+	default:
+		return 0
+	}
+
+	if wait := time.Until(until); wait > 0 {
+		return wait
+	}
+
+	return 0
+}
+
+// backoff returns BaseDelay doubled n times, capped at MaxDelay
+func backoff(n int, p model.LockoutPolicy) time.Duration {
+	if n >= 32 {
+		return p.MaxDelay
+	}
+
+	d := p.BaseDelay << n
+	if d <= 0 || d > p.MaxDelay {
+		return p.MaxDelay
+	}
+
+	return d
+}
+
+// userAttemptsKey counts failures per email address within the application in ctx,
+// as the same address may belong to users of different applications
+func userAttemptsKey(ctx context.Context, email string) string {
+	key := "user:" + strings.ToLower(strings.TrimSpace(email))
+
+	if app, ok := model.ApplicationFromContext(ctx); ok {
+		key = "app:" + app.ID.String() + ":" + key
+	}
+
+	return key
+}
+
+func ipAttemptsKey(ip string) string {
+	return "ip:" + ip
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
+	"github.com/weslleyrsr/auth-engine/account/model/repository"
+)
+
+func TestLockout(t *testing.T) {
+	email := "bob@bob.com"
+	ip := "203.0.113.7"
+
+	policy := &model.LockoutPolicy{
+		DelayAfter:      2,
+		BaseDelay:       10 * time.Second,
+		MaxDelay:        30 * time.Second,
+		MaxFailures:     5,
+		LockoutDuration: 15 * time.Minute,
+		Window:          time.Hour,
+		IPDelayAfter:    8,
+		IPMaxFailures:   20,
+	}
+
+	setup := func() (model.LockoutService, *mocks.MockUserRepository, *mocks.MockVerificationTokenRepository, *mocks.MockMailer) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockVerificationTokenRepository := new(mocks.MockVerificationTokenRepository)
+		mockMailer := new(mocks.MockMailer)
+
+		ls := NewLockoutService(&LSConfig{
+			LoginAttemptRepository:      repository.NewMemoryLoginAttemptRepository(),
+			UserRepository:              mockUserRepository,
+			VerificationTokenRepository: mockVerificationTokenRepository,
+			Mailer:                      mockMailer,
+			AppSettings:                 model.AppSettings{LockoutPolicy: policy},
+			AppURL:                      "https://app.test",
+		})
+
+		return ls, mockUserRepository, mockVerificationTokenRepository, mockMailer
+	}
+
+	retryAfterOf := func(err error) int {
+		return err.(*apperrors.Error).RetryAfter
+	}
+
+	t.Run("Allowed below the delay threshold", func(t *testing.T) {
+		ls, _, _, _ := setup()
+
+		assert.NoError(t, ls.RecordFailure(context.TODO(), email, ip))
+		assert.NoError(t, ls.Check(context.TODO(), email, ip))
+	})
+
+	t.Run("Exponential backoff", func(t *testing.T) {
+		ls, _, _, _ := setup()
+
+		for i := 0; i < 2; i++ {
+			assert.NoError(t, ls.RecordFailure(context.TODO(), email, ip))
+		}
+
+		err := ls.Check(context.TODO(), email, ip)
+		assert.Equal(t, apperrors.Authorization, err.(*apperrors.Error).Type)
+		assert.Equal(t, 10, retryAfterOf(err))
+
+		assert.NoError(t, ls.RecordFailure(context.TODO(), email, ip))
+		assert.Equal(t, 20, retryAfterOf(ls.Check(context.TODO(), email, ip)))
+
+		// capped at MaxDelay
+		assert.NoError(t, ls.RecordFailure(context.TODO(), email, ip))
+		assert.Equal(t, 30, retryAfterOf(ls.Check(context.TODO(), email, ip)))
+
+		// other accounts are not affected
+		assert.NoError(t, ls.Check(context.TODO(), "alice@bob.com", ip))
+	})
+
+	t.Run("Lockout sends an unlock email", func(t *testing.T) {
+		ls, mockUserRepository, mockVerificationTokenRepository, mockMailer := setup()
+
+		uid, _ := uuid.NewRandom()
+		var unlockToken string
+
+		mockUserRepository.On("FindByEmail", mock.Anything, email).Return(&model.User{UID: uid, Email: email}, nil)
+		mockVerificationTokenRepository.
+			On("Create", mock.Anything, mock.MatchedBy(func(vt *model.VerificationToken) bool {
+				return vt.UID == uid && vt.Purpose == model.Unlock && vt.Email == email
+			})).
+			Return(nil)
+		mockMailer.
+			On("Send", mock.Anything, email, mock.AnythingOfType("string"), mock.AnythingOfType("string")).
+			Run(func(args mock.Arguments) {
+				body := args.String(3)
+				i := strings.Index(body, "token=")
+				unlockToken = strings.Fields(body[i+len("token="):])[0]
+			}).
+			Return(nil)
+
+		for i := 0; i < 6; i++ {
+			assert.NoError(t, ls.RecordFailure(context.TODO(), email, ip))
+		}
+
+		err := ls.Check(context.TODO(), email, ip)
+		assert.InDelta(t, 15*60, retryAfterOf(err), 1)
+
+		// only emailed once
+		mockMailer.AssertNumberOfCalls(t, "Send", 1)
+		assert.NotEmpty(t, unlockToken)
+
+		mockVerificationTokenRepository.
+			On("Consume", mock.Anything, model.Unlock, hashVerificationToken(unlockToken)).
+			Return(&model.VerificationToken{UID: uid, Email: email, Purpose: model.Unlock, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+
+		assert.NoError(t, ls.UnlockWithToken(context.TODO(), unlockToken))
+		assert.NoError(t, ls.Check(context.TODO(), email, ip))
+	})
+
+	t.Run("Invalid unlock token", func(t *testing.T) {
+		ls, _, mockVerificationTokenRepository, _ := setup()
+
+		mockVerificationTokenRepository.
+			On("Consume", mock.Anything, model.Unlock, mock.AnythingOfType("string")).
+			Return(nil, apperrors.NewNotFound("token", string(model.Unlock)))
+
+		err := ls.UnlockWithToken(context.TODO(), "invalid")
+
+		assert.Equal(t, apperrors.BadRequest, err.(*apperrors.Error).Type)
+	})
+
+	t.Run("Success and admin unlock reset the account", func(t *testing.T) {
+		ls, _, _, _ := setup()
+
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, ls.RecordFailure(context.TODO(), email, ""))
+		}
+		assert.Error(t, ls.Check(context.TODO(), email, ""))
+
+		assert.NoError(t, ls.RecordSuccess(context.TODO(), email))
+		assert.NoError(t, ls.Check(context.TODO(), email, ""))
+
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, ls.RecordFailure(context.TODO(), email, ""))
+		}
+
+		assert.NoError(t, ls.Unlock(context.TODO(), strings.ToUpper(email)))
+		assert.NoError(t, ls.Check(context.TODO(), email, ""))
+	})
+
+	t.Run("Failures spread over accounts throttle the ip", func(t *testing.T) {
+		ls, mockUserRepository, _, _ := setup()
+		mockUserRepository.On("FindByEmail", mock.Anything, mock.Anything).Return(nil, apperrors.NewNotFound("email", ""))
+
+		for i := 0; i < 8; i++ {
+			assert.NoError(t, ls.RecordFailure(context.TODO(), strings.Repeat("a", i+1)+"@bob.com", ip))
+		}
+
+		assert.Error(t, ls.Check(context.TODO(), "fresh@bob.com", ip))
+		assert.NoError(t, ls.Check(context.TODO(), "fresh@bob.com", "198.51.100.1"))
+	})
+
+	t.Run("Failures are counted per application", func(t *testing.T) {
+		ls, _, _, _ := setup()
+
+		for i := 0; i < 2; i++ {
+			assert.NoError(t, ls.RecordFailure(context.TODO(), email, ""))
+		}
+
+		assert.Error(t, ls.Check(context.TODO(), email, ""))
+
+		app := &model.Application{ID: uuid.New(), Settings: model.AppSettings{LockoutPolicy: policy}}
+		assert.NoError(t, ls.Check(model.NewApplicationContext(context.TODO(), app), email, ""))
+	})
+
+	t.Run("Failures are forgotten after the window", func(t *testing.T) {
+		a := &model.LoginAttempts{Failures: 10, LastFailure: time.Now().Add(-2 * time.Hour)}
+
+		assert.Zero(t, retryAfter(a, *policy, policy.DelayAfter, policy.MaxFailures))
+	})
+}
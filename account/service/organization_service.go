@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// invitationExpiry is how long an invitation to join an organization can be accepted for
+const invitationExpiry = 7 * 24 * time.Hour
+
+// OrganizationService acts as a struct for injecting an implementation of OrganizationRepository
+// for use in service methods along with the Mailer sending invitations
+type OrganizationService struct {
+	OrganizationRepository model.OrganizationRepository
+	Mailer                 model.Mailer
+	AppURL                 string
+}
+
+// OSConfig will hold repositories that will eventually be injected into this
+// service layer
+type OSConfig struct {
+	OrganizationRepository model.OrganizationRepository
+	Mailer                 model.Mailer
+	AppURL                 string // base url of the client application, used for links sent by email
+}
+
+// NewOrganizationService is a factory function for
+// initializing an OrganizationService with its repository layer dependencies
+func NewOrganizationService(c *OSConfig) model.OrganizationService {
+	return &OrganizationService{
+		OrganizationRepository: c.OrganizationRepository,
+		Mailer:                 c.Mailer,
+		AppURL:                 c.AppURL,
+	}
+}
+
+// Create creates an organization owned by the user
+func (s *OrganizationService) Create(ctx context.Context, uid uuid.UUID, org *model.Organization) error {
+	return s.OrganizationRepository.Create(ctx, org, uid)
+}
+
+// ListForUser retrieves the organizations the user is a member of
+func (s *OrganizationService) ListForUser(ctx context.Context, uid uuid.UUID) ([]*model.Organization, error) {
+	return s.OrganizationRepository.FindByUser(ctx, uid)
+}
+
+// GetMembership retrieves the membership of the user to an organization
+func (s *OrganizationService) GetMembership(ctx context.Context, orgID uuid.UUID, uid uuid.UUID) (*model.Membership, error) {
+	return s.OrganizationRepository.FindMembership(ctx, orgID, uid)
+}
+
+// ListMembers retrieves the memberships of an organization
+func (s *OrganizationService) ListMembers(ctx context.Context, orgID uuid.UUID) ([]*model.Membership, error) {
+	return s.OrganizationRepository.FindMembers(ctx, orgID)
+}
+
+// Invite emails an invitation to join an organization with a role. Inviting requires being an
+// admin of the organization, and members cannot be invited with a role above the inviter's own
+func (s *OrganizationService) Invite(ctx context.Context, orgID uuid.UUID, inviter uuid.UUID, email string, role model.MembershipRole) (*model.Invitation, error) {
+	if !role.Valid() {
+		return nil, apperrors.NewBadRequest("Invalid membership role")
+	}
+
+	m, err := s.OrganizationRepository.FindMembership(ctx, orgID, inviter)
+	if err != nil || !m.Role.AtLeast(model.OrgAdmin) {
+		return nil, apperrors.NewForbidden("Only admins of the organization can invite members")
+	}
+
+	if !m.Role.AtLeast(role) {
+		return nil, apperrors.NewForbidden("Cannot invite members with a role above your own")
+	}
+
+	org, err := s.OrganizationRepository.FindByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, tokenHash, err := generateVerificationToken()
+	if err != nil {
+		log.Printf("Unable to generate invitation token for organization: %v\n", orgID)
+		return nil, apperrors.NewInternal()
+	}
+
+	inv := &model.Invitation{
+		OrgID:     orgID,
+		Email:     email,
+		Role:      role,
+		TokenHash: tokenHash,
+		InvitedBy: inviter,
+		ExpiresAt: time.Now().Add(invitationExpiry),
+	}
+
+	if err := s.OrganizationRepository.CreateInvitation(ctx, inv); err != nil {
+		return nil, err
+	}
+
+	subject, body := invitationEmail(appURL(ctx, s.AppURL), org.Name, token)
+
+	if err := s.Mailer.Send(ctx, email, subject, body); err != nil {
+		log.Printf("Unable to send invitation to organization: %v. Reason: %v\n", orgID, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	return inv, nil
+}
+
+// AcceptInvitation adds the user to the organization they were invited to, with the role they were invited
+// with. The invitation must have been sent to the email address of the user
+func (s *OrganizationService) AcceptInvitation(ctx context.Context, u *model.User, token string) (*model.Membership, error) {
+	inv, err := s.pendingInvitation(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(inv.Email, u.Email) {
+		return nil, apperrors.NewForbidden("Invitation was sent to another email address")
+	}
+
+	if err := s.OrganizationRepository.ResolveInvitation(ctx, inv.ID, model.InvitationAccepted); err != nil {
+		return nil, apperrors.NewBadRequest("Invalid or expired invitation")
+	}
+
+	m := &model.Membership{
+		OrgID: inv.OrgID,
+		UID:   u.UID,
+		Role:  inv.Role,
+	}
+
+	if err := s.OrganizationRepository.AddMember(ctx, m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// DeclineInvitation declines an invitation to join an organization
+func (s *OrganizationService) DeclineInvitation(ctx context.Context, token string) error {
+	inv, err := s.pendingInvitation(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	if err := s.OrganizationRepository.ResolveInvitation(ctx, inv.ID, model.InvitationDeclined); err != nil {
+		return apperrors.NewBadRequest("Invalid or expired invitation")
+	}
+
+	return nil
+}
+
+// pendingInvitation retrieves the invitation of a token, as long as it was not accepted, declined nor expired
+func (s *OrganizationService) pendingInvitation(ctx context.Context, token string) (*model.Invitation, error) {
+	inv, err := s.OrganizationRepository.FindInvitation(ctx, hashVerificationToken(token))
+	if err != nil {
+		return nil, apperrors.NewBadRequest("Invalid or expired invitation")
+	}
+
+	if inv.Status != model.InvitationPending || time.Now().After(inv.ExpiresAt) {
+		return nil, apperrors.NewBadRequest("Invalid or expired invitation")
+	}
+
+	return inv, nil
+}
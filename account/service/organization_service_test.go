@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
+)
+
+func TestInvite(t *testing.T) {
+	orgID := uuid.New()
+	inviter := uuid.New()
+	email := "alice@bob.com"
+
+	setup := func(inviterRole model.MembershipRole) (model.OrganizationService, *mocks.MockOrganizationRepository, *mocks.MockMailer) {
+		mockOrganizationRepository := new(mocks.MockOrganizationRepository)
+		mockMailer := new(mocks.MockMailer)
+
+		mockOrganizationRepository.On("FindMembership", mock.Anything, orgID, inviter).
+			Return(&model.Membership{OrgID: orgID, UID: inviter, Role: inviterRole}, nil)
+		mockOrganizationRepository.On("FindByID", mock.Anything, orgID).
+			Return(&model.Organization{ID: orgID, Name: "Acme"}, nil)
+
+		os := NewOrganizationService(&OSConfig{
+			OrganizationRepository: mockOrganizationRepository,
+			Mailer:                 mockMailer,
+			AppURL:                 "https://app.test",
+		})
+
+		return os, mockOrganizationRepository, mockMailer
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		os, mockOrganizationRepository, mockMailer := setup(model.OrgAdmin)
+
+		var token string
+
+		mockOrganizationRepository.
+			On("CreateInvitation", mock.Anything, mock.MatchedBy(func(inv *model.Invitation) bool {
+				return inv.OrgID == orgID && inv.Email == email && inv.Role == model.OrgMember && inv.InvitedBy == inviter
+			})).
+			Return(nil)
+		mockMailer.
+			On("Send", mock.Anything, email, "You have been invited to join Acme", mock.AnythingOfType("string")).
+			Run(func(args mock.Arguments) {
+				body := args.String(3)
+				i := strings.Index(body, "token=")
+				token, _ = url.QueryUnescape(strings.Fields(body[i+len("token="):])[0])
+			}).
+			Return(nil)
+
+		inv, err := os.Invite(context.TODO(), orgID, inviter, email, model.OrgMember)
+
+		assert.NoError(t, err)
+		assert.Equal(t, hashVerificationToken(token), inv.TokenHash)
+		assert.WithinDuration(t, time.Now().Add(invitationExpiry), inv.ExpiresAt, 5*time.Second)
+		mockOrganizationRepository.AssertExpectations(t)
+		mockMailer.AssertExpectations(t)
+	})
+
+	t.Run("Members cannot invite", func(t *testing.T) {
+		os, mockOrganizationRepository, mockMailer := setup(model.OrgMember)
+
+		_, err := os.Invite(context.TODO(), orgID, inviter, email, model.OrgMember)
+
+		assert.Equal(t, apperrors.Forbidden, err.(*apperrors.Error).Type)
+		mockOrganizationRepository.AssertNotCalled(t, "CreateInvitation", mock.Anything, mock.Anything)
+		mockMailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Role above the inviter's", func(t *testing.T) {
+		os, mockOrganizationRepository, _ := setup(model.OrgAdmin)
+
+		_, err := os.Invite(context.TODO(), orgID, inviter, email, model.OrgOwner)
+
+		assert.Equal(t, apperrors.Forbidden, err.(*apperrors.Error).Type)
+		mockOrganizationRepository.AssertNotCalled(t, "CreateInvitation", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAcceptInvitation(t *testing.T) {
+	token := "aninvitationtoken"
+	u := &model.User{
+		UID:   uuid.New(),
+		Email: "Alice@bob.com",
+	}
+
+	pending := func() *model.Invitation {
+		return &model.Invitation{
+			ID:        uuid.New(),
+			OrgID:     uuid.New(),
+			Email:     "alice@bob.com",
+			Role:      model.OrgAdmin,
+			Status:    model.InvitationPending,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+	}
+
+	setup := func(inv *model.Invitation) (model.OrganizationService, *mocks.MockOrganizationRepository) {
+		mockOrganizationRepository := new(mocks.MockOrganizationRepository)
+		mockOrganizationRepository.On("FindInvitation", mock.Anything, hashVerificationToken(token)).Return(inv, nil)
+
+		return NewOrganizationService(&OSConfig{
+			OrganizationRepository: mockOrganizationRepository,
+		}), mockOrganizationRepository
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		inv := pending()
+		os, mockOrganizationRepository := setup(inv)
+
+		expected := &model.Membership{OrgID: inv.OrgID, UID: u.UID, Role: model.OrgAdmin}
+
+		mockOrganizationRepository.On("ResolveInvitation", mock.Anything, inv.ID, model.InvitationAccepted).Return(nil)
+		mockOrganizationRepository.On("AddMember", mock.Anything, expected).Return(nil)
+
+		m, err := os.AcceptInvitation(context.TODO(), u, token)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, m)
+		mockOrganizationRepository.AssertExpectations(t)
+	})
+
+	t.Run("Sent to another email address", func(t *testing.T) {
+		inv := pending()
+		inv.Email = "mallory@bob.com"
+		os, mockOrganizationRepository := setup(inv)
+
+		_, err := os.AcceptInvitation(context.TODO(), u, token)
+
+		assert.Equal(t, apperrors.Forbidden, err.(*apperrors.Error).Type)
+		mockOrganizationRepository.AssertNotCalled(t, "ResolveInvitation", mock.Anything, mock.Anything, mock.Anything)
+		mockOrganizationRepository.AssertNotCalled(t, "AddMember", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		inv := pending()
+		inv.ExpiresAt = time.Now().Add(-time.Minute)
+		os, mockOrganizationRepository := setup(inv)
+
+		_, err := os.AcceptInvitation(context.TODO(), u, token)
+
+		assert.Equal(t, apperrors.BadRequest, err.(*apperrors.Error).Type)
+		mockOrganizationRepository.AssertNotCalled(t, "AddMember", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Already declined", func(t *testing.T) {
+		inv := pending()
+		inv.Status = model.InvitationDeclined
+		os, mockOrganizationRepository := setup(inv)
+
+		_, err := os.AcceptInvitation(context.TODO(), u, token)
+
+		assert.Equal(t, apperrors.BadRequest, err.(*apperrors.Error).Type)
+		mockOrganizationRepository.AssertNotCalled(t, "AddMember", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Decline", func(t *testing.T) {
+		inv := pending()
+		os, mockOrganizationRepository := setup(inv)
+
+		mockOrganizationRepository.On("ResolveInvitation", mock.Anything, inv.ID, model.InvitationDeclined).Return(nil)
+
+		assert.NoError(t, os.DeclineInvitation(context.TODO(), token))
+		mockOrganizationRepository.AssertExpectations(t)
+	})
+}
@@ -2,44 +2,337 @@ package service
 
 import (
 	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"strings"
 
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
 	"golang.org/x/crypto/scrypt"
 )
 
-func hashPassword(password string) (string, error) {
-	// example for making salt - https://play.golang.org/p/_Aw6WeWC42I
-	salt := make([]byte, 32)
-	_, err := rand.Read(salt)
+// PasswordHasher hashes passwords into self-describing strings, which embed the
+// algorithm, its cost parameters and the salt, so they can be verified later even
+// after the configured parameters or algorithm changed
+type PasswordHasher interface {
+	// Hash returns the encoded hash of the password
+	Hash(password string) (string, error)
+	// Verify reports whether the password matches an encoded hash produced by this algorithm
+	Verify(encoded string, password string) (bool, error)
+	// Recognizes reports whether the encoded hash was produced by this algorithm
+	Recognizes(encoded string) bool
+	// NeedsRehash reports whether an encoded hash produced by this algorithm uses
+	// parameters different from the ones this hasher is configured with
+	NeedsRehash(encoded string) bool
+}
+
+// defaultPasswordHasher is used when no PasswordHasher is configured.
+// Parameters are the minimums recommended by OWASP for argon2id
+var defaultPasswordHasher PasswordHasher = &Argon2idHasher{
+	Memory:      19 * 1024,
+	Iterations:  2,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// passwordHashers are all the algorithms stored passwords may be hashed with
+var passwordHashers = []PasswordHasher{
+	&Argon2idHasher{},
+	&ScryptHasher{},
+	&BcryptHasher{},
+}
+
+var errInvalidHash = errors.New("invalid password hash format")
+
+// comparePasswords checks the supplied password against the stored hash, using the
+// algorithm and parameters the stored hash was produced with. Hashes stored before
+// PHC strings were introduced ("hash.salt" hex encoded scrypt) are still supported
+func comparePasswords(storedPassword string, suppliedPassword string) (bool, error) {
+	for _, h := range passwordHashers {
+		if h.Recognizes(storedPassword) {
+			return h.Verify(storedPassword, suppliedPassword)
+		}
+	}
+
+	return compareLegacyPasswords(storedPassword, suppliedPassword)
+}
+
+// needsRehash reports whether a stored hash was not produced by the hasher with its current
+// parameters, either because the algorithm or its cost parameters changed since
+func needsRehash(h PasswordHasher, storedPassword string) bool {
+	if !h.Recognizes(storedPassword) {
+		return true
+	}
+
+	return h.NeedsRehash(storedPassword)
+}
+
+// compareLegacyPasswords verifies hashes in the original "hash.salt" format,
+// hex encoded scrypt with N=32768, r=8, p=1
+func compareLegacyPasswords(storedPassword string, suppliedPassword string) (bool, error) {
+	pwsalt := strings.Split(storedPassword, ".")
+	if len(pwsalt) != 2 {
+		return false, errInvalidHash
+	}
+
+	// check supplied password salted with hash
+	salt, err := hex.DecodeString(pwsalt[1])
+	if err != nil {
+		return false, fmt.Errorf("Unable to verify user password")
+	}
+
+	hash, err := hex.DecodeString(pwsalt[0])
+	if err != nil {
+		return false, fmt.Errorf("Unable to verify user password")
+	}
+
+	shash, err := scrypt.Key([]byte(suppliedPassword), salt, 32768, 8, 1, len(hash))
+	if err != nil {
+		return false, err
+	}
+
+	return subtle.ConstantTimeCompare(shash, hash) == 1, nil
+}
+
+// Argon2idHasher hashes passwords with argon2id, encoded as
+// $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<hash>
+type Argon2idHasher struct {
+	Memory      uint32 // in KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  int
+	KeyLength   uint32
+}
+
+// Hash returns the PHC string of the argon2id hash of the password
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt, err := generateSalt(h.SaltLength)
 	if err != nil {
 		return "", err
 	}
 
-	// using recommended cost parameters from - https://godoc.org/golang.org/x/crypto/scrypt
-	shash, err := scrypt.Key([]byte(password), salt, 32768, 8, 1, 32)
+	key := argon2.IDKey([]byte(password), salt, h.Iterations, h.Memory, h.Parallelism, h.KeyLength)
+
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.Memory, h.Iterations, h.Parallelism, encodePHC(salt), encodePHC(key),
+	), nil
+}
+
+// Verify checks the password against a PHC string of an argon2id hash
+func (h *Argon2idHasher) Verify(encoded string, password string) (bool, error) {
+	params, salt, hash, err := parseArgon2id(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+
+	return subtle.ConstantTimeCompare(key, hash) == 1, nil
+}
+
+// NeedsRehash reports whether the argon2id hash uses other parameters than the hasher
+func (h *Argon2idHasher) NeedsRehash(encoded string) bool {
+	params, _, _, err := parseArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+
+	return *params != *h
+}
+
+// parseArgon2id returns the parameters, salt and hash of a PHC string of an argon2id hash
+func parseArgon2id(encoded string) (*Argon2idHasher, []byte, []byte, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return nil, nil, nil, errInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return nil, nil, nil, errInvalidHash
+	}
+
+	if version != argon2.Version {
+		return nil, nil, nil, fmt.Errorf("unsupported argon2 version: %d", version)
+	}
+
+	params := &Argon2idHasher{}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return nil, nil, nil, errInvalidHash
+	}
+
+	salt, hash, err := decodeSaltAndHash(parts[4], parts[5])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	params.SaltLength = len(salt)
+	params.KeyLength = uint32(len(hash))
+
+	return params, salt, hash, nil
+}
+
+// Recognizes reports whether encoded is a PHC string of an argon2id hash
+func (h *Argon2idHasher) Recognizes(encoded string) bool {
+	return strings.HasPrefix(encoded, "$argon2id$")
+}
+
+// ScryptHasher hashes passwords with scrypt, encoded as
+// $scrypt$ln=<log2(N)>,r=<r>,p=<p>$<salt>$<hash>
+type ScryptHasher struct {
+	LogN       uint8 // N is 1<<LogN
+	R          int
+	P          int
+	SaltLength int
+	KeyLength  int
+}
+
+// Hash returns the PHC string of the scrypt hash of the password
+func (h *ScryptHasher) Hash(password string) (string, error) {
+	salt, err := generateSalt(h.SaltLength)
 	if err != nil {
 		return "", err
 	}
 
-	// return hex-encoded string with salt appended to password
-	hashedPW := fmt.Sprintf("%s.%s", hex.EncodeToString(shash), hex.EncodeToString(salt))
+	key, err := scrypt.Key([]byte(password), salt, 1<<h.LogN, h.R, h.P, h.KeyLength)
+	if err != nil {
+		return "", err
+	}
 
-	return hashedPW, nil
+	return fmt.Sprintf("$scrypt$ln=%d,r=%d,p=%d$%s$%s", h.LogN, h.R, h.P, encodePHC(salt), encodePHC(key)), nil
 }
 
-func comparePasswords(storedPassword string, suppliedPassword string) (bool, error) {
-	pwsalt := strings.Split(storedPassword, ".")
+// Verify checks the password against a PHC string of a scrypt hash
+func (h *ScryptHasher) Verify(encoded string, password string) (bool, error) {
+	params, salt, hash, err := parseScrypt(encoded)
+	if err != nil {
+		return false, err
+	}
 
-	// check supplied password salted with hash
-	salt, err := hex.DecodeString(pwsalt[1])
+	key, err := scrypt.Key([]byte(password), salt, 1<<params.LogN, params.R, params.P, params.KeyLength)
+	if err != nil {
+		return false, err
+	}
+
+	return subtle.ConstantTimeCompare(key, hash) == 1, nil
+}
 
+// NeedsRehash reports whether the scrypt hash uses other parameters than the hasher
+func (h *ScryptHasher) NeedsRehash(encoded string) bool {
+	params, _, _, err := parseScrypt(encoded)
 	if err != nil {
-		return false, fmt.Errorf("Unable to verify user password")
+		return true
+	}
+
+	return *params != *h
+}
+
+// parseScrypt returns the parameters, salt and hash of a PHC string of a scrypt hash
+func parseScrypt(encoded string) (*ScryptHasher, []byte, []byte, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 5 {
+		return nil, nil, nil, errInvalidHash
+	}
+
+	params := &ScryptHasher{}
+	if _, err := fmt.Sscanf(parts[2], "ln=%d,r=%d,p=%d", &params.LogN, &params.R, &params.P); err != nil {
+		return nil, nil, nil, errInvalidHash
+	}
+
+	salt, hash, err := decodeSaltAndHash(parts[3], parts[4])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	params.SaltLength = len(salt)
+	params.KeyLength = len(hash)
+
+	return params, salt, hash, nil
+}
+
+// Recognizes reports whether encoded is a PHC string of a scrypt hash
+func (h *ScryptHasher) Recognizes(encoded string) bool {
+	return strings.HasPrefix(encoded, "$scrypt$")
+}
+
+// BcryptHasher hashes passwords with bcrypt. bcrypt has its own modular crypt format
+// ($2a$<cost>$<salt+hash>) which already embeds the cost and salt, so it is stored as is.
+// bcrypt only uses the first 72 bytes of a password, longer passwords are rejected
+type BcryptHasher struct {
+	Cost int
+}
+
+// Hash returns the bcrypt hash of the password
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+// Verify checks the password against a bcrypt hash
+func (h *BcryptHasher) Verify(encoded string, password string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// NeedsRehash reports whether the bcrypt hash uses another cost than the hasher
+func (h *BcryptHasher) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	if err != nil {
+		return true
+	}
+
+	return cost != h.Cost
+}
+
+// Recognizes reports whether encoded is a bcrypt hash
+func (h *BcryptHasher) Recognizes(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") ||
+		strings.HasPrefix(encoded, "$2b$") ||
+		strings.HasPrefix(encoded, "$2y$")
+}
+
+func generateSalt(length int) ([]byte, error) {
+	salt := make([]byte, length)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
 	}
 
-	shash, err := scrypt.Key([]byte(suppliedPassword), salt, 32768, 8, 1, 32)
+	return salt, nil
+}
+
+// encodePHC encodes bytes with the unpadded standard base64 alphabet used by PHC strings
+func encodePHC(b []byte) string {
+	return base64.RawStdEncoding.EncodeToString(b)
+}
+
+func decodeSaltAndHash(encodedSalt string, encodedHash string) ([]byte, []byte, error) {
+	salt, err := base64.RawStdEncoding.DecodeString(encodedSalt)
+	if err != nil {
+		return nil, nil, errInvalidHash
+	}
+
+	hash, err := base64.RawStdEncoding.DecodeString(encodedHash)
+	if err != nil || len(hash) == 0 {
+		return nil, nil, errInvalidHash
+	}
 
-	return hex.EncodeToString(shash) == pwsalt[0], nil
+	return salt, hash, nil
 }
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordHashers(t *testing.T) {
+	hashers := map[string]PasswordHasher{
+		"argon2id": &Argon2idHasher{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+		"scrypt":   &ScryptHasher{LogN: 10, R: 8, P: 1, SaltLength: 16, KeyLength: 32},
+		"bcrypt":   &BcryptHasher{Cost: 4},
+	}
+
+	for name, h := range hashers {
+		t.Run(name, func(t *testing.T) {
+			encoded, err := h.Hash("avalidpassword")
+			assert.NoError(t, err)
+			assert.True(t, h.Recognizes(encoded))
+
+			match, err := comparePasswords(encoded, "avalidpassword")
+			assert.NoError(t, err)
+			assert.True(t, match)
+
+			match, err = comparePasswords(encoded, "awrongpassword")
+			assert.NoError(t, err)
+			assert.False(t, match)
+
+			// salts are random so two hashes of the same password differ
+			other, _ := h.Hash("avalidpassword")
+			assert.NotEqual(t, encoded, other)
+		})
+	}
+}
+
+func TestPasswordHashFormats(t *testing.T) {
+	t.Run("argon2id PHC string embeds parameters", func(t *testing.T) {
+		h := &Argon2idHasher{Memory: 1024, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32}
+
+		encoded, _ := h.Hash("avalidpassword")
+
+		assert.True(t, strings.HasPrefix(encoded, "$argon2id$v=19$m=1024,t=3,p=2$"))
+		assert.Len(t, strings.Split(encoded, "$"), 6)
+	})
+
+	t.Run("scrypt PHC string embeds parameters", func(t *testing.T) {
+		h := &ScryptHasher{LogN: 10, R: 8, P: 1, SaltLength: 16, KeyLength: 32}
+
+		encoded, _ := h.Hash("avalidpassword")
+
+		assert.True(t, strings.HasPrefix(encoded, "$scrypt$ln=10,r=8,p=1$"))
+	})
+
+	t.Run("Hashes verify with their own parameters", func(t *testing.T) {
+		weak := &ScryptHasher{LogN: 10, R: 8, P: 1, SaltLength: 16, KeyLength: 32}
+		encoded, _ := weak.Hash("avalidpassword")
+
+		// a hasher configured differently still verifies using the parameters of the hash
+		strong := &ScryptHasher{LogN: 12, R: 8, P: 2, SaltLength: 32, KeyLength: 64}
+		match, err := strong.Verify(encoded, "avalidpassword")
+
+		assert.NoError(t, err)
+		assert.True(t, match)
+	})
+
+	t.Run("Legacy hash.salt scrypt hashes", func(t *testing.T) {
+		// "avalidpassword" hashed by the original hashPassword implementation
+		legacy := "9482d21332d4a75e725fd133eb76d24d9bc698487917bf60d1e99f40c44bc867.00112233445566778899aabbccddeeff00112233445566778899aabbccddeeff"
+
+		match, err := comparePasswords(legacy, "avalidpassword")
+		assert.NoError(t, err)
+		assert.True(t, match)
+
+		match, err = comparePasswords(legacy, "awrongpassword")
+		assert.NoError(t, err)
+		assert.False(t, match)
+	})
+
+	t.Run("Malformed hashes", func(t *testing.T) {
+		for _, encoded := range []string{"", "nodelimiter", "$argon2id$v=19$m=1024$salt$hash", "$scrypt$ln=10,r=8,p=1$!!$!!"} {
+			match, err := comparePasswords(encoded, "avalidpassword")
+
+			assert.Error(t, err, encoded)
+			assert.False(t, match)
+		}
+	})
+}
+
+func TestNeedsRehash(t *testing.T) {
+	current := &Argon2idHasher{Memory: 1024, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+	t.Run("Same algorithm and parameters", func(t *testing.T) {
+		encoded, _ := current.Hash("avalidpassword")
+
+		assert.False(t, needsRehash(current, encoded))
+	})
+
+	t.Run("Outdated parameters", func(t *testing.T) {
+		encoded, _ := (&Argon2idHasher{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}).Hash("avalidpassword")
+
+		assert.True(t, needsRehash(current, encoded))
+	})
+
+	t.Run("Other algorithm", func(t *testing.T) {
+		encoded, _ := (&BcryptHasher{Cost: 4}).Hash("avalidpassword")
+
+		assert.True(t, needsRehash(current, encoded))
+		assert.False(t, needsRehash(&BcryptHasher{Cost: 4}, encoded))
+		assert.True(t, needsRehash(&BcryptHasher{Cost: 5}, encoded))
+	})
+
+	t.Run("Legacy hash.salt format", func(t *testing.T) {
+		assert.True(t, needsRehash(current, "9482d21332d4a75e725fd133eb76d24d9bc698487917bf60d1e99f40c44bc867.00112233445566778899aabbccddeeff00112233445566778899aabbccddeeff"))
+	})
+}
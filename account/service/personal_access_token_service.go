@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// PersonalAccessTokenService acts as a struct for injecting implementations of PersonalAccessTokenRepository
+// and AuditRepository for use in service methods
+type PersonalAccessTokenService struct {
+	PersonalAccessTokenRepository model.PersonalAccessTokenRepository
+	AuditRepository               model.AuditRepository
+}
+
+// PATSConfig will hold repositories that will eventually be injected into this
+// service layer
+type PATSConfig struct {
+	PersonalAccessTokenRepository model.PersonalAccessTokenRepository
+	// AuditRepository records the tokens users create and revoke, which are not recorded when nil
+	AuditRepository model.AuditRepository
+}
+
+// NewPersonalAccessTokenService is a factory function for
+// initializing a PersonalAccessTokenService with its repository layer dependencies
+func NewPersonalAccessTokenService(c *PATSConfig) model.PersonalAccessTokenService {
+	return &PersonalAccessTokenService{
+		PersonalAccessTokenRepository: c.PersonalAccessTokenRepository,
+		AuditRepository:               c.AuditRepository,
+	}
+}
+
+// Create creates a personal access token for the user, limited to scopes among the permissions
+// they hold. It returns the token, which is not stored and cannot be retrieved afterwards
+func (s *PersonalAccessTokenService) Create(ctx context.Context, u *model.User, t *model.PersonalAccessToken) (string, error) {
+	for _, scope := range t.Scopes {
+		if !u.Permissions.Contains(scope) {
+			return "", apperrors.NewForbidden(fmt.Sprintf("Missing permission: %v", scope))
+		}
+	}
+
+	if t.ExpiresAt != nil && t.Expired(time.Now()) {
+		return "", apperrors.NewBadRequest("Expiry must be in the future")
+	}
+
+	secret, _, err := generateVerificationToken()
+	if err != nil {
+		log.Printf("Could not generate personal access token for uid: %v. Reason: %v\n", u.UID, err)
+		return "", apperrors.NewInternal()
+	}
+
+	token := model.PersonalAccessTokenPrefix + secret
+
+	t.UID = u.UID
+	t.Prefix = token[:model.PersonalAccessTokenPrefixLength]
+	t.TokenHash = hashVerificationToken(token)
+
+	if t.Scopes == nil {
+		t.Scopes = model.Names{}
+	}
+
+	if err := s.PersonalAccessTokenRepository.Create(ctx, t); err != nil {
+		return "", err
+	}
+
+	recordAudit(ctx, s.AuditRepository, model.AuditUserTokenCreated, &u.UID, &u.UID, t.ID.String()+" "+t.Prefix)
+
+	return token, nil
+}
+
+// List retrieves the personal access tokens of a user
+func (s *PersonalAccessTokenService) List(ctx context.Context, uid uuid.UUID) ([]*model.PersonalAccessToken, error) {
+	return s.PersonalAccessTokenRepository.FindByUser(ctx, uid)
+}
+
+// Revoke deletes a personal access token of a user, which stops being accepted immediately
+func (s *PersonalAccessTokenService) Revoke(ctx context.Context, uid uuid.UUID, id uuid.UUID) error {
+	if err := s.PersonalAccessTokenRepository.Delete(ctx, uid, id); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.AuditRepository, model.AuditUserTokenRevoked, &uid, &uid, id.String())
+
+	return nil
+}
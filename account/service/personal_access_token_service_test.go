@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
+)
+
+func TestCreatePersonalAccessToken(t *testing.T) {
+	u := &model.User{
+		UID:         uuid.New(),
+		Permissions: model.Names{"articles.read", "articles.write"},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockPersonalAccessTokenRepository := new(mocks.MockPersonalAccessTokenRepository)
+		mockAuditRepository := new(mocks.MockAuditRepository)
+		ps := NewPersonalAccessTokenService(&PATSConfig{
+			PersonalAccessTokenRepository: mockPersonalAccessTokenRepository,
+			AuditRepository:               mockAuditRepository,
+		})
+
+		var stored *model.PersonalAccessToken
+		mockPersonalAccessTokenRepository.On("Create", mock.Anything, mock.AnythingOfType("*model.PersonalAccessToken")).
+			Run(func(args mock.Arguments) {
+				stored = args.Get(1).(*model.PersonalAccessToken)
+			}).
+			Return(nil)
+		mockAuditRepository.On("Create", mock.Anything, mock.MatchedBy(func(e *model.AuditEvent) bool {
+			return e.Type == model.AuditUserTokenCreated && *e.Actor == u.UID && *e.Target == u.UID
+		})).Return(nil)
+
+		pat := &model.PersonalAccessToken{
+			Name:   "deploy script",
+			Scopes: model.Names{"articles.read"},
+		}
+
+		token, err := ps.Create(context.TODO(), u, pat)
+
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(token, model.PersonalAccessTokenPrefix))
+		assert.Equal(t, u.UID, stored.UID)
+		assert.Equal(t, token[:model.PersonalAccessTokenPrefixLength], stored.Prefix)
+		assert.Equal(t, hashVerificationToken(token), stored.TokenHash)
+		assert.NotContains(t, stored.TokenHash, token)
+		mockAuditRepository.AssertExpectations(t)
+	})
+
+	t.Run("Scope the user does not hold", func(t *testing.T) {
+		mockPersonalAccessTokenRepository := new(mocks.MockPersonalAccessTokenRepository)
+		ps := NewPersonalAccessTokenService(&PATSConfig{
+			PersonalAccessTokenRepository: mockPersonalAccessTokenRepository,
+		})
+
+		_, err := ps.Create(context.TODO(), u, &model.PersonalAccessToken{
+			Name:   "deploy script",
+			Scopes: model.Names{"articles.read", "account.roles.write"},
+		})
+
+		assert.Equal(t, apperrors.Forbidden, err.(*apperrors.Error).Type)
+		mockPersonalAccessTokenRepository.AssertNotCalled(t, "Create")
+	})
+
+	t.Run("Expiry in the past", func(t *testing.T) {
+		mockPersonalAccessTokenRepository := new(mocks.MockPersonalAccessTokenRepository)
+		ps := NewPersonalAccessTokenService(&PATSConfig{
+			PersonalAccessTokenRepository: mockPersonalAccessTokenRepository,
+		})
+
+		expiresAt := time.Now().Add(-time.Minute)
+
+		_, err := ps.Create(context.TODO(), u, &model.PersonalAccessToken{
+			Name:      "deploy script",
+			ExpiresAt: &expiresAt,
+		})
+
+		assert.Equal(t, apperrors.BadRequest, err.(*apperrors.Error).Type)
+		mockPersonalAccessTokenRepository.AssertNotCalled(t, "Create")
+	})
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"github.com/weslleyrsr/auth-engine/account/policy"
+)
+
+// PolicyService acts as a struct for injecting implementations of PolicyRepository
+// and AuditRepository for use in service methods
+type PolicyService struct {
+	PolicyRepository model.PolicyRepository
+	AuditRepository  model.AuditRepository
+
+	// programs caches compiled policies by id, versions of policies never changing once published
+	programs sync.Map
+}
+
+// PSConfig will hold repositories that will eventually be injected into this
+// service layer
+type PSConfig struct {
+	PolicyRepository model.PolicyRepository
+	// AuditRepository records the policies admins publish, restore and delete, which are not recorded when nil
+	AuditRepository model.AuditRepository
+}
+
+// NewPolicyService is a factory function for
+// initializing a PolicyService with its repository layer dependencies
+func NewPolicyService(c *PSConfig) model.PolicyService {
+	return &PolicyService{
+		PolicyRepository: c.PolicyRepository,
+		AuditRepository:  c.AuditRepository,
+	}
+}
+
+// List retrieves the policies of the application, in their enforced version
+func (s *PolicyService) List(ctx context.Context) ([]*model.Policy, error) {
+	return s.PolicyRepository.FindActive(ctx)
+}
+
+// Versions retrieves every version of a policy, the latest first
+func (s *PolicyService) Versions(ctx context.Context, name string) ([]*model.Policy, error) {
+	return s.PolicyRepository.FindVersions(ctx, name)
+}
+
+// Publish checks a policy compiles and stores it as the latest version of the policy of its name
+func (s *PolicyService) Publish(ctx context.Context, actor uuid.UUID, p *model.Policy) error {
+	if _, err := compilePolicy(p); err != nil {
+		return err
+	}
+
+	if err := s.PolicyRepository.Create(ctx, p); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.AuditRepository, model.AuditPolicyPublished, &actor, nil, fmt.Sprintf("%s v%d", p.Name, p.Version))
+
+	return nil
+}
+
+// Restore publishes a previous version of a policy again, as its latest version
+func (s *PolicyService) Restore(ctx context.Context, actor uuid.UUID, name string, version int) (*model.Policy, error) {
+	prev, err := s.PolicyRepository.FindVersion(ctx, name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &model.Policy{
+		Name:        prev.Name,
+		Description: prev.Description,
+		Effect:      prev.Effect,
+		Actions:     prev.Actions,
+		Expression:  prev.Expression,
+	}
+
+	if err := s.PolicyRepository.Create(ctx, p); err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, s.AuditRepository, model.AuditPolicyRestored, &actor, nil, fmt.Sprintf("%s v%d as v%d", p.Name, version, p.Version))
+
+	return p, nil
+}
+
+// Delete deletes a policy along with its versions
+func (s *PolicyService) Delete(ctx context.Context, actor uuid.UUID, name string) error {
+	if err := s.PolicyRepository.Delete(ctx, name); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.AuditRepository, model.AuditPolicyDeleted, &actor, nil, name)
+
+	return nil
+}
+
+// Authorize decides a request against the policies of the application
+func (s *PolicyService) Authorize(ctx context.Context, req *model.AuthorizationRequest) (*model.AuthorizationDecision, error) {
+	policies, err := s.PolicyRepository.FindActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	programs, err := s.compile(policies)
+	if err != nil {
+		return nil, err
+	}
+
+	return policy.Evaluate(ctx, programs, req, time.Now()), nil
+}
+
+// DryRun decides a request as Authorize would at a point in time, with candidate enforced in place of
+// the policy of its name. Nothing is stored. An unpublished candidate is reported as version 0
+func (s *PolicyService) DryRun(ctx context.Context, candidate *model.Policy, req *model.AuthorizationRequest, at time.Time) (*model.AuthorizationDecision, error) {
+	policies, err := s.PolicyRepository.FindActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	programs, err := s.compile(policies)
+	if err != nil {
+		return nil, err
+	}
+
+	if candidate != nil {
+		prg, err := compilePolicy(candidate)
+		if err != nil {
+			return nil, err
+		}
+
+		replaced := programs[:0]
+		for _, p := range programs {
+			if p.Policy.Name != candidate.Name {
+				replaced = append(replaced, p)
+			}
+		}
+
+		programs = append(replaced, prg)
+	}
+
+	return policy.Evaluate(ctx, programs, req, at), nil
+}
+
+// compile returns the programs of stored policies, compiling those not cached yet
+func (s *PolicyService) compile(policies []*model.Policy) ([]*policy.Program, error) {
+	programs := make([]*policy.Program, 0, len(policies))
+
+	for _, p := range policies {
+		if prg, ok := s.programs.Load(p.ID); ok {
+			programs = append(programs, prg.(*policy.Program))
+			continue
+		}
+
+		prg, err := policy.Compile(p)
+		if err != nil {
+			// policies are compiled before being stored. Failing closed, as skipping a
+			// deny policy could allow requests it was published to deny
+			log.Printf("Could not compile policy: %v@%v. Reason: %v\n", p.Name, p.Version, err)
+			return nil, apperrors.NewInternal()
+		}
+
+		s.programs.Store(p.ID, prg)
+		programs = append(programs, prg)
+	}
+
+	return programs, nil
+}
+
+// compilePolicy validates a policy about to be published or dry run
+func compilePolicy(p *model.Policy) (*policy.Program, error) {
+	if p.Effect != model.PolicyAllow && p.Effect != model.PolicyDeny {
+		return nil, apperrors.NewBadRequest(fmt.Sprintf("Invalid effect: %v", p.Effect))
+	}
+
+	prg, err := policy.Compile(p)
+	if err != nil {
+		return nil, apperrors.NewBadRequest(fmt.Sprintf("Invalid expression: %v", err))
+	}
+
+	return prg, nil
+}
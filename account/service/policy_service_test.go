@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
+)
+
+func TestPublishPolicy(t *testing.T) {
+	actor := uuid.New()
+
+	t.Run("Success", func(t *testing.T) {
+		mockPolicyRepository := new(mocks.MockPolicyRepository)
+		mockAuditRepository := new(mocks.MockAuditRepository)
+		ps := NewPolicyService(&PSConfig{
+			PolicyRepository: mockPolicyRepository,
+			AuditRepository:  mockAuditRepository,
+		})
+
+		p := &model.Policy{
+			Name:       "editors-edit",
+			Effect:     model.PolicyAllow,
+			Actions:    model.Names{"documents.edit"},
+			Expression: `"editor" in subject.roles`,
+		}
+		mockPolicyRepository.On("Create", mock.Anything, p).
+			Run(func(args mock.Arguments) {
+				args.Get(1).(*model.Policy).Version = 2
+			}).
+			Return(nil)
+		mockAuditRepository.On("Create", mock.Anything, &model.AuditEvent{
+			Type:   model.AuditPolicyPublished,
+			Actor:  &actor,
+			Detail: "editors-edit v2",
+		}).Return(nil)
+
+		err := ps.Publish(context.TODO(), actor, p)
+
+		assert.NoError(t, err)
+		mockPolicyRepository.AssertExpectations(t)
+		mockAuditRepository.AssertExpectations(t)
+	})
+
+	t.Run("Invalid expression", func(t *testing.T) {
+		mockPolicyRepository := new(mocks.MockPolicyRepository)
+		ps := NewPolicyService(&PSConfig{PolicyRepository: mockPolicyRepository})
+
+		err := ps.Publish(context.TODO(), actor, &model.Policy{
+			Name:       "editors-edit",
+			Effect:     model.PolicyAllow,
+			Actions:    model.Names{"documents.edit"},
+			Expression: `subject.roles.size()`,
+		})
+
+		assert.Equal(t, apperrors.BadRequest, err.(*apperrors.Error).Type)
+		mockPolicyRepository.AssertNotCalled(t, "Create")
+	})
+}
+
+func TestRestorePolicy(t *testing.T) {
+	mockPolicyRepository := new(mocks.MockPolicyRepository)
+	ps := NewPolicyService(&PSConfig{PolicyRepository: mockPolicyRepository})
+
+	prev := &model.Policy{
+		ID:         uuid.New(),
+		Name:       "editors-edit",
+		Version:    1,
+		Effect:     model.PolicyAllow,
+		Actions:    model.Names{"documents.edit"},
+		Expression: `"editor" in subject.roles`,
+	}
+	mockPolicyRepository.On("FindVersion", mock.Anything, "editors-edit", 1).Return(prev, nil)
+	mockPolicyRepository.
+		On("Create", mock.Anything, mock.MatchedBy(func(p *model.Policy) bool {
+			return p.ID == uuid.Nil && p.Name == prev.Name && p.Expression == prev.Expression
+		})).
+		Return(nil)
+
+	p, err := ps.Restore(context.TODO(), uuid.New(), "editors-edit", 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, prev.Actions, p.Actions)
+	mockPolicyRepository.AssertExpectations(t)
+}
+
+func TestAuthorize(t *testing.T) {
+	editorsEdit := &model.Policy{
+		ID:         uuid.New(),
+		Name:       "editors-edit",
+		Version:    3,
+		Effect:     model.PolicyAllow,
+		Actions:    model.Names{"documents.edit"},
+		Expression: `"editor" in subject.roles`,
+	}
+	lockedDocuments := &model.Policy{
+		ID:         uuid.New(),
+		Name:       "locked-documents",
+		Version:    1,
+		Effect:     model.PolicyDeny,
+		Actions:    model.Names{"*"},
+		Expression: `resource.locked == true`,
+	}
+
+	editor := model.Attributes{"roles": []interface{}{"editor"}}
+
+	setup := func() model.PolicyService {
+		mockPolicyRepository := new(mocks.MockPolicyRepository)
+		mockPolicyRepository.On("FindActive", mock.Anything).Return([]*model.Policy{editorsEdit, lockedDocuments}, nil)
+
+		return NewPolicyService(&PSConfig{PolicyRepository: mockPolicyRepository})
+	}
+
+	t.Run("Allowed", func(t *testing.T) {
+		ps := setup()
+
+		d, err := ps.Authorize(context.TODO(), &model.AuthorizationRequest{
+			Subject:  editor,
+			Action:   "documents.edit",
+			Resource: model.Attributes{"locked": false},
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, d.Allowed)
+		assert.Equal(t, []*model.PolicyResult{{Name: "editors-edit", Version: 3, Effect: model.PolicyAllow}}, d.Matched)
+	})
+
+	t.Run("Compiled policies are reused", func(t *testing.T) {
+		ps := setup()
+
+		req := &model.AuthorizationRequest{
+			Subject:  editor,
+			Action:   "documents.edit",
+			Resource: model.Attributes{"locked": true},
+		}
+
+		for i := 0; i < 2; i++ {
+			d, err := ps.Authorize(context.TODO(), req)
+
+			assert.NoError(t, err)
+			assert.False(t, d.Allowed)
+		}
+
+		_, cached := ps.(*PolicyService).programs.Load(editorsEdit.ID)
+		assert.True(t, cached)
+	})
+
+	t.Run("Dry run of a candidate replacing its published version", func(t *testing.T) {
+		ps := setup()
+
+		candidate := &model.Policy{
+			Name:       "locked-documents",
+			Effect:     model.PolicyDeny,
+			Actions:    model.Names{"*"},
+			Expression: `resource.locked == true && now.getHours("UTC") >= 18`,
+		}
+
+		req := &model.AuthorizationRequest{
+			Subject:  editor,
+			Action:   "documents.edit",
+			Resource: model.Attributes{"locked": true},
+		}
+
+		d, err := ps.DryRun(context.TODO(), candidate, req, time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC))
+
+		assert.NoError(t, err)
+		assert.True(t, d.Allowed)
+
+		d, err = ps.DryRun(context.TODO(), candidate, req, time.Date(2024, 5, 6, 20, 0, 0, 0, time.UTC))
+
+		assert.NoError(t, err)
+		assert.False(t, d.Allowed)
+		assert.Equal(t, &model.PolicyResult{Name: "locked-documents", Version: 0, Effect: model.PolicyDeny}, d.Matched[1])
+	})
+
+	t.Run("Dry run of an invalid candidate", func(t *testing.T) {
+		ps := setup()
+
+		_, err := ps.DryRun(context.TODO(), &model.Policy{Name: "broken", Effect: "maybe", Expression: "true"},
+			&model.AuthorizationRequest{Action: "documents.edit"}, time.Now())
+
+		assert.Equal(t, apperrors.BadRequest, err.(*apperrors.Error).Type)
+	})
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/weslleyrsr/auth-engine/account/model"
+)
+
+// RoleService acts as a struct for injecting implementations of RoleRepository
+// and AuditRepository for use in service methods
+type RoleService struct {
+	RoleRepository  model.RoleRepository
+	AuditRepository model.AuditRepository
+}
+
+// RSConfig will hold repositories that will eventually be injected into this
+// service layer
+type RSConfig struct {
+	RoleRepository model.RoleRepository
+	// AuditRepository records the changes admins make to roles and permissions and the roles they assign
+	// to users, which are not recorded when nil
+	AuditRepository model.AuditRepository
+}
+
+// NewRoleService is a factory function for
+// initializing a RoleService with its repository layer dependencies
+func NewRoleService(c *RSConfig) model.RoleService {
+	return &RoleService{
+		RoleRepository:  c.RoleRepository,
+		AuditRepository: c.AuditRepository,
+	}
+}
+
+// ListRoles retrieves the roles of the application along with their permissions
+func (s *RoleService) ListRoles(ctx context.Context) ([]*model.Role, error) {
+	return s.RoleRepository.FindRoles(ctx)
+}
+
+// CreateRole creates a role without permissions, which are granted afterwards
+func (s *RoleService) CreateRole(ctx context.Context, actor uuid.UUID, r *model.Role) error {
+	if err := s.RoleRepository.CreateRole(ctx, r); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.AuditRepository, model.AuditRoleCreated, &actor, nil, r.ID.String()+" "+r.Name)
+
+	return nil
+}
+
+// DeleteRole deletes a role. Users it was assigned to keep it in their id tokens until they expire
+func (s *RoleService) DeleteRole(ctx context.Context, actor uuid.UUID, id uuid.UUID) error {
+	if err := s.RoleRepository.DeleteRole(ctx, id); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.AuditRepository, model.AuditRoleDeleted, &actor, nil, id.String())
+
+	return nil
+}
+
+// ListPermissions retrieves the permissions of the application
+func (s *RoleService) ListPermissions(ctx context.Context) ([]*model.Permission, error) {
+	return s.RoleRepository.FindPermissions(ctx)
+}
+
+// CreatePermission creates a permission
+func (s *RoleService) CreatePermission(ctx context.Context, actor uuid.UUID, p *model.Permission) error {
+	if err := s.RoleRepository.CreatePermission(ctx, p); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.AuditRepository, model.AuditPermissionCreated, &actor, nil, p.ID.String()+" "+p.Name)
+
+	return nil
+}
+
+// DeletePermission deletes a permission, revoking it from every role
+func (s *RoleService) DeletePermission(ctx context.Context, actor uuid.UUID, id uuid.UUID) error {
+	if err := s.RoleRepository.DeletePermission(ctx, id); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.AuditRepository, model.AuditPermissionDeleted, &actor, nil, id.String())
+
+	return nil
+}
+
+// GrantPermission adds a permission to a role
+func (s *RoleService) GrantPermission(ctx context.Context, actor uuid.UUID, roleID uuid.UUID, permissionID uuid.UUID) error {
+	if err := s.RoleRepository.GrantPermission(ctx, roleID, permissionID); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.AuditRepository, model.AuditRolePermissionGranted, &actor, nil, roleID.String()+" "+permissionID.String())
+
+	return nil
+}
+
+// RevokePermission removes a permission from a role
+func (s *RoleService) RevokePermission(ctx context.Context, actor uuid.UUID, roleID uuid.UUID, permissionID uuid.UUID) error {
+	if err := s.RoleRepository.RevokePermission(ctx, roleID, permissionID); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.AuditRepository, model.AuditRolePermissionRevoked, &actor, nil, roleID.String()+" "+permissionID.String())
+
+	return nil
+}
+
+// UserRoles retrieves the roles assigned to a user
+func (s *RoleService) UserRoles(ctx context.Context, uid uuid.UUID) ([]*model.Role, error) {
+	return s.RoleRepository.FindUserRoles(ctx, uid)
+}
+
+// AssignRole assigns a role to a user, taking effect in the next id token issued to the user
+func (s *RoleService) AssignRole(ctx context.Context, actor uuid.UUID, uid uuid.UUID, roleID uuid.UUID) error {
+	if err := s.RoleRepository.AssignRole(ctx, uid, roleID); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.AuditRepository, model.AuditUserRoleAssigned, &actor, &uid, roleID.String())
+
+	return nil
+}
+
+// UnassignRole removes a role from a user, taking effect in the next id token issued to the user
+func (s *RoleService) UnassignRole(ctx context.Context, actor uuid.UUID, uid uuid.UUID, roleID uuid.UUID) error {
+	if err := s.RoleRepository.UnassignRole(ctx, uid, roleID); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.AuditRepository, model.AuditUserRoleUnassigned, &actor, &uid, roleID.String())
+
+	return nil
+}
+
+// accessOf returns the sorted names of roles and of the permissions they grant, without duplicates
+func accessOf(roles []*model.Role) (model.Names, model.Names) {
+	roleNames := make(model.Names, 0, len(roles))
+	granted := make(map[string]bool)
+
+	for _, r := range roles {
+		roleNames = append(roleNames, r.Name)
+
+		for _, p := range r.Permissions {
+			granted[p] = true
+		}
+	}
+
+	permissions := make(model.Names, 0, len(granted))
+	for p := range granted {
+		permissions = append(permissions, p)
+	}
+
+	sort.Strings(roleNames)
+	sort.Strings(permissions)
+
+	return roleNames, permissions
+}
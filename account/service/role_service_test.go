@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
+)
+
+func TestRoleChangesAudited(t *testing.T) {
+	actor := uuid.New()
+	roleID := uuid.New()
+	permissionID := uuid.New()
+
+	t.Run("Create role", func(t *testing.T) {
+		mockRoleRepository := new(mocks.MockRoleRepository)
+		mockAuditRepository := new(mocks.MockAuditRepository)
+		rs := NewRoleService(&RSConfig{RoleRepository: mockRoleRepository, AuditRepository: mockAuditRepository})
+
+		r := &model.Role{Name: "editor"}
+		mockRoleRepository.On("CreateRole", mock.Anything, r).
+			Run(func(args mock.Arguments) {
+				args.Get(1).(*model.Role).ID = roleID
+			}).
+			Return(nil)
+		mockAuditRepository.On("Create", mock.Anything, &model.AuditEvent{
+			Type:   model.AuditRoleCreated,
+			Actor:  &actor,
+			Detail: roleID.String() + " editor",
+		}).Return(nil)
+
+		err := rs.CreateRole(context.TODO(), actor, r)
+
+		assert.NoError(t, err)
+		mockAuditRepository.AssertExpectations(t)
+	})
+
+	t.Run("Grant permission", func(t *testing.T) {
+		mockRoleRepository := new(mocks.MockRoleRepository)
+		mockAuditRepository := new(mocks.MockAuditRepository)
+		rs := NewRoleService(&RSConfig{RoleRepository: mockRoleRepository, AuditRepository: mockAuditRepository})
+
+		mockRoleRepository.On("GrantPermission", mock.Anything, roleID, permissionID).Return(nil)
+		mockAuditRepository.On("Create", mock.Anything, &model.AuditEvent{
+			Type:   model.AuditRolePermissionGranted,
+			Actor:  &actor,
+			Detail: roleID.String() + " " + permissionID.String(),
+		}).Return(nil)
+
+		err := rs.GrantPermission(context.TODO(), actor, roleID, permissionID)
+
+		assert.NoError(t, err)
+		mockAuditRepository.AssertExpectations(t)
+	})
+
+	t.Run("Failed change not recorded", func(t *testing.T) {
+		mockRoleRepository := new(mocks.MockRoleRepository)
+		mockAuditRepository := new(mocks.MockAuditRepository)
+		rs := NewRoleService(&RSConfig{RoleRepository: mockRoleRepository, AuditRepository: mockAuditRepository})
+
+		mockRoleRepository.On("DeleteRole", mock.Anything, roleID).Return(apperrors.NewNotFound("role", roleID.String()))
+
+		err := rs.DeleteRole(context.TODO(), actor, roleID)
+
+		assert.Equal(t, apperrors.NotFound, err.(*apperrors.Error).Type)
+		mockAuditRepository.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,326 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+)
+
+// maxAssertionLifetime is how far in the future assertions may expire, bounding how long the
+// identifiers of used assertions must be remembered
+const maxAssertionLifetime = time.Hour
+
+// assertionLeeway tolerates clock skew between service accounts and the engine
+const assertionLeeway = 30 * time.Second
+
+// serviceAccountActivityLimit is how many of the latest events of a service account are listed
+const serviceAccountActivityLimit = 100
+
+// ServiceAccountService acts as a struct for injecting implementations of ServiceAccountRepository
+// and AuditRepository for use in service methods
+type ServiceAccountService struct {
+	ServiceAccountRepository model.ServiceAccountRepository
+	AuditRepository          model.AuditRepository
+	Audience                 string
+}
+
+// SASConfig will hold repositories that will eventually be injected into this
+// service layer
+type SASConfig struct {
+	ServiceAccountRepository model.ServiceAccountRepository
+	// AuditRepository records the events of service accounts in the audit log of the application as well,
+	// where they are covered by its hash chain. They are only kept as activity of the accounts when nil
+	AuditRepository model.AuditRepository
+	// Audience identifies the engine in the aud claim of assertions, eg the url of its token endpoint.
+	// Assertions are rejected when empty
+	Audience string
+}
+
+// NewServiceAccountService is a factory function for
+// initializing a ServiceAccountService with its repository layer dependencies
+func NewServiceAccountService(c *SASConfig) model.ServiceAccountService {
+	return &ServiceAccountService{
+		ServiceAccountRepository: c.ServiceAccountRepository,
+		AuditRepository:          c.AuditRepository,
+		Audience:                 c.Audience,
+	}
+}
+
+// List retrieves the service accounts of an organization, or of the whole application when orgID is uuid.Nil
+func (s *ServiceAccountService) List(ctx context.Context, orgID uuid.UUID) ([]*model.ServiceAccount, error) {
+	return s.ServiceAccountRepository.FindAll(ctx, orgID)
+}
+
+// Create creates a service account with scopes among the permissions its creator holds
+func (s *ServiceAccountService) Create(ctx context.Context, creator *model.User, sa *model.ServiceAccount) error {
+	for _, scope := range sa.Scopes {
+		if !creator.Permissions.Contains(scope) {
+			return apperrors.NewForbidden(fmt.Sprintf("Missing permission: %v", scope))
+		}
+	}
+
+	if _, err := parsePublicKey(sa.PublicKey); err != nil {
+		return apperrors.NewBadRequest(fmt.Sprintf("Invalid public key: %v", err))
+	}
+
+	if sa.Scopes == nil {
+		sa.Scopes = model.Names{}
+	}
+
+	sa.CreatedBy = creator.UID
+
+	if err := s.ServiceAccountRepository.Create(ctx, sa); err != nil {
+		return err
+	}
+
+	s.record(ctx, sa.ID, model.ServiceAccountCreated, &creator.UID, "", "")
+
+	return nil
+}
+
+// RotateKey replaces the public key of a service account, assertions signed with the previous key
+// being rejected from then on
+func (s *ServiceAccountService) RotateKey(ctx context.Context, actor uuid.UUID, orgID uuid.UUID, id uuid.UUID, publicKey string) (*model.ServiceAccount, error) {
+	if _, err := s.find(ctx, orgID, id); err != nil {
+		return nil, err
+	}
+
+	if _, err := parsePublicKey(publicKey); err != nil {
+		return nil, apperrors.NewBadRequest(fmt.Sprintf("Invalid public key: %v", err))
+	}
+
+	sa, err := s.ServiceAccountRepository.UpdateKey(ctx, id, publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	s.record(ctx, id, model.ServiceAccountKeyRotated, &actor, "", "")
+
+	return sa, nil
+}
+
+// Delete deletes a service account. Access tokens already issued to it remain valid until they expire
+func (s *ServiceAccountService) Delete(ctx context.Context, actor uuid.UUID, orgID uuid.UUID, id uuid.UUID) error {
+	if _, err := s.find(ctx, orgID, id); err != nil {
+		return err
+	}
+
+	if err := s.ServiceAccountRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.record(ctx, id, model.ServiceAccountDeleted, &actor, "", "")
+
+	return nil
+}
+
+// Activity retrieves the latest events of the audit trail of a service account
+func (s *ServiceAccountService) Activity(ctx context.Context, orgID uuid.UUID, id uuid.UUID) ([]*model.ServiceAccountEvent, error) {
+	if _, err := s.find(ctx, orgID, id); err != nil {
+		return nil, err
+	}
+
+	return s.ServiceAccountRepository.FindEvents(ctx, id, serviceAccountActivityLimit)
+}
+
+// ExchangePolicy retrieves the token exchange policy of a service account
+func (s *ServiceAccountService) ExchangePolicy(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*model.TokenExchangePolicy, error) {
+	if _, err := s.find(ctx, orgID, id); err != nil {
+		return nil, err
+	}
+
+	return s.ServiceAccountRepository.FindExchangePolicy(ctx, id)
+}
+
+// SetExchangePolicy creates or replaces the token exchange policy of a service account,
+// allowing it to exchange the access tokens of users for tokens of the audiences of the policy
+func (s *ServiceAccountService) SetExchangePolicy(ctx context.Context, actor uuid.UUID, orgID uuid.UUID, p *model.TokenExchangePolicy) error {
+	if _, err := s.find(ctx, orgID, p.ServiceAccountID); err != nil {
+		return err
+	}
+
+	if p.MaxExpiresIn < 0 {
+		return apperrors.NewBadRequest("maxExpiresIn cannot be negative")
+	}
+
+	if p.Audiences == nil {
+		p.Audiences = model.Names{}
+	}
+
+	if p.Scopes == nil {
+		p.Scopes = model.Names{}
+	}
+
+	if err := s.ServiceAccountRepository.SetExchangePolicy(ctx, p); err != nil {
+		return err
+	}
+
+	s.record(ctx, p.ServiceAccountID, model.ServiceAccountExchangePolicyUpdated, &actor, "", strings.Join(p.Audiences, " "))
+
+	return nil
+}
+
+// DeleteExchangePolicy deletes the token exchange policy of a service account, which can no longer exchange tokens.
+// Tokens it already obtained remain valid until they expire
+func (s *ServiceAccountService) DeleteExchangePolicy(ctx context.Context, actor uuid.UUID, orgID uuid.UUID, id uuid.UUID) error {
+	if _, err := s.find(ctx, orgID, id); err != nil {
+		return err
+	}
+
+	if err := s.ServiceAccountRepository.DeleteExchangePolicy(ctx, id); err != nil {
+		return err
+	}
+
+	s.record(ctx, id, model.ServiceAccountExchangePolicyDeleted, &actor, "", "")
+
+	return nil
+}
+
+// Authenticate verifies a JWT assertion of a service account, as of the jwt-bearer grant of RFC 7523.
+// The assertion is issued by and about the service account, its iss and sub claims being the id of
+// the account, is addressed to the Audience of the engine, expires within maxAssertionLifetime and
+// has a jti claim which cannot be used twice. Both outcomes are recorded to the audit trail of the account.
+// Every assertion is rejected when no Audience is configured, the aud claim being required
+func (s *ServiceAccountService) Authenticate(ctx context.Context, assertion string, ip string) (*model.ServiceAccount, error) {
+	invalid := apperrors.NewAuthorization("Unable to verify assertion")
+
+	// an empty audience would let through assertions addressed to any party
+	if s.Audience == "" {
+		log.Printf("Unable to verify assertions of service accounts, no audience is configured\n")
+		return nil, invalid
+	}
+
+	unverified := &jwt.RegisteredClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(assertion, unverified); err != nil {
+		return nil, invalid
+	}
+
+	id, err := uuid.Parse(unverified.Issuer)
+	if err != nil {
+		return nil, invalid
+	}
+
+	sa, err := s.ServiceAccountRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, invalid
+	}
+
+	if err := s.verifyAssertion(ctx, sa, assertion); err != nil {
+		log.Printf("Rejected assertion of service account: %v. Reason: %v\n", sa.ID, err)
+		s.record(ctx, sa.ID, model.ServiceAccountRejected, nil, ip, err.Error())
+		return nil, invalid
+	}
+
+	s.record(ctx, sa.ID, model.ServiceAccountAuthenticated, nil, ip, "")
+
+	return sa, nil
+}
+
+// verifyAssertion checks the signature and claims of an assertion of a service account
+func (s *ServiceAccountService) verifyAssertion(ctx context.Context, sa *model.ServiceAccount, assertion string) error {
+	key, err := parsePublicKey(sa.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	methods := []string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodRS384.Alg(), jwt.SigningMethodRS512.Alg()}
+	if _, ok := key.(*ecdsa.PublicKey); ok {
+		methods = []string{jwt.SigningMethodES256.Alg(), jwt.SigningMethodES384.Alg(), jwt.SigningMethodES512.Alg()}
+	}
+
+	claims := &jwt.RegisteredClaims{}
+
+	_, err = jwt.ParseWithClaims(assertion, claims, func(token *jwt.Token) (interface{}, error) {
+		return key, nil
+	},
+		jwt.WithValidMethods(methods),
+		jwt.WithIssuer(sa.ID.String()),
+		jwt.WithSubject(sa.ID.String()),
+		jwt.WithAudience(s.Audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(assertionLeeway),
+	)
+	if err != nil {
+		return err
+	}
+
+	if time.Until(claims.ExpiresAt.Time) > maxAssertionLifetime {
+		return fmt.Errorf("assertion expires in more than %v", maxAssertionLifetime)
+	}
+
+	if claims.ID == "" {
+		return errors.New("assertion has no jti")
+	}
+
+	if err := s.ServiceAccountRepository.UseAssertion(ctx, sa.ID, claims.ID, claims.ExpiresAt.Time); err != nil {
+		var e *apperrors.Error
+		if errors.As(err, &e) && e.Type == apperrors.Conflict {
+			return fmt.Errorf("assertion %v was already used", claims.ID)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// find returns a service account of the organization, of any organization when orgID is uuid.Nil
+func (s *ServiceAccountService) find(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*model.ServiceAccount, error) {
+	sa, err := s.ServiceAccountRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if orgID != uuid.Nil && (sa.OrgID == nil || *sa.OrgID != orgID) {
+		return nil, apperrors.NewNotFound("service account", id.String())
+	}
+
+	return sa, nil
+}
+
+// record appends an event to the audit trail of a service account, and to the audit log of the
+// application with the service account as its target. Failing to do so is logged by the repositories
+// rather than failing the operation it is about
+func (s *ServiceAccountService) record(ctx context.Context, id uuid.UUID, t model.ServiceAccountEventType, actor *uuid.UUID, ip string, detail string) {
+	_ = s.ServiceAccountRepository.AddEvent(ctx, &model.ServiceAccountEvent{
+		ServiceAccountID: id,
+		Type:             t,
+		Actor:            actor,
+		IP:               ip,
+		Detail:           detail,
+	})
+
+	recordAudit(ctx, s.AuditRepository, model.AuditServiceAccountEvent(t), actor, &id, detail)
+}
+
+// parsePublicKey parses a PEM encoded RSA or EC public key
+func parsePublicKey(s string) (interface{}, error) {
+	block, _ := pem.Decode([]byte(s))
+	if block == nil {
+		return nil, errors.New("not PEM encoded")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch key.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+}
@@ -0,0 +1,350 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
+)
+
+const testAudience = "https://auth.test/oauth/token"
+
+// generateServiceAccountKey returns a new rsa key of a service account along with its PEM encoded public key
+func generateServiceAccountKey(t *testing.T) (*rsa.PrivateKey, string) {
+	t.Helper()
+
+	privKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(&privKey.PublicKey)
+	if err != nil {
+		t.Fatalf("failed to marshal public key: %v", err)
+	}
+
+	return privKey, string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func TestCreateServiceAccount(t *testing.T) {
+	_, publicKey := generateServiceAccountKey(t)
+
+	creator := &model.User{
+		UID:         uuid.New(),
+		Permissions: model.Names{"articles.read", "articles.write"},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockServiceAccountRepository := new(mocks.MockServiceAccountRepository)
+		sas := NewServiceAccountService(&SASConfig{ServiceAccountRepository: mockServiceAccountRepository})
+
+		sa := &model.ServiceAccount{
+			Name:      "billing sync",
+			PublicKey: publicKey,
+			Scopes:    model.Names{"articles.read"},
+		}
+
+		mockServiceAccountRepository.
+			On("Create", mock.Anything, sa).
+			Run(func(args mock.Arguments) {
+				args.Get(1).(*model.ServiceAccount).ID = uuid.New()
+			}).
+			Return(nil)
+		mockServiceAccountRepository.
+			On("AddEvent", mock.Anything, mock.MatchedBy(func(e *model.ServiceAccountEvent) bool {
+				return e.Type == model.ServiceAccountCreated && *e.Actor == creator.UID
+			})).
+			Return(nil)
+
+		err := sas.Create(context.TODO(), creator, sa)
+
+		assert.NoError(t, err)
+		assert.Equal(t, creator.UID, sa.CreatedBy)
+		mockServiceAccountRepository.AssertExpectations(t)
+	})
+
+	t.Run("Scope the creator does not hold", func(t *testing.T) {
+		mockServiceAccountRepository := new(mocks.MockServiceAccountRepository)
+		sas := NewServiceAccountService(&SASConfig{ServiceAccountRepository: mockServiceAccountRepository})
+
+		err := sas.Create(context.TODO(), creator, &model.ServiceAccount{
+			Name:      "billing sync",
+			PublicKey: publicKey,
+			Scopes:    model.Names{"account.roles.write"},
+		})
+
+		assert.Equal(t, apperrors.Forbidden, err.(*apperrors.Error).Type)
+		mockServiceAccountRepository.AssertNotCalled(t, "Create")
+	})
+
+	t.Run("Invalid public key", func(t *testing.T) {
+		mockServiceAccountRepository := new(mocks.MockServiceAccountRepository)
+		sas := NewServiceAccountService(&SASConfig{ServiceAccountRepository: mockServiceAccountRepository})
+
+		err := sas.Create(context.TODO(), creator, &model.ServiceAccount{
+			Name:      "billing sync",
+			PublicKey: "ssh-rsa AAAA",
+		})
+
+		assert.Equal(t, apperrors.BadRequest, err.(*apperrors.Error).Type)
+		mockServiceAccountRepository.AssertNotCalled(t, "Create")
+	})
+}
+
+func TestAuthenticateServiceAccount(t *testing.T) {
+	privKey, publicKey := generateServiceAccountKey(t)
+
+	sa := &model.ServiceAccount{
+		ID:        uuid.New(),
+		PublicKey: publicKey,
+		Scopes:    model.Names{"articles.read"},
+	}
+	ip := "10.0.0.1"
+
+	// claims returns valid claims of an assertion of the service account
+	claims := func() jwt.RegisteredClaims {
+		return jwt.RegisteredClaims{
+			Issuer:    sa.ID.String(),
+			Subject:   sa.ID.String(),
+			Audience:  jwt.ClaimStrings{testAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(5 * time.Minute)),
+			ID:        uuid.NewString(),
+		}
+	}
+
+	sign := func(c jwt.RegisteredClaims) string {
+		ss, _ := jwt.NewWithClaims(jwt.SigningMethodRS256, c).SignedString(privKey)
+		return ss
+	}
+
+	setup := func() (model.ServiceAccountService, *mocks.MockServiceAccountRepository) {
+		mockServiceAccountRepository := new(mocks.MockServiceAccountRepository)
+		mockServiceAccountRepository.On("FindByID", mock.Anything, sa.ID).Return(sa, nil)
+		mockServiceAccountRepository.On("AddEvent", mock.Anything, mock.AnythingOfType("*model.ServiceAccountEvent")).Return(nil)
+
+		sas := NewServiceAccountService(&SASConfig{
+			ServiceAccountRepository: mockServiceAccountRepository,
+			Audience:                 testAudience,
+		})
+
+		return sas, mockServiceAccountRepository
+	}
+
+	// recorded asserts the single event recorded to the audit trail is of type
+	recorded := func(t *testing.T, m *mocks.MockServiceAccountRepository, eventType model.ServiceAccountEventType) {
+		m.AssertCalled(t, "AddEvent", mock.Anything, mock.MatchedBy(func(e *model.ServiceAccountEvent) bool {
+			return e.ServiceAccountID == sa.ID && e.Type == eventType && e.IP == ip && e.Actor == nil
+		}))
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		sas, mockServiceAccountRepository := setup()
+
+		c := claims()
+		mockServiceAccountRepository.On("UseAssertion", mock.Anything, sa.ID, c.ID, c.ExpiresAt.Time).Return(nil)
+
+		authenticated, err := sas.Authenticate(context.TODO(), sign(c), ip)
+
+		assert.NoError(t, err)
+		assert.Equal(t, sa, authenticated)
+		recorded(t, mockServiceAccountRepository, model.ServiceAccountAuthenticated)
+	})
+
+	t.Run("Rejected without an audience", func(t *testing.T) {
+		mockServiceAccountRepository := new(mocks.MockServiceAccountRepository)
+		sas := NewServiceAccountService(&SASConfig{ServiceAccountRepository: mockServiceAccountRepository})
+
+		// the assertion is addressed to another party, which no audience would let through
+		c := claims()
+		c.Audience = jwt.ClaimStrings{"https://other.test"}
+
+		_, err := sas.Authenticate(context.TODO(), sign(c), ip)
+
+		assert.Equal(t, apperrors.Authorization, err.(*apperrors.Error).Type)
+		mockServiceAccountRepository.AssertNotCalled(t, "UseAssertion", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Recorded in the audit log", func(t *testing.T) {
+		mockServiceAccountRepository := new(mocks.MockServiceAccountRepository)
+		mockAuditRepository := new(mocks.MockAuditRepository)
+		sas := NewServiceAccountService(&SASConfig{
+			ServiceAccountRepository: mockServiceAccountRepository,
+			AuditRepository:          mockAuditRepository,
+			Audience:                 testAudience,
+		})
+
+		c := claims()
+		mockServiceAccountRepository.On("FindByID", mock.Anything, sa.ID).Return(sa, nil)
+		mockServiceAccountRepository.On("AddEvent", mock.Anything, mock.AnythingOfType("*model.ServiceAccountEvent")).Return(nil)
+		mockServiceAccountRepository.On("UseAssertion", mock.Anything, sa.ID, c.ID, c.ExpiresAt.Time).Return(nil)
+		mockAuditRepository.On("Create", mock.Anything, &model.AuditEvent{
+			Type:   "service_account.authenticated",
+			Target: &sa.ID,
+		}).Return(nil)
+
+		_, err := sas.Authenticate(context.TODO(), sign(c), ip)
+
+		assert.NoError(t, err)
+		mockAuditRepository.AssertExpectations(t)
+	})
+
+	t.Run("Replayed assertion", func(t *testing.T) {
+		sas, mockServiceAccountRepository := setup()
+
+		c := claims()
+		mockServiceAccountRepository.On("UseAssertion", mock.Anything, sa.ID, c.ID, c.ExpiresAt.Time).
+			Return(apperrors.NewConflict("assertion", c.ID))
+
+		_, err := sas.Authenticate(context.TODO(), sign(c), ip)
+
+		assert.Equal(t, apperrors.Authorization, err.(*apperrors.Error).Type)
+		recorded(t, mockServiceAccountRepository, model.ServiceAccountRejected)
+	})
+
+	rejected := map[string]func() string{
+		"Assertion addressed to another audience": func() string {
+			c := claims()
+			c.Audience = jwt.ClaimStrings{"https://elsewhere.test"}
+			return sign(c)
+		},
+		"Assertion about another subject": func() string {
+			c := claims()
+			c.Subject = uuid.NewString()
+			return sign(c)
+		},
+		"Expired assertion": func() string {
+			c := claims()
+			c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+			return sign(c)
+		},
+		"Assertion expiring too late": func() string {
+			c := claims()
+			c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(24 * time.Hour))
+			return sign(c)
+		},
+		"Assertion without jti": func() string {
+			c := claims()
+			c.ID = ""
+			return sign(c)
+		},
+		"Assertion signed by another key": func() string {
+			other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			ss, _ := jwt.NewWithClaims(jwt.SigningMethodES256, claims()).SignedString(other)
+			return ss
+		},
+	}
+
+	for name, assertion := range rejected {
+		t.Run(name, func(t *testing.T) {
+			sas, mockServiceAccountRepository := setup()
+
+			_, err := sas.Authenticate(context.TODO(), assertion(), ip)
+
+			assert.Equal(t, apperrors.Authorization, err.(*apperrors.Error).Type)
+			recorded(t, mockServiceAccountRepository, model.ServiceAccountRejected)
+			mockServiceAccountRepository.AssertNotCalled(t, "UseAssertion")
+		})
+	}
+
+	t.Run("Unknown service account", func(t *testing.T) {
+		sas, mockServiceAccountRepository := setup()
+
+		other := uuid.New()
+		mockServiceAccountRepository.On("FindByID", mock.Anything, other).Return(nil, apperrors.NewNotFound("service account", other.String()))
+
+		c := claims()
+		c.Issuer = other.String()
+		c.Subject = other.String()
+
+		_, err := sas.Authenticate(context.TODO(), sign(c), ip)
+
+		assert.Equal(t, apperrors.Authorization, err.(*apperrors.Error).Type)
+		mockServiceAccountRepository.AssertNotCalled(t, "AddEvent")
+	})
+}
+
+func TestServiceAccountOfOrganization(t *testing.T) {
+	orgID := uuid.New()
+	sa := &model.ServiceAccount{ID: uuid.New(), OrgID: &orgID}
+
+	mockServiceAccountRepository := new(mocks.MockServiceAccountRepository)
+	mockServiceAccountRepository.On("FindByID", mock.Anything, sa.ID).Return(sa, nil)
+
+	sas := NewServiceAccountService(&SASConfig{ServiceAccountRepository: mockServiceAccountRepository})
+
+	_, err := sas.Activity(context.TODO(), uuid.New(), sa.ID)
+
+	assert.Equal(t, apperrors.NotFound, err.(*apperrors.Error).Type)
+
+	err = sas.Delete(context.TODO(), uuid.New(), uuid.New(), sa.ID)
+
+	assert.Equal(t, apperrors.NotFound, err.(*apperrors.Error).Type)
+	mockServiceAccountRepository.AssertNotCalled(t, "Delete")
+}
+
+func TestSetExchangePolicy(t *testing.T) {
+	orgID := uuid.New()
+	actor := uuid.New()
+	sa := &model.ServiceAccount{ID: uuid.New(), OrgID: &orgID}
+
+	t.Run("Success", func(t *testing.T) {
+		mockServiceAccountRepository := new(mocks.MockServiceAccountRepository)
+		sas := NewServiceAccountService(&SASConfig{ServiceAccountRepository: mockServiceAccountRepository})
+
+		p := &model.TokenExchangePolicy{
+			ServiceAccountID: sa.ID,
+			Audiences:        model.Names{"billing-api"},
+		}
+
+		mockServiceAccountRepository.On("FindByID", mock.Anything, sa.ID).Return(sa, nil)
+		mockServiceAccountRepository.On("SetExchangePolicy", mock.Anything, p).Return(nil)
+		mockServiceAccountRepository.
+			On("AddEvent", mock.Anything, mock.MatchedBy(func(e *model.ServiceAccountEvent) bool {
+				return e.Type == model.ServiceAccountExchangePolicyUpdated && *e.Actor == actor && e.Detail == "billing-api"
+			})).
+			Return(nil)
+
+		err := sas.SetExchangePolicy(context.TODO(), actor, orgID, p)
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.Names{}, p.Scopes)
+		mockServiceAccountRepository.AssertExpectations(t)
+	})
+
+	t.Run("Service account of another organization", func(t *testing.T) {
+		mockServiceAccountRepository := new(mocks.MockServiceAccountRepository)
+		sas := NewServiceAccountService(&SASConfig{ServiceAccountRepository: mockServiceAccountRepository})
+
+		mockServiceAccountRepository.On("FindByID", mock.Anything, sa.ID).Return(sa, nil)
+
+		err := sas.SetExchangePolicy(context.TODO(), actor, uuid.New(), &model.TokenExchangePolicy{ServiceAccountID: sa.ID})
+
+		assert.Equal(t, apperrors.NotFound, err.(*apperrors.Error).Type)
+		mockServiceAccountRepository.AssertNotCalled(t, "SetExchangePolicy")
+	})
+
+	t.Run("Negative expiry", func(t *testing.T) {
+		mockServiceAccountRepository := new(mocks.MockServiceAccountRepository)
+		sas := NewServiceAccountService(&SASConfig{ServiceAccountRepository: mockServiceAccountRepository})
+
+		mockServiceAccountRepository.On("FindByID", mock.Anything, sa.ID).Return(sa, nil)
+
+		err := sas.SetExchangePolicy(context.TODO(), actor, orgID, &model.TokenExchangePolicy{ServiceAccountID: sa.ID, MaxExpiresIn: -1})
+
+		assert.Equal(t, apperrors.BadRequest, err.(*apperrors.Error).Type)
+		mockServiceAccountRepository.AssertNotCalled(t, "SetExchangePolicy")
+	})
+}
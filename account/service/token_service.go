@@ -3,42 +3,261 @@ package service
 import (
 	"context"
 	"crypto/rsa"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/weslleyrsr/auth-engine/account/model"
 	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
-	"log"
 )
 
+// applicationKeyTTL is how long the parsed key of an application is used to validate
+// tokens before the application is fetched again, picking up rotated keys
+const applicationKeyTTL = 5 * time.Minute
+
+// personalAccessTokenTouchInterval is how long the last use of a personal access token is left
+// as is while it keeps being used from the same ip address, sparing a write per request
+const personalAccessTokenTouchInterval = time.Minute
+
+// userStatusTTL is how long the status of a user is trusted when validating their access tokens before
+// being read again, bounding how long tokens remain usable once their user is disabled or deleted
+const userStatusTTL = 30 * time.Second
+
+// impersonationTokenExpiry caps the lifetime of tokens issued by impersonation, shorter id token expiries prevailing
+const impersonationTokenExpiry = 15 * time.Minute
+
 // TokenService used for injecting an implementation of TokenRepository for use in
 // service methods along with keys and secretes for signing JWTs
 type TokenService struct {
-	//TokenRepository model.TokenRepository
-	PrivKey       *rsa.PrivateKey
-	PubKey        *rsa.PublicKey
-	RefreshSecret string
+	TokenRepository       model.TokenRepository
+	ApplicationRepository model.ApplicationRepository
+	RoleRepository        model.RoleRepository
+	PrivKey               *rsa.PrivateKey
+	PubKey                *rsa.PublicKey
+	RefreshSecret         string
+	AppSettings           model.AppSettings
+
+	PersonalAccessTokenRepository model.PersonalAccessTokenRepository
+	UserRepository                model.UserRepository
+	AuditRepository               model.AuditRepository
+	OrganizationRepository        model.OrganizationRepository
+
+	applicationKeys     sync.Map     // application id to *applicationKey
+	keylessApplications sync.Map     // application id to the time.Time it was found without a key of its own
+	userStatuses        sync.Map     // user id to *userStatus
+	userStatusesEvicted atomic.Int64 // unix nanoseconds of the last eviction of expired user statuses
 }
 
 // TSConfig will hold repositories that will eventually be injected into this service layer
 type TSConfig struct {
-	// TokenRepository model.TokenRepository
-	PrivKey       *rsa.PrivateKey
-	PubKey        *rsa.PublicKey
-	RefreshSecret string
+	TokenRepository model.TokenRepository
+	// ApplicationRepository looks up the keys of applications validating their id tokens.
+	// Only tokens signed with the engine's own key are valid when nil
+	ApplicationRepository model.ApplicationRepository
+	// RoleRepository provides the roles and permissions claims of id tokens, which are left out when nil
+	RoleRepository model.RoleRepository
+	PrivKey        *rsa.PrivateKey
+	PubKey         *rsa.PublicKey
+	RefreshSecret  string
+	AppSettings    model.AppSettings // token expiries of requests not made to any application
+
+	// PersonalAccessTokenRepository looks up personal access tokens, which are rejected when nil.
+	// Their users are looked up with UserRepository, as are the users of refreshed tokens and the status
+	// of the users of access tokens, which is not enforced when nil
+	PersonalAccessTokenRepository model.PersonalAccessTokenRepository
+	UserRepository                model.UserRepository
+	// AuditRepository records impersonations, which are refused when nil, along with refreshes and signouts
+	AuditRepository model.AuditRepository
+	// OrganizationRepository checks the membership of users refreshing tokens scoped to an organization,
+	// which cannot be refreshed when nil
+	OrganizationRepository model.OrganizationRepository
+}
+
+// applicationKey is the parsed private key of an application
+type applicationKey struct {
+	pem      string
+	priv     *rsa.PrivateKey
+	loadedAt time.Time
+}
+
+// userStatus is the status of a user as read when validating one of their access tokens
+type userStatus struct {
+	status   model.UserStatus
+	loadedAt time.Time
 }
 
 // NewTokenService is a factory function for initializing a UserService with its repository layer dependencies
 func NewTokenService(c *TSConfig) model.TokenService {
 	return &TokenService{
-		PrivKey:       c.PrivKey,
-		PubKey:        c.PubKey,
-		RefreshSecret: c.RefreshSecret,
+		TokenRepository:       c.TokenRepository,
+		ApplicationRepository: c.ApplicationRepository,
+		RoleRepository:        c.RoleRepository,
+		PrivKey:               c.PrivKey,
+		PubKey:                c.PubKey,
+		RefreshSecret:         c.RefreshSecret,
+		AppSettings:           c.AppSettings,
+
+		PersonalAccessTokenRepository: c.PersonalAccessTokenRepository,
+		UserRepository:                c.UserRepository,
+		AuditRepository:               c.AuditRepository,
+		OrganizationRepository:        c.OrganizationRepository,
+	}
+}
+
+// signingKey returns the key id tokens of the application in ctx are signed with along with its
+// kid, or the engine's own key and an empty kid when the application has no key of its own
+func (s *TokenService) signingKey(ctx context.Context) (*rsa.PrivateKey, string, error) {
+	app, ok := model.ApplicationFromContext(ctx)
+	if !ok || app.PrivateKey == "" {
+		return s.PrivKey, "", nil
+	}
+
+	key, err := s.parseApplicationKey(app)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return key.priv, app.ID.String(), nil
+}
+
+// verificationKey returns the public key of id tokens of the application tenantID signed with the key
+// identified by kid. Tokens are only valid for the key of the application they were issued by, so the
+// kid must be the tenant, and the engine's own key, identified by an empty kid, only verifies tokens of
+// applications without a key of their own
+func (s *TokenService) verificationKey(kid string, tenantID uuid.UUID) (*rsa.PublicKey, error) {
+	if kid == "" {
+		if err := s.requireKeyless(tenantID); err != nil {
+			return nil, err
+		}
+
+		return s.PubKey, nil
+	}
+
+	id, err := uuid.Parse(kid)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kid: %v", kid)
+	}
+
+	if id != tenantID {
+		return nil, fmt.Errorf("kid: %v does not match the tenant: %v", kid, tenantID)
+	}
+
+	if cached, ok := s.applicationKeys.Load(id); ok && time.Since(cached.(*applicationKey).loadedAt) < applicationKeyTTL {
+		return &cached.(*applicationKey).priv.PublicKey, nil
+	}
+
+	if s.ApplicationRepository == nil {
+		return nil, fmt.Errorf("unknown kid: %v", kid)
+	}
+
+	app, err := s.ApplicationRepository.FindByID(context.Background(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	if app.PrivateKey == "" {
+		return nil, fmt.Errorf("application %v has no key of its own", id)
+	}
+
+	key, err := s.parseApplicationKey(app)
+	if err != nil {
+		return nil, err
+	}
+
+	return &key.priv.PublicKey, nil
+}
+
+// requireKeyless returns an error unless the application tenantID has no key of its own.
+// The default application always uses the engine's key
+func (s *TokenService) requireKeyless(tenantID uuid.UUID) error {
+	if tenantID == model.DefaultApplicationID || s.ApplicationRepository == nil {
+		return nil
+	}
+
+	if cached, ok := s.applicationKeys.Load(tenantID); ok && time.Since(cached.(*applicationKey).loadedAt) < applicationKeyTTL {
+		return fmt.Errorf("application %v has a key of its own", tenantID)
+	}
+
+	if checked, ok := s.keylessApplications.Load(tenantID); ok && time.Since(checked.(time.Time)) < applicationKeyTTL {
+		return nil
+	}
+
+	app, err := s.ApplicationRepository.FindByID(context.Background(), tenantID)
+	if err != nil {
+		return err
+	}
+
+	if app.PrivateKey != "" {
+		return fmt.Errorf("application %v has a key of its own", tenantID)
+	}
+
+	s.keylessApplications.Store(tenantID, time.Now())
+
+	return nil
+}
+
+// parseApplicationKey returns the parsed private key of an application, parsing it
+// only when it is not cached yet or was replaced since
+func (s *TokenService) parseApplicationKey(app *model.Application) (*applicationKey, error) {
+	if cached, ok := s.applicationKeys.Load(app.ID); ok && cached.(*applicationKey).pem == app.PrivateKey {
+		return cached.(*applicationKey), nil
+	}
+
+	priv, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(app.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse private key of application %v: %w", app.ID, err)
+	}
+
+	key := &applicationKey{
+		pem:      app.PrivateKey,
+		priv:     priv,
+		loadedAt: time.Now(),
+	}
+
+	s.applicationKeys.Store(app.ID, key)
+
+	return key, nil
+}
+
+// refreshSecret returns the secret refresh tokens of the application in ctx are signed with
+func (s *TokenService) refreshSecret(ctx context.Context) string {
+	if app, ok := model.ApplicationFromContext(ctx); ok && app.RefreshSecret != "" {
+		return app.RefreshSecret
 	}
+
+	return s.RefreshSecret
 }
 
 // NewPairFromUser creates fresh id and refresh tokens for the current user.
 // If a previous token is included, the previous token is removed from the tokens repository
 func (s *TokenService) NewPairFromUser(ctx context.Context, u *model.User, prevTokenID string) (*model.TokenPair, error) {
+	settings := model.ApplicationSettings(ctx, s.AppSettings)
+
+	key, kid, err := s.signingKey(ctx)
+	if err != nil {
+		log.Printf("Error loading signing key for uid: %v, error: %v\n", u.UID, err.Error())
+
+		return nil, apperrors.NewInternal()
+	}
+
+	// roles and permissions are read when issuing the token, changes to them take effect in the next one
+	if s.RoleRepository != nil {
+		roles, err := s.RoleRepository.FindUserRoles(ctx, u.UID)
+		if err != nil {
+			log.Printf("Error getting roles for uid: %v, error: %v\n", u.UID, err.Error())
+			return nil, apperrors.NewInternal()
+		}
+
+		u.Roles, u.Permissions = accessOf(roles)
+	}
+
 	// No need to use a repository for idToken as it is unrelated to any data source
-	idToken, err := generateIDToken(u, s.PrivKey)
+	idToken, err := generateIDToken(u, key, kid, settings.GetIDTokenExpiry())
 
 	if err != nil {
 		log.Printf("Error generating idToken for uid: %v, error: %v\n", u.UID, err.Error())
@@ -46,17 +265,485 @@ func (s *TokenService) NewPairFromUser(ctx context.Context, u *model.User, prevT
 		return nil, apperrors.NewInternal()
 	}
 
-	refreshToken, err := generateRefreshToken(u.UID, s.RefreshSecret)
+	refreshToken, err := generateRefreshToken(u.UID, u.OrgID, s.refreshSecret(ctx), settings.GetRefreshTokenExpiry())
 
 	if err != nil {
 		log.Printf("Error generating refreshToken for uid %v, error:%v\n", u.UID, err.Error())
 		return nil, apperrors.NewInternal()
 	}
 
-	// TODO: store refresh tokens by calling TokenRepository methods
+	// delete user's previous refresh token (if one exists) before storing the new one
+	if prevTokenID != "" {
+		if err := s.TokenRepository.DeleteRefreshToken(ctx, u.UID, prevTokenID); err != nil {
+			log.Printf("Could not delete previous refreshToken for uid: %v, tokenID: %v\n", u.UID, prevTokenID)
+
+			return nil, err
+		}
+	}
+
+	if err := s.TokenRepository.SetRefreshToken(ctx, u.UID, refreshToken.ID, refreshToken.ExpiresIn); err != nil {
+		log.Printf("Error storing tokenID for uid: %v, error: %v\n", u.UID, err.Error())
+		return nil, apperrors.NewInternal()
+	}
 
 	return &model.TokenPair{
 		RefreshToken: refreshToken.SS,
 		AccessToken:  idToken,
 	}, nil
 }
+
+// ValidateIDToken validates the id token jwt string
+// It returns the user extract from the IDTokenCustomClaims.
+// Tokens exchanged for another audience are rejected, being meant for the services of that audience only
+func (s *TokenService) ValidateIDToken(tokenString string) (*model.User, error) {
+	claims, err := s.validateAccessToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(claims.User.Audience) > 0 {
+		log.Printf("Rejecting idToken of uid: %v issued for audience: %v\n", claims.User.UID, claims.User.Audience)
+		return nil, apperrors.NewAuthorization("Unable to verify user from idToken")
+	}
+
+	return claims.User, nil
+}
+
+// validateAccessToken validates an access token issued by the engine, whatever its audience.
+// It returns its claims, the claims of their own being restored on the user
+func (s *TokenService) validateAccessToken(tokenString string) (*IDTokenCustomClaims, error) {
+	claims, err := validateIDToken(tokenString, s.verificationKey) // uses public RSA key
+
+	// We'll just return unauthorized error in all instances of failing to verify user
+	if err != nil {
+		log.Printf("Unable to validate or parse idToken - Error: %v\n", err)
+		return nil, apperrors.NewAuthorization("Unable to verify user from idToken")
+	}
+
+	claims.User.Roles = claims.Roles
+	claims.User.Permissions = claims.Permissions
+	claims.User.OrgRole = claims.OrgRole
+
+	if claims.OrgID != nil {
+		claims.User.OrgID = *claims.OrgID
+	}
+
+	if claims.ServiceAccount {
+		claims.User.ServiceAccountID = claims.User.UID
+	}
+
+	claims.User.Actor = claims.Act
+	claims.User.Audience = model.Names(claims.Audience)
+
+	return claims, nil
+}
+
+// ValidateUserStatus rejects users whose status no longer lets them use the access tokens issued to them,
+// such as disabled and deleted users. Their status is read at most once per userStatusTTL
+func (s *TokenService) ValidateUserStatus(ctx context.Context, u *model.User) error {
+	// service accounts are not users, they are refused their tokens by deleting them
+	if u.ServiceAccountID != uuid.Nil || s.UserRepository == nil {
+		return nil
+	}
+
+	if cached, ok := s.userStatuses.Load(u.UID); ok && time.Since(cached.(*userStatus).loadedAt) < userStatusTTL {
+		return statusError(&model.User{Status: cached.(*userStatus).status})
+	}
+
+	fetched, err := s.UserRepository.FindByID(ctx, u.UID)
+	if err != nil {
+		log.Printf("Unable to find uid: %v of access token. Reason: %v\n", u.UID, err)
+		return apperrors.NewAuthorization("Unable to verify user from idToken")
+	}
+
+	now := time.Now()
+	s.evictUserStatuses(now)
+
+	s.userStatuses.Store(u.UID, &userStatus{
+		status:   fetched.Status,
+		loadedAt: now,
+	})
+
+	return statusError(fetched)
+}
+
+// evictUserStatuses deletes the expired statuses of users, at most once per userStatusTTL,
+// so that only the users which recently used their tokens are kept in memory
+func (s *TokenService) evictUserStatuses(now time.Time) {
+	last := s.userStatusesEvicted.Load()
+	if now.UnixNano()-last < int64(userStatusTTL) || !s.userStatusesEvicted.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+
+	s.userStatuses.Range(func(uid, cached any) bool {
+		if now.Sub(cached.(*userStatus).loadedAt) >= userStatusTTL {
+			s.userStatuses.Delete(uid)
+		}
+		return true
+	})
+}
+
+// Refresh issues a new pair of tokens in exchange for a refresh token, which can only be used once.
+// The user is read again, so that changes to their profile, roles and status are taken into account,
+// as is their membership of the organization the tokens are scoped to
+func (s *TokenService) Refresh(ctx context.Context, refreshTokenString string) (*model.TokenPair, error) {
+	claims, err := validateRefreshToken(refreshTokenString, s.refreshSecret(ctx))
+	if err != nil {
+		log.Printf("Unable to validate or parse refreshToken, error: %v\n", err)
+		return nil, apperrors.NewAuthorization("Unable to verify refresh token")
+	}
+
+	if s.UserRepository == nil {
+		log.Printf("Unable to refresh tokens of uid: %v, no user repository is configured\n", claims.UID)
+		return nil, apperrors.NewInternal()
+	}
+
+	// users are looked up within the application the request is made to, which the token must belong to
+	u, err := s.UserRepository.FindByID(ctx, claims.UID)
+	if err != nil {
+		log.Printf("Unable to find uid: %v of refresh token. Reason: %v\n", claims.UID, err)
+		return nil, apperrors.NewAuthorization("Unable to verify refresh token")
+	}
+
+	if err := statusError(u); err != nil {
+		return nil, err
+	}
+
+	// tokens scoped to an organization stay scoped to it, with the current role of the user in it
+	if claims.OrgID != uuid.Nil {
+		if s.OrganizationRepository == nil {
+			log.Printf("Unable to refresh tokens of uid: %v scoped to org: %v, no organization repository is configured\n", u.UID, claims.OrgID)
+			return nil, apperrors.NewInternal()
+		}
+
+		m, err := s.OrganizationRepository.FindMembership(ctx, claims.OrgID, u.UID)
+		if err != nil {
+			log.Printf("Unable to find membership of uid: %v in org: %v. Reason: %v\n", u.UID, claims.OrgID, err)
+			return nil, apperrors.NewAuthorization("No longer a member of the organization")
+		}
+
+		u.OrgID, u.OrgRole = m.OrgID, m.Role
+	}
+
+	u.Password = ""
+
+	pair, err := s.NewPairFromUser(ctx, u, claims.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, s.AuditRepository, model.AuditUserTokenRefreshed, &u.UID, &u.UID, "")
+
+	return pair, nil
+}
+
+// RevokeOtherSessions removes every refresh token of the user except the one provided,
+// which must be a valid refresh token belonging to that same user.
+// Access tokens already issued to other sessions remain valid until they expire
+func (s *TokenService) RevokeOtherSessions(ctx context.Context, uid uuid.UUID, refreshTokenString string) error {
+	claims, err := validateRefreshToken(refreshTokenString, s.refreshSecret(ctx))
+
+	if err != nil {
+		log.Printf("Unable to validate or parse refreshToken for uid: %v, error: %v\n", uid, err)
+		return apperrors.NewAuthorization("Unable to verify refresh token")
+	}
+
+	if claims.UID != uid {
+		log.Printf("Refresh token uid: %v does not match uid: %v\n", claims.UID, uid)
+		return apperrors.NewAuthorization("Unable to verify refresh token")
+	}
+
+	return s.TokenRepository.DeleteUserRefreshTokens(ctx, uid, claims.ID)
+}
+
+// Signout removes every refresh token of the user, ending all of their sessions
+// once their current access tokens expire
+func (s *TokenService) Signout(ctx context.Context, uid uuid.UUID) error {
+	if err := s.TokenRepository.DeleteUserRefreshTokens(ctx, uid, ""); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.AuditRepository, model.AuditUserSignedOut, &uid, &uid, "")
+
+	return nil
+}
+
+// ValidatePersonalAccessToken returns the user a personal access token was created by, along with
+// the permissions they currently hold among the scopes of the token. Roles are left out, the token
+// acting through its scopes only. The time and ip address of the use are recorded
+func (s *TokenService) ValidatePersonalAccessToken(ctx context.Context, token string, ip string) (*model.User, error) {
+	invalid := apperrors.NewAuthorization("Unable to verify personal access token")
+
+	if s.PersonalAccessTokenRepository == nil {
+		return nil, invalid
+	}
+
+	t, err := s.PersonalAccessTokenRepository.FindByHash(ctx, hashVerificationToken(token))
+	if err != nil {
+		return nil, invalid
+	}
+
+	now := time.Now()
+
+	if t.Expired(now) {
+		return nil, invalid
+	}
+
+	u, err := s.UserRepository.FindByID(ctx, t.UID)
+	if err != nil {
+		log.Printf("Unable to find uid: %v of personal access token: %v. Reason: %v\n", t.UID, t.ID, err)
+		return nil, invalid
+	}
+
+	if err := statusError(u); err != nil {
+		return nil, err
+	}
+
+	u.Password = ""
+	u.PersonalAccessTokenID = t.ID
+	u.Permissions = model.Names{}
+
+	if s.RoleRepository != nil {
+		roles, err := s.RoleRepository.FindUserRoles(ctx, u.UID)
+		if err != nil {
+			log.Printf("Error getting roles for uid: %v, error: %v\n", u.UID, err.Error())
+			return nil, apperrors.NewInternal()
+		}
+
+		_, permissions := accessOf(roles)
+
+		for _, p := range permissions {
+			if t.Scopes.Contains(p) {
+				u.Permissions = append(u.Permissions, p)
+			}
+		}
+	}
+
+	if t.LastUsedAt == nil || t.LastUsedIP != ip || now.Sub(*t.LastUsedAt) >= personalAccessTokenTouchInterval {
+		// failing to record the use does not fail the request
+		_ = s.PersonalAccessTokenRepository.Touch(ctx, t.ID, ip, now)
+	}
+
+	return u, nil
+}
+
+// NewServiceAccountToken issues an access token to an authenticated service account, with the permissions
+// of the scopes requested among those of the account, or all of them when none are requested.
+// No refresh token is issued, service accounts authenticating again once it expires
+func (s *TokenService) NewServiceAccountToken(ctx context.Context, sa *model.ServiceAccount, scopes model.Names) (*model.OAuthToken, error) {
+	if len(scopes) == 0 {
+		scopes = sa.Scopes
+	}
+
+	for _, scope := range scopes {
+		if !sa.Scopes.Contains(scope) {
+			return nil, apperrors.NewForbidden(fmt.Sprintf("Scope not granted to the service account: %v", scope))
+		}
+	}
+
+	settings := model.ApplicationSettings(ctx, s.AppSettings)
+
+	key, kid, err := s.signingKey(ctx)
+	if err != nil {
+		log.Printf("Error loading signing key for service account: %v, error: %v\n", sa.ID, err.Error())
+		return nil, apperrors.NewInternal()
+	}
+
+	u := &model.User{
+		UID:              sa.ID,
+		TenantID:         sa.TenantID,
+		Permissions:      scopes,
+		ServiceAccountID: sa.ID,
+	}
+
+	if sa.OrgID != nil {
+		u.OrgID = *sa.OrgID
+	}
+
+	expiry := settings.GetIDTokenExpiry()
+
+	accessToken, err := generateIDToken(u, key, kid, expiry)
+	if err != nil {
+		log.Printf("Error generating access token for service account: %v, error: %v\n", sa.ID, err.Error())
+		return nil, apperrors.NewInternal()
+	}
+
+	return &model.OAuthToken{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(expiry.Seconds()),
+		Scope:       strings.Join(scopes, " "),
+	}, nil
+}
+
+// NewImpersonationToken issues a short-lived access token to an admin acting as the target user, with the
+// roles and permissions of the target and an act claim identifying the admin. No refresh token is issued.
+// Only users whose permissions are all held by the admin can be impersonated, so impersonating cannot escalate them.
+// The impersonation is recorded in the audit log along with the reason given, and refused if it cannot be
+func (s *TokenService) NewImpersonationToken(ctx context.Context, actor *model.User, target *model.User, reason string, ip string) (*model.OAuthToken, error) {
+	if actor.Actor != nil || actor.PersonalAccessTokenID != uuid.Nil || actor.ServiceAccountID != uuid.Nil {
+		return nil, apperrors.NewForbidden("Users can only be impersonated by an admin signed in as themselves")
+	}
+
+	if actor.UID == target.UID {
+		return nil, apperrors.NewBadRequest("Unable to impersonate yourself")
+	}
+
+	if s.AuditRepository == nil {
+		log.Printf("Refusing impersonation of uid: %v by uid: %v, no audit log is configured\n", target.UID, actor.UID)
+		return nil, apperrors.NewInternal()
+	}
+
+	settings := model.ApplicationSettings(ctx, s.AppSettings)
+
+	key, kid, err := s.signingKey(ctx)
+	if err != nil {
+		log.Printf("Error loading signing key for uid: %v, error: %v\n", target.UID, err.Error())
+		return nil, apperrors.NewInternal()
+	}
+
+	u := *target
+	u.Password = ""
+	u.Roles, u.Permissions = nil, nil
+	u.Actor = &model.Actor{
+		Subject: actor.UID,
+		Email:   actor.Email,
+	}
+
+	if s.RoleRepository != nil {
+		roles, err := s.RoleRepository.FindUserRoles(ctx, u.UID)
+		if err != nil {
+			log.Printf("Error getting roles for uid: %v, error: %v\n", u.UID, err.Error())
+			return nil, apperrors.NewInternal()
+		}
+
+		u.Roles, u.Permissions = accessOf(roles)
+	}
+
+	// impersonating another admin would let the actor act with permissions they were not granted
+	if u.Permissions.Contains(model.PermissionImpersonateUsers) {
+		return nil, apperrors.NewForbidden("Users allowed to impersonate others cannot be impersonated")
+	}
+
+	for _, p := range u.Permissions {
+		if !actor.Permissions.Contains(p) {
+			return nil, apperrors.NewForbidden("Users holding permissions the admin does not hold cannot be impersonated")
+		}
+	}
+
+	expiry := settings.GetIDTokenExpiry()
+	if expiry > impersonationTokenExpiry {
+		expiry = impersonationTokenExpiry
+	}
+
+	accessToken, err := generateIDToken(&u, key, kid, expiry)
+	if err != nil {
+		log.Printf("Error generating impersonation token for uid: %v, error: %v\n", u.UID, err.Error())
+		return nil, apperrors.NewInternal()
+	}
+
+	if err := s.AuditRepository.Create(ctx, &model.AuditEvent{
+		Type:      model.AuditUserImpersonated,
+		Actor:     &actor.UID,
+		Target:    &target.UID,
+		IP:        ip,
+		UserAgent: model.ClientFromContext(ctx).UserAgent,
+		Detail:    reason,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &model.OAuthToken{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(expiry.Seconds()),
+	}, nil
+}
+
+// ExchangeToken exchanges the access token of a subject for a token of another audience on behalf of a service
+// account, as of RFC 8693. The token keeps the permissions of the subject allowed by the policy of the service
+// account, narrowed to the scopes requested if any, and its act claim names the service account, nesting the
+// actors of the subject token. It expires with the subject token at the latest, and no refresh token is issued
+func (s *TokenService) ExchangeToken(ctx context.Context, client *model.ServiceAccount, policy *model.TokenExchangePolicy, e *model.TokenExchange) (*model.OAuthToken, error) {
+	claims, err := s.validateAccessToken(e.SubjectToken)
+	if err != nil {
+		return nil, apperrors.NewAuthorization("Unable to verify subject token")
+	}
+
+	subject := claims.User
+
+	// subject tokens are only valid for the application they were issued by, and the service accounts
+	// of an organization only act on behalf of users signed in to it
+	if subject.TenantID != model.ApplicationID(ctx) {
+		return nil, apperrors.NewAuthorization("Unable to verify subject token")
+	}
+
+	if client.OrgID != nil && subject.OrgID != *client.OrgID {
+		return nil, apperrors.NewAuthorization("Subject token was not issued for the organization of the service account")
+	}
+
+	if err := s.ValidateUserStatus(ctx, subject); err != nil {
+		return nil, err
+	}
+
+	if !policy.Audiences.Contains(e.Audience) {
+		return nil, apperrors.NewForbidden(fmt.Sprintf("Audience not allowed for the service account: %v", e.Audience))
+	}
+
+	allowed := model.Names{}
+	for _, p := range subject.Permissions {
+		if policy.Scopes.Contains(p) {
+			allowed = append(allowed, p)
+		}
+	}
+
+	scopes := e.Scopes
+	if len(scopes) == 0 {
+		scopes = allowed
+	}
+
+	for _, scope := range scopes {
+		if !allowed.Contains(scope) {
+			return nil, apperrors.NewForbidden(fmt.Sprintf("Scope not allowed for the subject token: %v", scope))
+		}
+	}
+
+	settings := model.ApplicationSettings(ctx, s.AppSettings)
+
+	key, kid, err := s.signingKey(ctx)
+	if err != nil {
+		log.Printf("Error loading signing key for uid: %v, error: %v\n", subject.UID, err.Error())
+		return nil, apperrors.NewInternal()
+	}
+
+	expiry := settings.GetIDTokenExpiry()
+	if limit := time.Duration(policy.MaxExpiresIn) * time.Second; limit > 0 && limit < expiry {
+		expiry = limit
+	}
+
+	if claims.ExpiresAt != nil && time.Until(claims.ExpiresAt.Time) < expiry {
+		expiry = time.Until(claims.ExpiresAt.Time)
+	}
+
+	subject.Roles = nil
+	subject.Permissions = scopes
+	subject.Audience = model.Names{e.Audience}
+	subject.Actor = &model.Actor{
+		Subject: client.ID,
+		Act:     subject.Actor,
+	}
+
+	accessToken, err := generateIDToken(subject, key, kid, expiry)
+	if err != nil {
+		log.Printf("Error generating exchanged token for uid: %v, error: %v\n", subject.UID, err.Error())
+		return nil, apperrors.NewInternal()
+	}
+
+	return &model.OAuthToken{
+		AccessToken:     accessToken,
+		TokenType:       "Bearer",
+		ExpiresIn:       int(expiry.Seconds()),
+		Scope:           strings.Join(scopes, " "),
+		IssuedTokenType: model.TokenTypeAccessToken,
+	}, nil
+}
@@ -2,18 +2,29 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
 	"github.com/golang-jwt/jwt/v5"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	"github.com/google/uuid"
 	"github.com/weslleyrsr/auth-engine/account/model"
+	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"github.com/weslleyrsr/auth-engine/account/model/mocks"
 )
 
-func TestNewPairFromUser(t *testing.T) {
+// loadTestKeys reads the rsa key pair generated with `make create-keypair ENV=test`
+func loadTestKeys(t *testing.T) (*rsa.PrivateKey, *rsa.PublicKey) {
+	t.Helper()
+
 	priv, err := os.ReadFile("../rsa_private_test.pem")
 	if err != nil {
 		t.Fatalf("failed to read private key file: %v", err)
@@ -34,13 +45,22 @@ func TestNewPairFromUser(t *testing.T) {
 		t.Fatalf("failed to parse public key: %v", err)
 	}
 
+	return privKey, pubKey
+}
+
+func TestNewPairFromUser(t *testing.T) {
+	privKey, pubKey := loadTestKeys(t)
+
 	secret := "anotsorandomtestsecret"
 
+	mockTokenRepository := new(mocks.MockTokenRepository)
+
 	// instantiate a common token service to be used by all tests
 	tokenService := NewTokenService(&TSConfig{
-		PrivKey:       privKey,
-		PubKey:        pubKey,
-		RefreshSecret: secret,
+		TokenRepository: mockTokenRepository,
+		PrivKey:         privKey,
+		PubKey:          pubKey,
+		RefreshSecret:   secret,
 	})
 
 	if tokenService == nil {
@@ -56,8 +76,41 @@ func TestNewPairFromUser(t *testing.T) {
 		Password: "blarghedymcblarghface",
 	}
 
+	uidErrorCase, _ := uuid.NewRandom()
+	uErrorCase := &model.User{
+		UID:      uidErrorCase,
+		Email:    "failure@failure.com",
+		Password: "blarghedymcblarghface",
+	}
+	prevID := "a_previous_tokenID"
+
+	setSuccessArguments := mock.Arguments{
+		mock.AnythingOfType("context.backgroundCtx"),
+		u.UID,
+		mock.AnythingOfType("string"),
+		mock.AnythingOfType("time.Duration"),
+	}
+
+	setErrorArguments := mock.Arguments{
+		mock.AnythingOfType("context.backgroundCtx"),
+		uidErrorCase,
+		mock.AnythingOfType("string"),
+		mock.AnythingOfType("time.Duration"),
+	}
+
+	deleteWithPrevIDArguments := mock.Arguments{
+		mock.AnythingOfType("context.backgroundCtx"),
+		u.UID,
+		prevID,
+	}
+
+	// mock call argument/responses
+	mockTokenRepository.On("SetRefreshToken", setSuccessArguments...).Return(nil)
+	mockTokenRepository.On("SetRefreshToken", setErrorArguments...).Return(fmt.Errorf("Error setting refresh token"))
+	mockTokenRepository.On("DeleteRefreshToken", deleteWithPrevIDArguments...).Return(nil)
+
 	t.Run("Returns a token pair with proper values", func(t *testing.T) {
-		ctx := context.TODO()
+		ctx := context.Background()
 		tokenPair, err := tokenService.NewPairFromUser(ctx, u, "")
 
 		assert.NotEmpty(t, tokenPair.AccessToken, "AccessToken should not be empty")
@@ -115,5 +168,990 @@ func TestNewPairFromUser(t *testing.T) {
 		expiresAt = time.Unix(refreshTokenClaims.ExpiresAt.Unix(), 0)
 		expectedExpiresAt = time.Now().Add(3 * 24 * time.Hour)
 		assert.WithinDuration(t, expectedExpiresAt, expiresAt, 5*time.Second)
+
+		mockTokenRepository.AssertCalled(t, "SetRefreshToken", setSuccessArguments...)
+		mockTokenRepository.AssertNotCalled(t, "DeleteRefreshToken")
+	})
+
+	t.Run("Error setting refresh token", func(t *testing.T) {
+		ctx := context.Background()
+		_, err := tokenService.NewPairFromUser(ctx, uErrorCase, "")
+		assert.Error(t, err) // should return an error
+
+		mockTokenRepository.AssertCalled(t, "SetRefreshToken", setErrorArguments...)
+		mockTokenRepository.AssertNotCalled(t, "DeleteRefreshToken")
+	})
+
+	t.Run("Empty string provided for prevID", func(t *testing.T) {
+		ctx := context.Background()
+		_, err := tokenService.NewPairFromUser(ctx, u, "")
+		assert.NoError(t, err)
+
+		mockTokenRepository.AssertCalled(t, "SetRefreshToken", setSuccessArguments...)
+		mockTokenRepository.AssertNotCalled(t, "DeleteRefreshToken")
+	})
+
+	t.Run("Prev token deleted", func(t *testing.T) {
+		ctx := context.Background()
+		_, err := tokenService.NewPairFromUser(ctx, u, prevID)
+		assert.NoError(t, err)
+
+		mockTokenRepository.AssertCalled(t, "DeleteRefreshToken", deleteWithPrevIDArguments...)
+	})
+}
+
+func TestValidateIDToken(t *testing.T) {
+	privKey, pubKey := loadTestKeys(t)
+
+	tokenService := NewTokenService(&TSConfig{
+		PrivKey: privKey,
+		PubKey:  pubKey,
+	})
+
+	uid, _ := uuid.NewRandom()
+	u := &model.User{
+		UID:   uid,
+		Email: "bob@bob.com",
+	}
+
+	t.Run("Valid token", func(t *testing.T) {
+		ss, _ := generateIDToken(u, privKey, "", 15*time.Minute)
+
+		uFromToken, err := tokenService.ValidateIDToken(ss)
+		assert.NoError(t, err)
+		assert.Equal(t, u.UID, uFromToken.UID)
+		assert.Equal(t, u.Email, uFromToken.Email)
+	})
+
+	t.Run("Invalid signature", func(t *testing.T) {
+		otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+		ss, _ := generateIDToken(u, otherKey, "", 15*time.Minute)
+
+		uFromToken, err := tokenService.ValidateIDToken(ss)
+		assert.Nil(t, uFromToken)
+		assert.Equal(t, apperrors.Authorization, err.(*apperrors.Error).Type)
+	})
+
+	t.Run("Malformed token", func(t *testing.T) {
+		uFromToken, err := tokenService.ValidateIDToken("not-a-jwt")
+		assert.Nil(t, uFromToken)
+		assert.Error(t, err)
+	})
+}
+
+func TestApplicationTokens(t *testing.T) {
+	privKey, pubKey := loadTestKeys(t)
+
+	appKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	appKeyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(appKey),
+	})
+
+	app := &model.Application{
+		ID:            uuid.New(),
+		Slug:          "acme",
+		PrivateKey:    string(appKeyPEM),
+		RefreshSecret: "acmerefreshsecret",
+		Settings: model.AppSettings{
+			IDTokenExpiry:      time.Hour,
+			RefreshTokenExpiry: 24 * time.Hour,
+		},
+	}
+	ctx := model.NewApplicationContext(context.Background(), app)
+
+	u := &model.User{
+		UID:      uuid.New(),
+		TenantID: app.ID,
+		Email:    "bob@bob.com",
+	}
+
+	mockTokenRepository := new(mocks.MockTokenRepository)
+	mockTokenRepository.On("SetRefreshToken", mock.Anything, u.UID, mock.AnythingOfType("string"), 24*time.Hour).Return(nil)
+
+	tokenService := NewTokenService(&TSConfig{
+		TokenRepository: mockTokenRepository,
+		PrivKey:         privKey,
+		PubKey:          pubKey,
+		RefreshSecret:   "anotsorandomtestsecret",
+	})
+
+	tokenPair, err := tokenService.NewPairFromUser(ctx, u, "")
+	assert.NoError(t, err)
+
+	t.Run("Signed with the application's keys and settings", func(t *testing.T) {
+		idTokenClaims := &IDTokenCustomClaims{}
+		token, err := jwt.ParseWithClaims(tokenPair.AccessToken, idTokenClaims, func(token *jwt.Token) (interface{}, error) {
+			return &appKey.PublicKey, nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, app.ID.String(), token.Header["kid"])
+		assert.Equal(t, app.ID, idTokenClaims.User.TenantID)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), idTokenClaims.ExpiresAt.Time, 5*time.Second)
+
+		_, err = validateRefreshToken(tokenPair.RefreshToken, app.RefreshSecret)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Validated with the application's key", func(t *testing.T) {
+		mockApplicationRepository := new(mocks.MockApplicationRepository)
+		mockApplicationRepository.On("FindByID", mock.Anything, app.ID).Return(app, nil)
+
+		// a fresh service has no cached keys and looks the application up by kid
+		validatingService := NewTokenService(&TSConfig{
+			ApplicationRepository: mockApplicationRepository,
+			PrivKey:               privKey,
+			PubKey:                pubKey,
+		})
+
+		uFromToken, err := validatingService.ValidateIDToken(tokenPair.AccessToken)
+		assert.NoError(t, err)
+		assert.Equal(t, u.UID, uFromToken.UID)
+		assert.Equal(t, app.ID, uFromToken.TenantID)
+
+		// the key is cached
+		_, err = validatingService.ValidateIDToken(tokenPair.AccessToken)
+		assert.NoError(t, err)
+		mockApplicationRepository.AssertNumberOfCalls(t, "FindByID", 1)
+	})
+
+	t.Run("Not valid for the engine's own key", func(t *testing.T) {
+		validatingService := NewTokenService(&TSConfig{
+			PrivKey: privKey,
+			PubKey:  pubKey,
+		})
+
+		uFromToken, err := validatingService.ValidateIDToken(tokenPair.AccessToken)
+		assert.Nil(t, uFromToken)
+		assert.Equal(t, apperrors.Authorization, err.(*apperrors.Error).Type)
+	})
+
+	t.Run("Kid of another application", func(t *testing.T) {
+		other := &model.Application{ID: uuid.New(), Slug: "globex", PrivateKey: string(appKeyPEM)}
+
+		mockApplicationRepository := new(mocks.MockApplicationRepository)
+		mockApplicationRepository.On("FindByID", mock.Anything, app.ID).Return(app, nil)
+		mockApplicationRepository.On("FindByID", mock.Anything, other.ID).Return(other, nil)
+
+		validatingService := NewTokenService(&TSConfig{
+			ApplicationRepository: mockApplicationRepository,
+			PrivKey:               privKey,
+			PubKey:                pubKey,
+		})
+
+		// signed with the key of the application, for a user of another one
+		token, _ := generateIDToken(&model.User{UID: uuid.New(), TenantID: other.ID}, appKey, app.ID.String(), time.Hour)
+
+		uFromToken, err := validatingService.ValidateIDToken(token)
+		assert.Nil(t, uFromToken)
+		assert.Equal(t, apperrors.Authorization, err.(*apperrors.Error).Type)
+	})
+
+	t.Run("Engine's key for an application with its own", func(t *testing.T) {
+		mockApplicationRepository := new(mocks.MockApplicationRepository)
+		mockApplicationRepository.On("FindByID", mock.Anything, app.ID).Return(app, nil)
+
+		validatingService := NewTokenService(&TSConfig{
+			ApplicationRepository: mockApplicationRepository,
+			PrivKey:               privKey,
+			PubKey:                pubKey,
+		})
+
+		token, _ := generateIDToken(u, privKey, "", time.Hour)
+
+		uFromToken, err := validatingService.ValidateIDToken(token)
+		assert.Nil(t, uFromToken)
+		assert.Equal(t, apperrors.Authorization, err.(*apperrors.Error).Type)
+	})
+
+	t.Run("Engine's key for an application without its own", func(t *testing.T) {
+		keyless := &model.Application{ID: uuid.New(), Slug: "initech"}
+
+		mockApplicationRepository := new(mocks.MockApplicationRepository)
+		mockApplicationRepository.On("FindByID", mock.Anything, keyless.ID).Return(keyless, nil)
+
+		validatingService := NewTokenService(&TSConfig{
+			ApplicationRepository: mockApplicationRepository,
+			PrivKey:               privKey,
+			PubKey:                pubKey,
+		})
+
+		token, _ := generateIDToken(&model.User{UID: uuid.New(), TenantID: keyless.ID}, privKey, "", time.Hour)
+
+		uFromToken, err := validatingService.ValidateIDToken(token)
+		assert.NoError(t, err)
+		assert.Equal(t, keyless.ID, uFromToken.TenantID)
+
+		// the application is remembered as keyless
+		_, err = validatingService.ValidateIDToken(token)
+		assert.NoError(t, err)
+		mockApplicationRepository.AssertNumberOfCalls(t, "FindByID", 1)
+	})
+}
+
+func TestRoleClaims(t *testing.T) {
+	privKey, pubKey := loadTestKeys(t)
+
+	u := &model.User{
+		UID:   uuid.New(),
+		Email: "bob@bob.com",
+	}
+
+	roles := []*model.Role{
+		{Name: "editor", Permissions: model.Names{"articles.write", "articles.read"}},
+		{Name: "admin", Permissions: model.Names{"articles.read", "account.roles.write"}},
+	}
+
+	mockTokenRepository := new(mocks.MockTokenRepository)
+	mockTokenRepository.On("SetRefreshToken", mock.Anything, u.UID, mock.AnythingOfType("string"), mock.AnythingOfType("time.Duration")).Return(nil)
+
+	mockRoleRepository := new(mocks.MockRoleRepository)
+	mockRoleRepository.On("FindUserRoles", mock.Anything, u.UID).Return(roles, nil)
+
+	tokenService := NewTokenService(&TSConfig{
+		TokenRepository: mockTokenRepository,
+		RoleRepository:  mockRoleRepository,
+		PrivKey:         privKey,
+		PubKey:          pubKey,
+		RefreshSecret:   "anotsorandomtestsecret",
+	})
+
+	tokenPair, err := tokenService.NewPairFromUser(context.Background(), u, "")
+	assert.NoError(t, err)
+
+	idTokenClaims := &IDTokenCustomClaims{}
+	_, err = jwt.ParseWithClaims(tokenPair.AccessToken, idTokenClaims, func(token *jwt.Token) (interface{}, error) {
+		return pubKey, nil
+	})
+	assert.NoError(t, err)
+
+	expectedRoles := model.Names{"admin", "editor"}
+	expectedPermissions := model.Names{"account.roles.write", "articles.read", "articles.write"}
+
+	assert.Equal(t, expectedRoles, idTokenClaims.Roles)
+	assert.Equal(t, expectedPermissions, idTokenClaims.Permissions)
+
+	// the claims are restored on the user of a validated token
+	uFromToken, err := tokenService.ValidateIDToken(tokenPair.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedRoles, uFromToken.Roles)
+	assert.Equal(t, expectedPermissions, uFromToken.Permissions)
+}
+
+func TestOrgClaims(t *testing.T) {
+	privKey, pubKey := loadTestKeys(t)
+
+	tokenService := NewTokenService(&TSConfig{
+		PrivKey: privKey,
+		PubKey:  pubKey,
+	})
+
+	t.Run("Token issued for an organization", func(t *testing.T) {
+		u := &model.User{UID: uuid.New(), OrgID: uuid.New(), OrgRole: model.OrgAdmin}
+
+		ss, _ := generateIDToken(u, privKey, "", 15*time.Minute)
+
+		claims := jwt.MapClaims{}
+		_, err := jwt.ParseWithClaims(ss, claims, func(token *jwt.Token) (interface{}, error) {
+			return pubKey, nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, u.OrgID.String(), claims["org_id"])
+		assert.Equal(t, "admin", claims["org_role"])
+
+		uFromToken, err := tokenService.ValidateIDToken(ss)
+		assert.NoError(t, err)
+		assert.Equal(t, u.OrgID, uFromToken.OrgID)
+		assert.Equal(t, model.OrgAdmin, uFromToken.OrgRole)
+	})
+
+	t.Run("Token of no organization", func(t *testing.T) {
+		u := &model.User{UID: uuid.New()}
+
+		ss, _ := generateIDToken(u, privKey, "", 15*time.Minute)
+
+		claims := jwt.MapClaims{}
+		_, err := jwt.ParseWithClaims(ss, claims, func(token *jwt.Token) (interface{}, error) {
+			return pubKey, nil
+		})
+		assert.NoError(t, err)
+		assert.NotContains(t, claims, "org_id")
+
+		uFromToken, err := tokenService.ValidateIDToken(ss)
+		assert.NoError(t, err)
+		assert.Equal(t, uuid.Nil, uFromToken.OrgID)
+	})
+}
+
+func TestRevokeOtherSessions(t *testing.T) {
+	secret := "anotsorandomtestsecret"
+
+	uid, _ := uuid.NewRandom()
+	refreshToken, _ := generateRefreshToken(uid, uuid.Nil, secret, time.Hour)
+
+	t.Run("Success", func(t *testing.T) {
+		mockTokenRepository := new(mocks.MockTokenRepository)
+		tokenService := NewTokenService(&TSConfig{
+			TokenRepository: mockTokenRepository,
+			RefreshSecret:   secret,
+		})
+
+		mockTokenRepository.On("DeleteUserRefreshTokens", mock.Anything, uid, refreshToken.ID).Return(nil)
+
+		err := tokenService.RevokeOtherSessions(context.TODO(), uid, refreshToken.SS)
+		assert.NoError(t, err)
+
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("Token of another user", func(t *testing.T) {
+		mockTokenRepository := new(mocks.MockTokenRepository)
+		tokenService := NewTokenService(&TSConfig{
+			TokenRepository: mockTokenRepository,
+			RefreshSecret:   secret,
+		})
+
+		otherUID, _ := uuid.NewRandom()
+
+		err := tokenService.RevokeOtherSessions(context.TODO(), otherUID, refreshToken.SS)
+		assert.Equal(t, apperrors.Authorization, err.(*apperrors.Error).Type)
+
+		mockTokenRepository.AssertNotCalled(t, "DeleteUserRefreshTokens", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Invalid token", func(t *testing.T) {
+		mockTokenRepository := new(mocks.MockTokenRepository)
+		tokenService := NewTokenService(&TSConfig{
+			TokenRepository: mockTokenRepository,
+			RefreshSecret:   "adifferentsecret",
+		})
+
+		err := tokenService.RevokeOtherSessions(context.TODO(), uid, refreshToken.SS)
+		assert.Equal(t, apperrors.Authorization, err.(*apperrors.Error).Type)
+
+		mockTokenRepository.AssertNotCalled(t, "DeleteUserRefreshTokens", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestRefresh(t *testing.T) {
+	privKey, pubKey := loadTestKeys(t)
+	secret := "anotsorandomtestsecret"
+
+	uid, _ := uuid.NewRandom()
+	refreshToken, _ := generateRefreshToken(uid, uuid.Nil, secret, time.Hour)
+
+	// setup returns a token service finding u as the user of refreshToken
+	setup := func(u *model.User) (model.TokenService, *mocks.MockTokenRepository) {
+		mockTokenRepository := new(mocks.MockTokenRepository)
+		mockTokenRepository.On("DeleteRefreshToken", mock.Anything, uid, refreshToken.ID).Return(nil)
+		mockTokenRepository.On("SetRefreshToken", mock.Anything, uid, mock.AnythingOfType("string"), mock.AnythingOfType("time.Duration")).Return(nil)
+
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(u, nil)
+
+		tokenService := NewTokenService(&TSConfig{
+			TokenRepository: mockTokenRepository,
+			UserRepository:  mockUserRepository,
+			PrivKey:         privKey,
+			PubKey:          pubKey,
+			RefreshSecret:   secret,
+		})
+
+		return tokenService, mockTokenRepository
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		tokenService, mockTokenRepository := setup(&model.User{UID: uid, Email: "bob@bob.com", Status: model.UserActive})
+
+		tokens, err := tokenService.Refresh(context.TODO(), refreshToken.SS)
+		assert.NoError(t, err)
+
+		u, err := tokenService.ValidateIDToken(tokens.AccessToken)
+		assert.NoError(t, err)
+		assert.Equal(t, "bob@bob.com", u.Email)
+
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("Disabled user", func(t *testing.T) {
+		tokenService, mockTokenRepository := setup(&model.User{UID: uid, Status: model.UserDisabled})
+
+		tokens, err := tokenService.Refresh(context.TODO(), refreshToken.SS)
+		assert.Nil(t, tokens)
+		assert.Equal(t, apperrors.Authorization, err.(*apperrors.Error).Type)
+		assert.Equal(t, "Account is disabled", err.(*apperrors.Error).Message)
+
+		mockTokenRepository.AssertNotCalled(t, "SetRefreshToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Organization scope", func(t *testing.T) {
+		orgID := uuid.New()
+		orgToken, _ := generateRefreshToken(uid, orgID, secret, time.Hour)
+
+		// setupOrg returns a token service finding the membership of the user in orgID, or none when nil
+		setupOrg := func(m *model.Membership) model.TokenService {
+			mockTokenRepository := new(mocks.MockTokenRepository)
+			mockTokenRepository.On("DeleteRefreshToken", mock.Anything, uid, orgToken.ID).Return(nil)
+			mockTokenRepository.On("SetRefreshToken", mock.Anything, uid, mock.AnythingOfType("string"), mock.AnythingOfType("time.Duration")).Return(nil)
+
+			mockUserRepository := new(mocks.MockUserRepository)
+			mockUserRepository.On("FindByID", mock.Anything, uid).Return(&model.User{UID: uid, Status: model.UserActive}, nil)
+
+			mockOrganizationRepository := new(mocks.MockOrganizationRepository)
+			if m != nil {
+				mockOrganizationRepository.On("FindMembership", mock.Anything, orgID, uid).Return(m, nil)
+			} else {
+				mockOrganizationRepository.On("FindMembership", mock.Anything, orgID, uid).
+					Return(nil, apperrors.NewNotFound("membership", uid.String()))
+			}
+
+			return NewTokenService(&TSConfig{
+				TokenRepository:        mockTokenRepository,
+				UserRepository:         mockUserRepository,
+				OrganizationRepository: mockOrganizationRepository,
+				PrivKey:                privKey,
+				PubKey:                 pubKey,
+				RefreshSecret:          secret,
+			})
+		}
+
+		t.Run("Kept with the current role", func(t *testing.T) {
+			tokenService := setupOrg(&model.Membership{OrgID: orgID, UID: uid, Role: model.OrgAdmin})
+
+			tokens, err := tokenService.Refresh(context.TODO(), orgToken.SS)
+			assert.NoError(t, err)
+
+			u, err := tokenService.ValidateIDToken(tokens.AccessToken)
+			assert.NoError(t, err)
+			assert.Equal(t, orgID, u.OrgID)
+			assert.Equal(t, model.OrgAdmin, u.OrgRole)
+
+			claims, err := validateRefreshToken(tokens.RefreshToken, secret)
+			assert.NoError(t, err)
+			assert.Equal(t, orgID, claims.OrgID)
+		})
+
+		t.Run("No longer a member", func(t *testing.T) {
+			tokenService := setupOrg(nil)
+
+			tokens, err := tokenService.Refresh(context.TODO(), orgToken.SS)
+			assert.Nil(t, tokens)
+			assert.Equal(t, apperrors.Authorization, err.(*apperrors.Error).Type)
+		})
+	})
+
+	t.Run("Invalid token", func(t *testing.T) {
+		tokenService, mockTokenRepository := setup(&model.User{UID: uid})
+
+		otherToken, _ := generateRefreshToken(uid, uuid.Nil, "adifferentsecret", time.Hour)
+
+		tokens, err := tokenService.Refresh(context.TODO(), otherToken.SS)
+		assert.Nil(t, tokens)
+		assert.Equal(t, apperrors.Authorization, err.(*apperrors.Error).Type)
+
+		mockTokenRepository.AssertNotCalled(t, "DeleteRefreshToken", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestValidateUserStatus(t *testing.T) {
+	uid := uuid.New()
+
+	t.Run("Status read once per ttl", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(&model.User{UID: uid, Status: model.UserActive}, nil).Once()
+
+		tokenService := NewTokenService(&TSConfig{UserRepository: mockUserRepository})
+
+		assert.NoError(t, tokenService.ValidateUserStatus(context.TODO(), &model.User{UID: uid}))
+		assert.NoError(t, tokenService.ValidateUserStatus(context.TODO(), &model.User{UID: uid}))
+
+		mockUserRepository.AssertNumberOfCalls(t, "FindByID", 1)
+	})
+
+	t.Run("Expired statuses evicted", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(&model.User{UID: uid, Status: model.UserActive}, nil)
+
+		tokenService := NewTokenService(&TSConfig{UserRepository: mockUserRepository}).(*TokenService)
+
+		expired := uuid.New()
+		tokenService.userStatuses.Store(expired, &userStatus{
+			status:   model.UserActive,
+			loadedAt: time.Now().Add(-userStatusTTL),
+		})
+
+		assert.NoError(t, tokenService.ValidateUserStatus(context.TODO(), &model.User{UID: uid}))
+
+		_, ok := tokenService.userStatuses.Load(expired)
+		assert.False(t, ok)
+		_, ok = tokenService.userStatuses.Load(uid)
+		assert.True(t, ok)
+	})
+
+	t.Run("Deleted user", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(&model.User{UID: uid, Status: model.UserDeleted}, nil)
+
+		tokenService := NewTokenService(&TSConfig{UserRepository: mockUserRepository})
+
+		err := tokenService.ValidateUserStatus(context.TODO(), &model.User{UID: uid})
+		assert.Equal(t, apperrors.Authorization, err.(*apperrors.Error).Type)
+	})
+
+	t.Run("Purged user", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(nil, apperrors.NewNotFound("uid", uid.String()))
+
+		tokenService := NewTokenService(&TSConfig{UserRepository: mockUserRepository})
+
+		err := tokenService.ValidateUserStatus(context.TODO(), &model.User{UID: uid})
+		assert.Equal(t, apperrors.Authorization, err.(*apperrors.Error).Type)
+	})
+
+	t.Run("Service account", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+
+		tokenService := NewTokenService(&TSConfig{UserRepository: mockUserRepository})
+
+		err := tokenService.ValidateUserStatus(context.TODO(), &model.User{UID: uid, ServiceAccountID: uid})
+		assert.NoError(t, err)
+
+		mockUserRepository.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestSignout(t *testing.T) {
+	uid, _ := uuid.NewRandom()
+
+	mockTokenRepository := new(mocks.MockTokenRepository)
+	mockAuditRepository := new(mocks.MockAuditRepository)
+	tokenService := NewTokenService(&TSConfig{
+		TokenRepository: mockTokenRepository,
+		AuditRepository: mockAuditRepository,
+	})
+
+	mockTokenRepository.On("DeleteUserRefreshTokens", mock.Anything, uid, "").Return(nil)
+	mockAuditRepository.On("Create", mock.Anything, &model.AuditEvent{
+		Type:   model.AuditUserSignedOut,
+		Actor:  &uid,
+		Target: &uid,
+	}).Return(nil)
+
+	err := tokenService.Signout(context.TODO(), uid)
+
+	assert.NoError(t, err)
+	mockTokenRepository.AssertExpectations(t)
+	mockAuditRepository.AssertExpectations(t)
+}
+
+func TestValidatePersonalAccessToken(t *testing.T) {
+	uid := uuid.New()
+	token := model.PersonalAccessTokenPrefix + "secret"
+	ip := "10.0.0.1"
+
+	roles := []*model.Role{
+		{Name: "editor", Permissions: model.Names{"articles.write", "articles.read"}},
+	}
+
+	// setup returns a token service finding pat as the personal access token of the user
+	setup := func(pat *model.PersonalAccessToken) (model.TokenService, *mocks.MockPersonalAccessTokenRepository) {
+		mockPersonalAccessTokenRepository := new(mocks.MockPersonalAccessTokenRepository)
+		mockPersonalAccessTokenRepository.On("FindByHash", mock.Anything, hashVerificationToken(token)).Return(pat, nil)
+		mockPersonalAccessTokenRepository.On("Touch", mock.Anything, pat.ID, ip, mock.AnythingOfType("time.Time")).Return(nil)
+
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(&model.User{UID: uid, Email: "bob@bob.com", Password: "hash"}, nil)
+
+		mockRoleRepository := new(mocks.MockRoleRepository)
+		mockRoleRepository.On("FindUserRoles", mock.Anything, uid).Return(roles, nil)
+
+		tokenService := NewTokenService(&TSConfig{
+			PersonalAccessTokenRepository: mockPersonalAccessTokenRepository,
+			UserRepository:                mockUserRepository,
+			RoleRepository:                mockRoleRepository,
+		})
+
+		return tokenService, mockPersonalAccessTokenRepository
+	}
+
+	t.Run("Permissions are limited to the scopes of the token", func(t *testing.T) {
+		pat := &model.PersonalAccessToken{
+			ID:     uuid.New(),
+			UID:    uid,
+			Scopes: model.Names{"articles.read", "account.roles.write"},
+		}
+		tokenService, mockPersonalAccessTokenRepository := setup(pat)
+
+		u, err := tokenService.ValidatePersonalAccessToken(context.TODO(), token, ip)
+
+		assert.NoError(t, err)
+		assert.Equal(t, uid, u.UID)
+		assert.Equal(t, pat.ID, u.PersonalAccessTokenID)
+		assert.Equal(t, model.Names{"articles.read"}, u.Permissions)
+		assert.Empty(t, u.Roles)
+		assert.Empty(t, u.Password)
+		mockPersonalAccessTokenRepository.AssertCalled(t, "Touch", mock.Anything, pat.ID, ip, mock.AnythingOfType("time.Time"))
+	})
+
+	t.Run("Recent use from the same ip is not recorded again", func(t *testing.T) {
+		lastUsedAt := time.Now().Add(-10 * time.Second)
+		pat := &model.PersonalAccessToken{
+			ID:         uuid.New(),
+			UID:        uid,
+			LastUsedAt: &lastUsedAt,
+			LastUsedIP: ip,
+		}
+		tokenService, mockPersonalAccessTokenRepository := setup(pat)
+
+		_, err := tokenService.ValidatePersonalAccessToken(context.TODO(), token, ip)
+
+		assert.NoError(t, err)
+		mockPersonalAccessTokenRepository.AssertNotCalled(t, "Touch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Expired token", func(t *testing.T) {
+		expiresAt := time.Now().Add(-time.Hour)
+		pat := &model.PersonalAccessToken{
+			ID:        uuid.New(),
+			UID:       uid,
+			ExpiresAt: &expiresAt,
+		}
+		tokenService, _ := setup(pat)
+
+		u, err := tokenService.ValidatePersonalAccessToken(context.TODO(), token, ip)
+
+		assert.Nil(t, u)
+		assert.Equal(t, apperrors.Authorization, err.(*apperrors.Error).Type)
+	})
+
+	t.Run("Unknown token", func(t *testing.T) {
+		mockPersonalAccessTokenRepository := new(mocks.MockPersonalAccessTokenRepository)
+		mockPersonalAccessTokenRepository.On("FindByHash", mock.Anything, mock.AnythingOfType("string")).
+			Return(nil, apperrors.NewNotFound("personal access token", "hash"))
+
+		tokenService := NewTokenService(&TSConfig{
+			PersonalAccessTokenRepository: mockPersonalAccessTokenRepository,
+		})
+
+		u, err := tokenService.ValidatePersonalAccessToken(context.TODO(), token, ip)
+
+		assert.Nil(t, u)
+		assert.Equal(t, apperrors.Authorization, err.(*apperrors.Error).Type)
+	})
+
+	t.Run("Personal access tokens disabled", func(t *testing.T) {
+		tokenService := NewTokenService(&TSConfig{})
+
+		_, err := tokenService.ValidatePersonalAccessToken(context.TODO(), token, ip)
+
+		assert.Equal(t, apperrors.Authorization, err.(*apperrors.Error).Type)
+	})
+}
+
+func TestNewServiceAccountToken(t *testing.T) {
+	privKey, pubKey := loadTestKeys(t)
+
+	tokenService := NewTokenService(&TSConfig{
+		PrivKey: privKey,
+		PubKey:  pubKey,
+	})
+
+	orgID := uuid.New()
+	sa := &model.ServiceAccount{
+		ID:     uuid.New(),
+		OrgID:  &orgID,
+		Scopes: model.Names{"articles.read", "articles.write"},
+	}
+
+	t.Run("Requested scopes", func(t *testing.T) {
+		token, err := tokenService.NewServiceAccountToken(context.TODO(), sa, model.Names{"articles.read"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Bearer", token.TokenType)
+		assert.Equal(t, "articles.read", token.Scope)
+		assert.Equal(t, 15*60, token.ExpiresIn)
+
+		u, err := tokenService.ValidateIDToken(token.AccessToken)
+
+		assert.NoError(t, err)
+		assert.Equal(t, sa.ID, u.UID)
+		assert.Equal(t, sa.ID, u.ServiceAccountID)
+		assert.Equal(t, orgID, u.OrgID)
+		assert.Equal(t, model.Names{"articles.read"}, u.Permissions)
+	})
+
+	t.Run("Every scope of the account by default", func(t *testing.T) {
+		token, err := tokenService.NewServiceAccountToken(context.TODO(), sa, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "articles.read articles.write", token.Scope)
+	})
+
+	t.Run("Scope not granted to the account", func(t *testing.T) {
+		token, err := tokenService.NewServiceAccountToken(context.TODO(), sa, model.Names{"account.roles.write"})
+
+		assert.Nil(t, token)
+		assert.Equal(t, apperrors.Forbidden, err.(*apperrors.Error).Type)
+	})
+
+	t.Run("Tokens of users are not those of service accounts", func(t *testing.T) {
+		ss, _ := generateIDToken(&model.User{UID: uuid.New()}, privKey, "", time.Minute)
+
+		u, err := tokenService.ValidateIDToken(ss)
+
+		assert.NoError(t, err)
+		assert.Equal(t, uuid.Nil, u.ServiceAccountID)
+	})
+}
+
+func TestNewImpersonationToken(t *testing.T) {
+	privKey, pubKey := loadTestKeys(t)
+
+	admin := &model.User{
+		UID:         uuid.New(),
+		Email:       "admin@bob.com",
+		Permissions: model.Names{model.PermissionImpersonateUsers, "articles.write"},
+	}
+	target := &model.User{
+		UID:      uuid.New(),
+		Email:    "bob@bob.com",
+		Password: "hashedPassword",
+	}
+
+	t.Run("Acting as the user", func(t *testing.T) {
+		mockRoleRepository := new(mocks.MockRoleRepository)
+		mockRoleRepository.On("FindUserRoles", mock.Anything, target.UID).
+			Return([]*model.Role{{Name: "editor", Permissions: model.Names{"articles.write"}}}, nil)
+
+		mockAuditRepository := new(mocks.MockAuditRepository)
+		mockAuditRepository.On("Create", mock.Anything, &model.AuditEvent{
+			Type:   model.AuditUserImpersonated,
+			Actor:  &admin.UID,
+			Target: &target.UID,
+			IP:     "10.0.0.1",
+			Detail: "ticket 4242",
+		}).Return(nil)
+
+		tokenService := NewTokenService(&TSConfig{
+			RoleRepository:  mockRoleRepository,
+			AuditRepository: mockAuditRepository,
+			PrivKey:         privKey,
+			PubKey:          pubKey,
+			AppSettings:     model.AppSettings{IDTokenExpiry: time.Hour},
+		})
+
+		token, err := tokenService.NewImpersonationToken(context.TODO(), admin, target, "ticket 4242", "10.0.0.1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Bearer", token.TokenType)
+		assert.Equal(t, 15*60, token.ExpiresIn)
+
+		u, err := tokenService.ValidateIDToken(token.AccessToken)
+
+		assert.NoError(t, err)
+		assert.Equal(t, target.UID, u.UID)
+		assert.Equal(t, "", u.Password)
+		assert.Equal(t, model.Names{"articles.write"}, u.Permissions)
+		assert.Equal(t, &model.Actor{Subject: admin.UID, Email: admin.Email}, u.Actor)
+		mockAuditRepository.AssertExpectations(t)
+	})
+
+	t.Run("Impersonating while impersonating", func(t *testing.T) {
+		mockAuditRepository := new(mocks.MockAuditRepository)
+
+		tokenService := NewTokenService(&TSConfig{
+			AuditRepository: mockAuditRepository,
+			PrivKey:         privKey,
+			PubKey:          pubKey,
+		})
+
+		impersonated := &model.User{UID: uuid.New(), Actor: &model.Actor{Subject: uuid.New()}}
+
+		token, err := tokenService.NewImpersonationToken(context.TODO(), impersonated, target, "ticket 4242", "10.0.0.1")
+
+		assert.Nil(t, token)
+		assert.Equal(t, apperrors.Forbidden, err.(*apperrors.Error).Type)
+		mockAuditRepository.AssertNotCalled(t, "Create")
+	})
+
+	t.Run("Users allowed to impersonate", func(t *testing.T) {
+		mockRoleRepository := new(mocks.MockRoleRepository)
+		mockRoleRepository.On("FindUserRoles", mock.Anything, target.UID).
+			Return([]*model.Role{{Name: "admin", Permissions: model.Names{model.PermissionImpersonateUsers}}}, nil)
+
+		mockAuditRepository := new(mocks.MockAuditRepository)
+
+		tokenService := NewTokenService(&TSConfig{
+			RoleRepository:  mockRoleRepository,
+			AuditRepository: mockAuditRepository,
+			PrivKey:         privKey,
+			PubKey:          pubKey,
+		})
+
+		token, err := tokenService.NewImpersonationToken(context.TODO(), admin, target, "ticket 4242", "10.0.0.1")
+
+		assert.Nil(t, token)
+		assert.Equal(t, apperrors.Forbidden, err.(*apperrors.Error).Type)
+		mockAuditRepository.AssertNotCalled(t, "Create")
+	})
+
+	t.Run("Users holding permissions the admin lacks", func(t *testing.T) {
+		mockRoleRepository := new(mocks.MockRoleRepository)
+		mockRoleRepository.On("FindUserRoles", mock.Anything, target.UID).
+			Return([]*model.Role{{Name: "roles admin", Permissions: model.Names{model.PermissionWriteRoles}}}, nil)
+
+		mockAuditRepository := new(mocks.MockAuditRepository)
+
+		tokenService := NewTokenService(&TSConfig{
+			RoleRepository:  mockRoleRepository,
+			AuditRepository: mockAuditRepository,
+			PrivKey:         privKey,
+			PubKey:          pubKey,
+		})
+
+		token, err := tokenService.NewImpersonationToken(context.TODO(), admin, target, "ticket 4242", "10.0.0.1")
+
+		assert.Nil(t, token)
+		assert.Equal(t, apperrors.Forbidden, err.(*apperrors.Error).Type)
+		mockAuditRepository.AssertNotCalled(t, "Create")
+	})
+
+	t.Run("Not recorded", func(t *testing.T) {
+		mockAuditRepository := new(mocks.MockAuditRepository)
+		mockAuditRepository.On("Create", mock.Anything, mock.AnythingOfType("*model.AuditEvent")).Return(apperrors.NewInternal())
+
+		tokenService := NewTokenService(&TSConfig{
+			AuditRepository: mockAuditRepository,
+			PrivKey:         privKey,
+			PubKey:          pubKey,
+		})
+
+		token, err := tokenService.NewImpersonationToken(context.TODO(), admin, target, "ticket 4242", "10.0.0.1")
+
+		assert.Nil(t, token)
+		assert.Equal(t, apperrors.Internal, err.(*apperrors.Error).Type)
+	})
+}
+
+func TestExchangeToken(t *testing.T) {
+	privKey, pubKey := loadTestKeys(t)
+
+	tokenService := NewTokenService(&TSConfig{
+		PrivKey: privKey,
+		PubKey:  pubKey,
+	})
+
+	subject := &model.User{
+		UID:         uuid.New(),
+		Email:       "bob@bob.com",
+		Roles:       model.Names{"editor"},
+		Permissions: model.Names{"articles.read", "articles.write", "billing.read"},
+	}
+	subjectToken, _ := generateIDToken(subject, privKey, "", 10*time.Minute)
+
+	client := &model.ServiceAccount{ID: uuid.New()}
+	policy := &model.TokenExchangePolicy{
+		ServiceAccountID: client.ID,
+		Audiences:        model.Names{"billing-api"},
+		Scopes:           model.Names{"articles.read", "billing.read", "billing.write"},
+	}
+
+	t.Run("Down-scoped token of the audience", func(t *testing.T) {
+		token, err := tokenService.ExchangeToken(context.TODO(), client, policy, &model.TokenExchange{
+			SubjectToken: subjectToken,
+			Audience:     "billing-api",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.TokenTypeAccessToken, token.IssuedTokenType)
+		assert.Equal(t, "articles.read billing.read", token.Scope)
+		assert.LessOrEqual(t, token.ExpiresIn, 10*60)
+
+		claims, err := tokenService.(*TokenService).validateAccessToken(token.AccessToken)
+
+		assert.NoError(t, err)
+		assert.Equal(t, subject.UID, claims.User.UID)
+		assert.Equal(t, model.Names{"billing-api"}, claims.User.Audience)
+		assert.Equal(t, model.Names{"articles.read", "billing.read"}, claims.User.Permissions)
+		assert.Empty(t, claims.User.Roles)
+		assert.Equal(t, &model.Actor{Subject: client.ID}, claims.User.Actor)
+
+		// tokens of another audience are not accepted by the engine itself
+		_, err = tokenService.ValidateIDToken(token.AccessToken)
+
+		assert.Equal(t, apperrors.Authorization, err.(*apperrors.Error).Type)
+	})
+
+	t.Run("Actor chain", func(t *testing.T) {
+		first, _ := tokenService.ExchangeToken(context.TODO(), client, policy, &model.TokenExchange{
+			SubjectToken: subjectToken,
+			Audience:     "billing-api",
+		})
+
+		next := &model.ServiceAccount{ID: uuid.New()}
+		nextPolicy := &model.TokenExchangePolicy{
+			ServiceAccountID: next.ID,
+			Audiences:        model.Names{"ledger-api"},
+			Scopes:           model.Names{"billing.read"},
+			MaxExpiresIn:     60,
+		}
+
+		token, err := tokenService.ExchangeToken(context.TODO(), next, nextPolicy, &model.TokenExchange{
+			SubjectToken: first.AccessToken,
+			Audience:     "ledger-api",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 60, token.ExpiresIn)
+
+		claims, _ := tokenService.(*TokenService).validateAccessToken(token.AccessToken)
+
+		assert.Equal(t, &model.Actor{Subject: next.ID, Act: &model.Actor{Subject: client.ID}}, claims.User.Actor)
+		assert.Equal(t, model.Names{"billing.read"}, claims.User.Permissions)
+	})
+
+	t.Run("Audience not allowed", func(t *testing.T) {
+		token, err := tokenService.ExchangeToken(context.TODO(), client, policy, &model.TokenExchange{
+			SubjectToken: subjectToken,
+			Audience:     "ledger-api",
+		})
+
+		assert.Nil(t, token)
+		assert.Equal(t, apperrors.Forbidden, err.(*apperrors.Error).Type)
+	})
+
+	t.Run("Scope the subject does not hold", func(t *testing.T) {
+		token, err := tokenService.ExchangeToken(context.TODO(), client, policy, &model.TokenExchange{
+			SubjectToken: subjectToken,
+			Audience:     "billing-api",
+			Scopes:       model.Names{"billing.write"},
+		})
+
+		assert.Nil(t, token)
+		assert.Equal(t, apperrors.Forbidden, err.(*apperrors.Error).Type)
+	})
+
+	t.Run("Subject of another organization", func(t *testing.T) {
+		orgID := uuid.New()
+		orgClient := &model.ServiceAccount{ID: uuid.New(), OrgID: &orgID}
+
+		token, err := tokenService.ExchangeToken(context.TODO(), orgClient, policy, &model.TokenExchange{
+			SubjectToken: subjectToken,
+			Audience:     "billing-api",
+		})
+
+		assert.Nil(t, token)
+		assert.Equal(t, apperrors.Authorization, err.(*apperrors.Error).Type)
+	})
+
+	t.Run("Invalid subject token", func(t *testing.T) {
+		token, err := tokenService.ExchangeToken(context.TODO(), client, policy, &model.TokenExchange{
+			SubjectToken: "invalid",
+			Audience:     "billing-api",
+		})
+
+		assert.Nil(t, token)
+		assert.Equal(t, apperrors.Authorization, err.(*apperrors.Error).Type)
 	})
 }
@@ -1,25 +1,125 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"github.com/google/uuid"
 	"github.com/weslleyrsr/auth-engine/account/model"
 	"github.com/weslleyrsr/auth-engine/account/model/apperrors"
+	"io"
 	"log"
+	"time"
+)
+
+const (
+	// verifyEmailTokenExpiry is how long a verification link stays valid
+	verifyEmailTokenExpiry = 24 * time.Hour
+	// verifyEmailResendInterval is the minimum time between two verification emails
+	verifyEmailResendInterval = time.Minute
+	// changeEmailTokenExpiry is how long the confirmation link sent to a new email address stays valid
+	changeEmailTokenExpiry = time.Hour
+	// revertEmailTokenExpiry is how long the previous email address can undo an email change
+	revertEmailTokenExpiry = 7 * 24 * time.Hour
+	// deletedByUserReason is the status reason of accounts deleted by their user
+	deletedByUserReason = "Deleted by the user"
+	// rehashPasswordTimeout bounds the background update of an outdated password hash
+	rehashPasswordTimeout = 10 * time.Second
 )
 
 type UserService struct {
-	UserRepository model.UserRepository
+	UserRepository              model.UserRepository
+	VerificationTokenRepository model.VerificationTokenRepository
+	PasswordHistoryRepository   model.PasswordHistoryRepository
+	ImageRepository             model.ImageRepository
+	Mailer                      model.Mailer
+	AuditRepository             model.AuditRepository
+	AppSettings                 model.AppSettings
+	AppURL                      string
+	ImageSizes                  []int
+	PasswordHasher              PasswordHasher
+	AuditSecret                 string
 }
 
 type USConfig struct {
-	UserRepository model.UserRepository
+	UserRepository              model.UserRepository
+	VerificationTokenRepository model.VerificationTokenRepository
+	PasswordHistoryRepository   model.PasswordHistoryRepository
+	ImageRepository             model.ImageRepository
+	Mailer                      model.Mailer
+	AuditRepository             model.AuditRepository // records signins, credential changes and deletions, which are not recorded when nil
+	AppSettings                 model.AppSettings
+	AppURL                      string         // base url of the client application, used for links sent by email
+	ImageSizes                  []int          // sizes of the profile image variants, defaults to defaultImageSizes
+	PasswordHasher              PasswordHasher // algorithm new passwords are hashed with, defaults to defaultPasswordHasher
+	// AuditSecret keys the hashes of the unknown emails failed signins are recorded with,
+	// which are left out when empty. Email addresses are never written to the append-only audit log
+	AuditSecret string
 }
 
 // NewUserService is a factory function for initializing a UserService with its repository layer dependencies
 func NewUserService(c *USConfig) model.UserService {
 	return &UserService{
-		UserRepository: c.UserRepository,
+		UserRepository:              c.UserRepository,
+		VerificationTokenRepository: c.VerificationTokenRepository,
+		PasswordHistoryRepository:   c.PasswordHistoryRepository,
+		ImageRepository:             c.ImageRepository,
+		Mailer:                      c.Mailer,
+		AuditRepository:             c.AuditRepository,
+		AppSettings:                 c.AppSettings,
+		AppURL:                      c.AppURL,
+		ImageSizes:                  c.ImageSizes,
+		PasswordHasher:              c.PasswordHasher,
+		AuditSecret:                 c.AuditSecret,
+	}
+}
+
+// passwordHasher returns the configured PasswordHasher
+func (s *UserService) passwordHasher() PasswordHasher {
+	if s.PasswordHasher == nil {
+		return defaultPasswordHasher
+	}
+
+	return s.PasswordHasher
+}
+
+// settings returns the settings of the application in ctx, or the configured AppSettings
+func (s *UserService) settings(ctx context.Context) model.AppSettings {
+	return model.ApplicationSettings(ctx, s.AppSettings)
+}
+
+// hashPassword hashes a password with the configured PasswordHasher
+func (s *UserService) hashPassword(password string) (string, error) {
+	return s.passwordHasher().Hash(password)
+}
+
+// detachApplication returns a context carrying only the application in ctx, for work
+// outliving the request ctx belongs to
+func detachApplication(ctx context.Context) context.Context {
+	detached := context.Background()
+
+	if app, ok := model.ApplicationFromContext(ctx); ok {
+		detached = model.NewApplicationContext(detached, app)
+	}
+
+	return detached
+}
+
+// rehashPassword replaces the stored hash of the password of the user with one produced by
+// the configured PasswordHasher, unless the password changed since oldHash was read. It runs after
+// the request has been answered so it is given a context detached from the request, and failures
+// are only logged as the previous hash keeps working
+func (s *UserService) rehashPassword(ctx context.Context, uid uuid.UUID, oldHash string, password string) {
+	ctx, cancel := context.WithTimeout(ctx, rehashPasswordTimeout)
+	defer cancel()
+
+	pw, err := s.hashPassword(password)
+	if err != nil {
+		log.Printf("Unable to rehash password for uid: %v, error: %v\n", uid, err)
+		return
+	}
+
+	if err := s.UserRepository.UpdatePasswordHash(ctx, uid, oldHash, pw); err != nil {
+		log.Printf("Unable to store rehashed password for uid: %v, error: %v\n", uid, err)
 	}
 }
 
@@ -33,7 +133,7 @@ func (s *UserService) Get(ctx context.Context, uid uuid.UUID) (*model.User, erro
 // Signup reaches out to a UserRepository to verify that the email address is available
 // and sign up the user if this is the case
 func (s *UserService) Signup(ctx context.Context, u *model.User) error {
-	pw, err := hashPassword(u.Password)
+	pw, err := s.hashPassword(u.Password)
 	if err != nil {
 		log.Printf("Unable to signup user fr email: %v\n", u.Email)
 		return apperrors.NewInternal()
@@ -41,10 +141,23 @@ func (s *UserService) Signup(ctx context.Context, u *model.User) error {
 
 	u.Password = pw
 
+	// users are let in right away unless admins of the application approve each of them
+	u.Status = model.UserActive
+	if s.settings(ctx).RequireApproval {
+		u.Status = model.UserPending
+	}
+
 	if err := s.UserRepository.Create(ctx, u); err != nil {
 		return err
 	}
 
+	recordAudit(ctx, s.AuditRepository, model.AuditUserSignedUp, &u.UID, &u.UID, "")
+
+	// the user can always request a new email, so failing to send one does not fail the signup
+	if err := s.sendVerificationEmail(ctx, u); err != nil {
+		log.Printf("Unable to send verification email for uid: %v. Reason: %v\n", u.UID, err)
+	}
+
 	//if we get around to adding events, we'd publish it here
 	//err:= s.EventsBroker.PublishUserUpdated(u, true)
 
@@ -54,3 +167,584 @@ func (s *UserService) Signup(ctx context.Context, u *model.User) error {
 
 	return nil
 }
+
+// ChangePassword verifies the user's current password and replaces it with
+// the hash of newPassword
+func (s *UserService) ChangePassword(ctx context.Context, uid uuid.UUID, currentPassword string, newPassword string) error {
+	u, err := s.UserRepository.FindByID(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	match, err := comparePasswords(u.Password, currentPassword)
+	if err != nil {
+		log.Printf("Unable to verify password for uid: %v\n", uid)
+		return apperrors.NewInternal()
+	}
+
+	if !match {
+		return apperrors.NewAuthorization("Invalid current password")
+	}
+
+	if err := s.setPassword(ctx, u, newPassword); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.AuditRepository, model.AuditUserPasswordChanged, &uid, &uid, "")
+
+	return nil
+}
+
+// ResetExpiredPassword lets a user whose password has expired, or who is required to reset it,
+// choose a new one by signing in with their current credentials. Other users change their password
+// while signed in instead. On success u holds the updated user
+func (s *UserService) ResetExpiredPassword(ctx context.Context, u *model.User, newPassword string) error {
+	uFetched, err := s.UserRepository.FindByEmail(ctx, u.Email)
+	if err != nil {
+		return apperrors.NewAuthorization("Invalid email and password combination")
+	}
+
+	match, err := comparePasswords(uFetched.Password, u.Password)
+	if err != nil {
+		return apperrors.NewInternal()
+	}
+
+	if !match {
+		return apperrors.NewAuthorization("Invalid email and password combination")
+	}
+
+	if err := statusError(uFetched); err != nil {
+		return err
+	}
+
+	if s.settings(ctx).RequireVerifiedEmail && !uFetched.EmailVerified {
+		return apperrors.NewAuthorization("Email address has not been verified")
+	}
+
+	if !s.passwordExpired(ctx, uFetched) {
+		return apperrors.NewBadRequest("Password has not expired")
+	}
+
+	if err := s.setPassword(ctx, uFetched, newPassword); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.AuditRepository, model.AuditUserPasswordChanged, &uFetched.UID, &uFetched.UID, "Expired password reset")
+
+	*u = *uFetched
+	return nil
+}
+
+// setPassword replaces the password of an existing user after checking it was not used recently.
+// The replaced hash is added to the password history of the user
+func (s *UserService) setPassword(ctx context.Context, u *model.User, newPassword string) error {
+	reused, err := s.isPasswordReused(ctx, u, newPassword)
+	if err != nil {
+		return err
+	}
+
+	if reused {
+		return apperrors.NewBadRequest("Password has been used recently, choose a different one")
+	}
+
+	pw, err := s.hashPassword(newPassword)
+	if err != nil {
+		log.Printf("Unable to change password for uid: %v\n", u.UID)
+		return apperrors.NewInternal()
+	}
+
+	if err := s.UserRepository.UpdatePassword(ctx, u.UID, pw); err != nil {
+		return err
+	}
+
+	// the password has been changed already, a missing history entry only weakens the reuse check
+	if size := s.settings(ctx).PasswordHistorySize; size > 0 && s.PasswordHistoryRepository != nil {
+		if err := s.PasswordHistoryRepository.Add(ctx, u.UID, u.Password, size); err != nil {
+			log.Printf("Unable to add password history for uid: %v. Reason: %v\n", u.UID, err)
+		}
+	}
+
+	u.Password = pw
+	u.PasswordChangedAt = time.Now()
+
+	return nil
+}
+
+// isPasswordReused reports whether password matches the current password of the user
+// or one of the retained previous ones
+func (s *UserService) isPasswordReused(ctx context.Context, u *model.User, password string) (bool, error) {
+	hashes := []string{u.Password}
+
+	if size := s.settings(ctx).PasswordHistorySize; size > 0 && s.PasswordHistoryRepository != nil {
+		previous, err := s.PasswordHistoryRepository.FindRecent(ctx, u.UID, size)
+		if err != nil {
+			return false, err
+		}
+
+		hashes = append(hashes, previous...)
+	}
+
+	for _, hash := range hashes {
+		match, err := comparePasswords(hash, password)
+		if err != nil {
+			log.Printf("Unable to compare password history for uid: %v. Reason: %v\n", u.UID, err)
+			continue
+		}
+
+		if match {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// passwordExpired reports whether the password of the user is older than MaxPasswordAge,
+// or an admin required the user to reset it
+func (s *UserService) passwordExpired(ctx context.Context, u *model.User) bool {
+	if u.PasswordResetRequired {
+		return true
+	}
+
+	maxAge := s.settings(ctx).MaxPasswordAge
+	return maxAge > 0 && time.Since(u.PasswordChangedAt) > maxAge
+}
+
+// statusError returns the error of users whose status does not let them sign in or use the tokens
+// issued to them, or nil. Users of no status, such as those of access tokens, are not refused
+func statusError(u *model.User) error {
+	switch u.Status {
+	case model.UserDisabled:
+		return apperrors.NewAuthorization("Account is disabled")
+	case model.UserPending:
+		return apperrors.NewAuthorization("Account is pending approval")
+	case model.UserDeleted:
+		return apperrors.NewAuthorization("Account has been deleted")
+	}
+
+	return nil
+}
+
+// Signin reaches out to a UserRepository check if the user exists
+// and then compares the supplied password with the provided password
+// if a valid email/password combo is provided, u will hold all
+// available user fields
+func (s *UserService) Signin(ctx context.Context, u *model.User) error {
+	uFetched, err := s.UserRepository.FindByEmail(ctx, u.Email)
+
+	// Will return NotAuthorized to client to omit details of why
+	if err != nil {
+		// there is no user to target, a keyed hash of the email tried is recorded instead
+		recordAudit(ctx, s.AuditRepository, model.AuditUserSigninFailed, nil, nil, hashAuditEmail(s.AuditSecret, u.Email))
+		return apperrors.NewAuthorization("Invalid email and password combination")
+	}
+
+	// verify password
+	match, err := comparePasswords(uFetched.Password, u.Password)
+
+	if err != nil {
+		return apperrors.NewInternal()
+	}
+
+	if !match {
+		recordAudit(ctx, s.AuditRepository, model.AuditUserSigninFailed, nil, &uFetched.UID, "Invalid password")
+		return apperrors.NewAuthorization("Invalid email and password combination")
+	}
+
+	if err := statusError(uFetched); err != nil {
+		recordAudit(ctx, s.AuditRepository, model.AuditUserSigninFailed, nil, &uFetched.UID, err.Error())
+		return err
+	}
+
+	if s.settings(ctx).RequireVerifiedEmail && !uFetched.EmailVerified {
+		return apperrors.NewAuthorization("Email address has not been verified")
+	}
+
+	if s.passwordExpired(ctx, uFetched) {
+		return apperrors.NewPasswordExpired()
+	}
+
+	// upgrade hashes produced with outdated parameters or algorithm while the plain password is known
+	if needsRehash(s.passwordHasher(), uFetched.Password) {
+		go s.rehashPassword(detachApplication(ctx), uFetched.UID, uFetched.Password, u.Password)
+	}
+
+	recordAudit(ctx, s.AuditRepository, model.AuditUserSignedIn, &uFetched.UID, &uFetched.UID, "")
+
+	*u = *uFetched
+	return nil
+}
+
+// VerifyEmail consumes an email verification token and marks the email
+// address it was sent to as verified
+func (s *UserService) VerifyEmail(ctx context.Context, token string) error {
+	t, err := s.VerificationTokenRepository.Consume(ctx, model.VerifyEmail, hashVerificationToken(token))
+	if err != nil {
+		return apperrors.NewBadRequest("Invalid or expired verification token")
+	}
+
+	if time.Now().After(t.ExpiresAt) {
+		return apperrors.NewBadRequest("Invalid or expired verification token")
+	}
+
+	return s.UserRepository.SetEmailVerified(ctx, t.UID, t.Email)
+}
+
+// ResendVerificationEmail sends a new verification email to a user who has not verified
+// their email address yet. It does not disclose whether an account exists for the email
+func (s *UserService) ResendVerificationEmail(ctx context.Context, email string) error {
+	u, err := s.UserRepository.FindByEmail(ctx, email)
+	if err != nil || u.EmailVerified {
+		return nil
+	}
+
+	latest, err := s.VerificationTokenRepository.FindLatest(ctx, u.UID, model.VerifyEmail)
+	if err == nil && time.Since(latest.CreatedAt) < verifyEmailResendInterval {
+		return apperrors.NewBadRequest("A verification email was sent recently, please try again later")
+	}
+
+	if err := s.sendVerificationEmail(ctx, u); err != nil {
+		log.Printf("Unable to resend verification email for uid: %v. Reason: %v\n", u.UID, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// sendVerificationEmail stores a new email verification token for the user and emails it
+func (s *UserService) sendVerificationEmail(ctx context.Context, u *model.User) error {
+	token, tokenHash, err := generateVerificationToken()
+	if err != nil {
+		return err
+	}
+
+	if err := s.VerificationTokenRepository.Create(ctx, &model.VerificationToken{
+		TokenHash: tokenHash,
+		UID:       u.UID,
+		Purpose:   model.VerifyEmail,
+		Email:     u.Email,
+		ExpiresAt: time.Now().Add(verifyEmailTokenExpiry),
+	}); err != nil {
+		return err
+	}
+
+	subject, body := verificationEmail(appURL(ctx, s.AppURL), token)
+
+	return s.Mailer.Send(ctx, u.Email, subject, body)
+}
+
+// RequestEmailChange verifies the user's password and emails a confirmation link to newEmail.
+// The email address is only changed once the link is followed
+func (s *UserService) RequestEmailChange(ctx context.Context, uid uuid.UUID, password string, newEmail string) error {
+	u, err := s.UserRepository.FindByID(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	match, err := comparePasswords(u.Password, password)
+	if err != nil {
+		log.Printf("Unable to verify password for uid: %v\n", uid)
+		return apperrors.NewInternal()
+	}
+
+	if !match {
+		return apperrors.NewAuthorization("Invalid password")
+	}
+
+	if newEmail == u.Email {
+		return apperrors.NewBadRequest("New email must be different from the current email")
+	}
+
+	if _, err := s.UserRepository.FindByEmail(ctx, newEmail); err == nil {
+		return apperrors.NewConflict("email", newEmail)
+	}
+
+	token, tokenHash, err := generateVerificationToken()
+	if err != nil {
+		log.Printf("Unable to generate email change token for uid: %v\n", uid)
+		return apperrors.NewInternal()
+	}
+
+	if err := s.VerificationTokenRepository.Create(ctx, &model.VerificationToken{
+		TokenHash: tokenHash,
+		UID:       uid,
+		Purpose:   model.ChangeEmail,
+		Email:     newEmail,
+		ExpiresAt: time.Now().Add(changeEmailTokenExpiry),
+	}); err != nil {
+		return err
+	}
+
+	subject, body := changeEmailConfirmation(appURL(ctx, s.AppURL), token)
+
+	if err := s.Mailer.Send(ctx, newEmail, subject, body); err != nil {
+		log.Printf("Unable to send email change confirmation for uid: %v. Reason: %v\n", uid, err)
+		return apperrors.NewInternal()
+	}
+
+	return nil
+}
+
+// ConfirmEmailChange consumes an email change token and switches the user to the new address.
+// The previous address is notified with a link which undoes the change
+func (s *UserService) ConfirmEmailChange(ctx context.Context, token string) (*model.User, error) {
+	t, err := s.VerificationTokenRepository.Consume(ctx, model.ChangeEmail, hashVerificationToken(token))
+	if err != nil || time.Now().After(t.ExpiresAt) {
+		return nil, apperrors.NewBadRequest("Invalid or expired email change token")
+	}
+
+	prev, err := s.UserRepository.FindByID(ctx, t.UID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the new address may have been registered since the change was requested
+	u, err := s.UserRepository.UpdateEmail(ctx, t.UID, t.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, s.AuditRepository, model.AuditUserEmailChanged, &u.UID, &u.UID, "")
+
+	// the previous address is notified of the change even when no revert link can be offered
+	revertToken, err := s.createRevertEmailToken(ctx, u.UID, prev.Email)
+	if err != nil {
+		log.Printf("Unable to create email revert token for uid: %v. Reason: %v\n", u.UID, err)
+		revertToken = ""
+	}
+
+	subject, body := emailChangedNotification(appURL(ctx, s.AppURL), u.Email, revertToken)
+
+	if err := s.Mailer.Send(ctx, prev.Email, subject, body); err != nil {
+		log.Printf("Unable to notify previous email of uid: %v. Reason: %v\n", u.UID, err)
+	}
+
+	return u, nil
+}
+
+// createRevertEmailToken stores a token restoring email as the address of the user, returning the token
+func (s *UserService) createRevertEmailToken(ctx context.Context, uid uuid.UUID, email string) (string, error) {
+	token, tokenHash, err := generateVerificationToken()
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.VerificationTokenRepository.Create(ctx, &model.VerificationToken{
+		TokenHash: tokenHash,
+		UID:       uid,
+		Purpose:   model.RevertEmail,
+		Email:     email,
+		ExpiresAt: time.Now().Add(revertEmailTokenExpiry),
+	}); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// RevertEmailChange consumes a token sent to a previous email address and restores that address
+func (s *UserService) RevertEmailChange(ctx context.Context, token string) (*model.User, error) {
+	t, err := s.VerificationTokenRepository.Consume(ctx, model.RevertEmail, hashVerificationToken(token))
+	if err != nil || time.Now().After(t.ExpiresAt) {
+		return nil, apperrors.NewBadRequest("Invalid or expired email revert token")
+	}
+
+	u, err := s.UserRepository.UpdateEmail(ctx, t.UID, t.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, s.AuditRepository, model.AuditUserEmailChangeReverted, &u.UID, &u.UID, "")
+
+	return u, nil
+}
+
+// DeleteAccount soft-deletes the account of a user confirming their password. The user is emailed a link
+// restoring the account until it is purged, once the DeletedUserRetention of the application is over.
+// Sessions of the user are left to the caller to revoke
+func (s *UserService) DeleteAccount(ctx context.Context, uid uuid.UUID, password string) error {
+	u, err := s.UserRepository.FindByID(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	match, err := comparePasswords(u.Password, password)
+	if err != nil {
+		log.Printf("Unable to verify password for uid: %v\n", uid)
+		return apperrors.NewInternal()
+	}
+
+	if !match {
+		return apperrors.NewAuthorization("Invalid password")
+	}
+
+	if _, err := s.UserRepository.SetStatus(ctx, uid, model.UserDeleted, deletedByUserReason); err != nil {
+		return err
+	}
+
+	// no personal data is recorded, the audit log being kept once the account is purged
+	recordAudit(ctx, s.AuditRepository, model.AuditUserDeleted, &uid, &uid, "")
+
+	// admins can restore the account as well, so failing to send the link does not fail the deletion
+	if err := s.sendRestoreEmail(ctx, u); err != nil {
+		log.Printf("Unable to send account restore email for uid: %v. Reason: %v\n", uid, err)
+	}
+
+	return nil
+}
+
+// sendRestoreEmail emails a user who deleted their account a link restoring it until it is purged
+func (s *UserService) sendRestoreEmail(ctx context.Context, u *model.User) error {
+	token, tokenHash, err := generateVerificationToken()
+	if err != nil {
+		return err
+	}
+
+	purgeAt := time.Now().Add(s.settings(ctx).GetDeletedUserRetention())
+
+	if err := s.VerificationTokenRepository.Create(ctx, &model.VerificationToken{
+		TokenHash: tokenHash,
+		UID:       u.UID,
+		Purpose:   model.RestoreAccount,
+		Email:     u.Email,
+		ExpiresAt: purgeAt,
+	}); err != nil {
+		return err
+	}
+
+	subject, body := accountDeletedEmail(appURL(ctx, s.AppURL), token, purgeAt)
+
+	return s.Mailer.Send(ctx, u.Email, subject, body)
+}
+
+// RestoreAccount consumes a token sent to a user who deleted their account and restores it, as long as it
+// is still deleted. Restoring is a conflict when the email address of the user was signed up with since
+func (s *UserService) RestoreAccount(ctx context.Context, token string) (*model.User, error) {
+	invalid := apperrors.NewBadRequest("Invalid or expired account restore token")
+
+	t, err := s.VerificationTokenRepository.Consume(ctx, model.RestoreAccount, hashVerificationToken(token))
+	if err != nil || time.Now().After(t.ExpiresAt) {
+		return nil, invalid
+	}
+
+	// the account may have been restored, or disabled, by an admin since
+	u, err := s.UserRepository.FindByID(ctx, t.UID)
+	if err != nil || u.Status != model.UserDeleted {
+		return nil, invalid
+	}
+
+	u, err = s.UserRepository.SetStatus(ctx, t.UID, model.UserActive, "")
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, s.AuditRepository, model.AuditUserRestored, &u.UID, &u.UID, "")
+
+	return u, nil
+}
+
+// UpdateDetails updates the profile fields of a user. The version is the one the
+// client last read, so concurrent updates do not overwrite each other
+func (s *UserService) UpdateDetails(ctx context.Context, uid uuid.UUID, version int, d *model.UserDetails) (*model.User, error) {
+	return s.UserRepository.Update(ctx, uid, version, d)
+}
+
+// SetProfileImage processes a new profile image into square variants of each of the configured
+// sizes, uploads them and deletes the variants of the previous image.
+// ImageURL of the user is set to the largest variant
+func (s *UserService) SetProfileImage(ctx context.Context, uid uuid.UUID, image io.Reader) (*model.User, error) {
+	u, err := s.UserRepository.FindByID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := s.ImageSizes
+	if len(sizes) == 0 {
+		sizes = defaultImageSizes
+	}
+
+	processed, err := processImage(image, sizes)
+	if err != nil {
+		log.Printf("Unable to process profile image for uid: %v. Reason: %v\n", uid, err)
+		return nil, apperrors.NewBadRequest("Unable to process image")
+	}
+
+	id, err := uuid.NewRandom()
+	if err != nil {
+		log.Printf("Unable to name profile image for uid: %v. Reason: %v\n", uid, err)
+		return nil, apperrors.NewInternal()
+	}
+
+	variants := model.ImageVariants{}
+	imageURL := ""
+	largest := 0
+
+	for _, v := range processed {
+		variantURL, err := s.ImageRepository.Upload(ctx, imageObjectName(uid, id, v), v.ContentType, bytes.NewReader(v.Data))
+		if err != nil {
+			// do not leave the variants uploaded so far behind
+			s.deleteImages(ctx, variants, "")
+			return nil, err
+		}
+
+		variants[v.Size] = variantURL
+
+		if v.Size > largest {
+			largest = v.Size
+			imageURL = variantURL
+		}
+	}
+
+	updatedUser, err := s.UserRepository.UpdateImage(ctx, uid, imageURL, variants)
+	if err != nil {
+		// do not leave the new image behind if the user could not be updated
+		s.deleteImages(ctx, variants, "")
+		return nil, err
+	}
+
+	s.deleteImages(ctx, u.ImageVariants, u.ImageURL)
+
+	return updatedUser, nil
+}
+
+// ClearProfileImage removes the profile image of the user
+func (s *UserService) ClearProfileImage(ctx context.Context, uid uuid.UUID) (*model.User, error) {
+	u, err := s.UserRepository.FindByID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.ImageURL == "" && len(u.ImageVariants) == 0 {
+		return u, nil
+	}
+
+	updatedUser, err := s.UserRepository.UpdateImage(ctx, uid, "", model.ImageVariants{})
+	if err != nil {
+		return nil, err
+	}
+
+	s.deleteImages(ctx, u.ImageVariants, u.ImageURL)
+
+	return updatedUser, nil
+}
+
+// deleteImages removes no longer referenced images. Failures only leave orphan
+// objects behind, so they are logged rather than returned
+func (s *UserService) deleteImages(ctx context.Context, variants model.ImageVariants, imageURL string) {
+	objNames := map[string]bool{}
+
+	for _, variantURL := range variants {
+		objNames[imageObjectNameFromURL(variantURL)] = true
+	}
+
+	// images uploaded before variants existed are only referenced by imageURL
+	if imageURL != "" {
+		objNames[imageObjectNameFromURL(imageURL)] = true
+	}
+
+	for objName := range objNames {
+		if err := s.ImageRepository.Delete(ctx, objName); err != nil {
+			log.Printf("Unable to delete image: %v. Reason: %v\n", objName, err)
+		}
+	}
+}
@@ -1,9 +1,12 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -66,8 +69,12 @@ func TestSignup(t *testing.T) {
 		}
 
 		mockUserRepository := new(mocks.MockUserRepository)
+		mockVerificationTokenRepository := new(mocks.MockVerificationTokenRepository)
+		mockMailer := new(mocks.MockMailer)
 		us := NewUserService(&USConfig{
-			UserRepository: mockUserRepository,
+			UserRepository:              mockUserRepository,
+			VerificationTokenRepository: mockVerificationTokenRepository,
+			Mailer:                      mockMailer,
 		})
 
 		// We can use Run method to modify the user when the Create method is called.
@@ -79,6 +86,14 @@ func TestSignup(t *testing.T) {
 				userArg.UID = uid
 			}).Return(nil)
 
+		mockVerificationTokenRepository.
+			On("Create", mock.Anything, mock.MatchedBy(func(vt *model.VerificationToken) bool {
+				return vt.UID == uid && vt.Purpose == model.VerifyEmail && vt.Email == mockUser.Email
+			})).
+			Return(nil)
+
+		mockMailer.On("Send", mock.Anything, mockUser.Email, mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
+
 		ctx := context.TODO()
 		err := us.Signup(ctx, mockUser)
 
@@ -88,6 +103,63 @@ func TestSignup(t *testing.T) {
 		assert.Equal(t, uid, mockUser.UID)
 
 		mockUserRepository.AssertExpectations(t)
+		mockVerificationTokenRepository.AssertExpectations(t)
+		mockMailer.AssertExpectations(t)
+	})
+
+	t.Run("Verification email failure does not fail signup", func(t *testing.T) {
+		mockUser := &model.User{
+			Email:    "bob@bob.com",
+			Password: "howdyhoneighbor!",
+		}
+
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockVerificationTokenRepository := new(mocks.MockVerificationTokenRepository)
+		mockMailer := new(mocks.MockMailer)
+		us := NewUserService(&USConfig{
+			UserRepository:              mockUserRepository,
+			VerificationTokenRepository: mockVerificationTokenRepository,
+			Mailer:                      mockMailer,
+		})
+
+		mockUserRepository.On("Create", mock.Anything, mockUser).Return(nil)
+		mockVerificationTokenRepository.On("Create", mock.Anything, mock.AnythingOfType("*model.VerificationToken")).Return(nil)
+		mockMailer.On("Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("smtp unavailable"))
+
+		err := us.Signup(context.TODO(), mockUser)
+
+		assert.NoError(t, err)
+		mockMailer.AssertExpectations(t)
+	})
+
+	t.Run("Pending approval", func(t *testing.T) {
+		mockUser := &model.User{
+			Email:    "bob@bob.com",
+			Password: "howdyhoneighbor!",
+		}
+
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockVerificationTokenRepository := new(mocks.MockVerificationTokenRepository)
+		mockMailer := new(mocks.MockMailer)
+		us := NewUserService(&USConfig{
+			UserRepository:              mockUserRepository,
+			VerificationTokenRepository: mockVerificationTokenRepository,
+			Mailer:                      mockMailer,
+			AppSettings:                 model.AppSettings{RequireApproval: true},
+		})
+
+		mockUserRepository.
+			On("Create", mock.Anything, mock.MatchedBy(func(u *model.User) bool {
+				return u.Status == model.UserPending
+			})).
+			Return(nil)
+		mockVerificationTokenRepository.On("Create", mock.Anything, mock.AnythingOfType("*model.VerificationToken")).Return(nil)
+		mockMailer.On("Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		err := us.Signup(context.TODO(), mockUser)
+
+		assert.NoError(t, err)
+		mockUserRepository.AssertExpectations(t)
 	})
 
 	t.Run("Error", func(t *testing.T) {
@@ -118,3 +190,1393 @@ func TestSignup(t *testing.T) {
 		mockUserRepository.AssertExpectations(t)
 	})
 }
+
+func TestAuditDetailsWithoutEmails(t *testing.T) {
+	uid, _ := uuid.NewRandom()
+	oldEmail := "bob@bob.com"
+	newEmail := "new@bob.com"
+
+	// setup returns a user service recording the details of its audit events to details
+	setup := func(details *[]string) (model.UserService, *mocks.MockUserRepository, *mocks.MockVerificationTokenRepository) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockVerificationTokenRepository := new(mocks.MockVerificationTokenRepository)
+		mockMailer := new(mocks.MockMailer)
+		mockAuditRepository := new(mocks.MockAuditRepository)
+
+		mockMailer.On("Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		mockVerificationTokenRepository.On("Create", mock.Anything, mock.AnythingOfType("*model.VerificationToken")).Return(nil)
+		mockAuditRepository.
+			On("Create", mock.Anything, mock.AnythingOfType("*model.AuditEvent")).
+			Run(func(args mock.Arguments) {
+				*details = append(*details, args.Get(1).(*model.AuditEvent).Detail)
+			}).Return(nil)
+
+		us := NewUserService(&USConfig{
+			UserRepository:              mockUserRepository,
+			VerificationTokenRepository: mockVerificationTokenRepository,
+			Mailer:                      mockMailer,
+			AuditRepository:             mockAuditRepository,
+			AuditSecret:                 "audit secret",
+		})
+
+		return us, mockUserRepository, mockVerificationTokenRepository
+	}
+
+	details := []string{}
+
+	t.Run("Signup", func(t *testing.T) {
+		us, mockUserRepository, _ := setup(&details)
+
+		mockUserRepository.On("Create", mock.Anything, mock.AnythingOfType("*model.User")).Return(nil)
+
+		assert.NoError(t, us.Signup(context.TODO(), &model.User{Email: oldEmail, Password: "howdyhoneighbor!"}))
+	})
+
+	t.Run("Signin with an unknown email", func(t *testing.T) {
+		us, mockUserRepository, _ := setup(&details)
+
+		mockUserRepository.On("FindByEmail", mock.Anything, newEmail).Return(nil, apperrors.NewNotFound("email", newEmail))
+
+		assert.Error(t, us.Signin(context.TODO(), &model.User{Email: newEmail, Password: "howdyhoneighbor!"}))
+	})
+
+	t.Run("Email change", func(t *testing.T) {
+		us, mockUserRepository, mockVerificationTokenRepository := setup(&details)
+
+		token, tokenHash, _ := generateVerificationToken()
+
+		mockVerificationTokenRepository.On("Consume", mock.Anything, model.ChangeEmail, tokenHash).Return(&model.VerificationToken{
+			UID:       uid,
+			Purpose:   model.ChangeEmail,
+			Email:     newEmail,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}, nil)
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(&model.User{UID: uid, Email: oldEmail}, nil)
+		mockUserRepository.On("UpdateEmail", mock.Anything, uid, newEmail).Return(&model.User{UID: uid, Email: newEmail}, nil)
+
+		_, err := us.ConfirmEmailChange(context.TODO(), token)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Email change reverted", func(t *testing.T) {
+		us, mockUserRepository, mockVerificationTokenRepository := setup(&details)
+
+		token, tokenHash, _ := generateVerificationToken()
+
+		mockVerificationTokenRepository.On("Consume", mock.Anything, model.RevertEmail, tokenHash).Return(&model.VerificationToken{
+			UID:       uid,
+			Purpose:   model.RevertEmail,
+			Email:     oldEmail,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}, nil)
+		mockUserRepository.On("UpdateEmail", mock.Anything, uid, oldEmail).Return(&model.User{UID: uid, Email: oldEmail}, nil)
+
+		_, err := us.RevertEmailChange(context.TODO(), token)
+		assert.NoError(t, err)
+	})
+
+	// every flow recorded its event, none of which holds an email address
+	assert.Len(t, details, 4)
+	for _, d := range details {
+		assert.NotContains(t, d, "@")
+		assert.NotContains(t, d, "bob.com")
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	uid, _ := uuid.NewRandom()
+	currentPassword := "howdyhoneighbor!"
+	hashedPassword, _ := defaultPasswordHasher.Hash(currentPassword)
+
+	t.Run("Success", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+		})
+
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(&model.User{
+			UID:      uid,
+			Password: hashedPassword,
+		}, nil)
+		mockUserRepository.
+			On("UpdatePassword", mock.Anything, uid, mock.AnythingOfType("string")).
+			Run(func(args mock.Arguments) {
+				// the new password must be stored hashed
+				match, err := comparePasswords(args.String(2), "anewpassword")
+				assert.NoError(t, err)
+				assert.True(t, match)
+			}).Return(nil)
+
+		err := us.ChangePassword(context.TODO(), uid, currentPassword, "anewpassword")
+
+		assert.NoError(t, err)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("Invalid current password", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+		})
+
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(&model.User{
+			UID:      uid,
+			Password: hashedPassword,
+		}, nil)
+
+		err := us.ChangePassword(context.TODO(), uid, "wrongpassword", "anewpassword")
+
+		assert.Equal(t, apperrors.Authorization, err.(*apperrors.Error).Type)
+		mockUserRepository.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("User not found", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+		})
+
+		mockErr := apperrors.NewNotFound("uid", uid.String())
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(nil, mockErr)
+
+		err := us.ChangePassword(context.TODO(), uid, currentPassword, "anewpassword")
+
+		assert.EqualError(t, err, mockErr.Error())
+		mockUserRepository.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Current password reused", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+		})
+
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(&model.User{
+			UID:      uid,
+			Password: hashedPassword,
+		}, nil)
+
+		err := us.ChangePassword(context.TODO(), uid, currentPassword, currentPassword)
+
+		assert.Equal(t, apperrors.BadRequest, err.(*apperrors.Error).Type)
+		mockUserRepository.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Retained password reused", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockPasswordHistoryRepository := new(mocks.MockPasswordHistoryRepository)
+		us := NewUserService(&USConfig{
+			UserRepository:            mockUserRepository,
+			PasswordHistoryRepository: mockPasswordHistoryRepository,
+			AppSettings:               model.AppSettings{PasswordHistorySize: 3},
+		})
+
+		previousHash, _ := defaultPasswordHasher.Hash("apreviouspassword")
+
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(&model.User{
+			UID:      uid,
+			Password: hashedPassword,
+		}, nil)
+		mockPasswordHistoryRepository.On("FindRecent", mock.Anything, uid, 3).Return([]string{"malformed", previousHash}, nil)
+
+		err := us.ChangePassword(context.TODO(), uid, currentPassword, "apreviouspassword")
+
+		assert.Equal(t, apperrors.BadRequest, err.(*apperrors.Error).Type)
+		mockUserRepository.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+		mockPasswordHistoryRepository.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Replaced password is retained", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockPasswordHistoryRepository := new(mocks.MockPasswordHistoryRepository)
+		us := NewUserService(&USConfig{
+			UserRepository:            mockUserRepository,
+			PasswordHistoryRepository: mockPasswordHistoryRepository,
+			AppSettings:               model.AppSettings{PasswordHistorySize: 3},
+		})
+
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(&model.User{
+			UID:      uid,
+			Password: hashedPassword,
+		}, nil)
+		mockPasswordHistoryRepository.On("FindRecent", mock.Anything, uid, 3).Return([]string{}, nil)
+		mockUserRepository.On("UpdatePassword", mock.Anything, uid, mock.AnythingOfType("string")).Return(nil)
+		mockPasswordHistoryRepository.On("Add", mock.Anything, uid, hashedPassword, 3).Return(nil)
+
+		err := us.ChangePassword(context.TODO(), uid, currentPassword, "anewpassword")
+
+		assert.NoError(t, err)
+		mockUserRepository.AssertExpectations(t)
+		mockPasswordHistoryRepository.AssertExpectations(t)
+	})
+}
+
+func TestSignin(t *testing.T) {
+	email := "bob@bob.com"
+	validPW := "howdyhoneighbor!"
+	hashedValidPW, _ := defaultPasswordHasher.Hash(validPW)
+	invalidPW := "howdyhodufus!"
+
+	uid, _ := uuid.NewRandom()
+
+	t.Run("Success", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+		})
+
+		mockUserResp := &model.User{
+			UID:      uid,
+			Email:    email,
+			Password: hashedValidPW,
+		}
+		mockUserRepository.On("FindByEmail", mock.Anything, email).Return(mockUserResp, nil)
+
+		u := &model.User{
+			Email:    email,
+			Password: validPW,
+		}
+		err := us.Signin(context.TODO(), u)
+
+		assert.NoError(t, err)
+		assert.Equal(t, uid, u.UID)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("Invalid email/password combination", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+		})
+
+		mockUserRepository.On("FindByEmail", mock.Anything, email).Return(&model.User{
+			UID:      uid,
+			Email:    email,
+			Password: hashedValidPW,
+		}, nil)
+
+		u := &model.User{
+			Email:    email,
+			Password: invalidPW,
+		}
+		err := us.Signin(context.TODO(), u)
+
+		assert.Error(t, err)
+		assert.EqualError(t, err, "Invalid email and password combination")
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("Failed signins recorded", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockAuditRepository := new(mocks.MockAuditRepository)
+		us := NewUserService(&USConfig{
+			UserRepository:  mockUserRepository,
+			AuditRepository: mockAuditRepository,
+			AuditSecret:     "audit secret",
+		})
+
+		mockUserRepository.On("FindByEmail", mock.Anything, email).Return(&model.User{
+			UID:      uid,
+			Email:    email,
+			Password: hashedValidPW,
+		}, nil)
+		mockUserRepository.On("FindByEmail", mock.Anything, "alice@alice.com").Return(nil, apperrors.NewNotFound("email", "alice@alice.com"))
+		mockAuditRepository.On("Create", mock.Anything, &model.AuditEvent{
+			Type:   model.AuditUserSigninFailed,
+			Target: &uid,
+			Detail: "Invalid password",
+		}).Return(nil)
+		mockAuditRepository.On("Create", mock.Anything, &model.AuditEvent{
+			Type:   model.AuditUserSigninFailed,
+			Detail: hashAuditEmail("audit secret", "alice@alice.com"),
+		}).Return(nil)
+
+		err := us.Signin(context.TODO(), &model.User{Email: email, Password: invalidPW})
+		assert.EqualError(t, err, "Invalid email and password combination")
+
+		err = us.Signin(context.TODO(), &model.User{Email: "alice@alice.com", Password: invalidPW})
+		assert.EqualError(t, err, "Invalid email and password combination")
+
+		mockAuditRepository.AssertExpectations(t)
+	})
+
+	t.Run("Disabled account", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+		})
+
+		mockUserRepository.On("FindByEmail", mock.Anything, email).Return(&model.User{
+			UID:      uid,
+			Email:    email,
+			Password: hashedValidPW,
+			Status:   model.UserDisabled,
+		}, nil)
+
+		u := &model.User{
+			Email:    email,
+			Password: validPW,
+		}
+		err := us.Signin(context.TODO(), u)
+
+		assert.EqualError(t, err, "Account is disabled")
+		assert.Equal(t, uuid.Nil, u.UID)
+	})
+
+	t.Run("Pending account", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+		})
+
+		mockUserRepository.On("FindByEmail", mock.Anything, email).Return(&model.User{
+			UID:      uid,
+			Email:    email,
+			Password: hashedValidPW,
+			Status:   model.UserPending,
+		}, nil)
+
+		u := &model.User{
+			Email:    email,
+			Password: validPW,
+		}
+		err := us.Signin(context.TODO(), u)
+
+		assert.EqualError(t, err, "Account is pending approval")
+		assert.Equal(t, uuid.Nil, u.UID)
+	})
+
+	t.Run("Password reset required by an admin", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+		})
+
+		mockUserRepository.On("FindByEmail", mock.Anything, email).Return(&model.User{
+			UID:                   uid,
+			Email:                 email,
+			Password:              hashedValidPW,
+			PasswordResetRequired: true,
+		}, nil)
+
+		u := &model.User{
+			Email:    email,
+			Password: validPW,
+		}
+		err := us.Signin(context.TODO(), u)
+
+		assert.Equal(t, apperrors.PasswordExpired, err.(*apperrors.Error).Type)
+	})
+
+	t.Run("Unverified email with verification required", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+			AppSettings: model.AppSettings{
+				RequireVerifiedEmail: true,
+			},
+		})
+
+		mockUserRepository.On("FindByEmail", mock.Anything, email).Return(&model.User{
+			UID:      uid,
+			Email:    email,
+			Password: hashedValidPW,
+		}, nil)
+
+		u := &model.User{
+			Email:    email,
+			Password: validPW,
+		}
+		err := us.Signin(context.TODO(), u)
+
+		assert.Equal(t, apperrors.Authorization, err.(*apperrors.Error).Type)
+		assert.Equal(t, uuid.Nil, u.UID)
+	})
+
+	t.Run("Unverified email with verification optional", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+		})
+
+		mockUserRepository.On("FindByEmail", mock.Anything, email).Return(&model.User{
+			UID:      uid,
+			Email:    email,
+			Password: hashedValidPW,
+		}, nil)
+
+		u := &model.User{
+			Email:    email,
+			Password: validPW,
+		}
+		err := us.Signin(context.TODO(), u)
+
+		assert.NoError(t, err)
+		assert.False(t, u.EmailVerified)
+	})
+
+	t.Run("Expired password", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+			AppSettings: model.AppSettings{
+				MaxPasswordAge: 90 * 24 * time.Hour,
+			},
+		})
+
+		mockUserRepository.On("FindByEmail", mock.Anything, email).Return(&model.User{
+			UID:               uid,
+			Email:             email,
+			Password:          hashedValidPW,
+			PasswordChangedAt: time.Now().Add(-91 * 24 * time.Hour),
+		}, nil)
+
+		u := &model.User{
+			Email:    email,
+			Password: validPW,
+		}
+		err := us.Signin(context.TODO(), u)
+
+		assert.Equal(t, apperrors.PasswordExpired, err.(*apperrors.Error).Type)
+		assert.Equal(t, uuid.Nil, u.UID)
+	})
+
+	t.Run("Password within max age", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+			AppSettings: model.AppSettings{
+				MaxPasswordAge: 90 * 24 * time.Hour,
+			},
+		})
+
+		mockUserRepository.On("FindByEmail", mock.Anything, email).Return(&model.User{
+			UID:               uid,
+			Email:             email,
+			Password:          hashedValidPW,
+			PasswordChangedAt: time.Now().Add(-89 * 24 * time.Hour),
+		}, nil)
+
+		u := &model.User{
+			Email:    email,
+			Password: validPW,
+		}
+		err := us.Signin(context.TODO(), u)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Rehashes outdated password hash", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		hasher := &Argon2idHasher{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+			PasswordHasher: hasher,
+		})
+
+		outdated, _ := (&ScryptHasher{LogN: 10, R: 8, P: 1, SaltLength: 16, KeyLength: 32}).Hash(validPW)
+
+		mockUserRepository.On("FindByEmail", mock.Anything, email).Return(&model.User{
+			UID:      uid,
+			Email:    email,
+			Password: outdated,
+		}, nil)
+
+		rehashed := make(chan string, 1)
+		mockUserRepository.
+			On("UpdatePasswordHash", mock.Anything, uid, outdated, mock.AnythingOfType("string")).
+			Run(func(args mock.Arguments) {
+				rehashed <- args.String(3)
+			}).
+			Return(nil)
+
+		u := &model.User{
+			Email:    email,
+			Password: validPW,
+		}
+		err := us.Signin(context.TODO(), u)
+
+		assert.NoError(t, err)
+
+		select {
+		case pw := <-rehashed:
+			assert.False(t, hasher.NeedsRehash(pw))
+
+			match, err := comparePasswords(pw, validPW)
+			assert.NoError(t, err)
+			assert.True(t, match)
+		case <-time.After(5 * time.Second):
+			t.Fatal("password was not rehashed")
+		}
+	})
+
+	t.Run("Rehash failure does not affect signin", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+		})
+
+		// "howdyhoneighbor!" hashed by the original "hash.salt" scrypt implementation
+		legacy := "013937c4e8540abdbc9460f38656ecb1754f74ccf6e2cdd86725d8134ad93539.00112233445566778899aabbccddeeff00112233445566778899aabbccddeeff"
+
+		mockUserRepository.On("FindByEmail", mock.Anything, email).Return(&model.User{
+			UID:      uid,
+			Email:    email,
+			Password: legacy,
+		}, nil)
+
+		attempted := make(chan struct{})
+		mockUserRepository.
+			On("UpdatePasswordHash", mock.Anything, uid, legacy, mock.AnythingOfType("string")).
+			Run(func(args mock.Arguments) {
+				close(attempted)
+			}).
+			Return(apperrors.NewInternal())
+
+		u := &model.User{
+			Email:    email,
+			Password: validPW,
+		}
+		err := us.Signin(context.TODO(), u)
+
+		assert.NoError(t, err)
+		assert.Equal(t, uid, u.UID)
+
+		select {
+		case <-attempted:
+		case <-time.After(5 * time.Second):
+			t.Fatal("password rehash was not attempted")
+		}
+	})
+
+	t.Run("Current password hash is kept", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+		})
+
+		mockUserRepository.On("FindByEmail", mock.Anything, email).Return(&model.User{
+			UID:      uid,
+			Email:    email,
+			Password: hashedValidPW,
+		}, nil)
+
+		u := &model.User{
+			Email:    email,
+			Password: validPW,
+		}
+		err := us.Signin(context.TODO(), u)
+
+		assert.NoError(t, err)
+		mockUserRepository.AssertNotCalled(t, "UpdatePasswordHash", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestResetExpiredPassword(t *testing.T) {
+	email := "bob@bob.com"
+	currentPassword := "howdyhoneighbor!"
+	hashedPassword, _ := defaultPasswordHasher.Hash(currentPassword)
+
+	uid, _ := uuid.NewRandom()
+
+	t.Run("Success", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+			AppSettings: model.AppSettings{
+				MaxPasswordAge: time.Hour,
+			},
+		})
+
+		mockUserRepository.On("FindByEmail", mock.Anything, email).Return(&model.User{
+			UID:               uid,
+			Email:             email,
+			Password:          hashedPassword,
+			PasswordChangedAt: time.Now().Add(-2 * time.Hour),
+		}, nil)
+		mockUserRepository.On("UpdatePassword", mock.Anything, uid, mock.AnythingOfType("string")).Return(nil)
+
+		u := &model.User{
+			Email:    email,
+			Password: currentPassword,
+		}
+		err := us.ResetExpiredPassword(context.TODO(), u, "anewpassword")
+
+		assert.NoError(t, err)
+		assert.Equal(t, uid, u.UID)
+		assert.WithinDuration(t, time.Now(), u.PasswordChangedAt, time.Minute)
+
+		match, _ := comparePasswords(u.Password, "anewpassword")
+		assert.True(t, match)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("Invalid current password", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+		})
+
+		mockUserRepository.On("FindByEmail", mock.Anything, email).Return(&model.User{
+			UID:      uid,
+			Email:    email,
+			Password: hashedPassword,
+		}, nil)
+
+		u := &model.User{
+			Email:    email,
+			Password: "wrongpassword",
+		}
+		err := us.ResetExpiredPassword(context.TODO(), u, "anewpassword")
+
+		assert.Equal(t, apperrors.Authorization, err.(*apperrors.Error).Type)
+		mockUserRepository.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unknown email", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+		})
+
+		mockUserRepository.On("FindByEmail", mock.Anything, email).Return(nil, apperrors.NewNotFound("email", email))
+
+		err := us.ResetExpiredPassword(context.TODO(), &model.User{Email: email, Password: currentPassword}, "anewpassword")
+
+		assert.Equal(t, apperrors.Authorization, err.(*apperrors.Error).Type)
+	})
+
+	t.Run("Reset required", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+		})
+
+		mockUserRepository.On("FindByEmail", mock.Anything, email).Return(&model.User{
+			UID:                   uid,
+			Email:                 email,
+			Password:              hashedPassword,
+			PasswordChangedAt:     time.Now(),
+			PasswordResetRequired: true,
+		}, nil)
+		mockUserRepository.On("UpdatePassword", mock.Anything, uid, mock.AnythingOfType("string")).Return(nil)
+
+		err := us.ResetExpiredPassword(context.TODO(), &model.User{Email: email, Password: currentPassword}, "anewpassword")
+
+		assert.NoError(t, err)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("Password not expired", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+			AppSettings: model.AppSettings{
+				MaxPasswordAge: time.Hour,
+			},
+		})
+
+		mockUserRepository.On("FindByEmail", mock.Anything, email).Return(&model.User{
+			UID:               uid,
+			Email:             email,
+			Password:          hashedPassword,
+			PasswordChangedAt: time.Now().Add(-time.Minute),
+		}, nil)
+
+		err := us.ResetExpiredPassword(context.TODO(), &model.User{Email: email, Password: currentPassword}, "anewpassword")
+
+		assert.Equal(t, apperrors.BadRequest, err.(*apperrors.Error).Type)
+		mockUserRepository.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestVerifyEmail(t *testing.T) {
+	uid, _ := uuid.NewRandom()
+	email := "bob@bob.com"
+	token, tokenHash, _ := generateVerificationToken()
+
+	t.Run("Success", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockVerificationTokenRepository := new(mocks.MockVerificationTokenRepository)
+		us := NewUserService(&USConfig{
+			UserRepository:              mockUserRepository,
+			VerificationTokenRepository: mockVerificationTokenRepository,
+		})
+
+		mockVerificationTokenRepository.On("Consume", mock.Anything, model.VerifyEmail, tokenHash).Return(&model.VerificationToken{
+			TokenHash: tokenHash,
+			UID:       uid,
+			Purpose:   model.VerifyEmail,
+			Email:     email,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}, nil)
+		mockUserRepository.On("SetEmailVerified", mock.Anything, uid, email).Return(nil)
+
+		err := us.VerifyEmail(context.TODO(), token)
+
+		assert.NoError(t, err)
+		mockVerificationTokenRepository.AssertExpectations(t)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("Expired token", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockVerificationTokenRepository := new(mocks.MockVerificationTokenRepository)
+		us := NewUserService(&USConfig{
+			UserRepository:              mockUserRepository,
+			VerificationTokenRepository: mockVerificationTokenRepository,
+		})
+
+		mockVerificationTokenRepository.On("Consume", mock.Anything, model.VerifyEmail, tokenHash).Return(&model.VerificationToken{
+			TokenHash: tokenHash,
+			UID:       uid,
+			Purpose:   model.VerifyEmail,
+			Email:     email,
+			ExpiresAt: time.Now().Add(-time.Minute),
+		}, nil)
+
+		err := us.VerifyEmail(context.TODO(), token)
+
+		assert.Equal(t, apperrors.BadRequest, err.(*apperrors.Error).Type)
+		mockUserRepository.AssertNotCalled(t, "SetEmailVerified", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unknown token", func(t *testing.T) {
+		mockVerificationTokenRepository := new(mocks.MockVerificationTokenRepository)
+		us := NewUserService(&USConfig{
+			VerificationTokenRepository: mockVerificationTokenRepository,
+		})
+
+		mockVerificationTokenRepository.On("Consume", mock.Anything, model.VerifyEmail, mock.AnythingOfType("string")).
+			Return(nil, apperrors.NewNotFound("token", string(model.VerifyEmail)))
+
+		err := us.VerifyEmail(context.TODO(), "unknown")
+
+		assert.Equal(t, apperrors.BadRequest, err.(*apperrors.Error).Type)
+	})
+}
+
+func TestResendVerificationEmail(t *testing.T) {
+	uid, _ := uuid.NewRandom()
+	email := "bob@bob.com"
+
+	t.Run("Success", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockVerificationTokenRepository := new(mocks.MockVerificationTokenRepository)
+		mockMailer := new(mocks.MockMailer)
+		us := NewUserService(&USConfig{
+			UserRepository:              mockUserRepository,
+			VerificationTokenRepository: mockVerificationTokenRepository,
+			Mailer:                      mockMailer,
+		})
+
+		mockUserRepository.On("FindByEmail", mock.Anything, email).Return(&model.User{UID: uid, Email: email}, nil)
+		mockVerificationTokenRepository.On("FindLatest", mock.Anything, uid, model.VerifyEmail).Return(&model.VerificationToken{
+			CreatedAt: time.Now().Add(-time.Hour),
+		}, nil)
+		mockVerificationTokenRepository.On("Create", mock.Anything, mock.AnythingOfType("*model.VerificationToken")).Return(nil)
+		mockMailer.On("Send", mock.Anything, email, mock.Anything, mock.Anything).Return(nil)
+
+		err := us.ResendVerificationEmail(context.TODO(), email)
+
+		assert.NoError(t, err)
+		mockVerificationTokenRepository.AssertExpectations(t)
+		mockMailer.AssertExpectations(t)
+	})
+
+	t.Run("Throttled", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockVerificationTokenRepository := new(mocks.MockVerificationTokenRepository)
+		mockMailer := new(mocks.MockMailer)
+		us := NewUserService(&USConfig{
+			UserRepository:              mockUserRepository,
+			VerificationTokenRepository: mockVerificationTokenRepository,
+			Mailer:                      mockMailer,
+		})
+
+		mockUserRepository.On("FindByEmail", mock.Anything, email).Return(&model.User{UID: uid, Email: email}, nil)
+		mockVerificationTokenRepository.On("FindLatest", mock.Anything, uid, model.VerifyEmail).Return(&model.VerificationToken{
+			CreatedAt: time.Now().Add(-10 * time.Second),
+		}, nil)
+
+		err := us.ResendVerificationEmail(context.TODO(), email)
+
+		assert.Equal(t, apperrors.BadRequest, err.(*apperrors.Error).Type)
+		mockMailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unknown or verified email", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockMailer := new(mocks.MockMailer)
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+			Mailer:         mockMailer,
+		})
+
+		mockUserRepository.On("FindByEmail", mock.Anything, "unknown@bob.com").Return(nil, apperrors.NewNotFound("email", "unknown@bob.com"))
+		mockUserRepository.On("FindByEmail", mock.Anything, email).Return(&model.User{UID: uid, Email: email, EmailVerified: true}, nil)
+
+		assert.NoError(t, us.ResendVerificationEmail(context.TODO(), "unknown@bob.com"))
+		assert.NoError(t, us.ResendVerificationEmail(context.TODO(), email))
+		mockMailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestRequestEmailChange(t *testing.T) {
+	uid, _ := uuid.NewRandom()
+	password := "howdyhoneighbor!"
+	hashedPassword, _ := defaultPasswordHasher.Hash(password)
+	newEmail := "new@bob.com"
+
+	mockUserResp := &model.User{
+		UID:      uid,
+		Email:    "bob@bob.com",
+		Password: hashedPassword,
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockVerificationTokenRepository := new(mocks.MockVerificationTokenRepository)
+		mockMailer := new(mocks.MockMailer)
+		us := NewUserService(&USConfig{
+			UserRepository:              mockUserRepository,
+			VerificationTokenRepository: mockVerificationTokenRepository,
+			Mailer:                      mockMailer,
+		})
+
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(mockUserResp, nil)
+		mockUserRepository.On("FindByEmail", mock.Anything, newEmail).Return(nil, apperrors.NewNotFound("email", newEmail))
+		mockVerificationTokenRepository.
+			On("Create", mock.Anything, mock.MatchedBy(func(vt *model.VerificationToken) bool {
+				return vt.UID == uid && vt.Purpose == model.ChangeEmail && vt.Email == newEmail
+			})).
+			Return(nil)
+		mockMailer.On("Send", mock.Anything, newEmail, mock.Anything, mock.Anything).Return(nil)
+
+		err := us.RequestEmailChange(context.TODO(), uid, password, newEmail)
+
+		assert.NoError(t, err)
+		mockVerificationTokenRepository.AssertExpectations(t)
+		mockMailer.AssertExpectations(t)
+		mockUserRepository.AssertNotCalled(t, "UpdateEmail", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Email already in use", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockMailer := new(mocks.MockMailer)
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+			Mailer:         mockMailer,
+		})
+
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(mockUserResp, nil)
+		mockUserRepository.On("FindByEmail", mock.Anything, newEmail).Return(&model.User{Email: newEmail}, nil)
+
+		err := us.RequestEmailChange(context.TODO(), uid, password, newEmail)
+
+		assert.Equal(t, apperrors.Conflict, err.(*apperrors.Error).Type)
+		mockMailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Invalid password", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+		})
+
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(mockUserResp, nil)
+
+		err := us.RequestEmailChange(context.TODO(), uid, "wrongpassword", newEmail)
+
+		assert.Equal(t, apperrors.Authorization, err.(*apperrors.Error).Type)
+		mockUserRepository.AssertNotCalled(t, "FindByEmail", mock.Anything, mock.Anything)
+	})
+}
+
+func TestConfirmEmailChange(t *testing.T) {
+	uid, _ := uuid.NewRandom()
+	oldEmail := "bob@bob.com"
+	newEmail := "new@bob.com"
+	token, tokenHash, _ := generateVerificationToken()
+
+	mockToken := &model.VerificationToken{
+		TokenHash: tokenHash,
+		UID:       uid,
+		Purpose:   model.ChangeEmail,
+		Email:     newEmail,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	t.Run("Success notifies previous email", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockVerificationTokenRepository := new(mocks.MockVerificationTokenRepository)
+		mockMailer := new(mocks.MockMailer)
+		us := NewUserService(&USConfig{
+			UserRepository:              mockUserRepository,
+			VerificationTokenRepository: mockVerificationTokenRepository,
+			Mailer:                      mockMailer,
+		})
+
+		updatedUser := &model.User{UID: uid, Email: newEmail, EmailVerified: true}
+
+		mockVerificationTokenRepository.On("Consume", mock.Anything, model.ChangeEmail, tokenHash).Return(mockToken, nil)
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(&model.User{UID: uid, Email: oldEmail}, nil)
+		mockUserRepository.On("UpdateEmail", mock.Anything, uid, newEmail).Return(updatedUser, nil)
+		mockVerificationTokenRepository.
+			On("Create", mock.Anything, mock.MatchedBy(func(vt *model.VerificationToken) bool {
+				return vt.UID == uid && vt.Purpose == model.RevertEmail && vt.Email == oldEmail
+			})).
+			Return(nil)
+		mockMailer.On("Send", mock.Anything, oldEmail, mock.Anything, mock.Anything).Return(nil)
+
+		u, err := us.ConfirmEmailChange(context.TODO(), token)
+
+		assert.NoError(t, err)
+		assert.Equal(t, updatedUser, u)
+		mockUserRepository.AssertExpectations(t)
+		mockVerificationTokenRepository.AssertExpectations(t)
+		mockMailer.AssertExpectations(t)
+	})
+
+	t.Run("Previous email notified without a revert link", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockVerificationTokenRepository := new(mocks.MockVerificationTokenRepository)
+		mockMailer := new(mocks.MockMailer)
+		us := NewUserService(&USConfig{
+			UserRepository:              mockUserRepository,
+			VerificationTokenRepository: mockVerificationTokenRepository,
+			Mailer:                      mockMailer,
+		})
+
+		mockVerificationTokenRepository.On("Consume", mock.Anything, model.ChangeEmail, tokenHash).Return(mockToken, nil)
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(&model.User{UID: uid, Email: oldEmail}, nil)
+		mockUserRepository.On("UpdateEmail", mock.Anything, uid, newEmail).Return(&model.User{UID: uid, Email: newEmail}, nil)
+		mockVerificationTokenRepository.On("Create", mock.Anything, mock.Anything).Return(apperrors.NewInternal())
+		mockMailer.
+			On("Send", mock.Anything, oldEmail, mock.Anything, mock.MatchedBy(func(body string) bool {
+				return strings.Contains(body, newEmail) && !strings.Contains(body, "revert-email")
+			})).
+			Return(nil)
+
+		_, err := us.ConfirmEmailChange(context.TODO(), token)
+
+		assert.NoError(t, err)
+		mockMailer.AssertExpectations(t)
+	})
+
+	t.Run("Email registered since the request", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockVerificationTokenRepository := new(mocks.MockVerificationTokenRepository)
+		mockMailer := new(mocks.MockMailer)
+		us := NewUserService(&USConfig{
+			UserRepository:              mockUserRepository,
+			VerificationTokenRepository: mockVerificationTokenRepository,
+			Mailer:                      mockMailer,
+		})
+
+		mockErr := apperrors.NewConflict("email", newEmail)
+
+		mockVerificationTokenRepository.On("Consume", mock.Anything, model.ChangeEmail, tokenHash).Return(mockToken, nil)
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(&model.User{UID: uid, Email: oldEmail}, nil)
+		mockUserRepository.On("UpdateEmail", mock.Anything, uid, newEmail).Return(nil, mockErr)
+
+		u, err := us.ConfirmEmailChange(context.TODO(), token)
+
+		assert.Nil(t, u)
+		assert.EqualError(t, err, mockErr.Error())
+		mockMailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Expired token", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockVerificationTokenRepository := new(mocks.MockVerificationTokenRepository)
+		us := NewUserService(&USConfig{
+			UserRepository:              mockUserRepository,
+			VerificationTokenRepository: mockVerificationTokenRepository,
+		})
+
+		mockVerificationTokenRepository.On("Consume", mock.Anything, model.ChangeEmail, tokenHash).Return(&model.VerificationToken{
+			UID:       uid,
+			Email:     newEmail,
+			ExpiresAt: time.Now().Add(-time.Minute),
+		}, nil)
+
+		_, err := us.ConfirmEmailChange(context.TODO(), token)
+
+		assert.Equal(t, apperrors.BadRequest, err.(*apperrors.Error).Type)
+		mockUserRepository.AssertNotCalled(t, "UpdateEmail", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestRevertEmailChange(t *testing.T) {
+	uid, _ := uuid.NewRandom()
+	oldEmail := "bob@bob.com"
+	token, tokenHash, _ := generateVerificationToken()
+
+	mockUserRepository := new(mocks.MockUserRepository)
+	mockVerificationTokenRepository := new(mocks.MockVerificationTokenRepository)
+	us := NewUserService(&USConfig{
+		UserRepository:              mockUserRepository,
+		VerificationTokenRepository: mockVerificationTokenRepository,
+	})
+
+	restoredUser := &model.User{UID: uid, Email: oldEmail, EmailVerified: true}
+
+	mockVerificationTokenRepository.On("Consume", mock.Anything, model.RevertEmail, tokenHash).Return(&model.VerificationToken{
+		UID:       uid,
+		Purpose:   model.RevertEmail,
+		Email:     oldEmail,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	mockUserRepository.On("UpdateEmail", mock.Anything, uid, oldEmail).Return(restoredUser, nil)
+
+	u, err := us.RevertEmailChange(context.TODO(), token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, restoredUser, u)
+	mockUserRepository.AssertExpectations(t)
+}
+
+func TestUpdateDetails(t *testing.T) {
+	uid, _ := uuid.NewRandom()
+	name := "Bobby Bobson"
+
+	details := &model.UserDetails{
+		Name: &name,
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+		})
+
+		updatedUser := &model.User{
+			UID:     uid,
+			Name:    name,
+			Version: 2,
+		}
+
+		mockUserRepository.On("Update", mock.Anything, uid, 1, details).Return(updatedUser, nil)
+
+		u, err := us.UpdateDetails(context.TODO(), uid, 1, details)
+
+		assert.NoError(t, err)
+		assert.Equal(t, updatedUser, u)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("Outdated version", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+		})
+
+		mockErr := apperrors.NewOutdated("user", uid.String())
+		mockUserRepository.On("Update", mock.Anything, uid, 1, details).Return(nil, mockErr)
+
+		u, err := us.UpdateDetails(context.TODO(), uid, 1, details)
+
+		assert.Nil(t, u)
+		assert.EqualError(t, err, mockErr.Error())
+	})
+}
+
+func TestSetProfileImage(t *testing.T) {
+	uid, _ := uuid.NewRandom()
+	prevVariants := model.ImageVariants{
+		64:  "https://images.test/" + uid.String() + "_previous_64.jpg",
+		512: "https://images.test/" + uid.String() + "_previous_512.jpg",
+	}
+
+	t.Run("Success replaces previous image", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockImageRepository := new(mocks.MockImageRepository)
+		us := NewUserService(&USConfig{
+			UserRepository:  mockUserRepository,
+			ImageRepository: mockImageRepository,
+			ImageSizes:      []int{64, 512},
+		})
+
+		var updatedVariants model.ImageVariants
+		updatedUser := &model.User{UID: uid}
+
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(&model.User{
+			UID:           uid,
+			ImageURL:      prevVariants[512],
+			ImageVariants: prevVariants,
+		}, nil)
+		mockImageRepository.
+			On("Upload", mock.Anything, mock.MatchedBy(func(objName string) bool {
+				return strings.HasSuffix(objName, "_64.jpg")
+			}), "image/jpeg", mock.Anything).
+			Return("https://images.test/new_64.jpg", nil)
+		mockImageRepository.
+			On("Upload", mock.Anything, mock.MatchedBy(func(objName string) bool {
+				return strings.HasSuffix(objName, "_512.jpg")
+			}), "image/jpeg", mock.Anything).
+			Return("https://images.test/new_512.jpg", nil)
+		mockUserRepository.
+			On("UpdateImage", mock.Anything, uid, "https://images.test/new_512.jpg", mock.AnythingOfType("model.ImageVariants")).
+			Run(func(args mock.Arguments) {
+				updatedVariants = args.Get(3).(model.ImageVariants)
+			}).
+			Return(updatedUser, nil)
+		mockImageRepository.On("Delete", mock.Anything, uid.String()+"_previous_64.jpg").Return(nil)
+		mockImageRepository.On("Delete", mock.Anything, uid.String()+"_previous_512.jpg").Return(nil)
+
+		u, err := us.SetProfileImage(context.TODO(), uid, bytes.NewReader(testImage(t, 800, 600)))
+
+		assert.NoError(t, err)
+		assert.Equal(t, updatedUser, u)
+		assert.Equal(t, model.ImageVariants{
+			64:  "https://images.test/new_64.jpg",
+			512: "https://images.test/new_512.jpg",
+		}, updatedVariants)
+
+		mockImageRepository.AssertNumberOfCalls(t, "Upload", 2)
+		mockImageRepository.AssertNumberOfCalls(t, "Delete", 2)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("Invalid image", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockImageRepository := new(mocks.MockImageRepository)
+		us := NewUserService(&USConfig{
+			UserRepository:  mockUserRepository,
+			ImageRepository: mockImageRepository,
+		})
+
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(&model.User{UID: uid}, nil)
+
+		u, err := us.SetProfileImage(context.TODO(), uid, bytes.NewReader([]byte("not an image")))
+
+		assert.Nil(t, u)
+		assert.Equal(t, apperrors.BadRequest, err.(*apperrors.Error).Type)
+		mockImageRepository.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Upload error removes uploaded variants", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockImageRepository := new(mocks.MockImageRepository)
+		us := NewUserService(&USConfig{
+			UserRepository:  mockUserRepository,
+			ImageRepository: mockImageRepository,
+			ImageSizes:      []int{64, 512},
+		})
+
+		mockErr := apperrors.NewInternal()
+
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(&model.User{UID: uid, ImageVariants: prevVariants}, nil)
+		mockImageRepository.
+			On("Upload", mock.Anything, mock.MatchedBy(func(objName string) bool {
+				return strings.HasSuffix(objName, "_64.jpg")
+			}), "image/jpeg", mock.Anything).
+			Return("https://images.test/new_64.jpg", nil)
+		mockImageRepository.
+			On("Upload", mock.Anything, mock.MatchedBy(func(objName string) bool {
+				return strings.HasSuffix(objName, "_512.jpg")
+			}), "image/jpeg", mock.Anything).
+			Return("", mockErr)
+		mockImageRepository.On("Delete", mock.Anything, "new_64.jpg").Return(nil)
+
+		u, err := us.SetProfileImage(context.TODO(), uid, bytes.NewReader(testImage(t, 100, 100)))
+
+		assert.Nil(t, u)
+		assert.EqualError(t, err, mockErr.Error())
+		mockUserRepository.AssertNotCalled(t, "UpdateImage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockImageRepository.AssertExpectations(t)
+		mockImageRepository.AssertNumberOfCalls(t, "Delete", 1)
+	})
+
+	t.Run("Update error removes uploaded variants", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockImageRepository := new(mocks.MockImageRepository)
+		us := NewUserService(&USConfig{
+			UserRepository:  mockUserRepository,
+			ImageRepository: mockImageRepository,
+			ImageSizes:      []int{64},
+		})
+
+		mockErr := apperrors.NewInternal()
+
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(&model.User{UID: uid, ImageVariants: prevVariants}, nil)
+		mockImageRepository.On("Upload", mock.Anything, mock.AnythingOfType("string"), "image/jpeg", mock.Anything).Return("https://images.test/new_64.jpg", nil)
+		mockUserRepository.On("UpdateImage", mock.Anything, uid, "https://images.test/new_64.jpg", mock.Anything).Return(nil, mockErr)
+		mockImageRepository.On("Delete", mock.Anything, "new_64.jpg").Return(nil)
+
+		_, err := us.SetProfileImage(context.TODO(), uid, bytes.NewReader(testImage(t, 100, 100)))
+
+		assert.EqualError(t, err, mockErr.Error())
+		mockImageRepository.AssertExpectations(t)
+		mockImageRepository.AssertNotCalled(t, "Delete", mock.Anything, uid.String()+"_previous_64.jpg")
+	})
+}
+
+func TestClearProfileImage(t *testing.T) {
+	uid, _ := uuid.NewRandom()
+	variants := model.ImageVariants{
+		64: "https://images.test/" + uid.String() + "_previous_64.jpg",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockImageRepository := new(mocks.MockImageRepository)
+		us := NewUserService(&USConfig{
+			UserRepository:  mockUserRepository,
+			ImageRepository: mockImageRepository,
+		})
+
+		updatedUser := &model.User{UID: uid}
+
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(&model.User{UID: uid, ImageURL: variants[64], ImageVariants: variants}, nil)
+		mockUserRepository.On("UpdateImage", mock.Anything, uid, "", model.ImageVariants{}).Return(updatedUser, nil)
+		mockImageRepository.On("Delete", mock.Anything, uid.String()+"_previous_64.jpg").Return(nil)
+
+		u, err := us.ClearProfileImage(context.TODO(), uid)
+
+		assert.NoError(t, err)
+		assert.Equal(t, updatedUser, u)
+		mockUserRepository.AssertExpectations(t)
+		mockImageRepository.AssertExpectations(t)
+		mockImageRepository.AssertNumberOfCalls(t, "Delete", 1)
+	})
+
+	t.Run("No image", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockImageRepository := new(mocks.MockImageRepository)
+		us := NewUserService(&USConfig{
+			UserRepository:  mockUserRepository,
+			ImageRepository: mockImageRepository,
+		})
+
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(&model.User{UID: uid}, nil)
+
+		_, err := us.ClearProfileImage(context.TODO(), uid)
+
+		assert.NoError(t, err)
+		mockUserRepository.AssertNotCalled(t, "UpdateImage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockImageRepository.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
+
+func TestDeleteAccount(t *testing.T) {
+	uid, _ := uuid.NewRandom()
+	email := "bob@bob.com"
+	password := "howdyhoneighbor!"
+	hashedPassword, _ := defaultPasswordHasher.Hash(password)
+
+	t.Run("Success", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockVerificationTokenRepository := new(mocks.MockVerificationTokenRepository)
+		mockAuditRepository := new(mocks.MockAuditRepository)
+		mockMailer := new(mocks.MockMailer)
+		us := NewUserService(&USConfig{
+			UserRepository:              mockUserRepository,
+			VerificationTokenRepository: mockVerificationTokenRepository,
+			AuditRepository:             mockAuditRepository,
+			Mailer:                      mockMailer,
+		})
+
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(&model.User{
+			UID:      uid,
+			Email:    email,
+			Password: hashedPassword,
+		}, nil)
+		mockUserRepository.
+			On("SetStatus", mock.Anything, uid, model.UserDeleted, deletedByUserReason).
+			Return(&model.User{UID: uid, Email: email, Status: model.UserDeleted}, nil)
+		mockAuditRepository.On("Create", mock.Anything, &model.AuditEvent{
+			Type:   model.AuditUserDeleted,
+			Actor:  &uid,
+			Target: &uid,
+		}).Return(nil)
+		mockVerificationTokenRepository.
+			On("Create", mock.Anything, mock.AnythingOfType("*model.VerificationToken")).
+			Run(func(args mock.Arguments) {
+				// the restore link lasts as long as the account is kept
+				vt := args.Get(1).(*model.VerificationToken)
+				assert.Equal(t, model.RestoreAccount, vt.Purpose)
+				assert.WithinDuration(t, time.Now().Add(model.AppSettings{}.GetDeletedUserRetention()), vt.ExpiresAt, time.Minute)
+			}).Return(nil)
+		mockMailer.On("Send", mock.Anything, email, mock.Anything, mock.Anything).Return(nil)
+
+		err := us.DeleteAccount(context.TODO(), uid, password)
+
+		assert.NoError(t, err)
+		mockUserRepository.AssertExpectations(t)
+		mockAuditRepository.AssertExpectations(t)
+		mockVerificationTokenRepository.AssertExpectations(t)
+		mockMailer.AssertExpectations(t)
+	})
+
+	t.Run("Invalid password", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		us := NewUserService(&USConfig{
+			UserRepository: mockUserRepository,
+		})
+
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(&model.User{
+			UID:      uid,
+			Email:    email,
+			Password: hashedPassword,
+		}, nil)
+
+		err := us.DeleteAccount(context.TODO(), uid, "wrongpassword")
+
+		assert.Equal(t, apperrors.Authorization, err.(*apperrors.Error).Type)
+		mockUserRepository.AssertNotCalled(t, "SetStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestRestoreAccount(t *testing.T) {
+	uid, _ := uuid.NewRandom()
+	token, tokenHash, _ := generateVerificationToken()
+
+	t.Run("Success", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockVerificationTokenRepository := new(mocks.MockVerificationTokenRepository)
+		us := NewUserService(&USConfig{
+			UserRepository:              mockUserRepository,
+			VerificationTokenRepository: mockVerificationTokenRepository,
+		})
+
+		restoredUser := &model.User{UID: uid, Status: model.UserActive}
+
+		mockVerificationTokenRepository.On("Consume", mock.Anything, model.RestoreAccount, tokenHash).Return(&model.VerificationToken{
+			UID:       uid,
+			Purpose:   model.RestoreAccount,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}, nil)
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(&model.User{UID: uid, Status: model.UserDeleted}, nil)
+		mockUserRepository.On("SetStatus", mock.Anything, uid, model.UserActive, "").Return(restoredUser, nil)
+
+		u, err := us.RestoreAccount(context.TODO(), token)
+
+		assert.NoError(t, err)
+		assert.Equal(t, restoredUser, u)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("Account no longer deleted", func(t *testing.T) {
+		mockUserRepository := new(mocks.MockUserRepository)
+		mockVerificationTokenRepository := new(mocks.MockVerificationTokenRepository)
+		us := NewUserService(&USConfig{
+			UserRepository:              mockUserRepository,
+			VerificationTokenRepository: mockVerificationTokenRepository,
+		})
+
+		mockVerificationTokenRepository.On("Consume", mock.Anything, model.RestoreAccount, tokenHash).Return(&model.VerificationToken{
+			UID:       uid,
+			Purpose:   model.RestoreAccount,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}, nil)
+		mockUserRepository.On("FindByID", mock.Anything, uid).Return(&model.User{UID: uid, Status: model.UserDisabled}, nil)
+
+		u, err := us.RestoreAccount(context.TODO(), token)
+
+		assert.Nil(t, u)
+		assert.Equal(t, apperrors.BadRequest, err.(*apperrors.Error).Type)
+		mockUserRepository.AssertNotCalled(t, "SetStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// generateVerificationToken returns a random url safe token to be emailed to a user
+// along with the hash of the token which is what gets stored
+func generateVerificationToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	return token, hashVerificationToken(token), nil
+}
+
+// hashVerificationToken hashes a verification token for storage and lookup.
+// Tokens have enough entropy that a fast unsalted hash is sufficient
+func hashVerificationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}